@@ -124,18 +124,38 @@ func (m *ListRequest) GetFilter() string {
 }
 
 type User struct {
-	Id                   string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	Username             string               `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
-	Mail                 string               `protobuf:"bytes,5,opt,name=mail,proto3" json:"mail,omitempty"`
-	Language             string               `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"`
-	Password             string               `protobuf:"bytes,7,opt,name=password,proto3" json:"password,omitempty"`
-	Confirmed            bool                 `protobuf:"varint,8,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
-	Roles                []string             `protobuf:"bytes,9,rep,name=roles,proto3" json:"roles,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Id        string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Username  string               `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Mail      string               `protobuf:"bytes,5,opt,name=mail,proto3" json:"mail,omitempty"`
+	Language  string               `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"`
+	Password  string               `protobuf:"bytes,7,opt,name=password,proto3" json:"password,omitempty"`
+	Confirmed bool                 `protobuf:"varint,8,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	Roles     []string             `protobuf:"bytes,9,rep,name=roles,proto3" json:"roles,omitempty"`
+	// true if the user is a service account authenticating using api keys.
+	ServiceAccount bool `protobuf:"varint,10,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	// true if the user is suspended and therefore not allowed to use gooser.
+	Suspended        bool   `protobuf:"varint,11,opt,name=suspended,proto3" json:"suspended,omitempty"`
+	SuspensionReason string `protobuf:"bytes,12,opt,name=suspension_reason,json=suspensionReason,proto3" json:"suspension_reason,omitempty"`
+	// end of the suspension, the user is suspended indefinitely if not set.
+	SuspendedUntil *timestamp.Timestamp `protobuf:"bytes,13,opt,name=suspended_until,json=suspendedUntil,proto3" json:"suspended_until,omitempty"`
+	// time at which the account will be deleted as requested by the user.
+	DeletionScheduledAt *timestamp.Timestamp `protobuf:"bytes,14,opt,name=deletion_scheduled_at,json=deletionScheduledAt,proto3" json:"deletion_scheduled_at,omitempty"`
+	// true if the user was invited and has not accepted the invitation yet.
+	InvitationPending   bool                 `protobuf:"varint,15,opt,name=invitation_pending,json=invitationPending,proto3" json:"invitation_pending,omitempty"`
+	InvitationExpiresAt *timestamp.Timestamp `protobuf:"bytes,16,opt,name=invitation_expires_at,json=invitationExpiresAt,proto3" json:"invitation_expires_at,omitempty"`
+	// id of the admin who invited the user.
+	InvitedBy string `protobuf:"bytes,17,opt,name=invited_by,json=invitedBy,proto3" json:"invited_by,omitempty"`
+	// custom attributes, keyed by the name of their attribute definition.
+	Attributes map[string]*AttributeValue `protobuf:"bytes,18,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// opaque version of the user, changing with every modification.
+	Etag string `protobuf:"bytes,19,opt,name=etag,proto3" json:"etag,omitempty"`
+	// new mail address which replaces the current one once it is confirmed, read only.
+	PendingMail          string   `protobuf:"bytes,20,opt,name=pending_mail,json=pendingMail,proto3" json:"pending_mail,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *User) Reset()         { *m = User{} }
@@ -226,12 +246,91 @@ func (m *User) GetRoles() []string {
 	return nil
 }
 
+func (m *User) GetServiceAccount() bool {
+	if m != nil {
+		return m.ServiceAccount
+	}
+	return false
+}
+
+func (m *User) GetSuspended() bool {
+	if m != nil {
+		return m.Suspended
+	}
+	return false
+}
+
+func (m *User) GetSuspensionReason() string {
+	if m != nil {
+		return m.SuspensionReason
+	}
+	return ""
+}
+
+func (m *User) GetSuspendedUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.SuspendedUntil
+	}
+	return nil
+}
+
+func (m *User) GetDeletionScheduledAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.DeletionScheduledAt
+	}
+	return nil
+}
+
+func (m *User) GetInvitationPending() bool {
+	if m != nil {
+		return m.InvitationPending
+	}
+	return false
+}
+
+func (m *User) GetInvitationExpiresAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.InvitationExpiresAt
+	}
+	return nil
+}
+
+func (m *User) GetInvitedBy() string {
+	if m != nil {
+		return m.InvitedBy
+	}
+	return ""
+}
+
+func (m *User) GetAttributes() map[string]*AttributeValue {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *User) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
+}
+
+func (m *User) GetPendingMail() string {
+	if m != nil {
+		return m.PendingMail
+	}
+	return ""
+}
+
 type UpdateUserRequest struct {
-	User                 *User                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	FieldMask            *field_mask.FieldMask `protobuf:"bytes,2,opt,name=field_mask,json=fieldMask,proto3" json:"field_mask,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	User      *User                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	FieldMask *field_mask.FieldMask `protobuf:"bytes,2,opt,name=field_mask,json=fieldMask,proto3" json:"field_mask,omitempty"`
+	// if set, the update is aborted if the user has been modified since the etag was read.
+	Etag                 string   `protobuf:"bytes,3,opt,name=etag,proto3" json:"etag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *UpdateUserRequest) Reset()         { *m = UpdateUserRequest{} }
@@ -273,6 +372,13 @@ func (m *UpdateUserRequest) GetFieldMask() *field_mask.FieldMask {
 	return nil
 }
 
+func (m *UpdateUserRequest) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
+}
+
 type ListUsersResponse struct {
 	Users                []*User  `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
 	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
@@ -524,779 +630,3766 @@ func (m *ResetPasswordRequest) GetPassword() string {
 	return ""
 }
 
-type Group struct {
-	Id                   string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	Name                 string               `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
-	Roles                []string             `protobuf:"bytes,5,rep,name=roles,proto3" json:"roles,omitempty"`
-	Members              []string             `protobuf:"bytes,6,rep,name=members,proto3" json:"members,omitempty"`
+type RevertMailChangeRequest struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevertMailChangeRequest) Reset()         { *m = RevertMailChangeRequest{} }
+func (m *RevertMailChangeRequest) String() string { return proto.CompactTextString(m) }
+func (*RevertMailChangeRequest) ProtoMessage()    {}
+func (*RevertMailChangeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{9}
+}
+
+func (m *RevertMailChangeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevertMailChangeRequest.Unmarshal(m, b)
+}
+func (m *RevertMailChangeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevertMailChangeRequest.Marshal(b, m, deterministic)
+}
+func (m *RevertMailChangeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevertMailChangeRequest.Merge(m, src)
+}
+func (m *RevertMailChangeRequest) XXX_Size() int {
+	return xxx_messageInfo_RevertMailChangeRequest.Size(m)
+}
+func (m *RevertMailChangeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevertMailChangeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevertMailChangeRequest proto.InternalMessageInfo
+
+func (m *RevertMailChangeRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type SuspendUserRequest struct {
+	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// optional end of the suspension.
+	Until                *timestamp.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
 }
 
-func (m *Group) Reset()         { *m = Group{} }
-func (m *Group) String() string { return proto.CompactTextString(m) }
-func (*Group) ProtoMessage()    {}
-func (*Group) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5fbca08c6b16090c, []int{9}
+func (m *SuspendUserRequest) Reset()         { *m = SuspendUserRequest{} }
+func (m *SuspendUserRequest) String() string { return proto.CompactTextString(m) }
+func (*SuspendUserRequest) ProtoMessage()    {}
+func (*SuspendUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{10}
 }
 
-func (m *Group) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Group.Unmarshal(m, b)
+func (m *SuspendUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SuspendUserRequest.Unmarshal(m, b)
 }
-func (m *Group) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Group.Marshal(b, m, deterministic)
+func (m *SuspendUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SuspendUserRequest.Marshal(b, m, deterministic)
 }
-func (m *Group) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Group.Merge(m, src)
+func (m *SuspendUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SuspendUserRequest.Merge(m, src)
 }
-func (m *Group) XXX_Size() int {
-	return xxx_messageInfo_Group.Size(m)
+func (m *SuspendUserRequest) XXX_Size() int {
+	return xxx_messageInfo_SuspendUserRequest.Size(m)
 }
-func (m *Group) XXX_DiscardUnknown() {
-	xxx_messageInfo_Group.DiscardUnknown(m)
+func (m *SuspendUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SuspendUserRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Group proto.InternalMessageInfo
+var xxx_messageInfo_SuspendUserRequest proto.InternalMessageInfo
 
-func (m *Group) GetId() string {
+func (m *SuspendUserRequest) GetId() string {
 	if m != nil {
 		return m.Id
 	}
 	return ""
 }
 
-func (m *Group) GetCreatedAt() *timestamp.Timestamp {
+func (m *SuspendUserRequest) GetReason() string {
 	if m != nil {
-		return m.CreatedAt
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *SuspendUserRequest) GetUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.Until
 	}
 	return nil
 }
 
-func (m *Group) GetUpdatedAt() *timestamp.Timestamp {
+// all data about a user, as exported by ExportMyData.
+type PersonalData struct {
+	User   *User    `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Groups []*Group `protobuf:"bytes,2,rep,name=groups,proto3" json:"groups,omitempty"`
+	Roles  []string `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
+	// audit events in which the user is the actor or the resource.
+	AuditEvents          []*AuditEvent        `protobuf:"bytes,4,rep,name=audit_events,json=auditEvents,proto3" json:"audit_events,omitempty"`
+	ExportedAt           *timestamp.Timestamp `protobuf:"bytes,5,opt,name=exported_at,json=exportedAt,proto3" json:"exported_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *PersonalData) Reset()         { *m = PersonalData{} }
+func (m *PersonalData) String() string { return proto.CompactTextString(m) }
+func (*PersonalData) ProtoMessage()    {}
+func (*PersonalData) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{11}
+}
+
+func (m *PersonalData) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PersonalData.Unmarshal(m, b)
+}
+func (m *PersonalData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PersonalData.Marshal(b, m, deterministic)
+}
+func (m *PersonalData) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PersonalData.Merge(m, src)
+}
+func (m *PersonalData) XXX_Size() int {
+	return xxx_messageInfo_PersonalData.Size(m)
+}
+func (m *PersonalData) XXX_DiscardUnknown() {
+	xxx_messageInfo_PersonalData.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PersonalData proto.InternalMessageInfo
+
+func (m *PersonalData) GetUser() *User {
 	if m != nil {
-		return m.UpdatedAt
+		return m.User
 	}
 	return nil
 }
 
-func (m *Group) GetName() string {
+func (m *PersonalData) GetGroups() []*Group {
 	if m != nil {
-		return m.Name
+		return m.Groups
 	}
-	return ""
+	return nil
 }
 
-func (m *Group) GetRoles() []string {
+func (m *PersonalData) GetRoles() []string {
 	if m != nil {
 		return m.Roles
 	}
 	return nil
 }
 
-func (m *Group) GetMembers() []string {
+func (m *PersonalData) GetAuditEvents() []*AuditEvent {
 	if m != nil {
-		return m.Members
+		return m.AuditEvents
 	}
 	return nil
 }
 
-type UpdateGroupRequest struct {
-	Group                *Group                `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
-	FieldMask            *field_mask.FieldMask `protobuf:"bytes,2,opt,name=field_mask,json=fieldMask,proto3" json:"field_mask,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+func (m *PersonalData) GetExportedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ExportedAt
+	}
+	return nil
 }
 
-func (m *UpdateGroupRequest) Reset()         { *m = UpdateGroupRequest{} }
-func (m *UpdateGroupRequest) String() string { return proto.CompactTextString(m) }
-func (*UpdateGroupRequest) ProtoMessage()    {}
-func (*UpdateGroupRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5fbca08c6b16090c, []int{10}
+type ExportMyDataResponse struct {
+	// the personal data as json document.
+	Data                 string   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UpdateGroupRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpdateGroupRequest.Unmarshal(m, b)
+func (m *ExportMyDataResponse) Reset()         { *m = ExportMyDataResponse{} }
+func (m *ExportMyDataResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportMyDataResponse) ProtoMessage()    {}
+func (*ExportMyDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{12}
 }
-func (m *UpdateGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpdateGroupRequest.Marshal(b, m, deterministic)
+
+func (m *ExportMyDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportMyDataResponse.Unmarshal(m, b)
 }
-func (m *UpdateGroupRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpdateGroupRequest.Merge(m, src)
+func (m *ExportMyDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportMyDataResponse.Marshal(b, m, deterministic)
 }
-func (m *UpdateGroupRequest) XXX_Size() int {
-	return xxx_messageInfo_UpdateGroupRequest.Size(m)
+func (m *ExportMyDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportMyDataResponse.Merge(m, src)
 }
-func (m *UpdateGroupRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpdateGroupRequest.DiscardUnknown(m)
+func (m *ExportMyDataResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportMyDataResponse.Size(m)
 }
-
-var xxx_messageInfo_UpdateGroupRequest proto.InternalMessageInfo
-
-func (m *UpdateGroupRequest) GetGroup() *Group {
-	if m != nil {
-		return m.Group
-	}
-	return nil
+func (m *ExportMyDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportMyDataResponse.DiscardUnknown(m)
 }
 
-func (m *UpdateGroupRequest) GetFieldMask() *field_mask.FieldMask {
+var xxx_messageInfo_ExportMyDataResponse proto.InternalMessageInfo
+
+func (m *ExportMyDataResponse) GetData() string {
 	if m != nil {
-		return m.FieldMask
+		return m.Data
 	}
-	return nil
+	return ""
 }
 
-type ListGroupsResponse struct {
-	Groups               []*Group `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
-	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
-	PageSize             int32    `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	TotalSize            int32    `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+type DeleteMyAccountRequest struct {
+	Password             string   `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ListGroupsResponse) Reset()         { *m = ListGroupsResponse{} }
-func (m *ListGroupsResponse) String() string { return proto.CompactTextString(m) }
-func (*ListGroupsResponse) ProtoMessage()    {}
-func (*ListGroupsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5fbca08c6b16090c, []int{11}
+func (m *DeleteMyAccountRequest) Reset()         { *m = DeleteMyAccountRequest{} }
+func (m *DeleteMyAccountRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteMyAccountRequest) ProtoMessage()    {}
+func (*DeleteMyAccountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{13}
 }
 
-func (m *ListGroupsResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ListGroupsResponse.Unmarshal(m, b)
+func (m *DeleteMyAccountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteMyAccountRequest.Unmarshal(m, b)
 }
-func (m *ListGroupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ListGroupsResponse.Marshal(b, m, deterministic)
+func (m *DeleteMyAccountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteMyAccountRequest.Marshal(b, m, deterministic)
 }
-func (m *ListGroupsResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ListGroupsResponse.Merge(m, src)
+func (m *DeleteMyAccountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteMyAccountRequest.Merge(m, src)
 }
-func (m *ListGroupsResponse) XXX_Size() int {
-	return xxx_messageInfo_ListGroupsResponse.Size(m)
+func (m *DeleteMyAccountRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteMyAccountRequest.Size(m)
 }
-func (m *ListGroupsResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ListGroupsResponse.DiscardUnknown(m)
+func (m *DeleteMyAccountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteMyAccountRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ListGroupsResponse proto.InternalMessageInfo
+var xxx_messageInfo_DeleteMyAccountRequest proto.InternalMessageInfo
 
-func (m *ListGroupsResponse) GetGroups() []*Group {
+func (m *DeleteMyAccountRequest) GetPassword() string {
 	if m != nil {
-		return m.Groups
+		return m.Password
 	}
-	return nil
+	return ""
 }
 
-func (m *ListGroupsResponse) GetNextPageToken() string {
+type InviteUserRequest struct {
+	Mail     string `protobuf:"bytes,1,opt,name=mail,proto3" json:"mail,omitempty"`
+	Language string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	// ids of the groups the user becomes member of when accepting the invitation.
+	GroupIds             []string                   `protobuf:"bytes,3,rep,name=group_ids,json=groupIds,proto3" json:"group_ids,omitempty"`
+	Attributes           map[string]*AttributeValue `protobuf:"bytes,4,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *InviteUserRequest) Reset()         { *m = InviteUserRequest{} }
+func (m *InviteUserRequest) String() string { return proto.CompactTextString(m) }
+func (*InviteUserRequest) ProtoMessage()    {}
+func (*InviteUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{14}
+}
+
+func (m *InviteUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InviteUserRequest.Unmarshal(m, b)
+}
+func (m *InviteUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InviteUserRequest.Marshal(b, m, deterministic)
+}
+func (m *InviteUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InviteUserRequest.Merge(m, src)
+}
+func (m *InviteUserRequest) XXX_Size() int {
+	return xxx_messageInfo_InviteUserRequest.Size(m)
+}
+func (m *InviteUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InviteUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InviteUserRequest proto.InternalMessageInfo
+
+func (m *InviteUserRequest) GetMail() string {
 	if m != nil {
-		return m.NextPageToken
+		return m.Mail
 	}
 	return ""
 }
 
-func (m *ListGroupsResponse) GetPageSize() int32 {
+func (m *InviteUserRequest) GetLanguage() string {
 	if m != nil {
-		return m.PageSize
+		return m.Language
 	}
-	return 0
+	return ""
 }
 
-func (m *ListGroupsResponse) GetTotalSize() int32 {
+func (m *InviteUserRequest) GetGroupIds() []string {
 	if m != nil {
-		return m.TotalSize
+		return m.GroupIds
 	}
-	return 0
+	return nil
 }
 
-func init() {
-	proto.RegisterType((*IdRequest)(nil), "gooser.v1.IdRequest")
-	proto.RegisterType((*ListRequest)(nil), "gooser.v1.ListRequest")
-	proto.RegisterType((*User)(nil), "gooser.v1.User")
-	proto.RegisterType((*UpdateUserRequest)(nil), "gooser.v1.UpdateUserRequest")
-	proto.RegisterType((*ListUsersResponse)(nil), "gooser.v1.ListUsersResponse")
-	proto.RegisterType((*ChangePasswordRequest)(nil), "gooser.v1.ChangePasswordRequest")
-	proto.RegisterType((*ConfirmMailRequest)(nil), "gooser.v1.ConfirmMailRequest")
-	proto.RegisterType((*ForgotPasswordRequest)(nil), "gooser.v1.ForgotPasswordRequest")
-	proto.RegisterType((*ResetPasswordRequest)(nil), "gooser.v1.ResetPasswordRequest")
-	proto.RegisterType((*Group)(nil), "gooser.v1.Group")
-	proto.RegisterType((*UpdateGroupRequest)(nil), "gooser.v1.UpdateGroupRequest")
-	proto.RegisterType((*ListGroupsResponse)(nil), "gooser.v1.ListGroupsResponse")
+func (m *InviteUserRequest) GetAttributes() map[string]*AttributeValue {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
 }
 
-func init() {
-	proto.RegisterFile("api/proto/v1/gooser_service.proto", fileDescriptor_5fbca08c6b16090c)
+type AcceptInvitationRequest struct {
+	Token    string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Username string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	// attributes the user may edit themselves.
+	Attributes           map[string]*AttributeValue `protobuf:"bytes,4,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
 }
 
-var fileDescriptor_5fbca08c6b16090c = []byte{
-	// 857 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x55, 0x5f, 0x6f, 0xdc, 0x44,
-	0x10, 0xbf, 0x7f, 0xbe, 0x9c, 0xc7, 0xb4, 0xa5, 0xab, 0x34, 0xb2, 0x2e, 0x8d, 0x7a, 0x5d, 0x44,
-	0x15, 0xf1, 0x70, 0x21, 0x29, 0x2f, 0x48, 0x54, 0xa2, 0x84, 0xe6, 0x00, 0xb5, 0x52, 0x65, 0xda,
-	0x17, 0x5e, 0x4e, 0x9b, 0x78, 0xce, 0x58, 0xb1, 0xbd, 0xc6, 0xbb, 0x97, 0x40, 0xbe, 0x06, 0xef,
-	0x3c, 0xf0, 0xc4, 0xd7, 0xe1, 0x1b, 0xa1, 0xdd, 0xb5, 0xcf, 0x7f, 0xef, 0x78, 0x40, 0x42, 0x7d,
-	0xf3, 0xcc, 0xfc, 0x66, 0x76, 0x76, 0xf6, 0x37, 0x3f, 0xc3, 0x53, 0x96, 0x86, 0x27, 0x69, 0xc6,
-	0x25, 0x3f, 0xb9, 0x39, 0x3d, 0x09, 0x38, 0x17, 0x98, 0x2d, 0x05, 0x66, 0x37, 0xe1, 0x15, 0xce,
-	0xb5, 0x9f, 0xd8, 0xc6, 0x3b, 0xbf, 0x39, 0x9d, 0x1e, 0x06, 0x9c, 0x07, 0x11, 0x9a, 0x84, 0xcb,
-	0xf5, 0xea, 0x04, 0xe3, 0x54, 0xfe, 0x66, 0x70, 0xd3, 0x59, 0x33, 0xb8, 0x0a, 0x31, 0xf2, 0x97,
-	0x31, 0x13, 0xd7, 0x39, 0xe2, 0x49, 0x13, 0x21, 0xc3, 0x18, 0x85, 0x64, 0x71, 0x6a, 0x00, 0xf4,
-	0x10, 0xec, 0xef, 0x7d, 0x0f, 0x7f, 0x59, 0xa3, 0x90, 0xe4, 0x3e, 0x0c, 0x42, 0xdf, 0xed, 0xcf,
-	0xfa, 0xc7, 0xb6, 0x37, 0x08, 0x7d, 0xca, 0xc0, 0x79, 0x1d, 0x0a, 0x59, 0x84, 0x0f, 0xc1, 0x4e,
-	0x59, 0x80, 0x4b, 0x11, 0xde, 0xa1, 0x46, 0x59, 0xde, 0x44, 0x39, 0x7e, 0x0c, 0xef, 0x90, 0x1c,
-	0x01, 0xe8, 0xa0, 0xe4, 0xd7, 0x98, 0xb8, 0x03, 0x5d, 0x43, 0xc3, 0xdf, 0x29, 0x07, 0x39, 0x80,
-	0xf1, 0x2a, 0x8c, 0x24, 0x66, 0xee, 0x50, 0x87, 0x72, 0x8b, 0xfe, 0x35, 0x80, 0xd1, 0x7b, 0x81,
-	0x59, 0xf3, 0x6c, 0xf2, 0x25, 0xc0, 0x55, 0x86, 0x4c, 0xa2, 0xbf, 0x64, 0x52, 0xd7, 0x73, 0xce,
-	0xa6, 0x73, 0x73, 0x9d, 0x79, 0x71, 0x9d, 0xf9, 0xbb, 0xe2, 0x3a, 0x9e, 0x9d, 0xa3, 0x5f, 0x4a,
-	0x95, 0xba, 0x4e, 0xfd, 0x22, 0x75, 0xf8, 0xef, 0xa9, 0x39, 0xfa, 0xa5, 0x24, 0x53, 0x98, 0xac,
-	0x05, 0x66, 0x09, 0x8b, 0xd1, 0x1d, 0xe9, 0x5e, 0x36, 0x36, 0x21, 0x30, 0x8a, 0x59, 0x18, 0xb9,
-	0x96, 0xf6, 0xeb, 0x6f, 0x85, 0x8f, 0x58, 0x12, 0xac, 0x59, 0x80, 0xee, 0xd8, 0xe0, 0x0b, 0x5b,
-	0xc5, 0x52, 0x26, 0xc4, 0x2d, 0xcf, 0x7c, 0x77, 0xcf, 0xc4, 0x0a, 0x9b, 0x3c, 0x06, 0xfb, 0x8a,
-	0x27, 0xab, 0x30, 0x8b, 0xd1, 0x77, 0x27, 0xb3, 0xfe, 0xf1, 0xc4, 0x2b, 0x1d, 0x64, 0x1f, 0xac,
-	0x8c, 0x47, 0x28, 0x5c, 0x7b, 0x36, 0x3c, 0xb6, 0x3d, 0x63, 0x50, 0x01, 0x0f, 0xdf, 0xeb, 0x46,
-	0xd5, 0xbc, 0x8a, 0x37, 0xf9, 0x04, 0x46, 0xaa, 0x41, 0x3d, 0x38, 0xe7, 0xec, 0xc1, 0x7c, 0xc3,
-	0x9c, 0xb9, 0x46, 0xe9, 0xa0, 0x1a, 0x48, 0xc9, 0x8c, 0xad, 0xb3, 0xbc, 0x50, 0x90, 0x37, 0x4c,
-	0x5c, 0x7b, 0xf6, 0xaa, 0xf8, 0xa4, 0x7f, 0xf4, 0xe1, 0xa1, 0xe2, 0x80, 0xaa, 0x26, 0x3c, 0x14,
-	0x29, 0x4f, 0x04, 0x92, 0x4f, 0xc1, 0x52, 0x85, 0x85, 0xdb, 0x9f, 0x0d, 0xbb, 0x8e, 0x35, 0x51,
-	0xf2, 0x0c, 0x1e, 0x24, 0xf8, 0xab, 0x5c, 0xb6, 0x88, 0x71, 0x4f, 0xb9, 0xdf, 0x6e, 0xc8, 0x51,
-	0x23, 0xd6, 0xb0, 0x4d, 0x2c, 0xc9, 0x25, 0x8b, 0x4c, 0x74, 0xa4, 0xa3, 0xb6, 0xf6, 0xa8, 0x30,
-	0x8d, 0xe1, 0xd1, 0xf9, 0xcf, 0x2c, 0x09, 0xf0, 0x6d, 0x3e, 0xdb, 0x2d, 0x64, 0x26, 0x4f, 0xe1,
-	0x23, 0x1e, 0xf9, 0xcb, 0xcd, 0x93, 0x98, 0x4e, 0x1c, 0x1e, 0xf9, 0x45, 0xa6, 0x82, 0x24, 0x78,
-	0x5b, 0x42, 0x0c, 0x55, 0x9d, 0x04, 0x6f, 0x0b, 0x08, 0xfd, 0x0c, 0xc8, 0xb9, 0x79, 0xa7, 0x37,
-	0x2c, 0x8c, 0x8a, 0xb3, 0xf6, 0xc1, 0x32, 0xd7, 0x33, 0xc7, 0x19, 0x83, 0x2e, 0xe0, 0xd1, 0x05,
-	0xcf, 0x02, 0x2e, 0x9b, 0xad, 0x55, 0x59, 0xd6, 0xdf, 0xc2, 0xb2, 0x41, 0xc9, 0x32, 0xfa, 0x1d,
-	0xec, 0x7b, 0x28, 0xb0, 0x55, 0xa7, 0xf3, 0xd8, 0x1a, 0xef, 0x06, 0x75, 0xde, 0xd1, 0xbf, 0xfb,
-	0x60, 0x2d, 0x32, 0xbe, 0x4e, 0x3f, 0x90, 0x7d, 0x23, 0x30, 0xaa, 0xec, 0x9a, 0xfe, 0x2e, 0xd9,
-	0x6f, 0x55, 0xd8, 0x4f, 0x5c, 0xd8, 0x8b, 0x31, 0xbe, 0x54, 0xa4, 0x1b, 0x6b, 0x7f, 0x61, 0xd2,
-	0x5b, 0x20, 0x66, 0x2f, 0xf4, 0xc5, 0x8a, 0xd9, 0x3c, 0x03, 0x2b, 0x50, 0x76, 0xbe, 0x19, 0x1f,
-	0x57, 0x28, 0x6a, 0x70, 0x26, 0xfc, 0x5f, 0x76, 0xe3, 0xcf, 0x3e, 0x10, 0xb5, 0x1b, 0xba, 0x5e,
-	0xb9, 0x1c, 0xc7, 0x30, 0xd6, 0xa5, 0x8b, 0xed, 0x68, 0x1f, 0x9d, 0xc7, 0xff, 0x8f, 0xfd, 0x38,
-	0xfb, 0x7d, 0x0f, 0xc6, 0x0b, 0x7d, 0x3e, 0x39, 0x07, 0x7b, 0xb3, 0xca, 0xe4, 0xa0, 0xd2, 0x55,
-	0x45, 0xe4, 0xa7, 0x8f, 0x1b, 0xfe, 0xda, 0xe2, 0xd3, 0x1e, 0x39, 0x83, 0xbd, 0x05, 0x6a, 0x2f,
-	0xd9, 0xaf, 0x40, 0x37, 0x3f, 0x91, 0x69, 0x53, 0x0c, 0x68, 0x8f, 0x7c, 0x0e, 0x70, 0xae, 0xd9,
-	0xa2, 0xd3, 0x9a, 0x80, 0xae, 0x8c, 0x17, 0x00, 0xa5, 0xd6, 0x91, 0x6a, 0x4f, 0x2d, 0x09, 0xec,
-	0x4a, 0xff, 0x0a, 0xe0, 0x5b, 0x8c, 0x30, 0x4f, 0xef, 0xee, 0xf3, 0xa0, 0xf5, 0xc8, 0xaf, 0xd4,
-	0xaf, 0x95, 0xf6, 0xc8, 0x6b, 0xb8, 0x5f, 0x97, 0x14, 0x32, 0xab, 0x54, 0xe8, 0x54, 0x9b, 0x1d,
-	0xd5, 0x2e, 0xc0, 0xa9, 0x28, 0x06, 0x39, 0xaa, 0x96, 0x6a, 0x29, 0xc9, 0xee, 0xae, 0xea, 0x6a,
-	0x52, 0xeb, 0xaa, 0x53, 0x68, 0x76, 0x54, 0xfb, 0x01, 0xee, 0xd5, 0x24, 0x85, 0x3c, 0xa9, 0x14,
-	0xeb, 0x12, 0x9b, 0x1d, 0xb5, 0x5e, 0x01, 0x94, 0x6b, 0xb0, 0x95, 0x58, 0x47, 0x0d, 0x7f, 0x7d,
-	0x6b, 0x68, 0x8f, 0x7c, 0x01, 0x93, 0x05, 0x1a, 0xf7, 0x96, 0x27, 0x6b, 0x6d, 0x12, 0xed, 0x91,
-	0xe7, 0xe0, 0x18, 0x6e, 0x99, 0xc4, 0x16, 0xa4, 0x33, 0xe9, 0x6b, 0x70, 0x2a, 0x92, 0x51, 0x7b,
-	0x93, 0xb6, 0x94, 0x74, 0x56, 0x78, 0x01, 0x8e, 0x61, 0xd8, 0xae, 0x7e, 0xb7, 0x8e, 0xec, 0x1b,
-	0xf8, 0x69, 0x62, 0x12, 0x6e, 0x4e, 0x2f, 0xc7, 0x3a, 0xfa, 0xfc, 0x9f, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x25, 0xfb, 0x26, 0x8e, 0x19, 0x0a, 0x00, 0x00,
+func (m *AcceptInvitationRequest) Reset()         { *m = AcceptInvitationRequest{} }
+func (m *AcceptInvitationRequest) String() string { return proto.CompactTextString(m) }
+func (*AcceptInvitationRequest) ProtoMessage()    {}
+func (*AcceptInvitationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{15}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
+func (m *AcceptInvitationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcceptInvitationRequest.Unmarshal(m, b)
+}
+func (m *AcceptInvitationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcceptInvitationRequest.Marshal(b, m, deterministic)
+}
+func (m *AcceptInvitationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcceptInvitationRequest.Merge(m, src)
+}
+func (m *AcceptInvitationRequest) XXX_Size() int {
+	return xxx_messageInfo_AcceptInvitationRequest.Size(m)
+}
+func (m *AcceptInvitationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcceptInvitationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcceptInvitationRequest proto.InternalMessageInfo
+
+func (m *AcceptInvitationRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *AcceptInvitationRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *AcceptInvitationRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *AcceptInvitationRequest) GetAttributes() map[string]*AttributeValue {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+type Group struct {
+	Id        string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Name      string               `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Roles     []string             `protobuf:"bytes,5,rep,name=roles,proto3" json:"roles,omitempty"`
+	Members   []string             `protobuf:"bytes,6,rep,name=members,proto3" json:"members,omitempty"`
+	// opaque version of the group, changing with every modification.
+	Etag                 string   `protobuf:"bytes,7,opt,name=etag,proto3" json:"etag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Group) Reset()         { *m = Group{} }
+func (m *Group) String() string { return proto.CompactTextString(m) }
+func (*Group) ProtoMessage()    {}
+func (*Group) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{16}
+}
+
+func (m *Group) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Group.Unmarshal(m, b)
+}
+func (m *Group) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Group.Marshal(b, m, deterministic)
+}
+func (m *Group) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Group.Merge(m, src)
+}
+func (m *Group) XXX_Size() int {
+	return xxx_messageInfo_Group.Size(m)
+}
+func (m *Group) XXX_DiscardUnknown() {
+	xxx_messageInfo_Group.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Group proto.InternalMessageInfo
+
+func (m *Group) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Group) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *Group) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *Group) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Group) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *Group) GetMembers() []string {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *Group) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
+}
+
+type UpdateGroupRequest struct {
+	Group     *Group                `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	FieldMask *field_mask.FieldMask `protobuf:"bytes,2,opt,name=field_mask,json=fieldMask,proto3" json:"field_mask,omitempty"`
+	// if set, the update is aborted if the group has been modified since the etag was read.
+	Etag                 string   `protobuf:"bytes,3,opt,name=etag,proto3" json:"etag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateGroupRequest) Reset()         { *m = UpdateGroupRequest{} }
+func (m *UpdateGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateGroupRequest) ProtoMessage()    {}
+func (*UpdateGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{17}
+}
+
+func (m *UpdateGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateGroupRequest.Unmarshal(m, b)
+}
+func (m *UpdateGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateGroupRequest.Merge(m, src)
+}
+func (m *UpdateGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateGroupRequest.Size(m)
+}
+func (m *UpdateGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateGroupRequest proto.InternalMessageInfo
+
+func (m *UpdateGroupRequest) GetGroup() *Group {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+func (m *UpdateGroupRequest) GetFieldMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.FieldMask
+	}
+	return nil
+}
+
+func (m *UpdateGroupRequest) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
+}
+
+type ListGroupsResponse struct {
+	Groups               []*Group `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	PageSize             int32    `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TotalSize            int32    `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListGroupsResponse) Reset()         { *m = ListGroupsResponse{} }
+func (m *ListGroupsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListGroupsResponse) ProtoMessage()    {}
+func (*ListGroupsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{18}
+}
+
+func (m *ListGroupsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGroupsResponse.Unmarshal(m, b)
+}
+func (m *ListGroupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGroupsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListGroupsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGroupsResponse.Merge(m, src)
+}
+func (m *ListGroupsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListGroupsResponse.Size(m)
+}
+func (m *ListGroupsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGroupsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGroupsResponse proto.InternalMessageInfo
+
+func (m *ListGroupsResponse) GetGroups() []*Group {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+func (m *ListGroupsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *ListGroupsResponse) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListGroupsResponse) GetTotalSize() int32 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+type Webhook struct {
+	Id        string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Url       string               `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Events    []string             `protobuf:"bytes,5,rep,name=events,proto3" json:"events,omitempty"`
+	// secret used to sign the payloads, only returned when creating the webhook.
+	Secret               string   `protobuf:"bytes,6,opt,name=secret,proto3" json:"secret,omitempty"`
+	Active               bool     `protobuf:"varint,7,opt,name=active,proto3" json:"active,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Webhook) Reset()         { *m = Webhook{} }
+func (m *Webhook) String() string { return proto.CompactTextString(m) }
+func (*Webhook) ProtoMessage()    {}
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{19}
+}
+
+func (m *Webhook) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Webhook.Unmarshal(m, b)
+}
+func (m *Webhook) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Webhook.Marshal(b, m, deterministic)
+}
+func (m *Webhook) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Webhook.Merge(m, src)
+}
+func (m *Webhook) XXX_Size() int {
+	return xxx_messageInfo_Webhook.Size(m)
+}
+func (m *Webhook) XXX_DiscardUnknown() {
+	xxx_messageInfo_Webhook.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Webhook proto.InternalMessageInfo
+
+func (m *Webhook) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Webhook) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *Webhook) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *Webhook) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Webhook) GetEvents() []string {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *Webhook) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *Webhook) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+type UpdateWebhookRequest struct {
+	Webhook              *Webhook              `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	FieldMask            *field_mask.FieldMask `protobuf:"bytes,2,opt,name=field_mask,json=fieldMask,proto3" json:"field_mask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *UpdateWebhookRequest) Reset()         { *m = UpdateWebhookRequest{} }
+func (m *UpdateWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateWebhookRequest) ProtoMessage()    {}
+func (*UpdateWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{20}
+}
+
+func (m *UpdateWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateWebhookRequest.Unmarshal(m, b)
+}
+func (m *UpdateWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateWebhookRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateWebhookRequest.Merge(m, src)
+}
+func (m *UpdateWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateWebhookRequest.Size(m)
+}
+func (m *UpdateWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateWebhookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateWebhookRequest proto.InternalMessageInfo
+
+func (m *UpdateWebhookRequest) GetWebhook() *Webhook {
+	if m != nil {
+		return m.Webhook
+	}
+	return nil
+}
+
+func (m *UpdateWebhookRequest) GetFieldMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.FieldMask
+	}
+	return nil
+}
+
+type ListWebhooksResponse struct {
+	Webhooks             []*Webhook `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	NextPageToken        string     `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	PageSize             int32      `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TotalSize            int32      `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListWebhooksResponse) Reset()         { *m = ListWebhooksResponse{} }
+func (m *ListWebhooksResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWebhooksResponse) ProtoMessage()    {}
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{21}
+}
+
+func (m *ListWebhooksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhooksResponse.Unmarshal(m, b)
+}
+func (m *ListWebhooksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhooksResponse.Marshal(b, m, deterministic)
+}
+func (m *ListWebhooksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhooksResponse.Merge(m, src)
+}
+func (m *ListWebhooksResponse) XXX_Size() int {
+	return xxx_messageInfo_ListWebhooksResponse.Size(m)
+}
+func (m *ListWebhooksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhooksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhooksResponse proto.InternalMessageInfo
+
+func (m *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if m != nil {
+		return m.Webhooks
+	}
+	return nil
+}
+
+func (m *ListWebhooksResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *ListWebhooksResponse) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListWebhooksResponse) GetTotalSize() int32 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+type WebhookDelivery struct {
+	Id        string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	WebhookId string               `protobuf:"bytes,4,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Event     string               `protobuf:"bytes,5,opt,name=event,proto3" json:"event,omitempty"`
+	Payload   string               `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"`
+	// one of pending, succeeded or failed.
+	State                string               `protobuf:"bytes,7,opt,name=state,proto3" json:"state,omitempty"`
+	Attempts             int32                `protobuf:"varint,8,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	NextAttemptAt        *timestamp.Timestamp `protobuf:"bytes,9,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	ResponseCode         int32                `protobuf:"varint,10,opt,name=response_code,json=responseCode,proto3" json:"response_code,omitempty"`
+	LastError            string               `protobuf:"bytes,11,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *WebhookDelivery) Reset()         { *m = WebhookDelivery{} }
+func (m *WebhookDelivery) String() string { return proto.CompactTextString(m) }
+func (*WebhookDelivery) ProtoMessage()    {}
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{22}
+}
+
+func (m *WebhookDelivery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WebhookDelivery.Unmarshal(m, b)
+}
+func (m *WebhookDelivery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WebhookDelivery.Marshal(b, m, deterministic)
+}
+func (m *WebhookDelivery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WebhookDelivery.Merge(m, src)
+}
+func (m *WebhookDelivery) XXX_Size() int {
+	return xxx_messageInfo_WebhookDelivery.Size(m)
+}
+func (m *WebhookDelivery) XXX_DiscardUnknown() {
+	xxx_messageInfo_WebhookDelivery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WebhookDelivery proto.InternalMessageInfo
+
+func (m *WebhookDelivery) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *WebhookDelivery) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *WebhookDelivery) GetWebhookId() string {
+	if m != nil {
+		return m.WebhookId
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetEvent() string {
+	if m != nil {
+		return m.Event
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetPayload() string {
+	if m != nil {
+		return m.Payload
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *WebhookDelivery) GetNextAttemptAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.NextAttemptAt
+	}
+	return nil
+}
+
+func (m *WebhookDelivery) GetResponseCode() int32 {
+	if m != nil {
+		return m.ResponseCode
+	}
+	return 0
+}
+
+func (m *WebhookDelivery) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries           []*WebhookDelivery `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	NextPageToken        string             `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	PageSize             int32              `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TotalSize            int32              `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ListWebhookDeliveriesResponse) Reset()         { *m = ListWebhookDeliveriesResponse{} }
+func (m *ListWebhookDeliveriesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWebhookDeliveriesResponse) ProtoMessage()    {}
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{23}
+}
+
+func (m *ListWebhookDeliveriesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Unmarshal(m, b)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhookDeliveriesResponse.Merge(m, src)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Size(m)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhookDeliveriesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhookDeliveriesResponse proto.InternalMessageInfo
+
+func (m *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if m != nil {
+		return m.Deliveries
+	}
+	return nil
+}
+
+func (m *ListWebhookDeliveriesResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *ListWebhookDeliveriesResponse) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListWebhookDeliveriesResponse) GetTotalSize() int32 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+type AuditEvent struct {
+	Id        string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// id of the user who executed the action, empty for anonymous actions.
+	ActorId string `protobuf:"bytes,3,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	// name of the executed function, for example UpdateUser.
+	Action string `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	// one of user, group, webhook, apiKey or attributeDefinition.
+	ResourceType string         `protobuf:"bytes,5,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	ResourceId   string         `protobuf:"bytes,6,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	Changes      []*AuditChange `protobuf:"bytes,7,rep,name=changes,proto3" json:"changes,omitempty"`
+	PeerAddress  string         `protobuf:"bytes,8,opt,name=peer_address,json=peerAddress,proto3" json:"peer_address,omitempty"`
+	// id of the admin who impersonated the actor, empty if the actor was not impersonated.
+	ImpersonatorId       string   `protobuf:"bytes,9,opt,name=impersonator_id,json=impersonatorId,proto3" json:"impersonator_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEvent) Reset()         { *m = AuditEvent{} }
+func (m *AuditEvent) String() string { return proto.CompactTextString(m) }
+func (*AuditEvent) ProtoMessage()    {}
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{24}
+}
+
+func (m *AuditEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEvent.Unmarshal(m, b)
+}
+func (m *AuditEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEvent.Marshal(b, m, deterministic)
+}
+func (m *AuditEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEvent.Merge(m, src)
+}
+func (m *AuditEvent) XXX_Size() int {
+	return xxx_messageInfo_AuditEvent.Size(m)
+}
+func (m *AuditEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEvent proto.InternalMessageInfo
+
+func (m *AuditEvent) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *AuditEvent) GetActorId() string {
+	if m != nil {
+		return m.ActorId
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetResourceType() string {
+	if m != nil {
+		return m.ResourceType
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetResourceId() string {
+	if m != nil {
+		return m.ResourceId
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetChanges() []*AuditChange {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+func (m *AuditEvent) GetPeerAddress() string {
+	if m != nil {
+		return m.PeerAddress
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetImpersonatorId() string {
+	if m != nil {
+		return m.ImpersonatorId
+	}
+	return ""
+}
+
+// changed field, the values of secrets are redacted.
+type AuditChange struct {
+	Field                string   `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	OldValue             string   `protobuf:"bytes,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue             string   `protobuf:"bytes,3,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditChange) Reset()         { *m = AuditChange{} }
+func (m *AuditChange) String() string { return proto.CompactTextString(m) }
+func (*AuditChange) ProtoMessage()    {}
+func (*AuditChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{25}
+}
+
+func (m *AuditChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditChange.Unmarshal(m, b)
+}
+func (m *AuditChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditChange.Marshal(b, m, deterministic)
+}
+func (m *AuditChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditChange.Merge(m, src)
+}
+func (m *AuditChange) XXX_Size() int {
+	return xxx_messageInfo_AuditChange.Size(m)
+}
+func (m *AuditChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditChange proto.InternalMessageInfo
+
+func (m *AuditChange) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *AuditChange) GetOldValue() string {
+	if m != nil {
+		return m.OldValue
+	}
+	return ""
+}
+
+func (m *AuditChange) GetNewValue() string {
+	if m != nil {
+		return m.NewValue
+	}
+	return ""
+}
+
+type ListAuditEventsResponse struct {
+	AuditEvents          []*AuditEvent `protobuf:"bytes,1,rep,name=audit_events,json=auditEvents,proto3" json:"audit_events,omitempty"`
+	NextPageToken        string        `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	PageSize             int32         `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TotalSize            int32         `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListAuditEventsResponse) Reset()         { *m = ListAuditEventsResponse{} }
+func (m *ListAuditEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAuditEventsResponse) ProtoMessage()    {}
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{26}
+}
+
+func (m *ListAuditEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAuditEventsResponse.Unmarshal(m, b)
+}
+func (m *ListAuditEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAuditEventsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListAuditEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAuditEventsResponse.Merge(m, src)
+}
+func (m *ListAuditEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAuditEventsResponse.Size(m)
+}
+func (m *ListAuditEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAuditEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAuditEventsResponse proto.InternalMessageInfo
+
+func (m *ListAuditEventsResponse) GetAuditEvents() []*AuditEvent {
+	if m != nil {
+		return m.AuditEvents
+	}
+	return nil
+}
+
+func (m *ListAuditEventsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *ListAuditEventsResponse) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListAuditEventsResponse) GetTotalSize() int32 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+type ApiKey struct {
+	Id               string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt        *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamp.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ServiceAccountId string               `protobuf:"bytes,4,opt,name=service_account_id,json=serviceAccountId,proto3" json:"service_account_id,omitempty"`
+	Name             string               `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	// public part of the key, used to identify it.
+	Prefix string `protobuf:"bytes,6,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// names of the rpc methods the key may be used for, * allows all methods.
+	Scopes     []string             `protobuf:"bytes,7,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	ExpiresAt  *timestamp.Timestamp `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	LastUsedAt *timestamp.Timestamp `protobuf:"bytes,9,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	// the key, only returned when creating the api key.
+	Key                  string   `protobuf:"bytes,10,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApiKey) Reset()         { *m = ApiKey{} }
+func (m *ApiKey) String() string { return proto.CompactTextString(m) }
+func (*ApiKey) ProtoMessage()    {}
+func (*ApiKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{27}
+}
+
+func (m *ApiKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApiKey.Unmarshal(m, b)
+}
+func (m *ApiKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApiKey.Marshal(b, m, deterministic)
+}
+func (m *ApiKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApiKey.Merge(m, src)
+}
+func (m *ApiKey) XXX_Size() int {
+	return xxx_messageInfo_ApiKey.Size(m)
+}
+func (m *ApiKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApiKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApiKey proto.InternalMessageInfo
+
+func (m *ApiKey) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ApiKey) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *ApiKey) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *ApiKey) GetServiceAccountId() string {
+	if m != nil {
+		return m.ServiceAccountId
+	}
+	return ""
+}
+
+func (m *ApiKey) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ApiKey) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ApiKey) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *ApiKey) GetExpiresAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
+func (m *ApiKey) GetLastUsedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastUsedAt
+	}
+	return nil
+}
+
+func (m *ApiKey) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type ListApiKeysResponse struct {
+	ApiKeys              []*ApiKey `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	NextPageToken        string    `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	PageSize             int32     `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TotalSize            int32     `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListApiKeysResponse) Reset()         { *m = ListApiKeysResponse{} }
+func (m *ListApiKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*ListApiKeysResponse) ProtoMessage()    {}
+func (*ListApiKeysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{28}
+}
+
+func (m *ListApiKeysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListApiKeysResponse.Unmarshal(m, b)
+}
+func (m *ListApiKeysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListApiKeysResponse.Marshal(b, m, deterministic)
+}
+func (m *ListApiKeysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListApiKeysResponse.Merge(m, src)
+}
+func (m *ListApiKeysResponse) XXX_Size() int {
+	return xxx_messageInfo_ListApiKeysResponse.Size(m)
+}
+func (m *ListApiKeysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListApiKeysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListApiKeysResponse proto.InternalMessageInfo
+
+func (m *ListApiKeysResponse) GetApiKeys() []*ApiKey {
+	if m != nil {
+		return m.ApiKeys
+	}
+	return nil
+}
+
+func (m *ListApiKeysResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *ListApiKeysResponse) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListApiKeysResponse) GetTotalSize() int32 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+// defines a custom attribute of users.
+type AttributeDefinition struct {
+	Id        string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// key of the attribute in the attributes of the users, cannot be changed.
+	Name string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	// one of string, number or boolean, cannot be changed.
+	Type     string `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	Required bool   `protobuf:"varint,6,opt,name=required,proto3" json:"required,omitempty"`
+	// true if no two users may have the same value.
+	Unique bool `protobuf:"varint,7,opt,name=unique,proto3" json:"unique,omitempty"`
+	// regular expression string values have to match.
+	Regex string `protobuf:"bytes,8,opt,name=regex,proto3" json:"regex,omitempty"`
+	// one of admin or self, self allows users to edit the attribute of their own user.
+	EditableBy           string   `protobuf:"bytes,9,opt,name=editable_by,json=editableBy,proto3" json:"editable_by,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AttributeDefinition) Reset()         { *m = AttributeDefinition{} }
+func (m *AttributeDefinition) String() string { return proto.CompactTextString(m) }
+func (*AttributeDefinition) ProtoMessage()    {}
+func (*AttributeDefinition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{29}
+}
+
+func (m *AttributeDefinition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AttributeDefinition.Unmarshal(m, b)
+}
+func (m *AttributeDefinition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AttributeDefinition.Marshal(b, m, deterministic)
+}
+func (m *AttributeDefinition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttributeDefinition.Merge(m, src)
+}
+func (m *AttributeDefinition) XXX_Size() int {
+	return xxx_messageInfo_AttributeDefinition.Size(m)
+}
+func (m *AttributeDefinition) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttributeDefinition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttributeDefinition proto.InternalMessageInfo
+
+func (m *AttributeDefinition) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AttributeDefinition) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *AttributeDefinition) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *AttributeDefinition) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AttributeDefinition) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *AttributeDefinition) GetRequired() bool {
+	if m != nil {
+		return m.Required
+	}
+	return false
+}
+
+func (m *AttributeDefinition) GetUnique() bool {
+	if m != nil {
+		return m.Unique
+	}
+	return false
+}
+
+func (m *AttributeDefinition) GetRegex() string {
+	if m != nil {
+		return m.Regex
+	}
+	return ""
+}
+
+func (m *AttributeDefinition) GetEditableBy() string {
+	if m != nil {
+		return m.EditableBy
+	}
+	return ""
+}
+
+// value of a custom attribute, the type depends on the attribute definition.
+type AttributeValue struct {
+	// Types that are valid to be assigned to Value:
+	//	*AttributeValue_StringValue
+	//	*AttributeValue_NumberValue
+	//	*AttributeValue_BoolValue
+	Value                isAttributeValue_Value `protobuf_oneof:"value"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *AttributeValue) Reset()         { *m = AttributeValue{} }
+func (m *AttributeValue) String() string { return proto.CompactTextString(m) }
+func (*AttributeValue) ProtoMessage()    {}
+func (*AttributeValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{30}
+}
+
+func (m *AttributeValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AttributeValue.Unmarshal(m, b)
+}
+func (m *AttributeValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AttributeValue.Marshal(b, m, deterministic)
+}
+func (m *AttributeValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttributeValue.Merge(m, src)
+}
+func (m *AttributeValue) XXX_Size() int {
+	return xxx_messageInfo_AttributeValue.Size(m)
+}
+func (m *AttributeValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttributeValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttributeValue proto.InternalMessageInfo
+
+type isAttributeValue_Value interface {
+	isAttributeValue_Value()
+}
+
+type AttributeValue_StringValue struct {
+	StringValue string `protobuf:"bytes,1,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type AttributeValue_NumberValue struct {
+	NumberValue float64 `protobuf:"fixed64,2,opt,name=number_value,json=numberValue,proto3,oneof"`
+}
+
+type AttributeValue_BoolValue struct {
+	BoolValue bool `protobuf:"varint,3,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+func (*AttributeValue_StringValue) isAttributeValue_Value() {}
+
+func (*AttributeValue_NumberValue) isAttributeValue_Value() {}
+
+func (*AttributeValue_BoolValue) isAttributeValue_Value() {}
+
+func (m *AttributeValue) GetValue() isAttributeValue_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *AttributeValue) GetStringValue() string {
+	if x, ok := m.GetValue().(*AttributeValue_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (m *AttributeValue) GetNumberValue() float64 {
+	if x, ok := m.GetValue().(*AttributeValue_NumberValue); ok {
+		return x.NumberValue
+	}
+	return 0
+}
+
+func (m *AttributeValue) GetBoolValue() bool {
+	if x, ok := m.GetValue().(*AttributeValue_BoolValue); ok {
+		return x.BoolValue
+	}
+	return false
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*AttributeValue) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*AttributeValue_StringValue)(nil),
+		(*AttributeValue_NumberValue)(nil),
+		(*AttributeValue_BoolValue)(nil),
+	}
+}
+
+type UpdateAttributeDefinitionRequest struct {
+	AttributeDefinition  *AttributeDefinition  `protobuf:"bytes,1,opt,name=attribute_definition,json=attributeDefinition,proto3" json:"attribute_definition,omitempty"`
+	FieldMask            *field_mask.FieldMask `protobuf:"bytes,2,opt,name=field_mask,json=fieldMask,proto3" json:"field_mask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *UpdateAttributeDefinitionRequest) Reset()         { *m = UpdateAttributeDefinitionRequest{} }
+func (m *UpdateAttributeDefinitionRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateAttributeDefinitionRequest) ProtoMessage()    {}
+func (*UpdateAttributeDefinitionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{31}
+}
+
+func (m *UpdateAttributeDefinitionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateAttributeDefinitionRequest.Unmarshal(m, b)
+}
+func (m *UpdateAttributeDefinitionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateAttributeDefinitionRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateAttributeDefinitionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateAttributeDefinitionRequest.Merge(m, src)
+}
+func (m *UpdateAttributeDefinitionRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateAttributeDefinitionRequest.Size(m)
+}
+func (m *UpdateAttributeDefinitionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateAttributeDefinitionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateAttributeDefinitionRequest proto.InternalMessageInfo
+
+func (m *UpdateAttributeDefinitionRequest) GetAttributeDefinition() *AttributeDefinition {
+	if m != nil {
+		return m.AttributeDefinition
+	}
+	return nil
+}
+
+func (m *UpdateAttributeDefinitionRequest) GetFieldMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.FieldMask
+	}
+	return nil
+}
+
+type ListAttributeDefinitionsResponse struct {
+	AttributeDefinitions []*AttributeDefinition `protobuf:"bytes,1,rep,name=attribute_definitions,json=attributeDefinitions,proto3" json:"attribute_definitions,omitempty"`
+	NextPageToken        string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	PageSize             int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TotalSize            int32                  `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *ListAttributeDefinitionsResponse) Reset()         { *m = ListAttributeDefinitionsResponse{} }
+func (m *ListAttributeDefinitionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAttributeDefinitionsResponse) ProtoMessage()    {}
+func (*ListAttributeDefinitionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{32}
+}
+
+func (m *ListAttributeDefinitionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAttributeDefinitionsResponse.Unmarshal(m, b)
+}
+func (m *ListAttributeDefinitionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAttributeDefinitionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListAttributeDefinitionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAttributeDefinitionsResponse.Merge(m, src)
+}
+func (m *ListAttributeDefinitionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAttributeDefinitionsResponse.Size(m)
+}
+func (m *ListAttributeDefinitionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAttributeDefinitionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAttributeDefinitionsResponse proto.InternalMessageInfo
+
+func (m *ListAttributeDefinitionsResponse) GetAttributeDefinitions() []*AttributeDefinition {
+	if m != nil {
+		return m.AttributeDefinitions
+	}
+	return nil
+}
+
+func (m *ListAttributeDefinitionsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *ListAttributeDefinitionsResponse) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListAttributeDefinitionsResponse) GetTotalSize() int32 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+type PreviewMailRequest struct {
+	// name of the template: confirm, password_reset, account_deletion, invitation,
+	// password_changed, mail_changed or group_granted.
+	Template string `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	// id of the user the template is rendered for.
+	UserId               string   `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PreviewMailRequest) Reset()         { *m = PreviewMailRequest{} }
+func (m *PreviewMailRequest) String() string { return proto.CompactTextString(m) }
+func (*PreviewMailRequest) ProtoMessage()    {}
+func (*PreviewMailRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{33}
+}
+
+func (m *PreviewMailRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreviewMailRequest.Unmarshal(m, b)
+}
+func (m *PreviewMailRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreviewMailRequest.Marshal(b, m, deterministic)
+}
+func (m *PreviewMailRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreviewMailRequest.Merge(m, src)
+}
+func (m *PreviewMailRequest) XXX_Size() int {
+	return xxx_messageInfo_PreviewMailRequest.Size(m)
+}
+func (m *PreviewMailRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreviewMailRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PreviewMailRequest proto.InternalMessageInfo
+
+func (m *PreviewMailRequest) GetTemplate() string {
+	if m != nil {
+		return m.Template
+	}
+	return ""
+}
+
+func (m *PreviewMailRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+type PreviewMailResponse struct {
+	Subject              string   `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Text                 string   `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Html                 string   `protobuf:"bytes,3,opt,name=html,proto3" json:"html,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PreviewMailResponse) Reset()         { *m = PreviewMailResponse{} }
+func (m *PreviewMailResponse) String() string { return proto.CompactTextString(m) }
+func (*PreviewMailResponse) ProtoMessage()    {}
+func (*PreviewMailResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{34}
+}
+
+func (m *PreviewMailResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreviewMailResponse.Unmarshal(m, b)
+}
+func (m *PreviewMailResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreviewMailResponse.Marshal(b, m, deterministic)
+}
+func (m *PreviewMailResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreviewMailResponse.Merge(m, src)
+}
+func (m *PreviewMailResponse) XXX_Size() int {
+	return xxx_messageInfo_PreviewMailResponse.Size(m)
+}
+func (m *PreviewMailResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreviewMailResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PreviewMailResponse proto.InternalMessageInfo
+
+func (m *PreviewMailResponse) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *PreviewMailResponse) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+func (m *PreviewMailResponse) GetHtml() string {
+	if m != nil {
+		return m.Html
+	}
+	return ""
+}
+
+// mail of the outbound mail queue, the bodies are not exposed.
+type Mail struct {
+	Id        string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	From      string               `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	To        []string             `protobuf:"bytes,5,rep,name=to,proto3" json:"to,omitempty"`
+	Cc        []string             `protobuf:"bytes,6,rep,name=cc,proto3" json:"cc,omitempty"`
+	Bcc       []string             `protobuf:"bytes,7,rep,name=bcc,proto3" json:"bcc,omitempty"`
+	Subject   string               `protobuf:"bytes,8,opt,name=subject,proto3" json:"subject,omitempty"`
+	// one of pending, sent or failed.
+	State                string               `protobuf:"bytes,9,opt,name=state,proto3" json:"state,omitempty"`
+	Attempts             int32                `protobuf:"varint,10,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	NextAttemptAt        *timestamp.Timestamp `protobuf:"bytes,11,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	LastError            string               `protobuf:"bytes,12,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Mail) Reset()         { *m = Mail{} }
+func (m *Mail) String() string { return proto.CompactTextString(m) }
+func (*Mail) ProtoMessage()    {}
+func (*Mail) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{35}
+}
+
+func (m *Mail) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Mail.Unmarshal(m, b)
+}
+func (m *Mail) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Mail.Marshal(b, m, deterministic)
+}
+func (m *Mail) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Mail.Merge(m, src)
+}
+func (m *Mail) XXX_Size() int {
+	return xxx_messageInfo_Mail.Size(m)
+}
+func (m *Mail) XXX_DiscardUnknown() {
+	xxx_messageInfo_Mail.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Mail proto.InternalMessageInfo
+
+func (m *Mail) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Mail) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *Mail) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *Mail) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *Mail) GetTo() []string {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *Mail) GetCc() []string {
+	if m != nil {
+		return m.Cc
+	}
+	return nil
+}
+
+func (m *Mail) GetBcc() []string {
+	if m != nil {
+		return m.Bcc
+	}
+	return nil
+}
+
+func (m *Mail) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *Mail) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Mail) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *Mail) GetNextAttemptAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.NextAttemptAt
+	}
+	return nil
+}
+
+func (m *Mail) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+type ListMailsResponse struct {
+	Mails                []*Mail  `protobuf:"bytes,1,rep,name=mails,proto3" json:"mails,omitempty"`
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	PageSize             int32    `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TotalSize            int32    `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListMailsResponse) Reset()         { *m = ListMailsResponse{} }
+func (m *ListMailsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListMailsResponse) ProtoMessage()    {}
+func (*ListMailsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fbca08c6b16090c, []int{36}
+}
+
+func (m *ListMailsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMailsResponse.Unmarshal(m, b)
+}
+func (m *ListMailsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMailsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListMailsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMailsResponse.Merge(m, src)
+}
+func (m *ListMailsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListMailsResponse.Size(m)
+}
+func (m *ListMailsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMailsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMailsResponse proto.InternalMessageInfo
+
+func (m *ListMailsResponse) GetMails() []*Mail {
+	if m != nil {
+		return m.Mails
+	}
+	return nil
+}
+
+func (m *ListMailsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *ListMailsResponse) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListMailsResponse) GetTotalSize() int32 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*IdRequest)(nil), "gooser.v1.IdRequest")
+	proto.RegisterType((*ListRequest)(nil), "gooser.v1.ListRequest")
+	proto.RegisterType((*User)(nil), "gooser.v1.User")
+	proto.RegisterMapType((map[string]*AttributeValue)(nil), "gooser.v1.User.AttributesEntry")
+	proto.RegisterType((*UpdateUserRequest)(nil), "gooser.v1.UpdateUserRequest")
+	proto.RegisterType((*ListUsersResponse)(nil), "gooser.v1.ListUsersResponse")
+	proto.RegisterType((*ChangePasswordRequest)(nil), "gooser.v1.ChangePasswordRequest")
+	proto.RegisterType((*ConfirmMailRequest)(nil), "gooser.v1.ConfirmMailRequest")
+	proto.RegisterType((*ForgotPasswordRequest)(nil), "gooser.v1.ForgotPasswordRequest")
+	proto.RegisterType((*ResetPasswordRequest)(nil), "gooser.v1.ResetPasswordRequest")
+	proto.RegisterType((*RevertMailChangeRequest)(nil), "gooser.v1.RevertMailChangeRequest")
+	proto.RegisterType((*SuspendUserRequest)(nil), "gooser.v1.SuspendUserRequest")
+	proto.RegisterType((*PersonalData)(nil), "gooser.v1.PersonalData")
+	proto.RegisterType((*ExportMyDataResponse)(nil), "gooser.v1.ExportMyDataResponse")
+	proto.RegisterType((*DeleteMyAccountRequest)(nil), "gooser.v1.DeleteMyAccountRequest")
+	proto.RegisterType((*InviteUserRequest)(nil), "gooser.v1.InviteUserRequest")
+	proto.RegisterMapType((map[string]*AttributeValue)(nil), "gooser.v1.InviteUserRequest.AttributesEntry")
+	proto.RegisterType((*AcceptInvitationRequest)(nil), "gooser.v1.AcceptInvitationRequest")
+	proto.RegisterMapType((map[string]*AttributeValue)(nil), "gooser.v1.AcceptInvitationRequest.AttributesEntry")
+	proto.RegisterType((*Group)(nil), "gooser.v1.Group")
+	proto.RegisterType((*UpdateGroupRequest)(nil), "gooser.v1.UpdateGroupRequest")
+	proto.RegisterType((*ListGroupsResponse)(nil), "gooser.v1.ListGroupsResponse")
+	proto.RegisterType((*Webhook)(nil), "gooser.v1.Webhook")
+	proto.RegisterType((*UpdateWebhookRequest)(nil), "gooser.v1.UpdateWebhookRequest")
+	proto.RegisterType((*ListWebhooksResponse)(nil), "gooser.v1.ListWebhooksResponse")
+	proto.RegisterType((*WebhookDelivery)(nil), "gooser.v1.WebhookDelivery")
+	proto.RegisterType((*ListWebhookDeliveriesResponse)(nil), "gooser.v1.ListWebhookDeliveriesResponse")
+	proto.RegisterType((*AuditEvent)(nil), "gooser.v1.AuditEvent")
+	proto.RegisterType((*AuditChange)(nil), "gooser.v1.AuditChange")
+	proto.RegisterType((*ListAuditEventsResponse)(nil), "gooser.v1.ListAuditEventsResponse")
+	proto.RegisterType((*ApiKey)(nil), "gooser.v1.ApiKey")
+	proto.RegisterType((*ListApiKeysResponse)(nil), "gooser.v1.ListApiKeysResponse")
+	proto.RegisterType((*AttributeDefinition)(nil), "gooser.v1.AttributeDefinition")
+	proto.RegisterType((*AttributeValue)(nil), "gooser.v1.AttributeValue")
+	proto.RegisterType((*UpdateAttributeDefinitionRequest)(nil), "gooser.v1.UpdateAttributeDefinitionRequest")
+	proto.RegisterType((*ListAttributeDefinitionsResponse)(nil), "gooser.v1.ListAttributeDefinitionsResponse")
+	proto.RegisterType((*PreviewMailRequest)(nil), "gooser.v1.PreviewMailRequest")
+	proto.RegisterType((*PreviewMailResponse)(nil), "gooser.v1.PreviewMailResponse")
+	proto.RegisterType((*Mail)(nil), "gooser.v1.Mail")
+	proto.RegisterType((*ListMailsResponse)(nil), "gooser.v1.ListMailsResponse")
+}
+
+func init() {
+	proto.RegisterFile("api/proto/v1/gooser_service.proto", fileDescriptor_5fbca08c6b16090c)
+}
+
+var fileDescriptor_5fbca08c6b16090c = []byte{
+	// 2714 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x5a, 0xcd, 0x6f, 0x1b, 0xc7,
+	0x15, 0xe7, 0x92, 0xa2, 0x48, 0x3e, 0x52, 0x5f, 0xa3, 0x0f, 0xaf, 0xe9, 0x38, 0x56, 0x36, 0x68,
+	0x2a, 0x24, 0xae, 0x64, 0x2b, 0x41, 0xe1, 0x38, 0x71, 0x5d, 0x4a, 0x96, 0x65, 0xd6, 0x1f, 0x55,
+	0xd7, 0x76, 0x53, 0xb4, 0x08, 0x88, 0x15, 0xf7, 0x89, 0xde, 0x68, 0xb9, 0x4b, 0xef, 0x0e, 0x25,
+	0x31, 0x97, 0xa2, 0x40, 0x81, 0x16, 0xc8, 0xb9, 0xd7, 0xa2, 0xed, 0xa5, 0x40, 0x0f, 0x45, 0x6f,
+	0x3d, 0xf6, 0x3f, 0xe8, 0xa1, 0xd7, 0x9e, 0x7b, 0x68, 0xff, 0x8a, 0x62, 0x3e, 0xf6, 0x93, 0xbb,
+	0xa4, 0x8c, 0xa4, 0xaa, 0x6e, 0x9c, 0x37, 0x6f, 0xde, 0xbc, 0x79, 0xf3, 0x9b, 0xdf, 0xbc, 0x79,
+	0x4b, 0x78, 0xc7, 0x18, 0x58, 0x5b, 0x03, 0xcf, 0xa5, 0xee, 0xd6, 0xc9, 0xed, 0xad, 0x9e, 0xeb,
+	0xfa, 0xe8, 0x75, 0x7c, 0xf4, 0x4e, 0xac, 0x2e, 0x6e, 0x72, 0x39, 0xa9, 0x09, 0xe9, 0xe6, 0xc9,
+	0xed, 0xe6, 0xb5, 0x9e, 0xeb, 0xf6, 0x6c, 0x14, 0x03, 0x0e, 0x87, 0x47, 0x5b, 0xd8, 0x1f, 0xd0,
+	0x91, 0xd0, 0x6b, 0xae, 0xa7, 0x3b, 0x8f, 0x2c, 0xb4, 0xcd, 0x4e, 0xdf, 0xf0, 0x8f, 0xa5, 0xc6,
+	0x8d, 0xb4, 0x06, 0xb5, 0xfa, 0xe8, 0x53, 0xa3, 0x3f, 0x10, 0x0a, 0xda, 0x35, 0xa8, 0xb5, 0x4d,
+	0x1d, 0x5f, 0x0f, 0xd1, 0xa7, 0x64, 0x1e, 0x8a, 0x96, 0xa9, 0x2a, 0xeb, 0xca, 0x46, 0x4d, 0x2f,
+	0x5a, 0xa6, 0x66, 0x40, 0xfd, 0x89, 0xe5, 0xd3, 0xa0, 0xfb, 0x1a, 0xd4, 0x06, 0x46, 0x0f, 0x3b,
+	0xbe, 0xf5, 0x25, 0x72, 0xad, 0xb2, 0x5e, 0x65, 0x82, 0xe7, 0xd6, 0x97, 0x48, 0xae, 0x03, 0xf0,
+	0x4e, 0xea, 0x1e, 0xa3, 0xa3, 0x16, 0xb9, 0x0d, 0xae, 0xfe, 0x82, 0x09, 0xc8, 0x1a, 0xcc, 0x1e,
+	0x59, 0x36, 0x45, 0x4f, 0x2d, 0xf1, 0x2e, 0xd9, 0xd2, 0x7e, 0x5f, 0x81, 0x99, 0x97, 0x3e, 0x7a,
+	0xe9, 0xb9, 0xc9, 0xc7, 0x00, 0x5d, 0x0f, 0x0d, 0x8a, 0x66, 0xc7, 0xa0, 0xdc, 0x5e, 0x7d, 0xbb,
+	0xb9, 0x29, 0x96, 0xb3, 0x19, 0x2c, 0x67, 0xf3, 0x45, 0xb0, 0x1c, 0xbd, 0x26, 0xb5, 0x5b, 0x94,
+	0x0d, 0x1d, 0x0e, 0xcc, 0x60, 0x68, 0x69, 0xfa, 0x50, 0xa9, 0xdd, 0xa2, 0xa4, 0x09, 0xd5, 0xa1,
+	0x8f, 0x9e, 0x63, 0xf4, 0x51, 0x9d, 0xe1, 0xbe, 0x84, 0x6d, 0x42, 0x60, 0xa6, 0x6f, 0x58, 0xb6,
+	0x5a, 0xe6, 0x72, 0xfe, 0x9b, 0xe9, 0xdb, 0x86, 0xd3, 0x1b, 0x1a, 0x3d, 0x54, 0x67, 0x85, 0x7e,
+	0xd0, 0x66, 0x7d, 0x03, 0xc3, 0xf7, 0x4f, 0x5d, 0xcf, 0x54, 0x2b, 0xa2, 0x2f, 0x68, 0x93, 0xb7,
+	0xa0, 0xd6, 0x75, 0x9d, 0x23, 0xcb, 0xeb, 0xa3, 0xa9, 0x56, 0xd7, 0x95, 0x8d, 0xaa, 0x1e, 0x09,
+	0xc8, 0x0a, 0x94, 0x3d, 0xd7, 0x46, 0x5f, 0xad, 0xad, 0x97, 0x36, 0x6a, 0xba, 0x68, 0x90, 0x6f,
+	0xc3, 0x82, 0x84, 0x49, 0xc7, 0xe8, 0x76, 0xdd, 0xa1, 0x43, 0x55, 0xe0, 0x23, 0xe7, 0xa5, 0xb8,
+	0x25, 0xa4, 0xcc, 0xb8, 0x3f, 0xf4, 0x07, 0xe8, 0x98, 0x68, 0xaa, 0x75, 0x61, 0x3c, 0x14, 0x90,
+	0x0f, 0x60, 0x49, 0x34, 0x7c, 0xcb, 0x75, 0x3a, 0x1e, 0x1a, 0xbe, 0xeb, 0xa8, 0x0d, 0xee, 0xdf,
+	0x62, 0xd4, 0xa1, 0x73, 0x39, 0xd9, 0x85, 0x85, 0x70, 0x64, 0x67, 0xe8, 0x50, 0xcb, 0x56, 0xe7,
+	0xa6, 0xc6, 0x73, 0x3e, 0x1c, 0xf2, 0x92, 0x8d, 0x20, 0xcf, 0x60, 0xd5, 0x44, 0x1b, 0x29, 0x9b,
+	0xcf, 0xef, 0xbe, 0x42, 0x73, 0x68, 0x8b, 0xad, 0x99, 0x9f, 0x6a, 0x6a, 0x39, 0x18, 0xf8, 0x3c,
+	0x18, 0xd7, 0xa2, 0xe4, 0x3b, 0x40, 0x2c, 0xe7, 0xc4, 0xa2, 0x06, 0xb7, 0xc8, 0x66, 0xb2, 0x9c,
+	0x9e, 0xba, 0xc0, 0x17, 0xba, 0x14, 0xf5, 0x1c, 0x88, 0x0e, 0x36, 0x7d, 0x4c, 0x1d, 0xcf, 0x06,
+	0x96, 0x87, 0x3e, 0x9b, 0x7e, 0x71, 0xfa, 0xf4, 0xd1, 0xc0, 0x3d, 0x31, 0xae, 0x45, 0x19, 0xd2,
+	0xb9, 0x18, 0xcd, 0xce, 0xe1, 0x48, 0x5d, 0x12, 0x48, 0x97, 0x92, 0x9d, 0x11, 0xb9, 0x0f, 0x60,
+	0x50, 0xea, 0x59, 0x87, 0x43, 0x8a, 0xbe, 0x4a, 0xd6, 0x4b, 0x1b, 0xf5, 0xed, 0x1b, 0x9b, 0xe1,
+	0x89, 0xde, 0x64, 0x68, 0xdf, 0x6c, 0x85, 0x1a, 0x7b, 0x0e, 0xf5, 0x46, 0x7a, 0x6c, 0x08, 0xc3,
+	0x19, 0x52, 0xa3, 0xa7, 0x2e, 0x0b, 0x9c, 0xb1, 0xdf, 0xe4, 0x1d, 0x68, 0xc8, 0x75, 0x76, 0x38,
+	0x06, 0x57, 0x78, 0x5f, 0x5d, 0xca, 0x9e, 0x1a, 0x96, 0xdd, 0xfc, 0x09, 0x2c, 0xa4, 0xac, 0x92,
+	0x45, 0x28, 0x1d, 0xe3, 0x48, 0x1e, 0x2a, 0xf6, 0x93, 0x6c, 0x41, 0xf9, 0xc4, 0xb0, 0x87, 0x28,
+	0x0f, 0xd4, 0xd5, 0x98, 0x5f, 0xe1, 0xe0, 0x1f, 0x33, 0x05, 0x5d, 0xe8, 0xdd, 0x2d, 0xde, 0x51,
+	0xb4, 0x5f, 0x29, 0xb0, 0xf4, 0x92, 0x1f, 0x11, 0xe6, 0x7b, 0xc0, 0x06, 0xef, 0xc2, 0x0c, 0x3b,
+	0x1a, 0xdc, 0x7a, 0x7d, 0x7b, 0x21, 0xb5, 0x42, 0x9d, 0x77, 0xb2, 0xa3, 0x18, 0x71, 0x52, 0xee,
+	0x29, 0x7e, 0xc8, 0x54, 0x9e, 0x1a, 0xfe, 0xb1, 0x5e, 0x3b, 0x0a, 0x7e, 0x86, 0x61, 0x28, 0x45,
+	0x61, 0xd0, 0x7e, 0xab, 0xc0, 0x12, 0x63, 0x24, 0x36, 0x83, 0xaf, 0xa3, 0x3f, 0x70, 0x1d, 0x1f,
+	0xc9, 0xb7, 0xa0, 0xcc, 0x26, 0xf3, 0x55, 0x65, 0xbd, 0x94, 0xe5, 0x8a, 0xe8, 0x25, 0xef, 0xc1,
+	0x82, 0x83, 0x67, 0xb4, 0x33, 0x46, 0x53, 0x73, 0x4c, 0x7c, 0x10, 0x52, 0x55, 0x82, 0xe6, 0x4a,
+	0xe3, 0x34, 0x47, 0x5d, 0x6a, 0xd8, 0xa2, 0x77, 0x86, 0xf7, 0xd6, 0xb8, 0x84, 0x75, 0x6b, 0x7d,
+	0x58, 0xdd, 0x7d, 0x65, 0x38, 0x3d, 0x3c, 0x90, 0x27, 0x3d, 0x87, 0x5a, 0xd9, 0x86, 0xba, 0xb6,
+	0xd9, 0x09, 0x09, 0x42, 0x78, 0x52, 0x77, 0x6d, 0x33, 0x18, 0xc9, 0x54, 0x1c, 0x3c, 0x8d, 0x54,
+	0x44, 0x20, 0xea, 0x0e, 0x9e, 0x06, 0x2a, 0xda, 0xfb, 0x40, 0x76, 0x05, 0x6b, 0x30, 0x08, 0x04,
+	0x73, 0xad, 0x40, 0x59, 0x2c, 0x4f, 0x4c, 0x27, 0x1a, 0xda, 0x3e, 0xac, 0x3e, 0x74, 0xbd, 0x9e,
+	0x4b, 0xd3, 0xae, 0xc5, 0x39, 0x4f, 0xc9, 0xe1, 0xbc, 0x62, 0xc4, 0x79, 0xda, 0x23, 0x58, 0xd1,
+	0xd1, 0xc7, 0x31, 0x3b, 0x99, 0xd3, 0x26, 0x58, 0xb0, 0x98, 0x64, 0x41, 0x6d, 0x0b, 0xae, 0xe8,
+	0x78, 0x82, 0x1e, 0x65, 0xde, 0x8b, 0xb8, 0x4d, 0x5e, 0x83, 0x03, 0xe4, 0xb9, 0xe0, 0x96, 0x38,
+	0x12, 0xd3, 0xb1, 0x5d, 0x83, 0x59, 0x49, 0x6b, 0x62, 0x42, 0xd9, 0x22, 0xb7, 0xa0, 0x2c, 0x28,
+	0x6c, 0xfa, 0x95, 0x20, 0x14, 0xb5, 0xff, 0x28, 0xd0, 0x38, 0x40, 0xcf, 0x77, 0x1d, 0xc3, 0x7e,
+	0x60, 0x50, 0xe3, 0x7c, 0xa0, 0xdf, 0x80, 0xd9, 0x9e, 0xe7, 0x0e, 0x07, 0xbe, 0x5a, 0xe4, 0x80,
+	0x5c, 0x8c, 0xa9, 0xed, 0xb3, 0x0e, 0x5d, 0xf6, 0x47, 0x44, 0x5f, 0x8a, 0x13, 0xfd, 0x1d, 0x68,
+	0x18, 0x43, 0xd3, 0xa2, 0x1d, 0x3c, 0x41, 0x87, 0xfa, 0xea, 0x0c, 0xb7, 0xb2, 0x1a, 0x3f, 0xab,
+	0xac, 0x7b, 0x8f, 0xf5, 0xea, 0x75, 0x23, 0xfc, 0xed, 0x93, 0x4f, 0xa0, 0x8e, 0x67, 0x03, 0xd7,
+	0x93, 0x57, 0x5f, 0x79, 0xea, 0x3a, 0x21, 0x50, 0x6f, 0x51, 0xed, 0x7d, 0x58, 0xd9, 0xe3, 0xad,
+	0xa7, 0x23, 0xb6, 0xd6, 0xf0, 0x78, 0x11, 0x98, 0x31, 0x0d, 0x6a, 0xc8, 0x00, 0xf3, 0xdf, 0xda,
+	0x47, 0xb0, 0xf6, 0x00, 0x6d, 0xa4, 0xf8, 0x74, 0x24, 0x6f, 0x9d, 0x18, 0x9a, 0xc2, 0xfd, 0x56,
+	0x52, 0xfb, 0xfd, 0xeb, 0x22, 0x2c, 0xb5, 0x39, 0x51, 0xc6, 0xb7, 0x2f, 0xc0, 0x98, 0x92, 0x73,
+	0xaf, 0x16, 0x53, 0xf7, 0xea, 0x35, 0xa8, 0xf1, 0xf0, 0x75, 0x2c, 0x33, 0x08, 0x5c, 0x95, 0x0b,
+	0xda, 0xa6, 0x4f, 0x9e, 0x24, 0xd8, 0x57, 0x44, 0xee, 0x66, 0x2c, 0x72, 0x63, 0xd3, 0x4f, 0xa2,
+	0xe2, 0xff, 0x21, 0xa7, 0xfe, 0xa6, 0x08, 0x57, 0x5a, 0xdd, 0x2e, 0x0e, 0x68, 0x3b, 0xbc, 0x62,
+	0xa6, 0x1e, 0xa4, 0xf0, 0x98, 0x16, 0x53, 0xc7, 0x34, 0x1e, 0xf4, 0x52, 0x2a, 0xd5, 0xd0, 0x33,
+	0x22, 0xb2, 0x1d, 0xf7, 0x31, 0xdb, 0x8b, 0xff, 0x53, 0x5c, 0xfe, 0xa5, 0x40, 0x99, 0x9f, 0x91,
+	0x4b, 0x92, 0x10, 0x12, 0x98, 0x89, 0x25, 0x83, 0xfc, 0x77, 0x74, 0x6a, 0xcb, 0xf1, 0x53, 0xab,
+	0x42, 0xa5, 0x8f, 0xfd, 0x43, 0x76, 0x0f, 0xcd, 0x72, 0x79, 0xd0, 0x0c, 0x6f, 0xb2, 0x4a, 0xec,
+	0x26, 0xfb, 0x4a, 0x01, 0x22, 0xee, 0x54, 0xc1, 0x08, 0x72, 0xeb, 0xdf, 0x83, 0x32, 0x87, 0xb2,
+	0x24, 0x98, 0x71, 0xe6, 0x10, 0xdd, 0xdf, 0xf4, 0xbd, 0xfa, 0x07, 0x05, 0x08, 0xbb, 0x57, 0xf9,
+	0x1c, 0xd1, 0xc5, 0x1a, 0x11, 0x99, 0x32, 0x85, 0xc8, 0x2e, 0xe2, 0x6e, 0xfd, 0xb7, 0x02, 0x95,
+	0xcf, 0xf0, 0xf0, 0x95, 0xeb, 0x1e, 0x5f, 0x12, 0x70, 0x2c, 0x42, 0x69, 0xe8, 0xd9, 0x12, 0x1b,
+	0xec, 0x27, 0xbb, 0x7a, 0x24, 0x69, 0x0b, 0x6c, 0xc8, 0x16, 0x93, 0xfb, 0xd8, 0xf5, 0x90, 0xca,
+	0x57, 0x82, 0x6c, 0x31, 0xb9, 0xd1, 0xa5, 0xd6, 0x09, 0x72, 0x70, 0x54, 0x75, 0xd9, 0xd2, 0x7e,
+	0x0e, 0x2b, 0x02, 0x1d, 0x72, 0xc1, 0x01, 0x3e, 0x6e, 0x42, 0xe5, 0x54, 0x48, 0x24, 0x42, 0x48,
+	0x6c, 0x4b, 0x02, 0xdd, 0x40, 0xe5, 0x6b, 0xa0, 0x44, 0xfb, 0x93, 0x02, 0x2b, 0x0c, 0x11, 0xd2,
+	0x66, 0x84, 0x89, 0x4d, 0xa8, 0x4a, 0xf3, 0x01, 0x2a, 0xb2, 0x5c, 0x08, 0x75, 0x2e, 0x04, 0x19,
+	0xbf, 0x2b, 0xc1, 0x82, 0x9c, 0xf9, 0x01, 0xda, 0xd6, 0x09, 0x7a, 0xa3, 0x4b, 0x82, 0x90, 0xeb,
+	0x00, 0x32, 0x12, 0x1d, 0xcb, 0x94, 0x40, 0xa9, 0x49, 0x49, 0x9b, 0x3f, 0xf4, 0x38, 0x40, 0xe4,
+	0x9b, 0x52, 0x34, 0x18, 0x93, 0x0c, 0x8c, 0x91, 0xed, 0x1a, 0xa6, 0x44, 0x4b, 0xd0, 0x64, 0xfa,
+	0x3e, 0x35, 0x28, 0x4a, 0x2a, 0x11, 0x0d, 0xc6, 0xfe, 0x06, 0xa5, 0xac, 0x30, 0xe0, 0xf3, 0xb7,
+	0x64, 0x59, 0x0f, 0xdb, 0x64, 0x47, 0x86, 0x5f, 0x0a, 0xd8, 0x02, 0x6a, 0x53, 0x17, 0xc0, 0xb7,
+	0xa6, 0x25, 0x46, 0xb4, 0x58, 0xa6, 0x3f, 0xe7, 0xc9, 0xed, 0xef, 0x74, 0x5d, 0x13, 0xf9, 0xb3,
+	0xb3, 0xac, 0x37, 0x02, 0xe1, 0xae, 0x6b, 0xf2, 0x2d, 0xb2, 0x0d, 0x9f, 0x76, 0xd0, 0xf3, 0x5c,
+	0x8f, 0xbf, 0x3a, 0x6b, 0x7a, 0x8d, 0x49, 0xf6, 0x98, 0x40, 0xfb, 0x9b, 0x02, 0xd7, 0x63, 0x78,
+	0x92, 0xdb, 0x64, 0x61, 0x04, 0xac, 0xbb, 0x00, 0x66, 0x28, 0x95, 0xd0, 0x6a, 0x8e, 0x43, 0x2b,
+	0xd8, 0x60, 0x3d, 0xa6, 0x7d, 0x21, 0x20, 0xfb, 0x7b, 0x11, 0x20, 0xca, 0xbb, 0xbe, 0x49, 0x7c,
+	0x5d, 0x85, 0xaa, 0xd1, 0xa5, 0xae, 0xc7, 0x20, 0x22, 0x58, 0xb9, 0xc2, 0xdb, 0x6d, 0x33, 0xe0,
+	0x07, 0xd7, 0x91, 0xd8, 0x91, 0x2d, 0xb9, 0x25, 0xee, 0xd0, 0xeb, 0x62, 0x87, 0x8e, 0x06, 0x28,
+	0x01, 0xd4, 0x08, 0x84, 0x2f, 0x46, 0x03, 0x24, 0x37, 0xa0, 0x1e, 0x2a, 0x59, 0x01, 0x96, 0x20,
+	0x10, 0xb5, 0x4d, 0x72, 0x0b, 0x2a, 0x5d, 0x9e, 0x75, 0xfb, 0x6a, 0x85, 0xc7, 0x7b, 0x2d, 0x9d,
+	0x63, 0xca, 0xa4, 0x3c, 0x50, 0x13, 0xef, 0x50, 0xf4, 0x3a, 0x86, 0x69, 0x7a, 0xe8, 0x0b, 0xb8,
+	0xf1, 0x77, 0x28, 0x7a, 0x2d, 0x21, 0x62, 0x65, 0x0a, 0xab, 0x3f, 0x10, 0x49, 0xb3, 0x5c, 0x54,
+	0x8d, 0x6b, 0xcd, 0xc7, 0xc5, 0x6d, 0x53, 0xfb, 0x1c, 0xea, 0xb1, 0x39, 0x18, 0xb6, 0x39, 0xfd,
+	0x04, 0x59, 0x0f, 0x6f, 0xb0, 0x1d, 0x63, 0xef, 0xa4, 0x28, 0x91, 0xa8, 0xe9, 0x55, 0xd7, 0x36,
+	0x79, 0xde, 0xc0, 0x3a, 0xd9, 0x0b, 0x49, 0x74, 0xca, 0xbc, 0xc7, 0xc1, 0x53, 0xde, 0xa9, 0xfd,
+	0x55, 0x81, 0x2b, 0x0c, 0x71, 0xd1, 0x9e, 0x45, 0x58, 0x4b, 0x67, 0xd8, 0xca, 0xb9, 0x33, 0xec,
+	0x8b, 0x40, 0xda, 0x57, 0x25, 0x98, 0x6d, 0x0d, 0xac, 0xc7, 0x78, 0x59, 0x58, 0xec, 0x26, 0x90,
+	0x54, 0xe5, 0x29, 0x62, 0xb3, 0xc5, 0x64, 0xf1, 0xa9, 0x6d, 0x86, 0x29, 0x53, 0x39, 0x96, 0x32,
+	0xad, 0xc1, 0xec, 0xc0, 0xc3, 0x23, 0xeb, 0x2c, 0xb8, 0xff, 0x44, 0x8b, 0xc9, 0xfd, 0xae, 0x3b,
+	0x90, 0x00, 0xac, 0xe9, 0xb2, 0xc5, 0x9c, 0x8d, 0x15, 0x6a, 0xaa, 0xd3, 0x9d, 0xc5, 0xb0, 0x3c,
+	0xf3, 0x29, 0x34, 0x38, 0x11, 0x0d, 0x7d, 0xb1, 0xd2, 0xe9, 0x74, 0xc7, 0x89, 0xeb, 0xa5, 0x1f,
+	0x5c, 0xe9, 0x2c, 0x8d, 0x85, 0x30, 0x8d, 0xd5, 0xfe, 0xa8, 0xc0, 0x32, 0xc7, 0x11, 0xdf, 0x91,
+	0x08, 0x43, 0x37, 0xa1, 0x6a, 0x0c, 0xac, 0xce, 0x31, 0x8e, 0x02, 0xfc, 0x2c, 0xc5, 0xf1, 0xc3,
+	0xb5, 0xf5, 0x8a, 0x21, 0x46, 0x5d, 0x08, 0x6e, 0xfe, 0x5c, 0x84, 0xe5, 0x30, 0xb3, 0x7e, 0x80,
+	0x47, 0x96, 0x63, 0x71, 0xb2, 0xb8, 0xbc, 0x99, 0x34, 0x81, 0x99, 0x18, 0x7b, 0xf1, 0xdf, 0xec,
+	0x36, 0xf3, 0xf0, 0xf5, 0xd0, 0xf2, 0x50, 0x50, 0x56, 0x55, 0x0f, 0xdb, 0x0c, 0x2e, 0x43, 0xc7,
+	0x7a, 0x3d, 0x0c, 0xd3, 0x25, 0xd1, 0xe2, 0x19, 0x39, 0xf6, 0xf0, 0x4c, 0xf2, 0x91, 0x68, 0x30,
+	0xfe, 0x43, 0xd3, 0xa2, 0xc6, 0xa1, 0x8d, 0xac, 0x52, 0x27, 0x58, 0x08, 0x02, 0xd1, 0xce, 0x48,
+	0xfb, 0xa5, 0x02, 0xf3, 0xc9, 0xa7, 0x08, 0x79, 0x17, 0x1a, 0x3e, 0xf5, 0x58, 0x9d, 0x4d, 0xb0,
+	0x0a, 0x8f, 0xda, 0xa3, 0x82, 0x5e, 0x17, 0xd2, 0x50, 0xc9, 0x19, 0xb2, 0xdc, 0x3e, 0xc6, 0x4b,
+	0x0a, 0x53, 0x12, 0x52, 0xa1, 0x74, 0x03, 0xe0, 0xd0, 0x75, 0xed, 0x18, 0x3b, 0x55, 0x1f, 0x15,
+	0xf4, 0x1a, 0x93, 0x71, 0x85, 0x9d, 0x8a, 0x7c, 0x1f, 0x69, 0x7f, 0x51, 0x60, 0x5d, 0x64, 0x7b,
+	0x19, 0xbb, 0x17, 0x64, 0x7e, 0x3f, 0x82, 0x95, 0xf0, 0x01, 0xd6, 0x31, 0xc3, 0x6e, 0x99, 0x06,
+	0xbe, 0x9d, 0xf5, 0xb8, 0x8a, 0x19, 0x59, 0x36, 0xc6, 0x85, 0x5f, 0x27, 0x3d, 0xfc, 0xa7, 0x02,
+	0xeb, 0xfc, 0x50, 0x8c, 0x9b, 0x8d, 0x4e, 0xc8, 0x73, 0x58, 0xcd, 0x72, 0x39, 0x38, 0x2e, 0xd3,
+	0x7c, 0x5e, 0xc9, 0xf0, 0xf9, 0x62, 0x0e, 0x52, 0x1b, 0xc8, 0x81, 0x87, 0x27, 0x16, 0x9e, 0xc6,
+	0xcb, 0x6a, 0x4d, 0xa8, 0xb2, 0x8c, 0xc8, 0x66, 0xf9, 0x97, 0xac, 0x6c, 0x04, 0x6d, 0x72, 0x05,
+	0x2a, 0x43, 0xf6, 0x1d, 0xc7, 0x0a, 0x8a, 0x5c, 0xb3, 0xac, 0xd9, 0x36, 0xb5, 0xcf, 0x60, 0x39,
+	0x61, 0x4a, 0x86, 0x46, 0x85, 0x8a, 0x3f, 0x3c, 0xfc, 0x02, 0xbb, 0x54, 0x9a, 0x0a, 0x9a, 0xfc,
+	0x48, 0xe0, 0x19, 0x0d, 0x2a, 0x6e, 0xec, 0x37, 0x93, 0xbd, 0xa2, 0x7d, 0x3b, 0x78, 0xb2, 0xb1,
+	0xdf, 0xda, 0x2f, 0x4a, 0x30, 0xc3, 0x4c, 0x5e, 0x9e, 0xd3, 0x7d, 0xe4, 0xb9, 0xfd, 0xe0, 0x74,
+	0xb3, 0xdf, 0xcc, 0x33, 0xea, 0xca, 0x87, 0x50, 0x91, 0xba, 0xac, 0xdd, 0xed, 0xca, 0xc7, 0x71,
+	0xb1, 0xdb, 0x65, 0x5c, 0x7b, 0xd8, 0xed, 0x4a, 0xe6, 0x67, 0x3f, 0xe3, 0x61, 0xa9, 0x26, 0xc3,
+	0x12, 0x66, 0xbe, 0xb5, 0xbc, 0xcc, 0x17, 0xa6, 0x67, 0xbe, 0xf5, 0x37, 0xcd, 0x7c, 0x93, 0x49,
+	0x6d, 0x23, 0x9d, 0xd4, 0x06, 0xe5, 0x68, 0xb6, 0x0f, 0x89, 0x72, 0x34, 0xab, 0x61, 0x65, 0x95,
+	0xa3, 0x39, 0x06, 0x44, 0xef, 0x45, 0x00, 0x79, 0xfb, 0x1f, 0x2a, 0xcc, 0xee, 0xf3, 0xd9, 0xc9,
+	0x2e, 0xd4, 0xc2, 0xca, 0x39, 0x89, 0xe7, 0x79, 0xb1, 0x2f, 0x7c, 0xcd, 0xb7, 0x52, 0xf2, 0x44,
+	0x9d, 0x5d, 0x2b, 0x90, 0x6d, 0xa8, 0xec, 0x23, 0x97, 0x92, 0x95, 0x78, 0x51, 0x2d, 0xa8, 0x01,
+	0x37, 0xd3, 0x15, 0x51, 0xad, 0x40, 0x6e, 0x01, 0xec, 0x72, 0x84, 0xf1, 0x61, 0x69, 0x85, 0xac,
+	0x11, 0xf7, 0x00, 0xa2, 0xcf, 0x0d, 0x24, 0xee, 0xd3, 0xd8, 0x57, 0x88, 0xac, 0xe1, 0x9f, 0x02,
+	0x88, 0xda, 0xe4, 0x04, 0x3f, 0xd7, 0xc6, 0x30, 0xb0, 0xc7, 0xbe, 0xab, 0x6a, 0x05, 0xf2, 0x5d,
+	0xa8, 0xeb, 0xe8, 0x53, 0xd7, 0xc3, 0x37, 0x5b, 0xe6, 0x13, 0x98, 0x4f, 0x56, 0xfe, 0xc9, 0x7a,
+	0x4c, 0x29, 0xf3, 0xa3, 0xc0, 0x04, 0x2f, 0x1e, 0x42, 0x3d, 0x56, 0xd8, 0x27, 0xd7, 0xe3, 0xa6,
+	0xc6, 0x0a, 0xfe, 0x13, 0xec, 0x3c, 0x81, 0xf9, 0x64, 0xd1, 0x3f, 0xe1, 0x55, 0xe6, 0xf7, 0x80,
+	0x09, 0xd6, 0x7e, 0x00, 0x73, 0x89, 0xca, 0x3f, 0x89, 0x7f, 0xd7, 0xca, 0xfa, 0x26, 0x30, 0xc1,
+	0xd6, 0x01, 0x2c, 0xa6, 0x6b, 0xff, 0x44, 0x4b, 0x98, 0xcb, 0xfc, 0x30, 0x30, 0xc1, 0xe2, 0x7d,
+	0xa8, 0xc7, 0x3e, 0x0e, 0x24, 0x62, 0x36, 0xfe, 0xd1, 0x20, 0x6b, 0x0b, 0x3f, 0x86, 0x79, 0x1d,
+	0x79, 0x01, 0xc6, 0xa0, 0x6f, 0xb8, 0xfb, 0x6d, 0x68, 0xc4, 0x6b, 0xe7, 0x24, 0xc7, 0xcb, 0x66,
+	0x3c, 0x60, 0x59, 0xc5, 0x76, 0xad, 0x40, 0x9e, 0xc1, 0x42, 0xaa, 0xb4, 0x4e, 0xde, 0x89, 0x8d,
+	0xca, 0x2e, 0xbb, 0x4f, 0x08, 0xcb, 0x63, 0xb8, 0xb2, 0x6b, 0x38, 0x5d, 0xb4, 0xc3, 0x31, 0x0f,
+	0xe4, 0x37, 0xd5, 0x5c, 0x2f, 0xf3, 0x8d, 0xdd, 0x03, 0x88, 0x2a, 0xe8, 0x89, 0xa3, 0x39, 0x56,
+	0x58, 0xcf, 0x0e, 0xd3, 0x62, 0xba, 0xdc, 0x9c, 0xd8, 0xf4, 0x9c, 0x5a, 0x74, 0x96, 0xa9, 0x4f,
+	0x18, 0x7e, 0x7c, 0x74, 0xcc, 0x98, 0xa9, 0x73, 0x6f, 0xd7, 0x0e, 0x07, 0x9f, 0x7b, 0x8c, 0x53,
+	0x07, 0xe7, 0x87, 0x62, 0x0f, 0x20, 0x2a, 0x99, 0xe6, 0x32, 0xea, 0xf5, 0x94, 0x3c, 0x59, 0x61,
+	0xd5, 0x0a, 0xe4, 0x23, 0xa8, 0xee, 0xa3, 0x10, 0xe7, 0xb8, 0x30, 0x56, 0x75, 0xd5, 0x0a, 0xe4,
+	0x43, 0xa8, 0x0b, 0x52, 0x15, 0x03, 0xc7, 0x54, 0x32, 0x07, 0x7d, 0x1f, 0xea, 0xb1, 0x92, 0x73,
+	0xe2, 0x80, 0x8c, 0x97, 0xa2, 0x33, 0x2d, 0xdc, 0x83, 0xba, 0xc0, 0xdf, 0x24, 0x7f, 0xf3, 0x43,
+	0x76, 0x07, 0x1a, 0x92, 0x5b, 0xdf, 0x74, 0xbd, 0x6d, 0x68, 0xc4, 0xab, 0x91, 0xb9, 0xe1, 0xbe,
+	0x91, 0x92, 0xa7, 0xcb, 0x97, 0xdc, 0x09, 0xd8, 0xc7, 0xa0, 0x23, 0xc7, 0x85, 0x8c, 0x92, 0x26,
+	0xe7, 0x87, 0x39, 0x11, 0xf4, 0x60, 0x70, 0x86, 0x5a, 0xce, 0xd0, 0x87, 0x30, 0x97, 0xa8, 0xe7,
+	0x26, 0x98, 0x33, 0xab, 0xd2, 0x9b, 0x63, 0xe7, 0x3e, 0xcc, 0x89, 0x0d, 0x98, 0xec, 0x7f, 0xfe,
+	0x16, 0xfc, 0x0c, 0x56, 0x33, 0xcb, 0x70, 0xb9, 0x11, 0xdd, 0xc8, 0x8e, 0xe8, 0x78, 0x01, 0x4f,
+	0x2b, 0x90, 0x1f, 0xc2, 0x42, 0xaa, 0xe2, 0x92, 0x6b, 0x56, 0x4b, 0xc9, 0x33, 0xaa, 0x34, 0x5a,
+	0x81, 0xdc, 0x85, 0x15, 0x11, 0xf1, 0xe7, 0xc9, 0x7f, 0xb8, 0x9c, 0x27, 0x8b, 0xd8, 0x17, 0x7f,
+	0x5e, 0x92, 0xcf, 0xf6, 0x5c, 0x47, 0xde, 0x4e, 0x3b, 0x92, 0x7c, 0xe6, 0xf3, 0x8c, 0xa0, 0x21,
+	0x9c, 0x10, 0x5d, 0x64, 0xfc, 0x99, 0xdf, 0x1c, 0x17, 0x69, 0x05, 0xf2, 0x3d, 0x68, 0x88, 0xbd,
+	0x92, 0xe3, 0xde, 0x74, 0xab, 0x0c, 0x50, 0xf3, 0x9e, 0x58, 0xb9, 0xab, 0xf9, 0x20, 0xbd, 0x9a,
+	0x09, 0xef, 0x33, 0x7e, 0x09, 0xaf, 0xed, 0x63, 0x96, 0x52, 0x8e, 0xb3, 0x53, 0x9e, 0x6c, 0x5a,
+	0x81, 0x7c, 0x0e, 0x57, 0x65, 0xb0, 0x32, 0x8c, 0x4e, 0x19, 0x7e, 0x0e, 0xf3, 0x5f, 0xc0, 0xd5,
+	0xdc, 0x97, 0x32, 0xf9, 0x60, 0xec, 0x4c, 0xe5, 0xbf, 0xa7, 0xcf, 0x31, 0xd7, 0x63, 0xb8, 0x2a,
+	0xf7, 0xef, 0xdc, 0xf1, 0xc9, 0xdf, 0xcc, 0x67, 0x50, 0x8f, 0xbd, 0x03, 0x13, 0xdc, 0x3b, 0xfe,
+	0xd4, 0x6c, 0xbe, 0x9d, 0xd7, 0x1d, 0xee, 0x9c, 0x4c, 0xe7, 0x9f, 0xf2, 0xa7, 0xc4, 0x79, 0xd3,
+	0xf9, 0xc4, 0x3b, 0x85, 0xdf, 0x3d, 0x35, 0x1d, 0xa9, 0x37, 0xe2, 0x2e, 0x4d, 0xbf, 0x3c, 0x99,
+	0x9a, 0x56, 0xd8, 0x81, 0x9f, 0x56, 0x85, 0xec, 0xe4, 0xf6, 0xe1, 0x2c, 0x5f, 0xe8, 0x87, 0xff,
+	0x1d, 0x00, 0xb1, 0x8b, 0xfe, 0x64, 0xd5, 0x28, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
 var _ grpc.ClientConnInterface
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// GooserClient is the client API for Gooser service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GooserClient interface {
+	// List users.
+	ListUsers(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	// Gets a user.
+	GetUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*User, error)
+	// Creates a user.
+	CreateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*User, error)
+	// Updates a user.
+	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error)
+	// Deletes a user.
+	DeleteUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Restores a deleted user.
+	RestoreUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*User, error)
+	// Change password.
+	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Confirm Mail.
+	ConfirmMail(ctx context.Context, in *ConfirmMailRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Forgot Password.
+	ForgotPassword(ctx context.Context, in *ForgotPasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Reset Password.
+	ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Reverts a mail address change using the token sent to the previous address.
+	RevertMailChange(ctx context.Context, in *RevertMailChangeRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Suspends a user.
+	SuspendUser(ctx context.Context, in *SuspendUserRequest, opts ...grpc.CallOption) (*User, error)
+	// Reactivates a suspended user.
+	ReactivateUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*User, error)
+	// Exports all data about the calling user.
+	ExportMyData(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ExportMyDataResponse, error)
+	// Schedules the deletion of the calling user's account.
+	DeleteMyAccount(ctx context.Context, in *DeleteMyAccountRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Cancels the scheduled deletion of the calling user's account.
+	CancelMyAccountDeletion(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Invites a user by mail.
+	InviteUser(ctx context.Context, in *InviteUserRequest, opts ...grpc.CallOption) (*User, error)
+	// Accepts an invitation.
+	AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*User, error)
+	// Resends an invitation with a new token.
+	ResendInvitation(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*User, error)
+	// Revokes an invitation.
+	RevokeInvitation(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// List groups.
+	ListGroups(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error)
+	// Gets a group.
+	GetGroup(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Group, error)
+	// Creates a group.
+	CreateGroup(ctx context.Context, in *Group, opts ...grpc.CallOption) (*Group, error)
+	// Updates a group.
+	UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	// Deletes a group.
+	DeleteGroup(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Restores a deleted group.
+	RestoreGroup(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Group, error)
+	// List webhooks.
+	ListWebhooks(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	// Gets a webhook.
+	GetWebhook(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Webhook, error)
+	// Creates a webhook.
+	CreateWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error)
+	// Updates a webhook.
+	UpdateWebhook(ctx context.Context, in *UpdateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	// Deletes a webhook.
+	DeleteWebhook(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// List webhook deliveries.
+	ListWebhookDeliveries(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	// List audit events.
+	ListAuditEvents(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+	// Creates a service account.
+	CreateServiceAccount(ctx context.Context, in *User, opts ...grpc.CallOption) (*User, error)
+	// List api keys.
+	ListApiKeys(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListApiKeysResponse, error)
+	// Creates an api key for a service account.
+	CreateApiKey(ctx context.Context, in *ApiKey, opts ...grpc.CallOption) (*ApiKey, error)
+	// Deletes an api key.
+	DeleteApiKey(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// List attribute definitions.
+	ListAttributeDefinitions(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListAttributeDefinitionsResponse, error)
+	// Gets an attribute definition.
+	GetAttributeDefinition(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*AttributeDefinition, error)
+	// Creates an attribute definition.
+	CreateAttributeDefinition(ctx context.Context, in *AttributeDefinition, opts ...grpc.CallOption) (*AttributeDefinition, error)
+	// Updates an attribute definition.
+	UpdateAttributeDefinition(ctx context.Context, in *UpdateAttributeDefinitionRequest, opts ...grpc.CallOption) (*AttributeDefinition, error)
+	// Deletes an attribute definition and removes the attribute from all users.
+	DeleteAttributeDefinition(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Renders a mail template for a user without sending it.
+	PreviewMail(ctx context.Context, in *PreviewMailRequest, opts ...grpc.CallOption) (*PreviewMailResponse, error)
+	// Lists the mails of the outbound mail queue.
+	ListMails(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListMailsResponse, error)
+	// Queues a failed mail for delivery again.
+	RetryMail(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Mail, error)
+}
+
+type gooserClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGooserClient(cc grpc.ClientConnInterface) GooserClient {
+	return &gooserClient{cc}
+}
+
+func (c *gooserClient) ListUsers(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) GetUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/GetUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) CreateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/CreateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/UpdateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) DeleteUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/DeleteUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) RestoreUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/RestoreUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ChangePassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ConfirmMail(ctx context.Context, in *ConfirmMailRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ConfirmMail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ForgotPassword(ctx context.Context, in *ForgotPasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ForgotPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ResetPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) RevertMailChange(ctx context.Context, in *RevertMailChangeRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/RevertMailChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) SuspendUser(ctx context.Context, in *SuspendUserRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/SuspendUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ReactivateUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ReactivateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ExportMyData(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ExportMyDataResponse, error) {
+	out := new(ExportMyDataResponse)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ExportMyData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) DeleteMyAccount(ctx context.Context, in *DeleteMyAccountRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/DeleteMyAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) CancelMyAccountDeletion(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/CancelMyAccountDeletion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) InviteUser(ctx context.Context, in *InviteUserRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/InviteUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/AcceptInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ResendInvitation(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ResendInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) RevokeInvitation(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/RevokeInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ListGroups(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error) {
+	out := new(ListGroupsResponse)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ListGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) GetGroup(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/GetGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) CreateGroup(ctx context.Context, in *Group, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/CreateGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/UpdateGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) DeleteGroup(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/DeleteGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) RestoreGroup(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/RestoreGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ListWebhooks(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ListWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) GetWebhook(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/GetWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) CreateWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/CreateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) UpdateWebhook(ctx context.Context, in *UpdateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/UpdateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) DeleteWebhook(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/DeleteWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ListWebhookDeliveries(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ListWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ListAuditEvents(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ListAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) CreateServiceAccount(ctx context.Context, in *User, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/CreateServiceAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ListApiKeys(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListApiKeysResponse, error) {
+	out := new(ListApiKeysResponse)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ListApiKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) CreateApiKey(ctx context.Context, in *ApiKey, opts ...grpc.CallOption) (*ApiKey, error) {
+	out := new(ApiKey)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/CreateApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) DeleteApiKey(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/DeleteApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ListAttributeDefinitions(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListAttributeDefinitionsResponse, error) {
+	out := new(ListAttributeDefinitionsResponse)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ListAttributeDefinitions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) GetAttributeDefinition(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*AttributeDefinition, error) {
+	out := new(AttributeDefinition)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/GetAttributeDefinition", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) CreateAttributeDefinition(ctx context.Context, in *AttributeDefinition, opts ...grpc.CallOption) (*AttributeDefinition, error) {
+	out := new(AttributeDefinition)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/CreateAttributeDefinition", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) UpdateAttributeDefinition(ctx context.Context, in *UpdateAttributeDefinitionRequest, opts ...grpc.CallOption) (*AttributeDefinition, error) {
+	out := new(AttributeDefinition)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/UpdateAttributeDefinition", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) DeleteAttributeDefinition(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/DeleteAttributeDefinition", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) PreviewMail(ctx context.Context, in *PreviewMailRequest, opts ...grpc.CallOption) (*PreviewMailResponse, error) {
+	out := new(PreviewMailResponse)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/PreviewMail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) ListMails(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListMailsResponse, error) {
+	out := new(ListMailsResponse)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ListMails", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gooserClient) RetryMail(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Mail, error) {
+	out := new(Mail)
+	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/RetryMail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GooserServer is the server API for Gooser service.
+type GooserServer interface {
+	// List users.
+	ListUsers(context.Context, *ListRequest) (*ListUsersResponse, error)
+	// Gets a user.
+	GetUser(context.Context, *IdRequest) (*User, error)
+	// Creates a user.
+	CreateUser(context.Context, *User) (*User, error)
+	// Updates a user.
+	UpdateUser(context.Context, *UpdateUserRequest) (*User, error)
+	// Deletes a user.
+	DeleteUser(context.Context, *IdRequest) (*empty.Empty, error)
+	// Restores a deleted user.
+	RestoreUser(context.Context, *IdRequest) (*User, error)
+	// Change password.
+	ChangePassword(context.Context, *ChangePasswordRequest) (*empty.Empty, error)
+	// Confirm Mail.
+	ConfirmMail(context.Context, *ConfirmMailRequest) (*empty.Empty, error)
+	// Forgot Password.
+	ForgotPassword(context.Context, *ForgotPasswordRequest) (*empty.Empty, error)
+	// Reset Password.
+	ResetPassword(context.Context, *ResetPasswordRequest) (*empty.Empty, error)
+	// Reverts a mail address change using the token sent to the previous address.
+	RevertMailChange(context.Context, *RevertMailChangeRequest) (*empty.Empty, error)
+	// Suspends a user.
+	SuspendUser(context.Context, *SuspendUserRequest) (*User, error)
+	// Reactivates a suspended user.
+	ReactivateUser(context.Context, *IdRequest) (*User, error)
+	// Exports all data about the calling user.
+	ExportMyData(context.Context, *empty.Empty) (*ExportMyDataResponse, error)
+	// Schedules the deletion of the calling user's account.
+	DeleteMyAccount(context.Context, *DeleteMyAccountRequest) (*empty.Empty, error)
+	// Cancels the scheduled deletion of the calling user's account.
+	CancelMyAccountDeletion(context.Context, *empty.Empty) (*empty.Empty, error)
+	// Invites a user by mail.
+	InviteUser(context.Context, *InviteUserRequest) (*User, error)
+	// Accepts an invitation.
+	AcceptInvitation(context.Context, *AcceptInvitationRequest) (*User, error)
+	// Resends an invitation with a new token.
+	ResendInvitation(context.Context, *IdRequest) (*User, error)
+	// Revokes an invitation.
+	RevokeInvitation(context.Context, *IdRequest) (*empty.Empty, error)
+	// List groups.
+	ListGroups(context.Context, *ListRequest) (*ListGroupsResponse, error)
+	// Gets a group.
+	GetGroup(context.Context, *IdRequest) (*Group, error)
+	// Creates a group.
+	CreateGroup(context.Context, *Group) (*Group, error)
+	// Updates a group.
+	UpdateGroup(context.Context, *UpdateGroupRequest) (*Group, error)
+	// Deletes a group.
+	DeleteGroup(context.Context, *IdRequest) (*empty.Empty, error)
+	// Restores a deleted group.
+	RestoreGroup(context.Context, *IdRequest) (*Group, error)
+	// List webhooks.
+	ListWebhooks(context.Context, *ListRequest) (*ListWebhooksResponse, error)
+	// Gets a webhook.
+	GetWebhook(context.Context, *IdRequest) (*Webhook, error)
+	// Creates a webhook.
+	CreateWebhook(context.Context, *Webhook) (*Webhook, error)
+	// Updates a webhook.
+	UpdateWebhook(context.Context, *UpdateWebhookRequest) (*Webhook, error)
+	// Deletes a webhook.
+	DeleteWebhook(context.Context, *IdRequest) (*empty.Empty, error)
+	// List webhook deliveries.
+	ListWebhookDeliveries(context.Context, *ListRequest) (*ListWebhookDeliveriesResponse, error)
+	// List audit events.
+	ListAuditEvents(context.Context, *ListRequest) (*ListAuditEventsResponse, error)
+	// Creates a service account.
+	CreateServiceAccount(context.Context, *User) (*User, error)
+	// List api keys.
+	ListApiKeys(context.Context, *ListRequest) (*ListApiKeysResponse, error)
+	// Creates an api key for a service account.
+	CreateApiKey(context.Context, *ApiKey) (*ApiKey, error)
+	// Deletes an api key.
+	DeleteApiKey(context.Context, *IdRequest) (*empty.Empty, error)
+	// List attribute definitions.
+	ListAttributeDefinitions(context.Context, *ListRequest) (*ListAttributeDefinitionsResponse, error)
+	// Gets an attribute definition.
+	GetAttributeDefinition(context.Context, *IdRequest) (*AttributeDefinition, error)
+	// Creates an attribute definition.
+	CreateAttributeDefinition(context.Context, *AttributeDefinition) (*AttributeDefinition, error)
+	// Updates an attribute definition.
+	UpdateAttributeDefinition(context.Context, *UpdateAttributeDefinitionRequest) (*AttributeDefinition, error)
+	// Deletes an attribute definition and removes the attribute from all users.
+	DeleteAttributeDefinition(context.Context, *IdRequest) (*empty.Empty, error)
+	// Renders a mail template for a user without sending it.
+	PreviewMail(context.Context, *PreviewMailRequest) (*PreviewMailResponse, error)
+	// Lists the mails of the outbound mail queue.
+	ListMails(context.Context, *ListRequest) (*ListMailsResponse, error)
+	// Queues a failed mail for delivery again.
+	RetryMail(context.Context, *IdRequest) (*Mail, error)
+}
+
+// UnimplementedGooserServer can be embedded to have forward compatible implementations.
+type UnimplementedGooserServer struct {
+}
+
+func (*UnimplementedGooserServer) ListUsers(ctx context.Context, req *ListRequest) (*ListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (*UnimplementedGooserServer) GetUser(ctx context.Context, req *IdRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (*UnimplementedGooserServer) CreateUser(ctx context.Context, req *User) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (*UnimplementedGooserServer) UpdateUser(ctx context.Context, req *UpdateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (*UnimplementedGooserServer) DeleteUser(ctx context.Context, req *IdRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (*UnimplementedGooserServer) RestoreUser(ctx context.Context, req *IdRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreUser not implemented")
+}
+func (*UnimplementedGooserServer) ChangePassword(ctx context.Context, req *ChangePasswordRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
+}
+func (*UnimplementedGooserServer) ConfirmMail(ctx context.Context, req *ConfirmMailRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmMail not implemented")
+}
+func (*UnimplementedGooserServer) ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForgotPassword not implemented")
+}
+func (*UnimplementedGooserServer) ResetPassword(ctx context.Context, req *ResetPasswordRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetPassword not implemented")
+}
+func (*UnimplementedGooserServer) RevertMailChange(ctx context.Context, req *RevertMailChangeRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevertMailChange not implemented")
+}
+func (*UnimplementedGooserServer) SuspendUser(ctx context.Context, req *SuspendUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuspendUser not implemented")
+}
+func (*UnimplementedGooserServer) ReactivateUser(ctx context.Context, req *IdRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReactivateUser not implemented")
+}
+func (*UnimplementedGooserServer) ExportMyData(ctx context.Context, req *empty.Empty) (*ExportMyDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportMyData not implemented")
+}
+func (*UnimplementedGooserServer) DeleteMyAccount(ctx context.Context, req *DeleteMyAccountRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMyAccount not implemented")
+}
+func (*UnimplementedGooserServer) CancelMyAccountDeletion(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelMyAccountDeletion not implemented")
+}
+func (*UnimplementedGooserServer) InviteUser(ctx context.Context, req *InviteUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteUser not implemented")
+}
+func (*UnimplementedGooserServer) AcceptInvitation(ctx context.Context, req *AcceptInvitationRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptInvitation not implemented")
+}
+func (*UnimplementedGooserServer) ResendInvitation(ctx context.Context, req *IdRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResendInvitation not implemented")
+}
+func (*UnimplementedGooserServer) RevokeInvitation(ctx context.Context, req *IdRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeInvitation not implemented")
+}
+func (*UnimplementedGooserServer) ListGroups(ctx context.Context, req *ListRequest) (*ListGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroups not implemented")
+}
+func (*UnimplementedGooserServer) GetGroup(ctx context.Context, req *IdRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroup not implemented")
+}
+func (*UnimplementedGooserServer) CreateGroup(ctx context.Context, req *Group) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGroup not implemented")
+}
+func (*UnimplementedGooserServer) UpdateGroup(ctx context.Context, req *UpdateGroupRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGroup not implemented")
+}
+func (*UnimplementedGooserServer) DeleteGroup(ctx context.Context, req *IdRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGroup not implemented")
+}
+func (*UnimplementedGooserServer) RestoreGroup(ctx context.Context, req *IdRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreGroup not implemented")
+}
+func (*UnimplementedGooserServer) ListWebhooks(ctx context.Context, req *ListRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (*UnimplementedGooserServer) GetWebhook(ctx context.Context, req *IdRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhook not implemented")
+}
+func (*UnimplementedGooserServer) CreateWebhook(ctx context.Context, req *Webhook) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (*UnimplementedGooserServer) UpdateWebhook(ctx context.Context, req *UpdateWebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWebhook not implemented")
+}
+func (*UnimplementedGooserServer) DeleteWebhook(ctx context.Context, req *IdRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (*UnimplementedGooserServer) ListWebhookDeliveries(ctx context.Context, req *ListRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (*UnimplementedGooserServer) ListAuditEvents(ctx context.Context, req *ListRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (*UnimplementedGooserServer) CreateServiceAccount(ctx context.Context, req *User) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateServiceAccount not implemented")
+}
+func (*UnimplementedGooserServer) ListApiKeys(ctx context.Context, req *ListRequest) (*ListApiKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListApiKeys not implemented")
+}
+func (*UnimplementedGooserServer) CreateApiKey(ctx context.Context, req *ApiKey) (*ApiKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApiKey not implemented")
+}
+func (*UnimplementedGooserServer) DeleteApiKey(ctx context.Context, req *IdRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteApiKey not implemented")
+}
+func (*UnimplementedGooserServer) ListAttributeDefinitions(ctx context.Context, req *ListRequest) (*ListAttributeDefinitionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAttributeDefinitions not implemented")
+}
+func (*UnimplementedGooserServer) GetAttributeDefinition(ctx context.Context, req *IdRequest) (*AttributeDefinition, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAttributeDefinition not implemented")
+}
+func (*UnimplementedGooserServer) CreateAttributeDefinition(ctx context.Context, req *AttributeDefinition) (*AttributeDefinition, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAttributeDefinition not implemented")
+}
+func (*UnimplementedGooserServer) UpdateAttributeDefinition(ctx context.Context, req *UpdateAttributeDefinitionRequest) (*AttributeDefinition, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAttributeDefinition not implemented")
+}
+func (*UnimplementedGooserServer) DeleteAttributeDefinition(ctx context.Context, req *IdRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAttributeDefinition not implemented")
+}
+func (*UnimplementedGooserServer) PreviewMail(ctx context.Context, req *PreviewMailRequest) (*PreviewMailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewMail not implemented")
+}
+func (*UnimplementedGooserServer) ListMails(ctx context.Context, req *ListRequest) (*ListMailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMails not implemented")
+}
+func (*UnimplementedGooserServer) RetryMail(ctx context.Context, req *IdRequest) (*Mail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryMail not implemented")
+}
+
+func RegisterGooserServer(s *grpc.Server, srv GooserServer) {
+	s.RegisterService(&_Gooser_serviceDesc, srv)
+}
 
-// GooserClient is the client API for Gooser service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type GooserClient interface {
-	// List users.
-	ListUsers(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
-	// Gets a user.
-	GetUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*User, error)
-	// Creates a user.
-	CreateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*User, error)
-	// Updates a user.
-	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error)
-	// Deletes a user.
-	DeleteUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// Change password.
-	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// Confirm Mail.
-	ConfirmMail(ctx context.Context, in *ConfirmMailRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// Forgot Password.
-	ForgotPassword(ctx context.Context, in *ForgotPasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// Reset Password.
-	ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// List groups.
-	ListGroups(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error)
-	// Gets a group.
-	GetGroup(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Group, error)
-	// Creates a group.
-	CreateGroup(ctx context.Context, in *Group, opts ...grpc.CallOption) (*Group, error)
-	// Updates a group.
-	UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*Group, error)
-	// Deletes a group.
-	DeleteGroup(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+func _Gooser_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).ListUsers(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/GetUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).GetUser(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/CreateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).CreateUser(ctx, req.(*User))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/UpdateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/DeleteUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).DeleteUser(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_RestoreUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).RestoreUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/RestoreUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).RestoreUser(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/ChangePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_ConfirmMail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmMailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).ConfirmMail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/ConfirmMail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).ConfirmMail(ctx, req.(*ConfirmMailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_ForgotPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForgotPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).ForgotPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/ForgotPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).ForgotPassword(ctx, req.(*ForgotPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).ResetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/ResetPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).ResetPassword(ctx, req.(*ResetPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_RevertMailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertMailChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).RevertMailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/RevertMailChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).RevertMailChange(ctx, req.(*RevertMailChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_SuspendUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuspendUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).SuspendUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/SuspendUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).SuspendUser(ctx, req.(*SuspendUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type gooserClient struct {
-	cc grpc.ClientConnInterface
+func _Gooser_ReactivateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).ReactivateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/ReactivateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).ReactivateUser(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func NewGooserClient(cc grpc.ClientConnInterface) GooserClient {
-	return &gooserClient{cc}
+func _Gooser_ExportMyData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).ExportMyData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/ExportMyData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).ExportMyData(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) ListUsers(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
-	out := new(ListUsersResponse)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ListUsers", in, out, opts...)
-	if err != nil {
+func _Gooser_DeleteMyAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMyAccountRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).DeleteMyAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/DeleteMyAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).DeleteMyAccount(ctx, req.(*DeleteMyAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) GetUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*User, error) {
-	out := new(User)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/GetUser", in, out, opts...)
-	if err != nil {
+func _Gooser_CancelMyAccountDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).CancelMyAccountDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/CancelMyAccountDeletion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).CancelMyAccountDeletion(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) CreateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*User, error) {
-	out := new(User)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/CreateUser", in, out, opts...)
-	if err != nil {
+func _Gooser_InviteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteUserRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).InviteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/InviteUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).InviteUser(ctx, req.(*InviteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error) {
-	out := new(User)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/UpdateUser", in, out, opts...)
-	if err != nil {
+func _Gooser_AcceptInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptInvitationRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).AcceptInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/AcceptInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).AcceptInvitation(ctx, req.(*AcceptInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) DeleteUser(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/DeleteUser", in, out, opts...)
-	if err != nil {
+func _Gooser_ResendInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).ResendInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/ResendInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).ResendInvitation(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ChangePassword", in, out, opts...)
-	if err != nil {
+func _Gooser_RevokeInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).RevokeInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/RevokeInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).RevokeInvitation(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) ConfirmMail(ctx context.Context, in *ConfirmMailRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ConfirmMail", in, out, opts...)
-	if err != nil {
+func _Gooser_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/ListGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).ListGroups(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) ForgotPassword(ctx context.Context, in *ForgotPasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ForgotPassword", in, out, opts...)
-	if err != nil {
+func _Gooser_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/GetGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).GetGroup(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ResetPassword", in, out, opts...)
-	if err != nil {
+func _Gooser_CreateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Group)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).CreateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/CreateGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).CreateGroup(ctx, req.(*Group))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) ListGroups(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error) {
-	out := new(ListGroupsResponse)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/ListGroups", in, out, opts...)
-	if err != nil {
+func _Gooser_UpdateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateGroupRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).UpdateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/UpdateGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).UpdateGroup(ctx, req.(*UpdateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) GetGroup(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Group, error) {
-	out := new(Group)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/GetGroup", in, out, opts...)
-	if err != nil {
+func _Gooser_DeleteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).DeleteGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/DeleteGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).DeleteGroup(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) CreateGroup(ctx context.Context, in *Group, opts ...grpc.CallOption) (*Group, error) {
-	out := new(Group)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/CreateGroup", in, out, opts...)
-	if err != nil {
+func _Gooser_RestoreGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).RestoreGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/RestoreGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).RestoreGroup(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/ListWebhooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).ListWebhooks(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gooser_GetWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).GetWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/GetWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).GetWebhook(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*Group, error) {
-	out := new(Group)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/UpdateGroup", in, out, opts...)
-	if err != nil {
+func _Gooser_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Webhook)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GooserServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/CreateWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).CreateWebhook(ctx, req.(*Webhook))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gooserClient) DeleteGroup(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/gooser.v1.Gooser/DeleteGroup", in, out, opts...)
-	if err != nil {
+func _Gooser_UpdateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateWebhookRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-// GooserServer is the server API for Gooser service.
-type GooserServer interface {
-	// List users.
-	ListUsers(context.Context, *ListRequest) (*ListUsersResponse, error)
-	// Gets a user.
-	GetUser(context.Context, *IdRequest) (*User, error)
-	// Creates a user.
-	CreateUser(context.Context, *User) (*User, error)
-	// Updates a user.
-	UpdateUser(context.Context, *UpdateUserRequest) (*User, error)
-	// Deletes a user.
-	DeleteUser(context.Context, *IdRequest) (*empty.Empty, error)
-	// Change password.
-	ChangePassword(context.Context, *ChangePasswordRequest) (*empty.Empty, error)
-	// Confirm Mail.
-	ConfirmMail(context.Context, *ConfirmMailRequest) (*empty.Empty, error)
-	// Forgot Password.
-	ForgotPassword(context.Context, *ForgotPasswordRequest) (*empty.Empty, error)
-	// Reset Password.
-	ResetPassword(context.Context, *ResetPasswordRequest) (*empty.Empty, error)
-	// List groups.
-	ListGroups(context.Context, *ListRequest) (*ListGroupsResponse, error)
-	// Gets a group.
-	GetGroup(context.Context, *IdRequest) (*Group, error)
-	// Creates a group.
-	CreateGroup(context.Context, *Group) (*Group, error)
-	// Updates a group.
-	UpdateGroup(context.Context, *UpdateGroupRequest) (*Group, error)
-	// Deletes a group.
-	DeleteGroup(context.Context, *IdRequest) (*empty.Empty, error)
-}
-
-// UnimplementedGooserServer can be embedded to have forward compatible implementations.
-type UnimplementedGooserServer struct {
-}
-
-func (*UnimplementedGooserServer) ListUsers(ctx context.Context, req *ListRequest) (*ListUsersResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
-}
-func (*UnimplementedGooserServer) GetUser(ctx context.Context, req *IdRequest) (*User, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
-}
-func (*UnimplementedGooserServer) CreateUser(ctx context.Context, req *User) (*User, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
-}
-func (*UnimplementedGooserServer) UpdateUser(ctx context.Context, req *UpdateUserRequest) (*User, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
-}
-func (*UnimplementedGooserServer) DeleteUser(ctx context.Context, req *IdRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
-}
-func (*UnimplementedGooserServer) ChangePassword(ctx context.Context, req *ChangePasswordRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
-}
-func (*UnimplementedGooserServer) ConfirmMail(ctx context.Context, req *ConfirmMailRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ConfirmMail not implemented")
-}
-func (*UnimplementedGooserServer) ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ForgotPassword not implemented")
-}
-func (*UnimplementedGooserServer) ResetPassword(ctx context.Context, req *ResetPasswordRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ResetPassword not implemented")
-}
-func (*UnimplementedGooserServer) ListGroups(ctx context.Context, req *ListRequest) (*ListGroupsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListGroups not implemented")
-}
-func (*UnimplementedGooserServer) GetGroup(ctx context.Context, req *IdRequest) (*Group, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetGroup not implemented")
-}
-func (*UnimplementedGooserServer) CreateGroup(ctx context.Context, req *Group) (*Group, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateGroup not implemented")
-}
-func (*UnimplementedGooserServer) UpdateGroup(ctx context.Context, req *UpdateGroupRequest) (*Group, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateGroup not implemented")
-}
-func (*UnimplementedGooserServer) DeleteGroup(ctx context.Context, req *IdRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteGroup not implemented")
+	if interceptor == nil {
+		return srv.(GooserServer).UpdateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/UpdateWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).UpdateWebhook(ctx, req.(*UpdateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterGooserServer(s *grpc.Server, srv GooserServer) {
-	s.RegisterService(&_Gooser_serviceDesc, srv)
+func _Gooser_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GooserServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gooser.v1.Gooser/DeleteWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GooserServer).DeleteWebhook(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _Gooser_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).ListUsers(ctx, in)
+		return srv.(GooserServer).ListWebhookDeliveries(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/ListUsers",
+		FullMethod: "/gooser.v1.Gooser/ListWebhookDeliveries",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).ListUsers(ctx, req.(*ListRequest))
+		return srv.(GooserServer).ListWebhookDeliveries(ctx, req.(*ListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(IdRequest)
+func _Gooser_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).GetUser(ctx, in)
+		return srv.(GooserServer).ListAuditEvents(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/GetUser",
+		FullMethod: "/gooser.v1.Gooser/ListAuditEvents",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).GetUser(ctx, req.(*IdRequest))
+		return srv.(GooserServer).ListAuditEvents(ctx, req.(*ListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _Gooser_CreateServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(User)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).CreateUser(ctx, in)
+		return srv.(GooserServer).CreateServiceAccount(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/CreateUser",
+		FullMethod: "/gooser.v1.Gooser/CreateServiceAccount",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).CreateUser(ctx, req.(*User))
+		return srv.(GooserServer).CreateServiceAccount(ctx, req.(*User))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateUserRequest)
+func _Gooser_ListApiKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).UpdateUser(ctx, in)
+		return srv.(GooserServer).ListApiKeys(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/UpdateUser",
+		FullMethod: "/gooser.v1.Gooser/ListApiKeys",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+		return srv.(GooserServer).ListApiKeys(ctx, req.(*ListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(IdRequest)
+func _Gooser_CreateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApiKey)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).DeleteUser(ctx, in)
+		return srv.(GooserServer).CreateApiKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/DeleteUser",
+		FullMethod: "/gooser.v1.Gooser/CreateApiKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).DeleteUser(ctx, req.(*IdRequest))
+		return srv.(GooserServer).CreateApiKey(ctx, req.(*ApiKey))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChangePasswordRequest)
+func _Gooser_DeleteApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).ChangePassword(ctx, in)
+		return srv.(GooserServer).DeleteApiKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/ChangePassword",
+		FullMethod: "/gooser.v1.Gooser/DeleteApiKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+		return srv.(GooserServer).DeleteApiKey(ctx, req.(*IdRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_ConfirmMail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ConfirmMailRequest)
+func _Gooser_ListAttributeDefinitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).ConfirmMail(ctx, in)
+		return srv.(GooserServer).ListAttributeDefinitions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/ConfirmMail",
+		FullMethod: "/gooser.v1.Gooser/ListAttributeDefinitions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).ConfirmMail(ctx, req.(*ConfirmMailRequest))
+		return srv.(GooserServer).ListAttributeDefinitions(ctx, req.(*ListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_ForgotPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ForgotPasswordRequest)
+func _Gooser_GetAttributeDefinition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).ForgotPassword(ctx, in)
+		return srv.(GooserServer).GetAttributeDefinition(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/ForgotPassword",
+		FullMethod: "/gooser.v1.Gooser/GetAttributeDefinition",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).ForgotPassword(ctx, req.(*ForgotPasswordRequest))
+		return srv.(GooserServer).GetAttributeDefinition(ctx, req.(*IdRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ResetPasswordRequest)
+func _Gooser_CreateAttributeDefinition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttributeDefinition)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).ResetPassword(ctx, in)
+		return srv.(GooserServer).CreateAttributeDefinition(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/ResetPassword",
+		FullMethod: "/gooser.v1.Gooser/CreateAttributeDefinition",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).ResetPassword(ctx, req.(*ResetPasswordRequest))
+		return srv.(GooserServer).CreateAttributeDefinition(ctx, req.(*AttributeDefinition))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListRequest)
+func _Gooser_UpdateAttributeDefinition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAttributeDefinitionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).ListGroups(ctx, in)
+		return srv.(GooserServer).UpdateAttributeDefinition(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/ListGroups",
+		FullMethod: "/gooser.v1.Gooser/UpdateAttributeDefinition",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).ListGroups(ctx, req.(*ListRequest))
+		return srv.(GooserServer).UpdateAttributeDefinition(ctx, req.(*UpdateAttributeDefinitionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _Gooser_DeleteAttributeDefinition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(IdRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).GetGroup(ctx, in)
+		return srv.(GooserServer).DeleteAttributeDefinition(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/GetGroup",
+		FullMethod: "/gooser.v1.Gooser/DeleteAttributeDefinition",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).GetGroup(ctx, req.(*IdRequest))
+		return srv.(GooserServer).DeleteAttributeDefinition(ctx, req.(*IdRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_CreateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Group)
+func _Gooser_PreviewMail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewMailRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).CreateGroup(ctx, in)
+		return srv.(GooserServer).PreviewMail(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/CreateGroup",
+		FullMethod: "/gooser.v1.Gooser/PreviewMail",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).CreateGroup(ctx, req.(*Group))
+		return srv.(GooserServer).PreviewMail(ctx, req.(*PreviewMailRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_UpdateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateGroupRequest)
+func _Gooser_ListMails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).UpdateGroup(ctx, in)
+		return srv.(GooserServer).ListMails(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/UpdateGroup",
+		FullMethod: "/gooser.v1.Gooser/ListMails",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).UpdateGroup(ctx, req.(*UpdateGroupRequest))
+		return srv.(GooserServer).ListMails(ctx, req.(*ListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gooser_DeleteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _Gooser_RetryMail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(IdRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GooserServer).DeleteGroup(ctx, in)
+		return srv.(GooserServer).RetryMail(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/gooser.v1.Gooser/DeleteGroup",
+		FullMethod: "/gooser.v1.Gooser/RetryMail",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GooserServer).DeleteGroup(ctx, req.(*IdRequest))
+		return srv.(GooserServer).RetryMail(ctx, req.(*IdRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -1325,6 +4418,10 @@ var _Gooser_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUser",
 			Handler:    _Gooser_DeleteUser_Handler,
 		},
+		{
+			MethodName: "RestoreUser",
+			Handler:    _Gooser_RestoreUser_Handler,
+		},
 		{
 			MethodName: "ChangePassword",
 			Handler:    _Gooser_ChangePassword_Handler,
@@ -1341,6 +4438,46 @@ var _Gooser_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ResetPassword",
 			Handler:    _Gooser_ResetPassword_Handler,
 		},
+		{
+			MethodName: "RevertMailChange",
+			Handler:    _Gooser_RevertMailChange_Handler,
+		},
+		{
+			MethodName: "SuspendUser",
+			Handler:    _Gooser_SuspendUser_Handler,
+		},
+		{
+			MethodName: "ReactivateUser",
+			Handler:    _Gooser_ReactivateUser_Handler,
+		},
+		{
+			MethodName: "ExportMyData",
+			Handler:    _Gooser_ExportMyData_Handler,
+		},
+		{
+			MethodName: "DeleteMyAccount",
+			Handler:    _Gooser_DeleteMyAccount_Handler,
+		},
+		{
+			MethodName: "CancelMyAccountDeletion",
+			Handler:    _Gooser_CancelMyAccountDeletion_Handler,
+		},
+		{
+			MethodName: "InviteUser",
+			Handler:    _Gooser_InviteUser_Handler,
+		},
+		{
+			MethodName: "AcceptInvitation",
+			Handler:    _Gooser_AcceptInvitation_Handler,
+		},
+		{
+			MethodName: "ResendInvitation",
+			Handler:    _Gooser_ResendInvitation_Handler,
+		},
+		{
+			MethodName: "RevokeInvitation",
+			Handler:    _Gooser_RevokeInvitation_Handler,
+		},
 		{
 			MethodName: "ListGroups",
 			Handler:    _Gooser_ListGroups_Handler,
@@ -1361,6 +4498,86 @@ var _Gooser_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteGroup",
 			Handler:    _Gooser_DeleteGroup_Handler,
 		},
+		{
+			MethodName: "RestoreGroup",
+			Handler:    _Gooser_RestoreGroup_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _Gooser_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "GetWebhook",
+			Handler:    _Gooser_GetWebhook_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _Gooser_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "UpdateWebhook",
+			Handler:    _Gooser_UpdateWebhook_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _Gooser_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _Gooser_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _Gooser_ListAuditEvents_Handler,
+		},
+		{
+			MethodName: "CreateServiceAccount",
+			Handler:    _Gooser_CreateServiceAccount_Handler,
+		},
+		{
+			MethodName: "ListApiKeys",
+			Handler:    _Gooser_ListApiKeys_Handler,
+		},
+		{
+			MethodName: "CreateApiKey",
+			Handler:    _Gooser_CreateApiKey_Handler,
+		},
+		{
+			MethodName: "DeleteApiKey",
+			Handler:    _Gooser_DeleteApiKey_Handler,
+		},
+		{
+			MethodName: "ListAttributeDefinitions",
+			Handler:    _Gooser_ListAttributeDefinitions_Handler,
+		},
+		{
+			MethodName: "GetAttributeDefinition",
+			Handler:    _Gooser_GetAttributeDefinition_Handler,
+		},
+		{
+			MethodName: "CreateAttributeDefinition",
+			Handler:    _Gooser_CreateAttributeDefinition_Handler,
+		},
+		{
+			MethodName: "UpdateAttributeDefinition",
+			Handler:    _Gooser_UpdateAttributeDefinition_Handler,
+		},
+		{
+			MethodName: "DeleteAttributeDefinition",
+			Handler:    _Gooser_DeleteAttributeDefinition_Handler,
+		},
+		{
+			MethodName: "PreviewMail",
+			Handler:    _Gooser_PreviewMail_Handler,
+		},
+		{
+			MethodName: "ListMails",
+			Handler:    _Gooser_ListMails_Handler,
+		},
+		{
+			MethodName: "RetryMail",
+			Handler:    _Gooser_RetryMail_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/v1/gooser_service.proto",
@@ -2,22 +2,38 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/rbicker/gooser/internal/auth"
+	"github.com/rbicker/gooser/internal/health"
+	"github.com/rbicker/gooser/internal/logging"
 	"github.com/rbicker/gooser/internal/mailer"
+	"github.com/rbicker/gooser/internal/metrics"
+	"github.com/rbicker/gooser/internal/purge"
 	"github.com/rbicker/gooser/internal/server"
 	"github.com/rbicker/gooser/internal/store"
+	"github.com/rbicker/gooser/internal/tracing"
 	_ "github.com/rbicker/gooser/internal/translations"
 	"github.com/rbicker/gooser/internal/utils"
+	"github.com/rbicker/gooser/internal/webhooks"
+	"go.uber.org/zap"
+	grpchealth "google.golang.org/grpc/health"
 )
 
 func main() {
-	infoLogger := log.New(os.Stdout, "INFO: ", log.Lmsgprefix+log.LstdFlags)
-	errLogger := log.New(os.Stderr, "ERROR: ", log.Lmsgprefix+log.LstdFlags)
+	logger, err := logging.New(utils.LookupEnv("GOOSER_LOG_LEVEL", "info"))
+	if err != nil {
+		log.Fatalf("unable to create logger: %s", err)
+	}
+	defer logger.Sync()
 	var dbOpts []func(*store.MGO) error
 	var srvOpts []func(*server.Server) error
 	// get or create secret
@@ -25,12 +41,25 @@ func main() {
 	if s, ok := os.LookupEnv("GOOSER_SECRET"); ok {
 		secret = s
 	} else {
-		infoLogger.Printf("because no secret was given, a random string will be used")
-		infoLogger.Printf("this means things like password reset links won't survive a restart of the application")
-		infoLogger.Printf("make sure to set the GOOSER_SECRET environment variable in production")
+		logger.Info("because no secret was given, a random string will be used")
+		logger.Info("this means things like password reset links won't survive a restart of the application")
+		logger.Info("make sure to set the GOOSER_SECRET environment variable in production")
 		secret = utils.RandomString(20)
 	}
+	// metrics
+	m, err := metrics.NewMetrics()
+	if err != nil {
+		logger.Fatal("unable to create metrics", zap.Error(err))
+	}
+	// tracing
+	traceExporter := utils.LookupEnv("GOOSER_TRACING_EXPORTER", tracing.ExporterNone)
+	shutdownTracing, err := tracing.Setup(context.Background(), traceExporter, "gooser")
+	if err != nil {
+		logger.Fatal("unable to set up tracing", zap.Error(err))
+	}
 	// init db connection
+	dbOpts = append(dbOpts, store.WithLogger(logger))
+	dbOpts = append(dbOpts, store.WithMetrics(m))
 	mongoUrl := utils.LookupEnv("GOOSER_MONGO_URL", "mongodb://localhost:27017")
 	dbOpts = append(dbOpts, store.WithURL(mongoUrl))
 	dbName := utils.LookupEnv("GOOSER_MONGO_DB", "db")
@@ -39,83 +68,355 @@ func main() {
 	dbOpts = append(dbOpts, store.WithUsersCollectionName(usersColName))
 	groupsColName := utils.LookupEnv("GOOSER_MONGO_GROUPS_COLLECTION", "groups")
 	dbOpts = append(dbOpts, store.WithGroupsCollectionName(groupsColName))
+	webhooksColName := utils.LookupEnv("GOOSER_MONGO_WEBHOOKS_COLLECTION", "webhooks")
+	dbOpts = append(dbOpts, store.WithWebhooksCollectionName(webhooksColName))
+	webhookDeliveriesColName := utils.LookupEnv("GOOSER_MONGO_WEBHOOK_DELIVERIES_COLLECTION", "webhookDeliveries")
+	dbOpts = append(dbOpts, store.WithWebhookDeliveriesCollectionName(webhookDeliveriesColName))
+	auditEventsColName := utils.LookupEnv("GOOSER_MONGO_AUDIT_EVENTS_COLLECTION", "auditEvents")
+	dbOpts = append(dbOpts, store.WithAuditEventsCollectionName(auditEventsColName))
+	apiKeysColName := utils.LookupEnv("GOOSER_MONGO_API_KEYS_COLLECTION", "apiKeys")
+	dbOpts = append(dbOpts, store.WithApiKeysCollectionName(apiKeysColName))
+	attributeDefinitionsColName := utils.LookupEnv("GOOSER_MONGO_ATTRIBUTE_DEFINITIONS_COLLECTION", "attributeDefinitions")
+	dbOpts = append(dbOpts, store.WithAttributeDefinitionsCollectionName(attributeDefinitionsColName))
+	migrationsColName := utils.LookupEnv("GOOSER_MONGO_MIGRATIONS_COLLECTION", "migrations")
+	dbOpts = append(dbOpts, store.WithMigrationsCollectionName(migrationsColName))
+	mailsColName := utils.LookupEnv("GOOSER_MONGO_MAILS_COLLECTION", "mails")
+	dbOpts = append(dbOpts, store.WithMailsCollectionName(mailsColName))
 	db, err := store.NewMongoConnection(secret, dbOpts...)
 	if err != nil {
-		errLogger.Fatalf("unable to create mongodb connection: %s", err)
+		logger.Fatal("unable to create mongodb connection", zap.Error(err))
 	}
 	err = db.Connect()
 	if err != nil {
-		errLogger.Fatalf("unable to connect to mongodb: %s", err)
+		logger.Fatal("unable to connect to mongodb", zap.Error(err))
+	}
+	logger.Info("connected to mongodb")
+	// subcommands
+	if len(os.Args) > 1 {
+		if os.Args[1] != "migrate" {
+			logger.Fatal("unknown command, expected migrate", zap.String("command", os.Args[1]))
+		}
+		err := migrate(context.Background(), db, os.Args[2:], os.Stdout)
+		db.Disconnect(context.TODO())
+		if err != nil {
+			logger.Fatal("unable to migrate", zap.Error(err))
+		}
+		return
+	}
+	// schema migrations
+	migrateOnStartup, err := strconv.ParseBool(utils.LookupEnv("GOOSER_MIGRATE_ON_STARTUP", "true"))
+	if err != nil {
+		logger.Fatal("unable to parse GOOSER_MIGRATE_ON_STARTUP", zap.Error(err))
+	}
+	if migrateOnStartup {
+		if err := db.Migrate(context.Background()); err != nil {
+			logger.Fatal("unable to migrate mongodb", zap.Error(err))
+		}
+	} else {
+		pending, err := pendingMigrations(context.Background(), db)
+		if err != nil {
+			logger.Fatal("unable to get migration status", zap.Error(err))
+		}
+		if pending > 0 {
+			logger.Fatal("mongodb has pending migrations, run 'gooser-server migrate up' first", zap.Int("pending", pending))
+		}
+	}
+	// health, mongodb is required to be ready
+	healthServer := grpchealth.NewServer()
+	healthOpts := []func(*health.Monitor) error{
+		health.WithLogger(logger),
+		health.WithStatusSetter(healthServer),
+		health.WithComponent("mongodb", true, db.Ping),
 	}
-	infoLogger.Println("connected to mongodb")
 	// mailer
 	var mailClient mailer.MailClient
+	var mailViewer *mailer.Viewer
 	smtpHost, ok := os.LookupEnv("GOOSER_SMTP_HOST")
 	if ok {
 		smtpPort := utils.LookupEnv("GOOSER_SMTP_PORT", "587")
 		smtpUsername, _ := os.LookupEnv("GOOSER_SMTP_USERNAME")
 		smtpPassword, _ := os.LookupEnv("GOOSER_SMTP_PASSWORD")
-		mailClient, err = mailer.NewTLSMailer(smtpHost, smtpPort, smtpUsername, smtpPassword)
+		smtpTimeout, err := time.ParseDuration(utils.LookupEnv("GOOSER_SMTP_TIMEOUT", "30s"))
 		if err != nil {
-			log.Fatalf("error while creating mail client: %s", err)
+			logger.Fatal("unable to parse GOOSER_SMTP_TIMEOUT", zap.Error(err))
+		}
+		smtpIdleTimeout, err := time.ParseDuration(utils.LookupEnv("GOOSER_SMTP_IDLE_TIMEOUT", "30s"))
+		if err != nil {
+			logger.Fatal("unable to parse GOOSER_SMTP_IDLE_TIMEOUT", zap.Error(err))
+		}
+		smtpOpts := []func(*mailer.SMTPMailer) error{
+			mailer.WithSMTPAuth(smtpUsername, smtpPassword),
+			mailer.WithSMTPHelo(utils.LookupEnv("GOOSER_SMTP_HELO", "")),
+			mailer.WithSMTPTimeout(smtpTimeout),
+			mailer.WithSMTPIdleTimeout(smtpIdleTimeout),
+		}
+		// by default, implicit tls is used for port 465 and starttls for any other port
+		if security, ok := os.LookupEnv("GOOSER_SMTP_SECURITY"); ok {
+			smtpOpts = append(smtpOpts, mailer.WithSMTPSecurity(security))
+		}
+		smtpMailer, err := mailer.NewSMTPMailer(smtpHost, smtpPort, smtpOpts...)
+		if err != nil {
+			logger.Fatal("error while creating mail client", zap.Error(err))
+		}
+		defer smtpMailer.Close()
+		mailClient = smtpMailer
+		// smtp is reported but not required to be ready
+		healthOpts = append(healthOpts, health.WithComponent("smtp", false, smtpMailer.Ping))
+	} else if captureDir, ok := os.LookupEnv("GOOSER_MAIL_CAPTURE_DIR"); ok {
+		logger.Info("no SMTP settings given, capturing mails in a directory", zap.String("dir", captureDir))
+		switch format := utils.LookupEnv("GOOSER_MAIL_CAPTURE_FORMAT", "eml"); format {
+		case "eml":
+			mailClient, err = mailer.NewFileMailer(captureDir)
+		case "maildir":
+			mailClient, err = mailer.NewMaildirMailer(captureDir)
+		default:
+			err = fmt.Errorf("unknown format %s, expected eml or maildir", format)
+		}
+		if err != nil {
+			logger.Fatal("unable to create mail capture", zap.Error(err))
+		}
+		mailViewer, err = mailer.NewViewer(captureDir, mailer.WithViewerLogger(logger))
+		if err != nil {
+			logger.Fatal("unable to create mail viewer", zap.Error(err))
 		}
 	} else {
-		infoLogger.Println("no SMTP settings given, sending mails by logging them to stdout")
-		infoLogger.Println("to send real mails, have a look at the GOOSER_SMTP_* environment variables")
-		mailClient = mailer.NewLogMailer(infoLogger)
+		logger.Info("no SMTP settings given, sending mails by logging them to stdout")
+		logger.Info("to send real mails, have a look at the GOOSER_SMTP_* environment variables")
+		mailClient = mailer.NewLogMailer(logger)
 	}
 	mailFrom, _ := os.LookupEnv("GOOSER_MAIL_FROM")
+	// mails are signed before they are handed to the transport
+	if dkimKeyFile, ok := os.LookupEnv("GOOSER_DKIM_KEY_FILE"); ok {
+		if mailFrom == "" {
+			logger.Fatal("GOOSER_MAIL_FROM is required for dkim signing")
+		}
+		dkimKey, err := mailer.LoadDKIMKey(dkimKeyFile)
+		if err != nil {
+			logger.Fatal("unable to load dkim key", zap.Error(err))
+		}
+		// the domain defaults to the domain of the from address
+		dkimDomain := utils.LookupEnv("GOOSER_DKIM_DOMAIN", strings.TrimRight(mailFrom[strings.LastIndex(mailFrom, "@")+1:], "> "))
+		dkimOpts := []func(*mailer.DKIMSigner) error{
+			mailer.WithDKIMCanonicalization(utils.LookupEnv("GOOSER_DKIM_CANONICALIZATION", "relaxed/relaxed")),
+		}
+		if headers, ok := os.LookupEnv("GOOSER_DKIM_HEADERS"); ok {
+			dkimOpts = append(dkimOpts, mailer.WithDKIMHeaders(strings.Split(headers, ",")))
+		}
+		mailClient, err = mailer.NewDKIMSigner(mailClient, dkimDomain, utils.LookupEnv("GOOSER_DKIM_SELECTOR", "gooser"), dkimKey, dkimOpts...)
+		if err != nil {
+			logger.Fatal("unable to create dkim signer", zap.Error(err))
+		}
+	}
+	// mails are queued in mongodb and sent in the background
+	mailMaxAttempts, err := strconv.Atoi(utils.LookupEnv("GOOSER_MAIL_MAX_ATTEMPTS", "10"))
+	if err != nil {
+		logger.Fatal("unable to convert GOOSER_MAIL_MAX_ATTEMPTS to number", zap.Error(err))
+	}
+	mailQueue, err := mailer.NewQueue(db, mailClient, mailer.WithQueueLogger(logger), mailer.WithQueueMaxAttempts(int32(mailMaxAttempts)))
+	if err != nil {
+		logger.Fatal("unable to create mail queue", zap.Error(err))
+	}
+	srvOpts = append(srvOpts, server.WithMailStore(db))
+	srvOpts = append(srvOpts, server.WithMailQueue(mailQueue))
 	siteName := utils.LookupEnv("GOOSER_SITE_NAME", "gooser")
 	confirmUrl := utils.LookupEnv("GOOSER_CONFIRM_URL", "http://localhost:1234/#/confirm-mail")
 	resetPasswordUrl := utils.LookupEnv("GOOSER_RESET_PASSWORD_URL", "http://localhost:1234/#/reset-password")
-	mailer, err := mailer.NewMailer(mailClient, mailFrom, siteName, confirmUrl, resetPasswordUrl)
+	invitationUrl := utils.LookupEnv("GOOSER_INVITATION_URL", "http://localhost:1234/#/accept-invitation")
+	revertMailUrl := utils.LookupEnv("GOOSER_REVERT_MAIL_URL", "http://localhost:1234/#/revert-mail")
+	// templates are loaded from the given directory, falling back to the embedded defaults
+	templates, err := mailer.LoadTemplates(utils.LookupEnv("GOOSER_MAIL_TEMPLATES_DIR", ""))
+	if err != nil {
+		logger.Fatal("unable to load mail templates", zap.Error(err))
+	}
+	mailerOpts := []func(*mailer.Mailer) error{
+		mailer.WithLogger(logger),
+		mailer.WithMetrics(m),
+		mailer.WithInvitationUrl(invitationUrl),
+		mailer.WithRevertMailUrl(revertMailUrl),
+		mailer.WithTemplates(templates),
+		mailer.WithLogoUrl(utils.LookupEnv("GOOSER_SITE_LOGO_URL", "")),
+		mailer.WithSupportAddress(utils.LookupEnv("GOOSER_SUPPORT_ADDRESS", "")),
+	}
+	mailer, err := mailer.NewMailer(mailQueue, mailFrom, siteName, confirmUrl, resetPasswordUrl, mailerOpts...)
+	if err != nil {
+		logger.Fatal("error while creating mailer", zap.Error(err))
+	}
+	// webhooks
+	var dispatcherOpts []func(*webhooks.Dispatcher) error
+	maxAttempts, err := strconv.Atoi(utils.LookupEnv("GOOSER_WEBHOOK_MAX_ATTEMPTS", "10"))
+	if err != nil {
+		logger.Fatal("unable to convert GOOSER_WEBHOOK_MAX_ATTEMPTS to number", zap.Error(err))
+	}
+	dispatcherOpts = append(dispatcherOpts, webhooks.WithLogger(logger))
+	dispatcherOpts = append(dispatcherOpts, webhooks.WithMaxAttempts(int32(maxAttempts)))
+	dispatcher, err := webhooks.NewDispatcher(db, dispatcherOpts...)
+	if err != nil {
+		logger.Fatal("unable to create webhook dispatcher", zap.Error(err))
+	}
+	srvOpts = append(srvOpts, server.WithWebhookStore(db))
+	srvOpts = append(srvOpts, server.WithEventEmitter(dispatcher))
+	// audit log
+	srvOpts = append(srvOpts, server.WithAuditStore(db))
+	// service accounts
+	srvOpts = append(srvOpts, server.WithApiKeyStore(db))
+	// custom user attributes
+	srvOpts = append(srvOpts, server.WithAttributeStore(db))
+	// purge soft deleted users and groups after the retention period, 0 disables purging
+	retention, err := time.ParseDuration(utils.LookupEnv("GOOSER_DELETED_RETENTION", "720h"))
+	if err != nil {
+		logger.Fatal("unable to parse GOOSER_DELETED_RETENTION", zap.Error(err))
+	}
+	purgeInterval, err := time.ParseDuration(utils.LookupEnv("GOOSER_PURGE_INTERVAL", "1h"))
+	if err != nil {
+		logger.Fatal("unable to parse GOOSER_PURGE_INTERVAL", zap.Error(err))
+	}
+	// failed mails contain tokens, their contents are purged after the retention period, 0 disables purging
+	failedMailRetention, err := time.ParseDuration(utils.LookupEnv("GOOSER_FAILED_MAIL_RETENTION", "72h"))
+	if err != nil {
+		logger.Fatal("unable to parse GOOSER_FAILED_MAIL_RETENTION", zap.Error(err))
+	}
+	// cooling-off period of account deletions requested by users
+	accountDeletionDelay, err := time.ParseDuration(utils.LookupEnv("GOOSER_ACCOUNT_DELETION_DELAY", "168h"))
+	if err != nil {
+		logger.Fatal("unable to parse GOOSER_ACCOUNT_DELETION_DELAY", zap.Error(err))
+	}
+	srvOpts = append(srvOpts, server.WithAccountDeletionDelay(accountDeletionDelay))
+	// how long invitations can be accepted
+	invitationTTL, err := time.ParseDuration(utils.LookupEnv("GOOSER_INVITATION_TTL", "168h"))
+	if err != nil {
+		logger.Fatal("unable to parse GOOSER_INVITATION_TTL", zap.Error(err))
+	}
+	srvOpts = append(srvOpts, server.WithInvitationTTL(invitationTTL))
+	// how long mail address changes can be reverted
+	mailRevertTTL, err := time.ParseDuration(utils.LookupEnv("GOOSER_MAIL_REVERT_TTL", "168h"))
 	if err != nil {
-		log.Fatalf("error while creating mailer: %s", err)
+		logger.Fatal("unable to parse GOOSER_MAIL_REVERT_TTL", zap.Error(err))
 	}
+	srvOpts = append(srvOpts, server.WithMailRevertTTL(mailRevertTTL))
 	// init server
+	monitor, err := health.NewMonitor(healthOpts...)
+	if err != nil {
+		logger.Fatal("unable to create health monitor", zap.Error(err))
+	}
+	srvOpts = append(srvOpts, server.WithLogger(logger))
+	srvOpts = append(srvOpts, server.WithHealthServer(healthServer))
+	srvOpts = append(srvOpts, server.WithMetrics(m))
 	srvOpts = append(srvOpts, server.EnableReflection())
+	// tls
+	if certFile, ok := os.LookupEnv("GOOSER_TLS_CERT_FILE"); ok {
+		keyFile := utils.LookupEnv("GOOSER_TLS_KEY_FILE", "")
+		srvOpts = append(srvOpts, server.WithTLS(certFile, keyFile))
+		if caFile, ok := os.LookupEnv("GOOSER_TLS_CLIENT_CA_FILE"); ok {
+			srvOpts = append(srvOpts, server.WithClientCA(caFile))
+		}
+		// comma separated list of common-name=username pairs
+		if principals, ok := os.LookupEnv("GOOSER_TLS_SERVICE_PRINCIPALS"); ok {
+			for _, p := range strings.Split(principals, ",") {
+				ss := strings.SplitN(strings.TrimSpace(p), "=", 2)
+				if len(ss) != 2 {
+					logger.Fatal("invalid service principal, expected common-name=username", zap.String("principal", p))
+				}
+				srvOpts = append(srvOpts, server.WithServicePrincipal(ss[0], ss[1]))
+			}
+		}
+	}
 	p := utils.LookupEnv("GOOSER_PORT", "50051")
 	srvOpts = append(srvOpts, server.SetPort(p))
 	oauthUrl := utils.LookupEnv("GOOSER_OAUTH_URL", "http://localhost:4444")
-	oAuth, err := auth.NewOAuthClient(oauthUrl)
+	oAuth, err := auth.NewOAuthClient(oauthUrl, auth.WithMetrics(m))
 	if err != nil {
-		errLogger.Fatalf("unable to create oAuth client: %s", err)
+		logger.Fatal("unable to create oAuth client", zap.Error(err))
 	}
 	srv, err := server.NewServer(secret, db, oAuth, mailer, srvOpts...)
 	if err != nil {
-		errLogger.Fatalf("unable to create new gooser server: %s", err)
+		logger.Fatal("unable to create new gooser server", zap.Error(err))
+	}
+	// delete scheduled accounts, purge deleted documents and the contents of failed mails
+	purger, err := purge.NewPurger(db, purge.WithLogger(logger), purge.WithAccountDeleter(srv), purge.WithRetention(retention), purge.WithFailedMails(db, failedMailRetention), purge.WithInterval(purgeInterval))
+	if err != nil {
+		logger.Fatal("unable to create purger", zap.Error(err))
 	}
 	// init collections
 	err = srv.InitCollections(context.Background())
 	if err != nil {
-		errLogger.Fatalf("unable to initialize collections: %s", err)
+		logger.Fatal("unable to initialize collections", zap.Error(err))
 	}
 	// channels
 	errChan := make(chan error)
-	stopChan := make(chan os.Signal)
+	stopChan := make(chan os.Signal, 1)
 	// bind OS events to the signal channel
 	signal.Notify(stopChan, syscall.SIGTERM, syscall.SIGINT)
+	// send mails, deliver webhooks, check health, delete scheduled accounts and purge deleted documents in the background
+	mailQueue.Start()
+	dispatcher.Start()
+	monitor.Start()
+	purger.Start()
 	// serve in a go routine
 	go func() {
-		infoLogger.Println("starting gooser server")
+		logger.Info("starting gooser server")
 		if err := srv.Serve(); err != nil {
 			errChan <- err
 		}
 	}()
+	// serve metrics and health endpoints in a go routine
+	httpPort := utils.LookupEnv("GOOSER_HTTP_PORT", "9090")
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	mux.Handle("/healthz", monitor.LivenessHandler())
+	mux.Handle("/readyz", monitor.ReadinessHandler())
+	httpSrv := &http.Server{
+		Addr:    fmt.Sprintf("0.0.0.0:%s", httpPort),
+		Handler: mux,
+	}
+	go func() {
+		logger.Info("serving metrics and health endpoints", zap.String("port", httpPort))
+		if err := httpSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errChan <- err
+		}
+	}()
+	// the captured mails contain tokens, so the viewer has its own listener, bound to localhost by default
+	var viewerSrv *http.Server
+	if mailViewer != nil {
+		viewerSrv = &http.Server{
+			Addr:    utils.LookupEnv("GOOSER_MAIL_VIEWER_ADDR", "127.0.0.1:9091"),
+			Handler: mailViewer.Handler(),
+		}
+		go func() {
+			logger.Warn("serving captured mails without authentication, they contain valid tokens", zap.String("addr", viewerSrv.Addr))
+			if err := viewerSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errChan <- err
+			}
+		}()
+	}
 	// terminate gracefully before leaving the main function
 	defer func() {
-		infoLogger.Println("stopping grpc server")
+		logger.Info("stopping grpc server and draining mail queue")
 		srv.Stop()
-		infoLogger.Println("disconnecting from mongodb")
+		logger.Info("stopping http server")
+		httpSrv.Shutdown(context.TODO())
+		if viewerSrv != nil {
+			viewerSrv.Shutdown(context.TODO())
+		}
+		logger.Info("stopping health monitor")
+		monitor.Stop()
+		logger.Info("stopping webhook dispatcher")
+		dispatcher.Stop()
+		logger.Info("stopping purger")
+		purger.Stop()
+		logger.Info("flushing traces")
+		if err := shutdownTracing(context.TODO()); err != nil {
+			logger.Error("error while flushing traces", zap.Error(err))
+		}
+		logger.Info("disconnecting from mongodb")
 		err := db.Disconnect(context.TODO())
 		if err != nil {
-			errLogger.Fatalf("error while disconnecting from mongodb: %s", err)
+			logger.Fatal("error while disconnecting from mongodb", zap.Error(err))
 		}
 	}()
 	// block until either OS signal, or server fatal error
 	select {
 	case err := <-errChan:
-		errLogger.Printf("Fatal error: %v\n", err)
+		logger.Error("fatal error", zap.Error(err))
 	case <-stopChan:
 	}
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rbicker/gooser/internal/store"
+)
+
+// migrate runs the migrate subcommand with the given arguments,
+// either applying the pending migrations or printing their status.
+func migrate(ctx context.Context, db *store.MGO, args []string, w io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: gooser-server migrate up|status")
+	}
+	switch args[0] {
+	case "up":
+		return db.Migrate(ctx)
+	case "status":
+		statuses, err := db.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tAPPLIED AT\tDESCRIPTION")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", s.Version, appliedAt, s.Description)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command '%s', expected up or status", args[0])
+	}
+}
+
+// pendingMigrations returns the number of migrations which have not been applied yet.
+func pendingMigrations(ctx context.Context, db *store.MGO) (int, error) {
+	statuses, err := db.MigrationStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var pending int
+	for _, s := range statuses {
+		if !s.Applied {
+			pending++
+		}
+	}
+	return pending, nil
+}
@@ -1,9 +1,16 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
+
+	"github.com/rbicker/gooser/internal/metrics"
+	"github.com/rbicker/gooser/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -11,36 +18,61 @@ import (
 
 // UserLookup describes functions for querying user.
 type UserLookup interface {
-	GetUserIDbyToken(token string) (string, error)
+	GetUserIDbyToken(ctx context.Context, token string) (string, error)
 }
 
 // OAuth implements the UserLookup interface.
 type OAuth struct {
-	url string
+	url     string
+	metrics *metrics.Metrics
 }
 
 // ensure OAuth implements the UserLookup interface.
 var _ UserLookup = &OAuth{}
 
 // NewOAuthClient returns a new hydra client for the given url.
-func NewOAuthClient(url string) (*OAuth, error) {
-	return &OAuth{
+// It takes functional parameters to change default options.
+func NewOAuthClient(url string, opts ...func(*OAuth) error) (*OAuth, error) {
+	a := OAuth{
 		url: url,
-	}, nil
+	}
+	// run functional options
+	for _, op := range opts {
+		err := op(&a)
+		if err != nil {
+			return nil, fmt.Errorf("setting option failed: %w", err)
+		}
+	}
+	return &a, nil
+}
+
+// WithMetrics sets the metrics used to observe the latency of the user lookups.
+func WithMetrics(m *metrics.Metrics) func(*OAuth) error {
+	return func(a *OAuth) error {
+		a.metrics = m
+		return nil
+	}
 }
 
 // GetUserIDbyToken queries the user id from UserLookup using the given access token.
-func (a *OAuth) GetUserIDbyToken(accessToken string) (string, error) {
+// The trace context is propagated to the oauth server.
+func (a *OAuth) GetUserIDbyToken(ctx context.Context, accessToken string) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "auth.GetUserIDbyToken")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		a.metrics.ObserveAuthLookup(start, err)
+	}(time.Now())
 	headers := map[string][]string{
 		"Accept":        []string{"application/json"},
 		"Authorization": []string{fmt.Sprintf("Bearer %s", accessToken)},
 	}
 	// query user info from oauthClient
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/userinfo", a.url), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/userinfo", a.url), nil)
 	if err != nil {
 		return "", status.Errorf(codes.Internal, "unable to create http request for querying user info")
 	}
 	req.Header = headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 	client := &http.Client{}
 	res, err := client.Do(req)
 	if err != nil {
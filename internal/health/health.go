@@ -0,0 +1,255 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rbicker/gooser/internal/logging"
+	"go.uber.org/zap"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Check checks the health of a component and returns an error if it is not healthy.
+type Check func(ctx context.Context) error
+
+// StatusSetter describes the functions to publish the serving status of a service,
+// as implemented by the grpc health server.
+type StatusSetter interface {
+	SetServingStatus(service string, servingStatus healthpb.HealthCheckResponse_ServingStatus)
+}
+
+// component is a dependency of gooser which is checked periodically.
+type component struct {
+	name     string
+	required bool
+	check    Check
+}
+
+// ComponentStatus is the result of the last check of a component.
+type ComponentStatus struct {
+	Healthy   bool      `json:"healthy"`
+	Required  bool      `json:"required"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Monitor checks the components periodically. Gooser is ready
+// if all the required components are healthy.
+type Monitor struct {
+	components   []component
+	statusSetter StatusSetter
+	logger       *zap.Logger
+	interval     time.Duration
+	timeout      time.Duration
+	mu           sync.RWMutex
+	statuses     map[string]ComponentStatus
+	stop         chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewMonitor creates a new health monitor.
+// It takes functional parameters to change default options.
+func NewMonitor(opts ...func(*Monitor) error) (*Monitor, error) {
+	var m = Monitor{
+		interval: 10 * time.Second,
+		timeout:  2 * time.Second,
+		statuses: make(map[string]ComponentStatus),
+	}
+	// run functional options
+	for _, op := range opts {
+		err := op(&m)
+		if err != nil {
+			return nil, fmt.Errorf("setting option failed: %w", err)
+		}
+	}
+	// default logger
+	if m.logger == nil {
+		m.logger = logging.Default()
+	}
+	return &m, nil
+}
+
+// CheckAll checks all the components once and publishes the resulting serving statuses.
+// The overall status is published using the empty service name, each component using its name.
+func (m *Monitor) CheckAll(ctx context.Context) {
+	for _, c := range m.components {
+		checkCtx, cancel := context.WithTimeout(ctx, m.timeout)
+		err := c.check(checkCtx)
+		cancel()
+		status := ComponentStatus{
+			Healthy:   err == nil,
+			Required:  c.required,
+			CheckedAt: time.Now(),
+		}
+		if err != nil {
+			status.Error = err.Error()
+		}
+		m.mu.Lock()
+		previous, checked := m.statuses[c.name]
+		m.statuses[c.name] = status
+		m.mu.Unlock()
+		if err != nil && (!checked || previous.Healthy) {
+			m.logger.Error("health check failed", zap.String("component", c.name), zap.Error(err))
+		}
+		m.publish(c.name, status.Healthy)
+	}
+	m.publish("", m.Ready())
+}
+
+// publish sets the serving status of the given service if a status setter is configured.
+func (m *Monitor) publish(service string, healthy bool) {
+	if m.statusSetter == nil {
+		return
+	}
+	servingStatus := healthpb.HealthCheckResponse_NOT_SERVING
+	if healthy {
+		servingStatus = healthpb.HealthCheckResponse_SERVING
+	}
+	m.statusSetter.SetServingStatus(service, servingStatus)
+}
+
+// Ready returns true if all the required components were healthy during the last check.
+// Components which have not been checked yet are considered unhealthy.
+func (m *Monitor) Ready() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, c := range m.components {
+		if !c.required {
+			continue
+		}
+		if s, ok := m.statuses[c.name]; !ok || !s.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// Statuses returns the results of the last checks by component name.
+func (m *Monitor) Statuses() map[string]ComponentStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	statuses := make(map[string]ComponentStatus, len(m.statuses))
+	for k, v := range m.statuses {
+		statuses[k] = v
+	}
+	return statuses
+}
+
+// Start checks the components immediately and then periodically in the background.
+func (m *Monitor) Start() {
+	m.stop = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer cancel()
+		t := time.NewTicker(m.interval)
+		defer t.Stop()
+		for {
+			m.CheckAll(ctx)
+			select {
+			case <-m.stop:
+				return
+			case <-t.C:
+			}
+		}
+	}()
+}
+
+// Stop stops checking the components and waits for a running check to finish.
+func (m *Monitor) Stop() {
+	if m.stop == nil {
+		return
+	}
+	close(m.stop)
+	m.wg.Wait()
+}
+
+// LivenessHandler returns a http handler which always responds with ok
+// as long as the process is able to serve http requests.
+func (m *Monitor) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("ok\n"))
+	})
+}
+
+// ReadinessHandler returns a http handler which responds with the status of
+// every component. The status code is 503 if gooser is not ready.
+func (m *Monitor) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := struct {
+			Ready      bool                       `json:"ready"`
+			Components map[string]ComponentStatus `json:"components"`
+		}{
+			Ready:      m.Ready(),
+			Components: m.Statuses(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !res.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			m.logger.Error("unable to encode readiness response", zap.Error(err))
+		}
+	})
+}
+
+// WithComponent adds a component which is checked using the given function.
+// If the component is required, gooser is not ready as long as the component is unhealthy.
+func WithComponent(name string, required bool, check Check) func(*Monitor) error {
+	return func(m *Monitor) error {
+		if name == "" {
+			return fmt.Errorf("component name cannot be empty")
+		}
+		m.components = append(m.components, component{
+			name:     name,
+			required: required,
+			check:    check,
+		})
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the monitor.
+func WithLogger(logger *zap.Logger) func(*Monitor) error {
+	return func(m *Monitor) error {
+		m.logger = logger
+		return nil
+	}
+}
+
+// WithStatusSetter sets the status setter, usually the grpc health server,
+// which is informed about changes of the serving status.
+func WithStatusSetter(statusSetter StatusSetter) func(*Monitor) error {
+	return func(m *Monitor) error {
+		m.statusSetter = statusSetter
+		return nil
+	}
+}
+
+// WithInterval changes the interval in which the components are checked.
+func WithInterval(interval time.Duration) func(*Monitor) error {
+	return func(m *Monitor) error {
+		if interval <= 0 {
+			return fmt.Errorf("interval needs to be greater than 0")
+		}
+		m.interval = interval
+		return nil
+	}
+}
+
+// WithTimeout changes the timeout of a single check.
+func WithTimeout(timeout time.Duration) func(*Monitor) error {
+	return func(m *Monitor) error {
+		if timeout <= 0 {
+			return fmt.Errorf("timeout needs to be greater than 0")
+		}
+		m.timeout = timeout
+		return nil
+	}
+}
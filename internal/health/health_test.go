@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestMonitor(t *testing.T) {
+	var mongoErr, smtpErr error
+	healthServer := grpchealth.NewServer()
+	m, err := NewMonitor(
+		WithStatusSetter(healthServer),
+		WithComponent("mongodb", true, func(ctx context.Context) error { return mongoErr }),
+		WithComponent("smtp", false, func(ctx context.Context) error { return smtpErr }),
+	)
+	if err != nil {
+		t.Fatalf("unable to create monitor: %s", err)
+	}
+	servingStatus := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		res, err := healthServer.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("unable to check service '%s': %s", service, err)
+		}
+		return res.GetStatus()
+	}
+	readyz := func() int {
+		rec := httptest.NewRecorder()
+		m.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+		return rec.Code
+	}
+	tests := []struct {
+		name      string
+		mongoErr  error
+		smtpErr   error
+		wantReady bool
+		wantCode  int
+	}{
+		{
+			name:      "all healthy",
+			wantReady: true,
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "smtp unreachable",
+			smtpErr:   errors.New("connection refused"),
+			wantReady: true,
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "mongodb unreachable",
+			mongoErr:  errors.New("server selection timeout"),
+			wantReady: false,
+			wantCode:  http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			mongoErr, smtpErr = tt.mongoErr, tt.smtpErr
+			m.CheckAll(context.Background())
+			assert.Equal(tt.wantReady, m.Ready(), "ready mismatch")
+			assert.Equal(tt.wantCode, readyz(), "readiness status code mismatch")
+			wantStatus := healthpb.HealthCheckResponse_NOT_SERVING
+			if tt.wantReady {
+				wantStatus = healthpb.HealthCheckResponse_SERVING
+			}
+			assert.Equal(wantStatus, servingStatus(""), "overall serving status mismatch")
+			assert.Equal(tt.smtpErr == nil, m.Statuses()["smtp"].Healthy, "smtp status mismatch")
+		})
+	}
+}
+
+func TestReadyBeforeCheck(t *testing.T) {
+	m, err := NewMonitor(WithComponent("mongodb", true, func(ctx context.Context) error { return nil }))
+	if err != nil {
+		t.Fatalf("unable to create monitor: %s", err)
+	}
+	assert.False(t, m.Ready(), "unchecked components should not be ready")
+}
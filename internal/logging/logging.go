@@ -0,0 +1,148 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIdKey is the metadata key of the request id.
+const RequestIdKey = "x-request-id"
+
+// maxRequestIdLength is the maximum length of a request id given by the client.
+const maxRequestIdLength = 128
+
+// New returns a logger writing json to stdout using the given level
+// (debug, info, warn or error).
+func New(level string) (*zap.Logger, error) {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level '%s': %w", level, err)
+	}
+	config := zap.NewProductionConfig()
+	config.Level = zap.NewAtomicLevelAt(l)
+	config.OutputPaths = []string{"stdout"}
+	config.EncoderConfig.TimeKey = "time"
+	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	return config.Build()
+}
+
+// Default returns the logger used if no logger is configured.
+func Default() *zap.Logger {
+	l, err := New("info")
+	if err != nil {
+		return zap.NewNop()
+	}
+	return l
+}
+
+// contextKey is the type of the context keys of this package.
+type contextKey struct{}
+
+// request holds the request scoped logger and the information
+// gathered while handling the request.
+type request struct {
+	mu             sync.Mutex
+	logger         *zap.Logger
+	userId         string
+	impersonatorId string
+}
+
+// FromContext returns the request scoped logger from the given context
+// or the given fallback logger if the context does not belong to a request.
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if r, ok := ctx.Value(contextKey{}).(*request); ok {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.logger
+	}
+	return fallback
+}
+
+// SetUserId records the id of the authenticated user for the request of the given context.
+func SetUserId(ctx context.Context, userId string) {
+	if r, ok := ctx.Value(contextKey{}).(*request); ok {
+		r.mu.Lock()
+		r.userId = userId
+		r.mu.Unlock()
+	}
+}
+
+// SetImpersonatorId records the id of the admin impersonating the authenticated user
+// for the request of the given context. Messages logged afterwards contain the impersonator id.
+func SetImpersonatorId(ctx context.Context, impersonatorId string) {
+	if r, ok := ctx.Value(contextKey{}).(*request); ok {
+		r.mu.Lock()
+		if r.impersonatorId != impersonatorId {
+			r.impersonatorId = impersonatorId
+			r.logger = r.logger.With(zap.String("impersonatorId", impersonatorId))
+		}
+		r.mu.Unlock()
+	}
+}
+
+// UnaryServerInterceptor returns a grpc interceptor which takes the request id from the
+// incoming metadata or generates a new one, returns it as response header and puts a logger
+// with the request id into the context. After the request is handled, the method, user id, duration and
+// status are logged, together with the impersonator id if the user was impersonated.
+// Neither the request, the response nor the metadata are logged as they might contain secrets.
+func UnaryServerInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		var requestId string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(RequestIdKey); len(values) > 0 && len(values[0]) <= maxRequestIdLength {
+				requestId = values[0]
+			}
+		}
+		if requestId == "" {
+			requestId = newRequestId()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIdKey, requestId))
+		r := &request{
+			logger: logger.With(zap.String("requestId", requestId)),
+		}
+		resp, err := handler(context.WithValue(ctx, contextKey{}, r), req)
+		code := status.Code(err)
+		r.mu.Lock()
+		logger := r.logger
+		fields := []zap.Field{
+			zap.String("method", info.FullMethod),
+			zap.String("userId", r.userId),
+			zap.Duration("duration", time.Since(start)),
+			zap.String("code", code.String()),
+		}
+		r.mu.Unlock()
+		if err != nil {
+			fields = append(fields, zap.String("error", status.Convert(err).Message()))
+		}
+		switch code {
+		case codes.OK:
+			logger.Info("handled request", fields...)
+		case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable, codes.DeadlineExceeded:
+			logger.Error("handled request", fields...)
+		default:
+			logger.Warn("handled request", fields...)
+		}
+		return resp, err
+	}
+}
+
+// newRequestId returns a random request id.
+func newRequestId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
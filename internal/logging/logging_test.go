@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/gooser.v1.Gooser/ChangePassword"}
+	tests := []struct {
+		name          string
+		md            metadata.MD
+		err           error
+		wantRequestId string
+		wantLevel     zapcore.Level
+		wantCode      string
+	}{
+		{
+			name:          "given request id",
+			md:            metadata.Pairs(RequestIdKey, "abc", "access_token", "secret-token"),
+			wantRequestId: "abc",
+			wantLevel:     zapcore.InfoLevel,
+			wantCode:      "OK",
+		},
+		{
+			name:      "generated request id",
+			md:        metadata.Pairs("access_token", "secret-token"),
+			err:       status.Errorf(codes.InvalidArgument, "invalid password"),
+			wantLevel: zapcore.WarnLevel,
+			wantCode:  "InvalidArgument",
+		},
+		{
+			name:      "internal error",
+			err:       status.Errorf(codes.Internal, "database down"),
+			wantLevel: zapcore.ErrorLevel,
+			wantCode:  "Internal",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			core, logs := observer.New(zapcore.DebugLevel)
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			_, err := UnaryServerInterceptor(zap.New(core))(ctx, "new-password", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				SetUserId(ctx, "user1")
+				FromContext(ctx, nil).Info("inside handler")
+				return nil, tt.err
+			})
+			assert.Equal(tt.err, err)
+			entries := logs.AllUntimed()
+			if !assert.Len(entries, 2) {
+				return
+			}
+			handlerRequestId := entries[0].ContextMap()["requestId"].(string)
+			if tt.wantRequestId != "" {
+				assert.Equal(tt.wantRequestId, handlerRequestId)
+			} else {
+				assert.Len(handlerRequestId, 32, "request id should be generated")
+			}
+			fields := entries[1].ContextMap()
+			assert.Equal(tt.wantLevel, entries[1].Level)
+			assert.Equal(handlerRequestId, fields["requestId"])
+			assert.Equal(info.FullMethod, fields["method"])
+			assert.Equal("user1", fields["userId"])
+			assert.Equal(tt.wantCode, fields["code"])
+			assert.Contains(fields, "duration")
+			// neither the request nor the metadata must be logged
+			for _, e := range entries {
+				for _, v := range e.ContextMap() {
+					if s, ok := v.(string); ok {
+						assert.NotContains(s, "secret-token")
+						assert.NotContains(s, "new-password")
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	fallback := zap.NewNop()
+	assert.Equal(t, fallback, FromContext(context.Background(), fallback))
+	// setting the user id outside of a request does nothing
+	SetUserId(context.Background(), "user1")
+}
+
+func TestSetImpersonatorId(t *testing.T) {
+	assert := assert.New(t)
+	core, logs := observer.New(zapcore.DebugLevel)
+	info := &grpc.UnaryServerInfo{FullMethod: "/gooser.v1.Gooser/GetUser"}
+	UnaryServerInterceptor(zap.New(core))(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		FromContext(ctx, nil).Info("before impersonation")
+		SetUserId(ctx, "user1")
+		SetImpersonatorId(ctx, "admin1")
+		SetImpersonatorId(ctx, "admin1")
+		FromContext(ctx, nil).Info("while impersonating")
+		return nil, nil
+	})
+	entries := logs.AllUntimed()
+	if !assert.Len(entries, 3) {
+		return
+	}
+	assert.NotContains(entries[0].ContextMap(), "impersonatorId")
+	for _, e := range entries[1:] {
+		assert.Equal("admin1", e.ContextMap()["impersonatorId"])
+	}
+	// the field is only added once
+	assert.Len(entries[2].Context, 6)
+}
@@ -0,0 +1,310 @@
+package mailer
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// canonicalization algorithms of DKIM signatures.
+const (
+	DKIMSimple  = "simple"
+	DKIMRelaxed = "relaxed"
+)
+
+// DefaultDKIMHeaders are the headers signed by default. Headers which are
+// missing in a message are signed as well, so they cannot be added later.
+var DefaultDKIMHeaders = []string{
+	"From",
+	"Reply-To",
+	"Subject",
+	"Date",
+	"Message-ID",
+	"To",
+	"Cc",
+	"MIME-Version",
+	"Content-Type",
+	"Content-Transfer-Encoding",
+}
+
+// DKIMSigner implements the MailClient interface. It adds a DKIM signature
+// to every message and sends it using the wrapped mail client.
+type DKIMSigner struct {
+	client          MailClient
+	domain          string
+	selector        string
+	key             crypto.Signer
+	headers         []string
+	headerCanonical string
+	bodyCanonical   string
+	now             func() time.Time
+}
+
+// ensure DKIMSigner implements the MailClient interface.
+var _ MailClient = &DKIMSigner{}
+
+// NewDKIMSigner creates a new DKIM signer signing the messages for the given domain
+// using the given selector and key, which has to be an RSA or Ed25519 private key.
+// It takes functional parameters to change default options.
+func NewDKIMSigner(client MailClient, domain, selector string, key crypto.Signer, opts ...func(*DKIMSigner) error) (*DKIMSigner, error) {
+	switch key.(type) {
+	case *rsa.PrivateKey, ed25519.PrivateKey:
+	default:
+		return nil, fmt.Errorf("unsupported dkim key type %T, expected rsa or ed25519", key)
+	}
+	if domain == "" || selector == "" {
+		return nil, fmt.Errorf("dkim domain and selector must not be empty")
+	}
+	var s = DKIMSigner{
+		client:          client,
+		domain:          domain,
+		selector:        selector,
+		key:             key,
+		headers:         DefaultDKIMHeaders,
+		headerCanonical: DKIMRelaxed,
+		bodyCanonical:   DKIMRelaxed,
+		now:             time.Now,
+	}
+	// run functional options
+	for _, op := range opts {
+		err := op(&s)
+		if err != nil {
+			return nil, fmt.Errorf("setting option failed: %w", err)
+		}
+	}
+	return &s, nil
+}
+
+// LoadDKIMKey loads a PEM encoded RSA or Ed25519 private key from the given file,
+// either in PKCS#8 or, for RSA keys, in PKCS#1 format.
+func LoadDKIMKey(file string) (crypto.Signer, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read dkim key file: %w", err)
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no pem encoded key found in %s", file)
+	}
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse rsa key from %s: %w", file, err)
+		}
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse key from %s: %w", file, err)
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("unsupported dkim key type %T in %s, expected rsa or ed25519", key, file)
+}
+
+// WithDKIMHeaders sets the names of the signed headers, which have to include From.
+func WithDKIMHeaders(headers []string) func(*DKIMSigner) error {
+	return func(s *DKIMSigner) error {
+		var names []string
+		from := false
+		for _, h := range headers {
+			h = strings.TrimSpace(h)
+			if h == "" {
+				continue
+			}
+			from = from || strings.EqualFold(h, "From")
+			names = append(names, h)
+		}
+		if !from {
+			return fmt.Errorf("the signed dkim headers must contain From")
+		}
+		s.headers = names
+		return nil
+	}
+}
+
+// WithDKIMCanonicalization sets the canonicalization of the header and the body,
+// given as header/body, for example relaxed/simple. If only one algorithm is given,
+// it is used for the header and simple is used for the body.
+func WithDKIMCanonicalization(canonicalization string) func(*DKIMSigner) error {
+	return func(s *DKIMSigner) error {
+		ss := strings.SplitN(canonicalization, "/", 2)
+		if len(ss) == 1 {
+			ss = append(ss, DKIMSimple)
+		}
+		for _, c := range ss {
+			if c != DKIMSimple && c != DKIMRelaxed {
+				return fmt.Errorf("invalid dkim canonicalization %s", canonicalization)
+			}
+		}
+		s.headerCanonical, s.bodyCanonical = ss[0], ss[1]
+		return nil
+	}
+}
+
+// Send signs the given message and sends it using the wrapped mail client.
+func (s *DKIMSigner) Send(ctx context.Context, msg *Message) error {
+	raw, err := composeOrRaw(msg)
+	if err != nil {
+		return err
+	}
+	signed, err := s.Sign(raw)
+	if err != nil {
+		return fmt.Errorf("unable to sign message: %w", err)
+	}
+	m := *msg
+	m.Raw = signed
+	return s.client.Send(ctx, &m)
+}
+
+// Sign returns the given message with a DKIM-Signature header prepended.
+// Line endings are normalized to CRLF, as the message is sent using them.
+func (s *DKIMSigner) Sign(raw string) (string, error) {
+	raw = strings.ReplaceAll(strings.ReplaceAll(raw, "\r\n", "\n"), "\n", "\r\n")
+	headers, body := splitMessage(raw)
+	bodyHash := sha256.Sum256([]byte(canonicalBody(body, s.bodyCanonical)))
+	algorithm := "rsa-sha256"
+	if _, ok := s.key.(ed25519.PrivateKey); ok {
+		algorithm = "ed25519-sha256"
+	}
+	names := make([]string, len(s.headers))
+	for i, h := range s.headers {
+		names[i] = strings.ToLower(h)
+	}
+	header := fmt.Sprintf("DKIM-Signature: v=1; a=%s; c=%s/%s; d=%s; s=%s; t=%d; h=%s; bh=%s; b=",
+		algorithm,
+		s.headerCanonical,
+		s.bodyCanonical,
+		s.domain,
+		s.selector,
+		s.now().Unix(),
+		strings.Join(names, ":"),
+		base64.StdEncoding.EncodeToString(bodyHash[:]),
+	)
+	hash := sha256.Sum256([]byte(signedHeaders(headers, s.headers, header, s.headerCanonical)))
+	var sig []byte
+	var err error
+	if key, ok := s.key.(ed25519.PrivateKey); ok {
+		sig = ed25519.Sign(key, hash[:])
+	} else {
+		sig, err = s.key.Sign(rand.Reader, hash[:], crypto.SHA256)
+		if err != nil {
+			return "", err
+		}
+	}
+	return header + base64.StdEncoding.EncodeToString(sig) + "\r\n" + raw, nil
+}
+
+// splitMessage splits the given message into its header fields, including
+// their folded lines and the trailing CRLF, and its body.
+func splitMessage(raw string) ([]string, string) {
+	var headers []string
+	rest := raw
+	for rest != "" && !strings.HasPrefix(rest, "\r\n") {
+		var line string
+		if i := strings.Index(rest, "\r\n"); i >= 0 {
+			line, rest = rest[:i+2], rest[i+2:]
+		} else {
+			// message without body and without trailing CRLF
+			line, rest = rest+"\r\n", ""
+		}
+		if len(headers) > 0 && (line[0] == ' ' || line[0] == '\t') {
+			headers[len(headers)-1] += line
+			continue
+		}
+		headers = append(headers, line)
+	}
+	return headers, strings.TrimPrefix(rest, "\r\n")
+}
+
+// signedHeaders returns the data which is signed: the canonicalized headers with the
+// given names, followed by the canonicalized signature header without trailing CRLF.
+// If a header occurs multiple times, the instances are used from the bottom up.
+func signedHeaders(headers, names []string, signature, canonical string) string {
+	used := make(map[int]bool)
+	var b strings.Builder
+	for _, name := range names {
+		for i := len(headers) - 1; i >= 0; i-- {
+			if used[i] || !strings.EqualFold(headerName(headers[i]), name) {
+				continue
+			}
+			used[i] = true
+			b.WriteString(canonicalHeader(headers[i], canonical))
+			break
+		}
+	}
+	b.WriteString(strings.TrimSuffix(canonicalHeader(signature+"\r\n", canonical), "\r\n"))
+	return b.String()
+}
+
+// headerName returns the name of the given header field.
+func headerName(header string) string {
+	return strings.TrimRight(strings.SplitN(header, ":", 2)[0], " \t")
+}
+
+// canonicalHeader canonicalizes a header field including its trailing CRLF.
+// The relaxed algorithm lowercases the name, unfolds the value and
+// reduces whitespace, the simple algorithm keeps the field as is.
+func canonicalHeader(header, canonical string) string {
+	if canonical == DKIMSimple {
+		return header
+	}
+	ss := strings.SplitN(header, ":", 2)
+	name := strings.ToLower(strings.TrimRight(ss[0], " \t"))
+	value := ""
+	if len(ss) == 2 {
+		value = strings.ReplaceAll(ss[1], "\r\n", "")
+		value = strings.Join(strings.FieldsFunc(value, isWSP), " ")
+	}
+	return name + ":" + value + "\r\n"
+}
+
+// canonicalBody canonicalizes the body. Both algorithms remove empty lines at
+// the end of the body, the relaxed algorithm also reduces whitespace.
+func canonicalBody(body, canonical string) string {
+	lines := strings.Split(body, "\r\n")
+	if canonical == DKIMRelaxed {
+		for i, l := range lines {
+			// whitespace at the start of a line is reduced, not removed
+			lead := ""
+			if len(l) > 0 && isWSP(rune(l[0])) {
+				lead = " "
+			}
+			fields := strings.FieldsFunc(l, isWSP)
+			if len(fields) == 0 {
+				lines[i] = ""
+				continue
+			}
+			lines[i] = lead + strings.Join(fields, " ")
+		}
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		if canonical == DKIMSimple {
+			return "\r\n"
+		}
+		return ""
+	}
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// isWSP checks if the given rune is a space or a tab.
+func isWSP(r rune) bool {
+	return r == ' ' || r == '\t'
+}
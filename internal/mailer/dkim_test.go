@@ -0,0 +1,271 @@
+package mailer
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// signatureValue matches the b= tag of a DKIM-Signature header.
+var signatureValue = regexp.MustCompile(`(^|[;\s])b=[^;]*`)
+
+// verifyDKIM verifies the DKIM-Signature header at the top of the given message
+// using the given public key.
+func verifyDKIM(raw string, pub crypto.PublicKey) error {
+	headers, body := splitMessage(raw)
+	if len(headers) == 0 || !strings.EqualFold(headerName(headers[0]), "DKIM-Signature") {
+		return errors.New("no DKIM-Signature header found")
+	}
+	signature := headers[0]
+	tags := make(map[string]string)
+	value := strings.SplitN(strings.ReplaceAll(signature, "\r\n", ""), ":", 2)[1]
+	for _, tag := range strings.Split(value, ";") {
+		kv := strings.SplitN(strings.TrimSpace(tag), "=", 2)
+		if len(kv) == 2 {
+			tags[kv[0]] = strings.Join(strings.FieldsFunc(kv[1], isWSP), "")
+		}
+	}
+	canonical := strings.SplitN(tags["c"], "/", 2)
+	if len(canonical) == 1 {
+		canonical = append(canonical, DKIMSimple)
+	}
+	bodyHash := sha256.Sum256([]byte(canonicalBody(body, canonical[1])))
+	if base64.StdEncoding.EncodeToString(bodyHash[:]) != tags["bh"] {
+		return errors.New("body hash mismatch")
+	}
+	var names []string
+	for _, name := range strings.Split(tags["h"], ":") {
+		names = append(names, strings.TrimSpace(name))
+	}
+	withoutSignature := strings.TrimSuffix(signatureValue.ReplaceAllString(signature, "${1}b="), "\r\n")
+	hash := sha256.Sum256([]byte(signedHeaders(headers[1:], names, withoutSignature, canonical[0])))
+	sig, err := base64.StdEncoding.DecodeString(tags["b"])
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, hash[:], sig) {
+			return errors.New("invalid ed25519 signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported key type %T", pub)
+}
+
+// TestDKIMCanonicalization tests the canonicalization examples of RFC 6376, section 3.4.5.
+func TestDKIMCanonicalization(t *testing.T) {
+	assert := assert.New(t)
+	headers, body := splitMessage("A: X\r\nB : Y\t\r\n\tZ  \r\n\r\n C \r\nD \t E\r\n\r\n\r\n")
+	assert.Equal([]string{"A: X\r\n", "B : Y\t\r\n\tZ  \r\n"}, headers)
+	assert.Equal("a:X\r\n", canonicalHeader(headers[0], DKIMRelaxed))
+	assert.Equal("b:Y Z\r\n", canonicalHeader(headers[1], DKIMRelaxed))
+	assert.Equal("B : Y\t\r\n\tZ  \r\n", canonicalHeader(headers[1], DKIMSimple))
+	assert.Equal(" C\r\nD E\r\n", canonicalBody(body, DKIMRelaxed))
+	assert.Equal(" C \r\nD \t E\r\n", canonicalBody(body, DKIMSimple))
+	// empty bodies
+	assert.Equal("", canonicalBody("", DKIMRelaxed))
+	assert.Equal("\r\n", canonicalBody("\r\n\r\n", DKIMSimple))
+}
+
+// TestDKIMVerifyRFC8463 verifies the ed25519 example of RFC 8463, appendix A.
+func TestDKIMVerifyRFC8463(t *testing.T) {
+	pub, _ := base64.StdEncoding.DecodeString("11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo=")
+	raw := "DKIM-Signature: v=1; a=ed25519-sha256; c=relaxed/relaxed;\r\n" +
+		" d=football.example.com; i=@football.example.com;\r\n" +
+		" q=dns/txt; s=brisbane; t=1528637909; h=from : to :\r\n" +
+		" subject : date : message-id : from : subject : date;\r\n" +
+		" bh=2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=;\r\n" +
+		" b=/gCrinpcQOoIfuHNQIbq4pgh9kyIK3AQUdt9OdqQehSwhEIug4D11Bus\r\n" +
+		" Fa3bT3FY5OsU7ZbnKELq+eXdp1Q1Dw==\r\n" +
+		"From: Joe SixPack <joe@football.example.com>\r\n" +
+		"To: Suzie Q <suzie@shopping.example.net>\r\n" +
+		"Subject: Is dinner ready?\r\n" +
+		"Date: Fri, 11 Jul 2003 21:00:37 -0700 (PDT)\r\n" +
+		"Message-ID: <20030712040037.46341.5F8J@football.example.com>\r\n" +
+		"\r\n" +
+		"Hi.\r\n" +
+		"\r\n" +
+		"We lost the game.  Are you hungry yet?\r\n" +
+		"\r\n" +
+		"Joe.\r\n"
+	assert.NoError(t, verifyDKIM(raw, ed25519.PublicKey(pub)))
+	assert.Error(t, verifyDKIM(strings.Replace(raw, "Is dinner ready?", "Is lunch ready?", 1), ed25519.PublicKey(pub)))
+}
+
+// fakeMailClient records the sent messages.
+type fakeMailClient struct {
+	messages []*Message
+}
+
+// Send records the given message.
+func (c *fakeMailClient) Send(ctx context.Context, msg *Message) error {
+	c.messages = append(c.messages, msg)
+	return nil
+}
+
+// TestDKIMSigner tests signing messages with rsa and ed25519 keys.
+func TestDKIMSigner(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate rsa key: %s", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate ed25519 key: %s", err)
+	}
+	tests := []struct {
+		name             string
+		key              crypto.Signer
+		canonicalization string
+		wantAlgorithm    string
+	}{
+		{
+			name:             "rsa relaxed",
+			key:              rsaKey,
+			canonicalization: "relaxed/relaxed",
+			wantAlgorithm:    "a=rsa-sha256",
+		},
+		{
+			name:             "rsa simple",
+			key:              rsaKey,
+			canonicalization: "simple/simple",
+			wantAlgorithm:    "a=rsa-sha256",
+		},
+		{
+			name:             "ed25519 relaxed",
+			key:              edKey,
+			canonicalization: "relaxed/relaxed",
+			wantAlgorithm:    "a=ed25519-sha256",
+		},
+		{
+			name:             "ed25519 relaxed header, simple body",
+			key:              edKey,
+			canonicalization: "relaxed",
+			wantAlgorithm:    "a=ed25519-sha256",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			client := &fakeMailClient{}
+			s, err := NewDKIMSigner(client, "example.com", "gooser", tt.key, WithDKIMCanonicalization(tt.canonicalization))
+			if err != nil {
+				t.Fatalf("unable to create dkim signer: %s", err)
+			}
+			err = s.Send(context.Background(), &Message{
+				From:    "gooser@example.com",
+				To:      []string{"user1@example.com"},
+				Subject: "confirm mail address",
+				Text:    "Hi user1!\nPlease confirm your mail address.  \n\n",
+				HTML:    "<p>Hi user1!</p>",
+			})
+			assert.NoError(err)
+			if !assert.Len(client.messages, 1) {
+				return
+			}
+			raw := client.messages[0].Raw
+			assert.True(strings.HasPrefix(raw, "DKIM-Signature: v=1; "), "message should start with the signature")
+			assert.Contains(raw, tt.wantAlgorithm, "algorithm mismatch")
+			assert.Contains(raw, "d=example.com; s=gooser;", "domain or selector mismatch")
+			pub := tt.key.Public()
+			assert.NoError(verifyDKIM(raw, pub), "signature should be valid")
+			// signed headers and the body must not be changed
+			assert.Error(verifyDKIM(strings.Replace(raw, "Subject: confirm", "Subject: reset", 1), pub), "changed subject")
+			assert.Error(verifyDKIM(strings.Replace(raw, "Hi user1!", "Hi user2!", 1), pub), "changed body")
+			assert.Error(verifyDKIM(strings.Replace(raw, "\r\n\r\n", "\r\nReply-To: evil@example.com\r\n\r\n", 1), pub), "added header")
+			// unsigned headers may be added
+			assert.NoError(verifyDKIM(strings.Replace(raw, "\r\n\r\n", "\r\nX-Spam: no\r\n\r\n", 1), pub), "added unsigned header")
+		})
+	}
+}
+
+// TestDKIMSignerOptions tests that invalid settings are rejected.
+func TestDKIMSignerOptions(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	client := &fakeMailClient{}
+	_, err := NewDKIMSigner(client, "example.com", "gooser", key, WithDKIMHeaders([]string{"Subject"}))
+	assert.Error(t, err, "from has to be signed")
+	_, err = NewDKIMSigner(client, "example.com", "gooser", key, WithDKIMCanonicalization("loose/simple"))
+	assert.Error(t, err, "invalid canonicalization")
+	_, err = NewDKIMSigner(client, "", "gooser", key)
+	assert.Error(t, err, "empty domain")
+}
+
+// TestLoadDKIMKey tests loading keys in the supported formats.
+func TestLoadDKIMKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dkim")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	pkcs8 := func(key interface{}) []byte {
+		b, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatalf("unable to marshal key: %s", err)
+		}
+		return b
+	}
+	tests := []struct {
+		name    string
+		block   *pem.Block
+		want    crypto.Signer
+		wantErr bool
+	}{
+		{
+			name:  "rsa pkcs1",
+			block: &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+			want:  rsaKey,
+		},
+		{
+			name:  "rsa pkcs8",
+			block: &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8(rsaKey)},
+			want:  rsaKey,
+		},
+		{
+			name:  "ed25519 pkcs8",
+			block: &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8(edKey)},
+			want:  edKey,
+		},
+		{
+			name:    "invalid key",
+			block:   &pem.Block{Type: "PRIVATE KEY", Bytes: []byte("invalid")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".pem")
+			if err := ioutil.WriteFile(file, pem.EncodeToMemory(tt.block), 0600); err != nil {
+				t.Fatalf("unable to write key: %s", err)
+			}
+			key, err := LoadDKIMKey(file)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, key)
+		})
+	}
+}
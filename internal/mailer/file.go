@@ -0,0 +1,120 @@
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileMailer implements the MailClient interface by writing every message
+// as .eml file into a directory. It is meant for development and testing.
+type FileMailer struct {
+	dir string
+}
+
+// ensure FileMailer implements the MailClient interface.
+var _ MailClient = &FileMailer{}
+
+// NewFileMailer creates a new file mailer writing into the given directory,
+// which is created if it does not exist.
+func NewFileMailer(dir string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create mail directory %s: %w", dir, err)
+	}
+	return &FileMailer{
+		dir: dir,
+	}, nil
+}
+
+// Send composes the given message and writes it into a new .eml file. The names
+// of the files start with the time they were written, so they are sorted by time.
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	message, err := composeOrRaw(msg)
+	if err != nil {
+		return err
+	}
+	name, err := uniqueName()
+	if err != nil {
+		return err
+	}
+	// readers never see partially written files
+	return writeAtomic(filepath.Join(m.dir, "."+name), filepath.Join(m.dir, name+".eml"), message)
+}
+
+// MaildirMailer implements the MailClient interface by delivering every message
+// into a Maildir, which can be read by most mail clients. It is meant for
+// development and testing.
+type MaildirMailer struct {
+	dir string
+}
+
+// ensure MaildirMailer implements the MailClient interface.
+var _ MailClient = &MaildirMailer{}
+
+// NewMaildirMailer creates a new maildir mailer delivering into the given Maildir.
+// The directory and its tmp, new and cur subdirectories are created if they do not exist.
+func NewMaildirMailer(dir string) (*MaildirMailer, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return nil, fmt.Errorf("unable to create maildir %s: %w", dir, err)
+		}
+	}
+	return &MaildirMailer{
+		dir: dir,
+	}, nil
+}
+
+// Send composes the given message and delivers it into the Maildir, by writing
+// it into tmp and moving it into new as described by the Maildir specification.
+func (m *MaildirMailer) Send(ctx context.Context, msg *Message) error {
+	message, err := composeOrRaw(msg)
+	if err != nil {
+		return err
+	}
+	name, err := uniqueName()
+	if err != nil {
+		return err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+	// slashes and colons are not allowed in the names of maildir files
+	name += "." + strings.NewReplacer("/", `\057`, ":", `\072`).Replace(hostname)
+	return writeAtomic(filepath.Join(m.dir, "tmp", name), filepath.Join(m.dir, "new", name), message)
+}
+
+// composeOrRaw returns the raw message if it is set, otherwise it composes the message.
+func composeOrRaw(msg *Message) (string, error) {
+	if msg.Raw != "" {
+		return msg.Raw, nil
+	}
+	return Compose(msg)
+}
+
+// uniqueName returns a new unique file name, starting with the current time.
+func uniqueName() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("unable to generate file name: %w", err)
+	}
+	return fmt.Sprintf("%d.%s", time.Now().UnixNano(), hex.EncodeToString(buf)), nil
+}
+
+// writeAtomic writes the given message into the temporary file and moves it to the given file.
+func writeAtomic(tmp, file, message string) error {
+	if err := ioutil.WriteFile(tmp, []byte(message), 0600); err != nil {
+		return fmt.Errorf("unable to write mail to %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("unable to move mail to %s: %w", file, err)
+	}
+	return nil
+}
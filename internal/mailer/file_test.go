@@ -0,0 +1,171 @@
+package mailer
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testMessage returns a message containing a confirmation link.
+func testMessage(subject string) *Message {
+	return &Message{
+		From:    "gooser <gooser@example.com>",
+		To:      []string{"Jürg <juerg@example.com>"},
+		Subject: subject,
+		Text:    "Grüezi Jürg!\nhttps://example.com/confirm?token=abc&user=1\n",
+		HTML:    `<p>Grüezi Jürg!</p><a href="https://example.com/confirm?token=abc&amp;user=1">confirm</a>`,
+		Attachments: []Attachment{
+			{Filename: "data.json", Data: []byte("{}")},
+		},
+	}
+}
+
+// TestFileMailer tests writing mails as .eml files.
+func TestFileMailer(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "mails")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	m, err := NewFileMailer(filepath.Join(dir, "captured"))
+	if err != nil {
+		t.Fatalf("unable to create file mailer: %s", err)
+	}
+	for _, subject := range []string{"first", "second"} {
+		assert.NoError(m.Send(context.Background(), testMessage(subject)))
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "captured", "*"))
+	if !assert.Len(files, 2) {
+		return
+	}
+	for i, subject := range []string{"first", "second"} {
+		assert.True(strings.HasSuffix(files[i], ".eml"), "file name should end with .eml")
+		f, err := os.Open(files[i])
+		if err != nil {
+			t.Fatalf("unable to open mail: %s", err)
+		}
+		msg, err := mail.ReadMessage(f)
+		f.Close()
+		assert.NoError(err)
+		assert.Equal(subject, msg.Header.Get("Subject"), "files should be sorted by time")
+	}
+	// raw messages are written as is
+	assert.NoError(m.Send(context.Background(), &Message{Raw: "Subject: raw\r\n\r\nraw\r\n"}))
+	files, _ = filepath.Glob(filepath.Join(dir, "captured", "*.eml"))
+	b, _ := ioutil.ReadFile(files[len(files)-1])
+	assert.Equal("Subject: raw\r\n\r\nraw\r\n", string(b))
+}
+
+// TestMaildirMailer tests delivering mails into a Maildir.
+func TestMaildirMailer(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "maildir")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	m, err := NewMaildirMailer(dir)
+	if err != nil {
+		t.Fatalf("unable to create maildir mailer: %s", err)
+	}
+	assert.NoError(m.Send(context.Background(), testMessage("hello")))
+	tmp, _ := ioutil.ReadDir(filepath.Join(dir, "tmp"))
+	assert.Empty(tmp, "tmp should be empty after delivery")
+	delivered, _ := ioutil.ReadDir(filepath.Join(dir, "new"))
+	if !assert.Len(delivered, 1) {
+		return
+	}
+	assert.NotContains(delivered[0].Name(), ":", "maildir file names must not contain colons")
+	b, _ := ioutil.ReadFile(filepath.Join(dir, "new", delivered[0].Name()))
+	assert.Contains(string(b), "Subject: hello\r\n")
+}
+
+// TestViewer tests listing and showing captured mails.
+func TestViewer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mails")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	m, err := NewMaildirMailer(dir)
+	if err != nil {
+		t.Fatalf("unable to create maildir mailer: %s", err)
+	}
+	if err := m.Send(context.Background(), testMessage("Bestätige deine Adresse")); err != nil {
+		t.Fatalf("unable to send mail: %s", err)
+	}
+	// mails read by a mail client are moved to cur
+	delivered, _ := ioutil.ReadDir(filepath.Join(dir, "new"))
+	id := delivered[0].Name() + ":2,S"
+	os.Rename(filepath.Join(dir, "new", delivered[0].Name()), filepath.Join(dir, "cur", id))
+	v, err := NewViewer(dir)
+	if err != nil {
+		t.Fatalf("unable to create viewer: %s", err)
+	}
+	captured, err := v.Get(id)
+	if err != nil {
+		t.Fatalf("unable to get mail: %s", err)
+	}
+	assert.Equal(t, "Bestätige deine Adresse", captured.Subject)
+	assert.Equal(t, "Jürg <juerg@example.com>", captured.To)
+	assert.Equal(t, "Grüezi Jürg!\r\nhttps://example.com/confirm?token=abc&user=1\r\n", captured.Text)
+	assert.Contains(t, captured.HTML, "<p>Grüezi Jürg!</p>")
+	assert.Equal(t, []string{"data.json"}, captured.Attachments)
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBody   []string
+	}{
+		{
+			name:       "list",
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"Bestätige deine Adresse", "Jürg &lt;juerg@example.com&gt;"},
+		},
+		{
+			name:       "mail",
+			query:      "?id=" + id,
+			wantStatus: http.StatusOK,
+			wantBody: []string{
+				`<a href="https://example.com/confirm?token=abc&amp;user=1" target="_blank" rel="noopener">`,
+				`sandbox="allow-popups allow-popups-to-escape-sandbox"`,
+				"data.json",
+			},
+		},
+		{
+			name:       "raw mail",
+			query:      "?raw=1&id=" + id,
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"Content-Transfer-Encoding: quoted-printable"},
+		},
+		{
+			name:       "unknown mail",
+			query:      "?id=unknown",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "path traversal",
+			query:      "?raw=1&id=../../../etc/passwd",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			v.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/"+tt.query, nil))
+			assert.Equal(t, tt.wantStatus, rec.Code, "status code mismatch")
+			for _, want := range tt.wantBody {
+				assert.Contains(t, rec.Body.String(), want)
+			}
+		})
+	}
+}
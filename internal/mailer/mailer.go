@@ -1,7 +1,15 @@
 package mailer
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rbicker/gooser/internal/logging"
+	"github.com/rbicker/gooser/internal/metrics"
 	"github.com/rbicker/gooser/internal/store"
+	"go.uber.org/zap"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 	"google.golang.org/grpc/codes"
@@ -10,8 +18,15 @@ import (
 
 // Messenger describes the functions to deliver application specific messages.
 type Messenger interface {
-	SendConfirmToken(user *store.User) error
-	SendPasswordResetToken(user *store.User) error
+	SendConfirmToken(ctx context.Context, user *store.User) error
+	SendMailChangeToken(ctx context.Context, user *store.User) error
+	SendPasswordResetToken(ctx context.Context, user *store.User) error
+	SendAccountDeletionScheduled(ctx context.Context, user *store.User) error
+	SendInvitation(ctx context.Context, user *store.User) error
+	SendPasswordChanged(ctx context.Context, user *store.User) error
+	SendMailChanged(ctx context.Context, user *store.User, previousMail string) error
+	SendGroupGranted(ctx context.Context, user *store.User, group *store.Group, roles []string) error
+	PreviewMail(ctx context.Context, template string, user *store.User) (*Content, error)
 }
 
 // Mailer implements the Messenger interface.
@@ -19,55 +34,364 @@ type Mailer struct {
 	mailClient       MailClient
 	confirmUrl       string
 	resetPasswordUrl string
+	invitationUrl    string
+	revertMailUrl    string
 	from             string
 	siteName         string
+	logoUrl          string
+	supportAddress   string
+	templates        *Templates
+	metrics          *metrics.Metrics
+	logger           *zap.Logger
 }
 
 // ensure Mailer implements the Messenger interface.
 var _ Messenger = &Mailer{}
 
 // NewMailer creates a new mailer and returns the pointer.
-func NewMailer(mailClient MailClient, mailFrom, siteName, confirmUrl, resetPasswordUrl string) (*Mailer, error) {
-	return &Mailer{
-		mailClient: mailClient,
-		confirmUrl: confirmUrl,
-		from:       mailFrom,
-		siteName:   siteName,
-	}, nil
+// It takes functional parameters to change default options.
+func NewMailer(mailClient MailClient, mailFrom, siteName, confirmUrl, resetPasswordUrl string, opts ...func(*Mailer) error) (*Mailer, error) {
+	m := Mailer{
+		mailClient:       mailClient,
+		confirmUrl:       confirmUrl,
+		resetPasswordUrl: resetPasswordUrl,
+		from:             mailFrom,
+		siteName:         siteName,
+	}
+	// run functional options
+	for _, op := range opts {
+		err := op(&m)
+		if err != nil {
+			return nil, fmt.Errorf("setting option failed: %w", err)
+		}
+	}
+	// default logger
+	if m.logger == nil {
+		m.logger = logging.Default()
+	}
+	// embedded templates
+	if m.templates == nil {
+		m.templates = DefaultTemplates()
+	}
+	return &m, nil
 }
 
-// SendConfirmToken sends the confirmation token.
-func (m Mailer) SendConfirmToken(user *store.User) error {
+// WithMetrics sets the metrics used to count the sent mails.
+func WithMetrics(metrics *metrics.Metrics) func(*Mailer) error {
+	return func(m *Mailer) error {
+		m.metrics = metrics
+		return nil
+	}
+}
+
+// WithInvitationUrl sets the base url which is sent to invited users to accept the invitation.
+func WithInvitationUrl(invitationUrl string) func(*Mailer) error {
+	return func(m *Mailer) error {
+		m.invitationUrl = invitationUrl
+		return nil
+	}
+}
+
+// WithRevertMailUrl sets the base url which is sent to the previous mail address
+// of a user to revert the change of the mail address.
+func WithRevertMailUrl(revertMailUrl string) func(*Mailer) error {
+	return func(m *Mailer) error {
+		m.revertMailUrl = revertMailUrl
+		return nil
+	}
+}
+
+// WithTemplates sets the templates used to render the messages.
+func WithTemplates(templates *Templates) func(*Mailer) error {
+	return func(m *Mailer) error {
+		m.templates = templates
+		return nil
+	}
+}
+
+// WithLogoUrl sets the url of the logo shown in html messages.
+func WithLogoUrl(logoUrl string) func(*Mailer) error {
+	return func(m *Mailer) error {
+		m.logoUrl = logoUrl
+		return nil
+	}
+}
+
+// WithSupportAddress sets the mail address users can contact for support,
+// which is shown in every message and used as its Reply-To address.
+func WithSupportAddress(supportAddress string) func(*Mailer) error {
+	return func(m *Mailer) error {
+		m.supportAddress = supportAddress
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the mailer.
+func WithLogger(logger *zap.Logger) func(*Mailer) error {
+	return func(m *Mailer) error {
+		m.logger = logger
+		return nil
+	}
+}
+
+// link returns the link sent with the given template containing the given token.
+func (m Mailer) link(template, token string) string {
+	switch template {
+	case TemplateConfirm:
+		return m.confirmUrl + "?token=" + token
+	case TemplatePasswordReset:
+		return m.resetPasswordUrl + "?token=" + token
+	case TemplateInvitation:
+		return m.invitationUrl + "?token=" + token
+	case TemplateMailChanged:
+		return m.revertMailUrl + "?token=" + token
+	}
+	return ""
+}
+
+// data returns the data to render the given template for the given user with.
+func (m Mailer) data(template string, user *store.User, token string) *TemplateData {
+	return &TemplateData{
+		Branding: Branding{
+			SiteName:       m.siteName,
+			LogoUrl:        m.logoUrl,
+			SupportAddress: m.supportAddress,
+		},
+		User:   user,
+		Link:   m.link(template, token),
+		Notice: m.notice(template, user),
+	}
+}
+
+// notice returns the sentences of the given template in the language of the given user.
+// The sentences of security notifications depend on the event and are set by their data functions.
+func (m Mailer) notice(template string, user *store.User) Notice {
+	printer := message.NewPrinter(language.Make(user.Language))
+	switch template {
+	case TemplateConfirm:
+		return Notice{
+			Subject:  printer.Sprintf("confirm mail address"),
+			Greeting: printer.Sprintf("Hi %s!", user.Username),
+			Message:  printer.Sprintf("Please confirm your mail address by clicking the following link. Thanks!"),
+			LinkText: printer.Sprintf("Confirm mail address"),
+		}
+	case TemplatePasswordReset:
+		return Notice{
+			Subject:  printer.Sprintf("password reset"),
+			Greeting: printer.Sprintf("Hi %s!", user.Username),
+			Message:  printer.Sprintf("To reset your password, click the following link:"),
+			Advice:   printer.Sprintf("If you did not request to reset your password, please ignore this message. Thanks"),
+			LinkText: printer.Sprintf("Reset password"),
+		}
+	case TemplateAccountDeletion:
+		return Notice{
+			Subject:  printer.Sprintf("account deletion"),
+			Greeting: printer.Sprintf("Hi %s!", user.Username),
+			Message:  printer.Sprintf("As requested, your account will be deleted on %s.", formatTime(user.DeletionScheduledAt)),
+			Advice:   printer.Sprintf("If you changed your mind, sign in and cancel the deletion before then. If you did not request to delete your account, please change your password immediately."),
+		}
+	case TemplateInvitation:
+		return Notice{
+			Subject:  printer.Sprintf("invitation"),
+			Greeting: printer.Sprintf("Hi!"),
+			Message:  printer.Sprintf("You have been invited to %s. To accept the invitation, choose a username and a password using the following link:", m.siteName),
+			Advice:   printer.Sprintf("The invitation expires on %s.", formatTime(user.InvitationExpiresAt)),
+			LinkText: printer.Sprintf("Accept invitation"),
+		}
+	}
+	return Notice{}
+}
+
+// passwordChangedData returns the data of the notification about
+// the password of the given user being changed at the given time.
+func (m Mailer) passwordChangedData(user *store.User, changedAt time.Time) *TemplateData {
+	printer := message.NewPrinter(language.Make(user.Language))
+	data := m.data(TemplatePasswordChanged, user, "")
+	data.Notice = Notice{
+		Subject:  printer.Sprintf("password changed"),
+		Greeting: printer.Sprintf("Hi %s!", user.Username),
+		Message:  printer.Sprintf("The password of your account was changed on %s.", formatTime(changedAt)),
+		Advice:   printer.Sprintf("If you did not change your password, please reset it immediately and contact the support."),
+	}
+	return data
+}
+
+// mailChangedData returns the data of the notification about the mail address of the
+// given user being changed from the given previous one at the given time.
+// If the change is still pending, the notification is about the requested change.
+// The given token allows to revert or cancel the change.
+func (m Mailer) mailChangedData(user *store.User, previousMail, token string, changedAt time.Time) *TemplateData {
+	printer := message.NewPrinter(language.Make(user.Language))
+	data := m.data(TemplateMailChanged, user, token)
+	data.PreviousMail = previousMail
+	if user.PendingMail != "" {
+		data.Notice = Notice{
+			Subject:  printer.Sprintf("mail address change requested"),
+			Greeting: printer.Sprintf("Hi %s!", user.Username),
+			Message:  printer.Sprintf("A change of the mail address of your account from %s to %s was requested on %s. The new address is used once it is confirmed.", previousMail, user.PendingMail, formatTime(changedAt)),
+			Advice:   printer.Sprintf("If you did not request this change, cancel it using the following link and reset your password."),
+			LinkText: printer.Sprintf("Cancel mail address change"),
+		}
+		return data
+	}
+	data.Notice = Notice{
+		Subject:  printer.Sprintf("mail address changed"),
+		Greeting: printer.Sprintf("Hi %s!", user.Username),
+		Message:  printer.Sprintf("The mail address of your account was changed from %s to %s on %s.", previousMail, user.Mail, formatTime(changedAt)),
+		Advice:   printer.Sprintf("If you did not change your mail address, restore the previous one using the following link and reset your password."),
+		LinkText: printer.Sprintf("Restore previous mail address"),
+	}
+	return data
+}
+
+// groupGrantedData returns the data of the notification about the given user
+// being added to the given group or being granted the given roles through it.
+func (m Mailer) groupGrantedData(user *store.User, group *store.Group, roles []string) *TemplateData {
 	printer := message.NewPrinter(language.Make(user.Language))
-	link := m.confirmUrl + "?token=" + user.ConfirmToken
-	err := m.mailClient.Send(
-		m.from,
-		[]string{user.Mail},
-		printer.Sprintf("%s: confirm mail address", m.siteName),
-		printer.Sprintf("Hi %s! Please confirm your mail address by clicking the following link. Thanks!\n%s", user.Username, link),
-		nil,
-		nil,
-	)
+	data := m.data(TemplateGroupGranted, user, "")
+	data.Group = group
+	data.Roles = roles
+	data.Notice = Notice{
+		Subject:  printer.Sprintf("access granted"),
+		Greeting: printer.Sprintf("Hi %s!", user.Username),
+		Message:  printer.Sprintf("You were added to the group %s.", group.Name),
+		Advice:   printer.Sprintf("If you think this is a mistake, please contact the support."),
+	}
+	if len(roles) > 0 {
+		data.Notice.Message = printer.Sprintf("You were granted the roles %s through the group %s.", strings.Join(roles, ", "), group.Name)
+	}
+	return data
+}
+
+// send renders the given template with the given data and sends it to the given mail address.
+func (m Mailer) send(ctx context.Context, template, to string, data *TemplateData) error {
+	content, err := m.templates.Render(template, data.User.Language, data)
+	if err != nil {
+		return err
+	}
+	return m.mailClient.Send(ctx, &Message{
+		From:    m.from,
+		To:      []string{to},
+		ReplyTo: m.supportAddress,
+		Subject: content.Subject,
+		Text:    content.Text,
+		HTML:    content.HTML,
+	})
+}
+
+// notify sends the given security notification to the given mail address.
+// Notifications to users without mail address are skipped.
+func (m Mailer) notify(ctx context.Context, kind, template, to string, data *TemplateData) error {
+	if to == "" {
+		return nil
+	}
+	printer := message.NewPrinter(language.Make(data.User.Language))
+	err := m.send(ctx, template, to, data)
+	m.metrics.ObserveMail(kind, err)
 	if err != nil {
-		status.Errorf(codes.Internal, printer.Sprintf("error while sending mail: %s", err))
+		logging.FromContext(ctx, m.logger).Error("unable to send notification mail", zap.String("template", template), zap.String("userId", data.User.Id), zap.Error(err))
+		return status.Errorf(codes.Internal, printer.Sprintf("error while sending mail: %s", err))
 	}
 	return nil
 }
 
+// PreviewMail renders the given template for the given user without sending it.
+// Placeholders are used instead of tokens, previous mail addresses and groups.
+func (m Mailer) PreviewMail(ctx context.Context, template string, user *store.User) (*Content, error) {
+	printer := message.NewPrinter(language.Make(user.Language))
+	var data *TemplateData
+	switch template {
+	case TemplatePasswordChanged:
+		data = m.passwordChangedData(user, time.Now())
+	case TemplateMailChanged:
+		data = m.mailChangedData(user, "previous@example.com", "preview", time.Now())
+	case TemplateGroupGranted:
+		data = m.groupGrantedData(user, &store.Group{Name: "preview"}, []string{"preview"})
+	default:
+		data = m.data(template, user, "preview")
+	}
+	content, err := m.templates.Render(template, user.Language, data)
+	if err != nil {
+		logging.FromContext(ctx, m.logger).Error("unable to render mail preview", zap.String("template", template), zap.Error(err))
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("unable to render template %s: %s", template, err))
+	}
+	return content, nil
+}
+
 // SendConfirmToken sends the confirmation token.
-func (m Mailer) SendPasswordResetToken(user *store.User) error {
+func (m Mailer) SendConfirmToken(ctx context.Context, user *store.User) error {
 	printer := message.NewPrinter(language.Make(user.Language))
-	link := m.resetPasswordUrl + "?token=" + user.PasswordResetToken
-	err := m.mailClient.Send(
-		m.from,
-		[]string{user.Mail},
-		printer.Sprintf("%s: password reset", m.siteName),
-		printer.Sprintf("Hi %s! To reset your password, click the following link: \n%s\n\nIf you did not request to reset your password, please ignore this message. Thanks", user.Username, link),
-		nil,
-		nil,
-	)
+	err := m.send(ctx, TemplateConfirm, user.Mail, m.data(TemplateConfirm, user, user.ConfirmToken))
+	m.metrics.ObserveMail("confirm", err)
 	if err != nil {
-		status.Errorf(codes.Internal, printer.Sprintf("error while sending mail: %s", err))
+		logging.FromContext(ctx, m.logger).Error("unable to send confirmation mail", zap.String("userId", user.Id), zap.Error(err))
+		return status.Errorf(codes.Internal, printer.Sprintf("error while sending mail: %s", err))
 	}
 	return nil
 }
+
+// SendMailChangeToken sends the token to confirm the pending mail address
+// of the user to the pending address.
+func (m Mailer) SendMailChangeToken(ctx context.Context, user *store.User) error {
+	printer := message.NewPrinter(language.Make(user.Language))
+	err := m.send(ctx, TemplateConfirm, user.PendingMail, m.data(TemplateConfirm, user, user.PendingMailToken))
+	m.metrics.ObserveMail("mail_change", err)
+	if err != nil {
+		logging.FromContext(ctx, m.logger).Error("unable to send mail change confirmation", zap.String("userId", user.Id), zap.Error(err))
+		return status.Errorf(codes.Internal, printer.Sprintf("error while sending mail: %s", err))
+	}
+	return nil
+}
+
+// SendPasswordResetToken sends the password reset token.
+func (m Mailer) SendPasswordResetToken(ctx context.Context, user *store.User) error {
+	printer := message.NewPrinter(language.Make(user.Language))
+	err := m.send(ctx, TemplatePasswordReset, user.Mail, m.data(TemplatePasswordReset, user, user.PasswordResetToken))
+	m.metrics.ObserveMail("password_reset", err)
+	if err != nil {
+		logging.FromContext(ctx, m.logger).Error("unable to send password reset mail", zap.String("userId", user.Id), zap.Error(err))
+		return status.Errorf(codes.Internal, printer.Sprintf("error while sending mail: %s", err))
+	}
+	return nil
+}
+
+// SendAccountDeletionScheduled informs the user about the scheduled deletion of the account.
+func (m Mailer) SendAccountDeletionScheduled(ctx context.Context, user *store.User) error {
+	printer := message.NewPrinter(language.Make(user.Language))
+	err := m.send(ctx, TemplateAccountDeletion, user.Mail, m.data(TemplateAccountDeletion, user, ""))
+	m.metrics.ObserveMail("account_deletion", err)
+	if err != nil {
+		logging.FromContext(ctx, m.logger).Error("unable to send account deletion mail", zap.String("userId", user.Id), zap.Error(err))
+		return status.Errorf(codes.Internal, printer.Sprintf("error while sending mail: %s", err))
+	}
+	return nil
+}
+
+// SendInvitation sends the invitation token to the invited user.
+func (m Mailer) SendInvitation(ctx context.Context, user *store.User) error {
+	printer := message.NewPrinter(language.Make(user.Language))
+	err := m.send(ctx, TemplateInvitation, user.Mail, m.data(TemplateInvitation, user, user.InvitationToken))
+	m.metrics.ObserveMail("invitation", err)
+	if err != nil {
+		logging.FromContext(ctx, m.logger).Error("unable to send invitation mail", zap.String("userId", user.Id), zap.Error(err))
+		return status.Errorf(codes.Internal, printer.Sprintf("error while sending mail: %s", err))
+	}
+	return nil
+}
+
+// SendPasswordChanged informs the user about the changed password.
+func (m Mailer) SendPasswordChanged(ctx context.Context, user *store.User) error {
+	return m.notify(ctx, "password_changed", TemplatePasswordChanged, user.Mail, m.passwordChangedData(user, time.Now()))
+}
+
+// SendMailChanged informs the user about the changed or the requested change of the mail address.
+// The notification is sent to the given previous mail address and contains a link to revert the change.
+func (m Mailer) SendMailChanged(ctx context.Context, user *store.User, previousMail string) error {
+	return m.notify(ctx, "mail_changed", TemplateMailChanged, previousMail, m.mailChangedData(user, previousMail, user.MailRevertToken, time.Now()))
+}
+
+// SendGroupGranted informs the user about being added to the given group
+// or being granted the given roles through it.
+func (m Mailer) SendGroupGranted(ctx context.Context, user *store.User, group *store.Group, roles []string) error {
+	return m.notify(ctx, "group_granted", TemplateGroupGranted, user.Mail, m.groupGrantedData(user, group, roles))
+}
@@ -0,0 +1,191 @@
+package mailer_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rbicker/gooser/internal/mailer"
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestSendErrors tests that errors of the mail client are returned by all send functions.
+func TestSendErrors(t *testing.T) {
+	user := &store.User{
+		Id:                 "user1",
+		Username:           "user1",
+		Mail:               "user1@example.com",
+		ConfirmToken:       "confirm",
+		PasswordResetToken: "reset",
+		PendingMail:        "new@example.com",
+		PendingMailToken:   "pending",
+	}
+	tests := []struct {
+		name string
+		send func(m *mailer.Mailer) error
+	}{
+		{
+			name: "confirm token",
+			send: func(m *mailer.Mailer) error { return m.SendConfirmToken(context.Background(), user) },
+		},
+		{
+			name: "mail change token",
+			send: func(m *mailer.Mailer) error { return m.SendMailChangeToken(context.Background(), user) },
+		},
+		{
+			name: "password reset token",
+			send: func(m *mailer.Mailer) error { return m.SendPasswordResetToken(context.Background(), user) },
+		},
+		{
+			name: "account deletion",
+			send: func(m *mailer.Mailer) error { return m.SendAccountDeletionScheduled(context.Background(), user) },
+		},
+		{
+			name: "invitation",
+			send: func(m *mailer.Mailer) error { return m.SendInvitation(context.Background(), user) },
+		},
+		{
+			name: "password changed",
+			send: func(m *mailer.Mailer) error { return m.SendPasswordChanged(context.Background(), user) },
+		},
+		{
+			name: "mail changed",
+			send: func(m *mailer.Mailer) error {
+				return m.SendMailChanged(context.Background(), user, "previous@example.com")
+			},
+		},
+		{
+			name: "group granted",
+			send: func(m *mailer.Mailer) error {
+				return m.SendGroupGranted(context.Background(), user, &store.Group{Name: "testers"}, []string{"tester"})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(mocks.MailClient)
+			client.On("Send", mock.Anything, mock.Anything).Return(errors.New("unable to queue mail")).Once()
+			m, err := mailer.NewMailer(client, "gooser@example.com", "gooser", "https://example.com/confirm", "https://example.com/reset")
+			if err != nil {
+				t.Fatalf("unable to create mailer: %s", err)
+			}
+			err = tt.send(m)
+			client.AssertExpectations(t)
+			code, _ := status.FromError(err)
+			assert.Equal(t, codes.Internal, code.Code(), "response statuscode mismatch")
+		})
+	}
+}
+
+// TestSendNotifications tests the recipients and contents of the security notifications.
+func TestSendNotifications(t *testing.T) {
+	user := &store.User{
+		Id:              "user1",
+		Username:        "user1",
+		Mail:            "new@example.com",
+		MailRevertToken: "revert",
+	}
+	requested := &store.User{
+		Id:               "user1",
+		Username:         "user1",
+		Mail:             "previous@example.com",
+		MailRevertToken:  "cancel",
+		PendingMail:      "new@example.com",
+		PendingMailToken: "pending",
+	}
+	tests := []struct {
+		name     string
+		send     func(m *mailer.Mailer) error
+		wantTo   string
+		wantText []string
+	}{
+		{
+			name:     "password changed",
+			send:     func(m *mailer.Mailer) error { return m.SendPasswordChanged(context.Background(), user) },
+			wantTo:   "new@example.com",
+			wantText: []string{"Hi user1!", "The password of your account was changed"},
+		},
+		{
+			name: "mail changed",
+			send: func(m *mailer.Mailer) error {
+				return m.SendMailChanged(context.Background(), user, "previous@example.com")
+			},
+			wantTo: "previous@example.com",
+			wantText: []string{
+				"changed from previous@example.com to new@example.com",
+				"https://example.com/revert-mail?token=revert",
+			},
+		},
+		{
+			name: "mail change requested",
+			send: func(m *mailer.Mailer) error {
+				return m.SendMailChanged(context.Background(), requested, "previous@example.com")
+			},
+			wantTo: "previous@example.com",
+			wantText: []string{
+				"from previous@example.com to new@example.com was requested",
+				"https://example.com/revert-mail?token=cancel",
+			},
+		},
+		{
+			name: "mail change confirmation",
+			send: func(m *mailer.Mailer) error {
+				return m.SendMailChangeToken(context.Background(), requested)
+			},
+			wantTo:   "new@example.com",
+			wantText: []string{"https://example.com/confirm?token=pending"},
+		},
+		{
+			name: "added to group",
+			send: func(m *mailer.Mailer) error {
+				return m.SendGroupGranted(context.Background(), user, &store.Group{Name: "testers"}, nil)
+			},
+			wantTo:   "new@example.com",
+			wantText: []string{"You were added to the group testers."},
+		},
+		{
+			name: "roles granted",
+			send: func(m *mailer.Mailer) error {
+				return m.SendGroupGranted(context.Background(), user, &store.Group{Name: "testers"}, []string{"tester", "reviewer"})
+			},
+			wantTo:   "new@example.com",
+			wantText: []string{"You were granted the roles tester, reviewer through the group testers."},
+		},
+		{
+			name: "without previous mail address",
+			send: func(m *mailer.Mailer) error {
+				return m.SendMailChanged(context.Background(), user, "")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(mocks.MailClient)
+			if tt.wantTo != "" {
+				client.On("Send", mock.Anything, mock.MatchedBy(func(msg *mailer.Message) bool {
+					for _, want := range tt.wantText {
+						if !strings.Contains(msg.Text, want) {
+							return false
+						}
+					}
+					return len(msg.To) == 1 && msg.To[0] == tt.wantTo
+				})).Return(nil).Once()
+			}
+			m, err := mailer.NewMailer(client, "gooser@example.com", "gooser", "https://example.com/confirm", "https://example.com/reset",
+				mailer.WithRevertMailUrl("https://example.com/revert-mail"),
+			)
+			if err != nil {
+				t.Fatalf("unable to create mailer: %s", err)
+			}
+			assert.NoError(t, tt.send(m))
+			// notifications without recipient are skipped
+			client.AssertExpectations(t)
+		})
+	}
+}
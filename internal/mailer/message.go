@@ -0,0 +1,368 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxLineLength is the line length recommended by RFC 5322, lines are wrapped after it.
+const maxLineLength = 78
+
+// maxEncodedWordLength is the maximum length of an RFC 2047 encoded word.
+const maxEncodedWordLength = 75
+
+// reservedHeaders are composed from the fields of a message and cannot be set as custom headers.
+var reservedHeaders = map[string]bool{
+	"From":                      true,
+	"Reply-To":                  true,
+	"To":                        true,
+	"Cc":                        true,
+	"Bcc":                       true,
+	"Subject":                   true,
+	"Mime-Version":              true,
+	"Content-Type":              true,
+	"Content-Transfer-Encoding": true,
+	"Content-Disposition":       true,
+}
+
+// Attachment is a file attached to a message.
+type Attachment struct {
+	Filename string
+	// content type, guessed from the extension of the filename if empty.
+	ContentType string
+	Data        []byte
+}
+
+// entity is a MIME entity, either the whole message body or a part of a multipart body.
+type entity struct {
+	contentType string
+	encoding    string
+	disposition string
+	body        string
+}
+
+// Compose builds the given message in the internet message format of RFC 5322 and MIME.
+// The headers are written in a fixed order and wrapped, non-ASCII subjects, display names
+// and header values are written as RFC 2047 encoded words. Bodies which are not 7bit ASCII
+// with short lines are quoted-printable encoded, attachments are base64 encoded.
+// Date and Message-ID are generated unless they are set as custom headers.
+// Bcc recipients are not part of the composed message.
+func Compose(msg *Message) (string, error) {
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return "", fmt.Errorf("invalid from address %s: %w", msg.From, err)
+	}
+	custom := make(map[string]string)
+	for k, v := range msg.Headers {
+		if !validHeaderName(k) {
+			return "", fmt.Errorf("invalid header name %q", k)
+		}
+		name := textproto.CanonicalMIMEHeaderKey(k)
+		if reservedHeaders[name] {
+			return "", fmt.Errorf("header %s is composed from the message and cannot be set", k)
+		}
+		custom[name] = v
+	}
+	date, ok := custom["Date"]
+	if !ok {
+		date = time.Now().Format(time.RFC1123Z)
+	}
+	id, ok := custom["Message-Id"]
+	if !ok {
+		id, err = NewMessageID(from.Address)
+		if err != nil {
+			return "", err
+		}
+	}
+	delete(custom, "Date")
+	delete(custom, "Message-Id")
+	var b strings.Builder
+	writeHeader(&b, "Date", date)
+	writeHeader(&b, "From", from.String())
+	for _, h := range []struct {
+		name      string
+		addresses []string
+	}{
+		{name: "Reply-To", addresses: []string{msg.ReplyTo}},
+		{name: "To", addresses: msg.To},
+		{name: "Cc", addresses: msg.Cc},
+	} {
+		value, err := formatAddresses(h.addresses)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s address: %w", h.name, err)
+		}
+		if value != "" {
+			writeHeader(&b, h.name, value)
+		}
+	}
+	writeHeader(&b, "Subject", encodeWords(msg.Subject, maxLineLength-len("Subject: ")))
+	writeHeader(&b, "Message-ID", id)
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeHeader(&b, name, encodeWords(custom[name], maxLineLength-len(name)-2))
+	}
+	writeHeader(&b, "MIME-Version", "1.0")
+	body, err := composeBody(msg)
+	if err != nil {
+		return "", fmt.Errorf("unable to compose message body: %w", err)
+	}
+	writeEntity(&b, body)
+	return b.String(), nil
+}
+
+// NewMessageID returns a new, random message id for a message sent from the given address.
+func NewMessageID(from string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("unable to generate message id: %w", err)
+	}
+	domain := "localhost"
+	if i := strings.LastIndex(from, "@"); i >= 0 && i < len(from)-1 {
+		domain = from[i+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(buf), domain), nil
+}
+
+// composeBody returns the body of the given message. Messages with a html body
+// are composed as multipart/alternative with the plain text first, as the last part
+// is preferred by mail clients. Messages with attachments are composed as multipart/mixed.
+func composeBody(msg *Message) (*entity, error) {
+	body := textEntity("text/plain; charset=utf-8", msg.Text)
+	if msg.HTML != "" {
+		var err error
+		body, err = multipartEntity("alternative", body, textEntity("text/html; charset=utf-8", msg.HTML))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(msg.Attachments) == 0 {
+		return body, nil
+	}
+	parts := []*entity{body}
+	for _, a := range msg.Attachments {
+		part, err := attachmentEntity(a)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return multipartEntity("mixed", parts...)
+}
+
+// textEntity returns an entity containing the given text. The text is sent as is if it only
+// contains ASCII characters and short lines, otherwise it is quoted-printable encoded.
+func textEntity(contentType, text string) *entity {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if is7bit(text) {
+		return &entity{
+			contentType: contentType,
+			encoding:    "7bit",
+			body:        strings.ReplaceAll(text, "\n", "\r\n"),
+		}
+	}
+	var buf bytes.Buffer
+	w := quotedprintable.NewWriter(&buf)
+	// writing to a buffer does not fail
+	w.Write([]byte(text))
+	w.Close()
+	return &entity{
+		contentType: contentType,
+		encoding:    "quoted-printable",
+		body:        buf.String(),
+	}
+}
+
+// attachmentEntity returns a base64 encoded entity containing the given attachment.
+func attachmentEntity(a Attachment) (*entity, error) {
+	if a.Filename == "" {
+		return nil, fmt.Errorf("attachment without filename")
+	}
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(a.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if _, _, err := mime.ParseMediaType(contentType); err != nil {
+		return nil, fmt.Errorf("invalid content type %s of attachment %s: %w", contentType, a.Filename, err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	var lines []string
+	for len(encoded) > 76 {
+		lines = append(lines, encoded[:76])
+		encoded = encoded[76:]
+	}
+	lines = append(lines, encoded)
+	return &entity{
+		contentType: contentType,
+		encoding:    "base64",
+		disposition: mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}),
+		body:        strings.Join(lines, "\r\n"),
+	}, nil
+}
+
+// multipartEntity returns a multipart entity of the given subtype containing the given parts.
+func multipartEntity(subtype string, parts ...*entity) (*entity, error) {
+	buf := make([]byte, 15)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("unable to generate boundary: %w", err)
+	}
+	// "=_" never occurs in quoted-printable or base64 encoded parts,
+	// the random suffix makes a collision with 7bit parts unlikely
+	boundary := "=_" + hex.EncodeToString(buf)
+	var b strings.Builder
+	for i, p := range parts {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString("--" + boundary + "\r\n")
+		writeEntity(&b, p)
+	}
+	b.WriteString("\r\n--" + boundary + "--\r\n")
+	return &entity{
+		contentType: mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": boundary}),
+		body:        b.String(),
+	}, nil
+}
+
+// writeEntity writes the headers and the body of the given entity.
+func writeEntity(b *strings.Builder, e *entity) {
+	writeHeader(b, "Content-Type", e.contentType)
+	if e.encoding != "" {
+		writeHeader(b, "Content-Transfer-Encoding", e.encoding)
+	}
+	if e.disposition != "" {
+		writeHeader(b, "Content-Disposition", e.disposition)
+	}
+	b.WriteString("\r\n")
+	b.WriteString(e.body)
+}
+
+// writeHeader writes the given header field, folding the value at spaces
+// to keep the lines shorter than the maximum line length where possible.
+func writeHeader(b *strings.Builder, name, value string) {
+	line := name + ":"
+	for _, word := range strings.Split(value, " ") {
+		if word != "" && len(line)+1+len(word) > maxLineLength && strings.TrimSpace(line) != name+":" {
+			b.WriteString(line + "\r\n")
+			line = ""
+		}
+		line += " " + word
+	}
+	b.WriteString(line + "\r\n")
+}
+
+// formatAddresses parses the given addresses and formats them for a header field.
+// Display names containing non-ASCII characters are written as encoded words.
+func formatAddresses(addresses []string) (string, error) {
+	var formatted []string
+	for _, a := range addresses {
+		if a == "" {
+			continue
+		}
+		address, err := mail.ParseAddress(a)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse address %s: %w", a, err)
+		}
+		if address.Name == "" || !needsEncoding(address.Name) {
+			formatted = append(formatted, address.String())
+			continue
+		}
+		formatted = append(formatted, encodeWords(address.Name, maxEncodedWordLength)+" <"+address.Address+">")
+	}
+	return strings.Join(formatted, ", "), nil
+}
+
+// encodeWords returns the given header value as is if it only contains printable ASCII
+// characters, otherwise as RFC 2047 Q encoded words, separated by spaces to allow folding.
+// The first word is at most first characters long, so it fits behind the header name.
+func encodeWords(value string, first int) string {
+	if !needsEncoding(value) {
+		return value
+	}
+	const prefix, suffix = "=?utf-8?q?", "?="
+	max := first
+	if max > maxEncodedWordLength {
+		max = maxEncodedWordLength
+	}
+	var words []string
+	word := prefix
+	for _, r := range value {
+		var encoded string
+		switch {
+		case r == ' ':
+			encoded = "_"
+		case r > ' ' && r < 0x7f && !strings.ContainsRune("=?_\"()<>,;:", r):
+			encoded = string(r)
+		default:
+			for _, c := range []byte(string(r)) {
+				encoded += fmt.Sprintf("=%02X", c)
+			}
+		}
+		// runes are never split across words
+		if len(word)+len(encoded)+len(suffix) > max && word != prefix {
+			words = append(words, word+suffix)
+			word = prefix
+			max = maxEncodedWordLength
+		}
+		word += encoded
+	}
+	return strings.Join(append(words, word+suffix), " ")
+}
+
+// needsEncoding checks if the given header value contains characters
+// which are not printable ASCII characters.
+func needsEncoding(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if (value[i] < ' ' || value[i] > '~') && value[i] != '\t' {
+			return true
+		}
+	}
+	return false
+}
+
+// validHeaderName checks if the given header name only consists of printable
+// ASCII characters except the colon.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r <= ' ' || r > '~' || r == ':' {
+			return false
+		}
+	}
+	return true
+}
+
+// is7bit checks if the given text only contains ASCII characters without
+// carriage returns and lines which do not need to be wrapped.
+func is7bit(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		if len(line) > maxLineLength {
+			return false
+		}
+		for i := 0; i < len(line); i++ {
+			if line[i] >= 0x80 || line[i] == '\r' || line[i] == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
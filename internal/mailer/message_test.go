@@ -0,0 +1,209 @@
+package mailer
+
+import (
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readParts reads the parts of the given multipart body, decoding quoted-printable parts.
+func readParts(t *testing.T, contentType string, body io.Reader) ([]*multipart.Part, []string) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		t.Fatalf("expected multipart content type, got %s", contentType)
+	}
+	var parts []*multipart.Part
+	var bodies []string
+	r := multipart.NewReader(body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			return parts, bodies
+		}
+		if err != nil {
+			t.Fatalf("unable to read part: %s", err)
+		}
+		b, err := ioutil.ReadAll(p)
+		if err != nil {
+			t.Fatalf("unable to read part body: %s", err)
+		}
+		parts = append(parts, p)
+		bodies = append(bodies, string(b))
+	}
+}
+
+// TestCompose tests composing a plain text message.
+func TestCompose(t *testing.T) {
+	raw, err := Compose(&Message{
+		From:    "gooser <gooser@example.com>",
+		To:      []string{"user1@example.com", "User Two <user2@example.com>"},
+		Bcc:     []string{"audit@example.com"},
+		ReplyTo: "support@example.com",
+		Subject: "confirm mail address",
+		Text:    "Hi user1!\n\nPlease confirm your mail address.\n",
+		Headers: map[string]string{
+			"date":             "Sun, 18 Oct 2026 10:00:00 +0000",
+			"Message-ID":       "<1@example.com>",
+			"X-Mailer":         "gooser",
+			"List-Unsubscribe": "<mailto:unsubscribe@example.com>",
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Date: Sun, 18 Oct 2026 10:00:00 +0000\r\n"+
+		"From: \"gooser\" <gooser@example.com>\r\n"+
+		"Reply-To: <support@example.com>\r\n"+
+		"To: <user1@example.com>, \"User Two\" <user2@example.com>\r\n"+
+		"Subject: confirm mail address\r\n"+
+		"Message-ID: <1@example.com>\r\n"+
+		"List-Unsubscribe: <mailto:unsubscribe@example.com>\r\n"+
+		"X-Mailer: gooser\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=utf-8\r\n"+
+		"Content-Transfer-Encoding: 7bit\r\n"+
+		"\r\n"+
+		"Hi user1!\r\n\r\nPlease confirm your mail address.\r\n", raw)
+}
+
+// TestComposeEncoding tests encoding non-ASCII headers and bodies and wrapping long lines.
+func TestComposeEncoding(t *testing.T) {
+	assert := assert.New(t)
+	subject := "Bestätige deine E-Mail-Adresse für gooser, damit du dich anmelden kannst – es dauert nur eine Minute"
+	text := "Grüezi Jürg!\n\n" + strings.Repeat("Bitte bestätige deine Adresse. ", 5) + "\nDanke  \n"
+	html := "<p>Grüezi Jürg!</p>"
+	raw, err := Compose(&Message{
+		From:    "gooser <gooser@example.com>",
+		To:      []string{"Jürg Müller <juerg@example.com>"},
+		Cc:      []string{"Zoë <zoe@example.com>"},
+		Subject: subject,
+		Text:    text,
+		HTML:    html,
+	})
+	if err != nil {
+		t.Fatalf("unable to compose message: %s", err)
+	}
+	for _, line := range strings.Split(raw, "\r\n") {
+		assert.LessOrEqual(len(line), maxLineLength, "line too long: %s", line)
+		for _, r := range line {
+			assert.Less(r, rune(0x80), "line contains non-ASCII characters: %s", line)
+		}
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("unable to parse message: %s", err)
+	}
+	decoded, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.NoError(err)
+	assert.Equal(subject, decoded)
+	to, err := msg.Header.AddressList("To")
+	assert.NoError(err)
+	assert.Equal([]*mail.Address{{Name: "Jürg Müller", Address: "juerg@example.com"}}, to)
+	cc, err := msg.Header.AddressList("Cc")
+	assert.NoError(err)
+	assert.Equal([]*mail.Address{{Name: "Zoë", Address: "zoe@example.com"}}, cc)
+	assert.Regexp(regexp.MustCompile(`^<[0-9a-f]{32}@example\.com>$`), msg.Header.Get("Message-ID"))
+	_, err = msg.Header.Date()
+	assert.NoError(err, "invalid date")
+	assert.Equal("1.0", msg.Header.Get("MIME-Version"))
+	parts, bodies := readParts(t, msg.Header.Get("Content-Type"), msg.Body)
+	if !assert.Len(parts, 2) {
+		return
+	}
+	assert.Equal("text/plain; charset=utf-8", parts[0].Header.Get("Content-Type"))
+	assert.Equal(strings.ReplaceAll(text, "\n", "\r\n"), bodies[0])
+	assert.Equal("text/html; charset=utf-8", parts[1].Header.Get("Content-Type"))
+	assert.Equal(html, bodies[1])
+}
+
+// TestComposeAttachments tests composing a message with attachments.
+func TestComposeAttachments(t *testing.T) {
+	assert := assert.New(t)
+	data := []byte(strings.Repeat(`{"username":"user1"}`, 10))
+	raw, err := Compose(&Message{
+		From:    "gooser@example.com",
+		To:      []string{"user1@example.com"},
+		Subject: "your data",
+		Text:    "your data is attached",
+		HTML:    "<p>your data is attached</p>",
+		Attachments: []Attachment{
+			{Filename: "data.json", Data: data},
+			{Filename: "Übersicht.bin", ContentType: "application/x-gooser", Data: []byte{0, 1, 2}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to compose message: %s", err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("unable to parse message: %s", err)
+	}
+	parts, bodies := readParts(t, msg.Header.Get("Content-Type"), msg.Body)
+	if !assert.Len(parts, 3) {
+		return
+	}
+	// the first part contains the alternative bodies
+	alternatives, _ := readParts(t, parts[0].Header.Get("Content-Type"), strings.NewReader(bodies[0]))
+	assert.Len(alternatives, 2)
+	for i, want := range []struct {
+		filename    string
+		contentType string
+		data        []byte
+	}{
+		{filename: "data.json", contentType: "application/json", data: data},
+		{filename: "Übersicht.bin", contentType: "application/x-gooser", data: []byte{0, 1, 2}},
+	} {
+		p := parts[i+1]
+		assert.Equal(want.filename, p.FileName())
+		assert.Equal(want.contentType, p.Header.Get("Content-Type"))
+		assert.Equal("base64", p.Header.Get("Content-Transfer-Encoding"))
+		b, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(bodies[i+1], "\r\n", ""))
+		assert.NoError(err)
+		assert.Equal(want.data, b)
+	}
+}
+
+// TestComposeErrors tests that invalid messages are rejected.
+func TestComposeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *Message
+	}{
+		{
+			name: "missing from",
+			msg:  &Message{To: []string{"user1@example.com"}},
+		},
+		{
+			name: "invalid recipient",
+			msg:  &Message{From: "gooser@example.com", To: []string{"user1"}},
+		},
+		{
+			name: "invalid reply to",
+			msg:  &Message{From: "gooser@example.com", ReplyTo: "support"},
+		},
+		{
+			name: "reserved header",
+			msg:  &Message{From: "gooser@example.com", Headers: map[string]string{"bcc": "user2@example.com"}},
+		},
+		{
+			name: "invalid header name",
+			msg:  &Message{From: "gooser@example.com", Headers: map[string]string{"X-Injected:\r\nBcc": "user2@example.com"}},
+		},
+		{
+			name: "attachment without filename",
+			msg:  &Message{From: "gooser@example.com", Attachments: []Attachment{{Data: []byte("data")}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compose(tt.msg)
+			assert.Error(t, err)
+		})
+	}
+}
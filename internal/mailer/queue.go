@@ -0,0 +1,297 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rbicker/gooser/internal/logging"
+	"github.com/rbicker/gooser/internal/store"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Queue implements the MailClient interface. Instead of sending the messages
+// right away, it persists them in the store and sends them in the background
+// using the given mail client. Failed messages are retried with an exponential
+// backoff and marked as failed once the maximum number of attempts is reached.
+type Queue struct {
+	store        store.MailStore
+	client       MailClient
+	logger       *zap.Logger
+	workers      int
+	pollInterval time.Duration
+	lease        time.Duration
+	maxAttempts  int32
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	drainTimeout time.Duration
+	wake         chan struct{}
+	stop         chan struct{}
+	wg           sync.WaitGroup
+}
+
+// ensure Queue implements the MailClient interface.
+var _ MailClient = &Queue{}
+
+// NewQueue creates a new mail queue persisting the messages in the given store
+// and sending them using the given mail client.
+// It takes functional parameters to change default options.
+func NewQueue(s store.MailStore, client MailClient, opts ...func(*Queue) error) (*Queue, error) {
+	var q = Queue{
+		store:        s,
+		client:       client,
+		workers:      2,
+		pollInterval: 5 * time.Second,
+		lease:        time.Minute,
+		maxAttempts:  10,
+		minBackoff:   30 * time.Second,
+		maxBackoff:   6 * time.Hour,
+		drainTimeout: 10 * time.Second,
+		wake:         make(chan struct{}, 1),
+	}
+	// run functional options
+	for _, op := range opts {
+		err := op(&q)
+		if err != nil {
+			return nil, fmt.Errorf("setting option failed: %w", err)
+		}
+	}
+	// default logger
+	if q.logger == nil {
+		q.logger = logging.Default()
+	}
+	return &q, nil
+}
+
+// Send queues the given message. It only fails if the message cannot be persisted.
+func (q *Queue) Send(ctx context.Context, msg *Message) error {
+	printer := message.NewPrinter(language.English)
+	var attachments []store.MailAttachment
+	for _, a := range msg.Attachments {
+		attachments = append(attachments, store.MailAttachment{
+			Filename:    a.Filename,
+			ContentType: a.ContentType,
+			Data:        a.Data,
+		})
+	}
+	_, err := q.store.SaveMail(ctx, printer, &store.Mail{
+		From:          msg.From,
+		To:            msg.To,
+		Cc:            msg.Cc,
+		Bcc:           msg.Bcc,
+		ReplyTo:       msg.ReplyTo,
+		Subject:       msg.Subject,
+		Text:          msg.Text,
+		HTML:          msg.HTML,
+		Raw:           msg.Raw,
+		Headers:       msg.Headers,
+		Attachments:   attachments,
+		State:         store.MailPending,
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to queue mail: %w", err)
+	}
+	// let an idle worker pick up the message without waiting for the poll interval
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Backoff returns the duration to wait before the next attempt,
+// after the given number of failed attempts.
+func (q *Queue) Backoff(attempts int32) time.Duration {
+	backoff := q.minBackoff
+	for i := int32(1); i < attempts; i++ {
+		backoff *= 2
+		if backoff >= q.maxBackoff {
+			return q.maxBackoff
+		}
+	}
+	return backoff
+}
+
+// Start starts the workers which send the queued messages.
+func (q *Queue) Start() {
+	q.stop = make(chan struct{})
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+}
+
+// Stop stops the workers. Before returning, the workers send the messages which
+// are already due, until none are left or the drain timeout has passed. Messages
+// which could not be sent remain queued and are sent after the next start.
+func (q *Queue) Stop() {
+	if q.stop == nil {
+		return
+	}
+	close(q.stop)
+	q.wg.Wait()
+	q.stop = nil
+}
+
+// work sends messages until there are none left, then waits for
+// the poll interval to pass or a new message to be queued.
+func (q *Queue) work() {
+	defer q.wg.Done()
+	t := time.NewTicker(q.pollInterval)
+	defer t.Stop()
+	for {
+		q.sendDue(time.Time{})
+		select {
+		case <-q.stop:
+			q.sendDue(time.Now().Add(q.drainTimeout))
+			return
+		case <-q.wake:
+		case <-t.C:
+		}
+	}
+}
+
+// sendDue sends the due messages until there are none left. If the given deadline
+// is not zero, it also returns once the deadline has passed.
+func (q *Queue) sendDue(deadline time.Time) {
+	printer := message.NewPrinter(language.English)
+	for deadline.IsZero() || time.Now().Before(deadline) {
+		mail, err := q.store.ClaimMail(context.Background(), printer, q.lease)
+		if err != nil {
+			q.logger.Error("unable to claim mail", zap.Error(err))
+			return
+		}
+		if mail == nil {
+			return
+		}
+		q.Deliver(context.Background(), mail)
+		if deadline.IsZero() {
+			// the remaining messages are sent while draining
+			select {
+			case <-q.stop:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// Deliver sends the given mail using the queue's mail client and saves the outcome.
+// Failed mails are rescheduled with an exponential backoff until the maximum
+// number of attempts is reached. The bodies and attachments of sent mails are removed,
+// those of failed mails are kept for RetryMail until they are purged.
+func (q *Queue) Deliver(ctx context.Context, mail *store.Mail) {
+	printer := message.NewPrinter(language.English)
+	mail.Attempts++
+	mail.LastError = ""
+	var attachments []Attachment
+	for _, a := range mail.Attachments {
+		attachments = append(attachments, Attachment{
+			Filename:    a.Filename,
+			ContentType: a.ContentType,
+			Data:        a.Data,
+		})
+	}
+	err := q.client.Send(ctx, &Message{
+		From:        mail.From,
+		To:          mail.To,
+		Cc:          mail.Cc,
+		Bcc:         mail.Bcc,
+		ReplyTo:     mail.ReplyTo,
+		Subject:     mail.Subject,
+		Text:        mail.Text,
+		HTML:        mail.HTML,
+		Raw:         mail.Raw,
+		Headers:     mail.Headers,
+		Attachments: attachments,
+	})
+	if err != nil {
+		mail.LastError = err.Error()
+		if mail.Attempts >= q.maxAttempts {
+			mail.State = store.MailFailed
+		} else {
+			mail.NextAttemptAt = time.Now().Add(q.Backoff(mail.Attempts))
+		}
+		q.logger.Warn("unable to send mail",
+			zap.String("mailId", mail.Id),
+			zap.Int32("attempts", mail.Attempts),
+			zap.String("state", mail.State),
+			zap.Error(err),
+		)
+	} else {
+		mail.State = store.MailSent
+		mail.Text = ""
+		mail.HTML = ""
+		mail.Raw = ""
+		mail.Attachments = nil
+	}
+	if _, err := q.store.SaveMail(ctx, printer, mail); err != nil {
+		q.logger.Error("unable to save mail", zap.String("mailId", mail.Id), zap.Error(err))
+	}
+}
+
+// WithQueueLogger sets the logger of the mail queue.
+func WithQueueLogger(logger *zap.Logger) func(*Queue) error {
+	return func(q *Queue) error {
+		q.logger = logger
+		return nil
+	}
+}
+
+// WithQueueWorkers sets the number of workers sending the queued messages.
+func WithQueueWorkers(workers int) func(*Queue) error {
+	return func(q *Queue) error {
+		if workers <= 0 {
+			return fmt.Errorf("number of workers %v is invalid because it is less or equal 0", workers)
+		}
+		q.workers = workers
+		return nil
+	}
+}
+
+// WithQueuePollInterval sets the interval in which the workers look for due messages.
+func WithQueuePollInterval(interval time.Duration) func(*Queue) error {
+	return func(q *Queue) error {
+		q.pollInterval = interval
+		return nil
+	}
+}
+
+// WithQueueMaxAttempts sets the number of attempts after which a message is
+// marked as failed.
+func WithQueueMaxAttempts(attempts int32) func(*Queue) error {
+	return func(q *Queue) error {
+		if attempts <= 0 {
+			return fmt.Errorf("number of attempts %v is invalid because it is less or equal 0", attempts)
+		}
+		q.maxAttempts = attempts
+		return nil
+	}
+}
+
+// WithQueueBackoff sets the minimum and maximum duration between two attempts.
+func WithQueueBackoff(min, max time.Duration) func(*Queue) error {
+	return func(q *Queue) error {
+		if min <= 0 || max < min {
+			return fmt.Errorf("invalid backoff from %s to %s", min, max)
+		}
+		q.minBackoff = min
+		q.maxBackoff = max
+		return nil
+	}
+}
+
+// WithQueueDrainTimeout sets how long the workers keep sending due messages when stopped.
+func WithQueueDrainTimeout(timeout time.Duration) func(*Queue) error {
+	return func(q *Queue) error {
+		if timeout < 0 {
+			return fmt.Errorf("drain timeout cannot be negative")
+		}
+		q.drainTimeout = timeout
+		return nil
+	}
+}
@@ -0,0 +1,203 @@
+package mailer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbicker/gooser/internal/mailer"
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"golang.org/x/text/message"
+)
+
+func TestQueueBackoff(t *testing.T) {
+	q, err := mailer.NewQueue(nil, nil, mailer.WithQueueBackoff(time.Second, 10*time.Second))
+	if err != nil {
+		t.Fatalf("unable to create queue: %s", err)
+	}
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 3, want: 4 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, q.Backoff(tt.attempts), "backoff mismatch for %v attempts", tt.attempts)
+	}
+}
+
+func TestQueueSend(t *testing.T) {
+	assert := assert.New(t)
+	db := new(mocks.MailStore)
+	var saved *store.Mail
+	db.On("SaveMail", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, printer *message.Printer, m *store.Mail) *store.Mail {
+			saved = m
+			return m
+		},
+		nil,
+	).Once()
+	client := new(mocks.MailClient)
+	q, err := mailer.NewQueue(db, client)
+	if err != nil {
+		t.Fatalf("unable to create queue: %s", err)
+	}
+	err = q.Send(context.Background(), &mailer.Message{
+		From:    "gooser@example.com",
+		To:      []string{"user1@example.com"},
+		ReplyTo: "support@example.com",
+		Subject: "hello",
+		Text:    "hello",
+		Headers: map[string]string{"X-Mailer": "gooser"},
+		Attachments: []mailer.Attachment{
+			{Filename: "data.json", Data: []byte("{}")},
+		},
+	})
+	assert.NoError(err)
+	db.AssertExpectations(t)
+	// the message is only sent by the workers
+	client.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	assert.Equal(store.MailPending, saved.State, "state mismatch")
+	assert.Equal([]string{"user1@example.com"}, saved.To, "recipients mismatch")
+	assert.Equal("hello", saved.Text, "text mismatch")
+	assert.Equal("support@example.com", saved.ReplyTo, "reply to mismatch")
+	assert.Equal(map[string]string{"X-Mailer": "gooser"}, saved.Headers, "headers mismatch")
+	assert.Equal([]store.MailAttachment{{Filename: "data.json", Data: []byte("{}")}}, saved.Attachments, "attachments mismatch")
+}
+
+func TestQueueDeliver(t *testing.T) {
+	tests := []struct {
+		name          string
+		sendErr       error
+		attempts      int32
+		wantState     string
+		wantAttempts  int32
+		wantRetryWait bool
+		wantText      string
+	}{
+		{
+			name:         "successful delivery",
+			wantState:    store.MailSent,
+			wantAttempts: 1,
+		},
+		{
+			name:          "failed delivery is retried",
+			sendErr:       errors.New("connection refused"),
+			wantState:     store.MailPending,
+			wantAttempts:  1,
+			wantRetryWait: true,
+			wantText:      "hello",
+		},
+		{
+			name:         "failed delivery without attempts left",
+			sendErr:      errors.New("connection refused"),
+			attempts:     2,
+			wantState:    store.MailFailed,
+			wantAttempts: 3,
+			wantText:     "hello",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			client := new(mocks.MailClient)
+			client.On("Send", mock.Anything, &mailer.Message{
+				To:   []string{"user1@example.com"},
+				Text: "hello",
+			}).Return(tt.sendErr).Once()
+			db := new(mocks.MailStore)
+			var saved *store.Mail
+			db.On("SaveMail", mock.Anything, mock.Anything, mock.Anything).Return(
+				func(ctx context.Context, printer *message.Printer, m *store.Mail) *store.Mail {
+					saved = m
+					return m
+				},
+				nil,
+			).Once()
+			q, err := mailer.NewQueue(db, client, mailer.WithQueueMaxAttempts(3))
+			if err != nil {
+				t.Fatalf("unable to create queue: %s", err)
+			}
+			q.Deliver(context.Background(), &store.Mail{
+				Id:       "mail1",
+				To:       []string{"user1@example.com"},
+				Text:     "hello",
+				State:    store.MailPending,
+				Attempts: tt.attempts,
+			})
+			client.AssertExpectations(t)
+			db.AssertExpectations(t)
+			assert.Equal(tt.wantState, saved.State, "state mismatch")
+			assert.Equal(tt.wantAttempts, saved.Attempts, "attempts mismatch")
+			assert.Equal(tt.wantRetryWait, saved.NextAttemptAt.After(time.Now()), "next attempt mismatch")
+			assert.Equal(tt.wantText, saved.Text, "text mismatch")
+			if tt.sendErr != nil {
+				assert.Equal(tt.sendErr.Error(), saved.LastError, "last error mismatch")
+			}
+		})
+	}
+}
+
+// TestQueueRaw tests that raw messages, for example signed ones, are queued and sent as is.
+func TestQueueRaw(t *testing.T) {
+	assert := assert.New(t)
+	raw := "DKIM-Signature: v=1\r\nSubject: hello\r\n\r\nhello\r\n"
+	db := new(mocks.MailStore)
+	var saved *store.Mail
+	db.On("SaveMail", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, printer *message.Printer, m *store.Mail) *store.Mail {
+			saved = m
+			return m
+		},
+		nil,
+	).Twice()
+	client := new(mocks.MailClient)
+	client.On("Send", mock.Anything, &mailer.Message{
+		To:  []string{"user1@example.com"},
+		Raw: raw,
+	}).Return(nil).Once()
+	q, err := mailer.NewQueue(db, client)
+	if err != nil {
+		t.Fatalf("unable to create queue: %s", err)
+	}
+	err = q.Send(context.Background(), &mailer.Message{To: []string{"user1@example.com"}, Raw: raw})
+	assert.NoError(err)
+	assert.Equal(raw, saved.Raw, "raw message mismatch")
+	q.Deliver(context.Background(), saved)
+	client.AssertExpectations(t)
+	db.AssertExpectations(t)
+	// the raw message contains tokens as well
+	assert.Empty(saved.Raw, "raw message should be removed")
+}
+
+// TestQueueStop tests that stopping the queue sends the mails which are due.
+func TestQueueStop(t *testing.T) {
+	client := new(mocks.MailClient)
+	client.On("Send", mock.Anything, mock.Anything).Return(nil).Twice()
+	db := new(mocks.MailStore)
+	// the workers only find mails once the queue has been stopped
+	idle := make(chan struct{})
+	db.On("ClaimMail", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Run(func(mock.Arguments) {
+		close(idle)
+	}).Once()
+	db.On("ClaimMail", mock.Anything, mock.Anything, mock.Anything).Return(&store.Mail{Id: "mail1", State: store.MailPending}, nil).Once()
+	db.On("ClaimMail", mock.Anything, mock.Anything, mock.Anything).Return(&store.Mail{Id: "mail2", State: store.MailPending}, nil).Once()
+	db.On("ClaimMail", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	db.On("SaveMail", mock.Anything, mock.Anything, mock.Anything).Return(&store.Mail{}, nil).Twice()
+	q, err := mailer.NewQueue(db, client, mailer.WithQueueWorkers(1), mailer.WithQueuePollInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("unable to create queue: %s", err)
+	}
+	q.Start()
+	<-idle
+	q.Stop()
+	// due mails are sent when stopping
+	client.AssertExpectations(t)
+	db.AssertExpectations(t)
+}
@@ -0,0 +1,385 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbicker/gooser/internal/tracing"
+)
+
+// security modes of the smtp connection.
+const (
+	// SMTPSecurityStartTLS upgrades the connection using STARTTLS, which the server has to support.
+	SMTPSecurityStartTLS = "starttls"
+	// SMTPSecurityTLS uses implicit TLS, usually on port 465.
+	SMTPSecurityTLS = "tls"
+	// SMTPSecurityNone uses a plaintext connection, meant for local relays.
+	SMTPSecurityNone = "none"
+)
+
+// SMTPMailer implements the MailClient interface by sending the messages to an smtp server.
+// The connection is kept open for the idle timeout, so bursts of messages are sent using
+// the same connection.
+type SMTPMailer struct {
+	host        string
+	port        string
+	username    string
+	password    string
+	security    string
+	helo        string
+	tlsConfig   *tls.Config
+	timeout     time.Duration
+	idleTimeout time.Duration
+	mu          sync.Mutex
+	conn        net.Conn
+	client      *smtp.Client
+	lastUsed    time.Time
+}
+
+// ensure SMTPMailer implements the MailClient interface.
+var _ MailClient = &SMTPMailer{}
+
+// NewSMTPMailer creates a new smtp mailer sending to the given host and port.
+// Unless set otherwise, implicit TLS is used for port 465 and STARTTLS for any other port.
+// It takes functional parameters to change default options.
+func NewSMTPMailer(host, port string, opts ...func(*SMTPMailer) error) (*SMTPMailer, error) {
+	var m = SMTPMailer{
+		host:        host,
+		port:        port,
+		timeout:     30 * time.Second,
+		idleTimeout: 30 * time.Second,
+	}
+	// run functional options
+	for _, op := range opts {
+		err := op(&m)
+		if err != nil {
+			return nil, fmt.Errorf("setting option failed: %w", err)
+		}
+	}
+	if m.security == "" {
+		m.security = SMTPSecurityStartTLS
+		if port == "465" {
+			m.security = SMTPSecurityTLS
+		}
+	}
+	if m.tlsConfig == nil {
+		m.tlsConfig = &tls.Config{}
+	}
+	if m.tlsConfig.ServerName == "" {
+		m.tlsConfig = m.tlsConfig.Clone()
+		m.tlsConfig.ServerName = host
+	}
+	return &m, nil
+}
+
+// WithSMTPAuth sets the credentials used to authenticate. No authentication
+// takes place if the username is empty.
+func WithSMTPAuth(username, password string) func(*SMTPMailer) error {
+	return func(m *SMTPMailer) error {
+		m.username = username
+		m.password = password
+		return nil
+	}
+}
+
+// WithSMTPSecurity sets the security mode of the connection,
+// one of starttls, tls or none.
+func WithSMTPSecurity(security string) func(*SMTPMailer) error {
+	return func(m *SMTPMailer) error {
+		switch security {
+		case SMTPSecurityStartTLS, SMTPSecurityTLS, SMTPSecurityNone:
+		default:
+			return fmt.Errorf("invalid smtp security %s, expected %s, %s or %s", security, SMTPSecurityStartTLS, SMTPSecurityTLS, SMTPSecurityNone)
+		}
+		m.security = security
+		return nil
+	}
+}
+
+// WithSMTPHelo sets the name sent with the EHLO command, localhost is used by default.
+func WithSMTPHelo(name string) func(*SMTPMailer) error {
+	return func(m *SMTPMailer) error {
+		m.helo = name
+		return nil
+	}
+}
+
+// WithSMTPTLSConfig sets the tls configuration, for example to trust a custom CA.
+func WithSMTPTLSConfig(config *tls.Config) func(*SMTPMailer) error {
+	return func(m *SMTPMailer) error {
+		m.tlsConfig = config
+		return nil
+	}
+}
+
+// WithSMTPTimeout sets the timeout for establishing a connection and for sending a message.
+func WithSMTPTimeout(timeout time.Duration) func(*SMTPMailer) error {
+	return func(m *SMTPMailer) error {
+		if timeout <= 0 {
+			return fmt.Errorf("smtp timeout needs to be greater than 0")
+		}
+		m.timeout = timeout
+		return nil
+	}
+}
+
+// WithSMTPIdleTimeout sets how long an unused connection is kept open. 0 disables
+// the reuse of connections, every message is sent using a new connection.
+func WithSMTPIdleTimeout(timeout time.Duration) func(*SMTPMailer) error {
+	return func(m *SMTPMailer) error {
+		if timeout < 0 {
+			return fmt.Errorf("smtp idle timeout cannot be negative")
+		}
+		m.idleTimeout = timeout
+		return nil
+	}
+}
+
+// deadline returns the deadline of an operation started now,
+// which is the earlier of the timeout and the context's deadline.
+func (m *SMTPMailer) deadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(m.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		return d
+	}
+	return deadline
+}
+
+// connect opens a connection to the server, using TLS in case of implicit TLS,
+// and waits for the server's greeting.
+func (m *SMTPMailer) connect(ctx context.Context) (net.Conn, *smtp.Client, error) {
+	addr := net.JoinHostPort(m.host, m.port)
+	dialer := net.Dialer{Timeout: m.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while creating tcp connection to host %s with port %s: %w", m.host, m.port, err)
+	}
+	conn.SetDeadline(m.deadline(ctx))
+	if m.security == SMTPSecurityTLS {
+		tlsConn := tls.Client(conn, m.tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("tls handshake with host %s with port %s failed: %w", m.host, m.port, err)
+		}
+		conn = tlsConn
+	}
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("error while creating smtp client for host %s with port %s: %w", m.host, m.port, err)
+	}
+	return conn, client, nil
+}
+
+// open connects to the server, upgrades the connection using STARTTLS if required
+// and authenticates.
+func (m *SMTPMailer) open(ctx context.Context) (net.Conn, *smtp.Client, error) {
+	conn, client, err := m.connect(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := m.handshake(client); err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+	return conn, client, nil
+}
+
+// handshake greets the server, starts TLS and authenticates, depending on the settings.
+func (m *SMTPMailer) handshake(client *smtp.Client) error {
+	if m.helo != "" {
+		if err := client.Hello(m.helo); err != nil {
+			return fmt.Errorf("error while greeting smtp server: %w", err)
+		}
+	}
+	if m.security == SMTPSecurityStartTLS {
+		// never fall back to plaintext
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server %s does not support STARTTLS", m.host)
+		}
+		if err := client.StartTLS(m.tlsConfig); err != nil {
+			return fmt.Errorf("unable to initialize StartTLS: %w", err)
+		}
+	}
+	if m.username == "" {
+		return nil
+	}
+	auth, err := m.auth(client)
+	if err != nil {
+		return err
+	}
+	if err := client.Auth(auth); err != nil {
+		return fmt.Errorf("error while creating smtp client authentication: %w", err)
+	}
+	return nil
+}
+
+// auth chooses the authentication mechanism from the ones advertised by the server.
+// On encrypted connections, PLAIN is preferred over LOGIN and CRAM-MD5. On plaintext
+// connections, only CRAM-MD5 is used, as it does not reveal the password.
+func (m *SMTPMailer) auth(client *smtp.Client) (smtp.Auth, error) {
+	ok, params := client.Extension("AUTH")
+	if !ok {
+		return nil, fmt.Errorf("smtp server %s does not support authentication", m.host)
+	}
+	advertised := strings.Fields(strings.ToUpper(params))
+	preferred := []string{"CRAM-MD5"}
+	if _, encrypted := client.TLSConnectionState(); encrypted {
+		preferred = []string{"PLAIN", "LOGIN", "CRAM-MD5"}
+	}
+	for _, mechanism := range preferred {
+		if !contains(advertised, mechanism) {
+			continue
+		}
+		switch mechanism {
+		case "PLAIN":
+			return smtp.PlainAuth("", m.username, m.password, m.host), nil
+		case "LOGIN":
+			return LoginAuth(m.username, m.password), nil
+		case "CRAM-MD5":
+			return smtp.CRAMMD5Auth(m.username, m.password), nil
+		}
+	}
+	return nil, fmt.Errorf("smtp server %s supports none of the authentication mechanisms %s", m.host, strings.Join(preferred, ", "))
+}
+
+// connection returns the open connection if it is still usable,
+// otherwise it opens a new one. The caller has to hold the lock.
+func (m *SMTPMailer) connection(ctx context.Context) (*smtp.Client, error) {
+	if m.client != nil {
+		m.conn.SetDeadline(m.deadline(ctx))
+		if time.Since(m.lastUsed) < m.idleTimeout && m.client.Noop() == nil {
+			return m.client, nil
+		}
+		m.closeConnection()
+	}
+	conn, client, err := m.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.conn, m.client = conn, client
+	return client, nil
+}
+
+// closeConnection quits and closes the open connection. The caller has to hold the lock.
+func (m *SMTPMailer) closeConnection() {
+	if m.client == nil {
+		return
+	}
+	m.client.Quit()
+	m.client.Close()
+	m.conn, m.client = nil, nil
+}
+
+// closeIdle closes the open connection if it has not been used for the idle timeout.
+func (m *SMTPMailer) closeIdle() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.client != nil && time.Since(m.lastUsed) >= m.idleTimeout {
+		m.closeConnection()
+	}
+}
+
+// Close closes the open connection, if any.
+func (m *SMTPMailer) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closeConnection()
+	return nil
+}
+
+// Ping checks if the configured SMTP server is reachable by
+// connecting to it and waiting for its greeting.
+func (m *SMTPMailer) Ping(ctx context.Context) error {
+	_, client, err := m.connect(ctx)
+	if err != nil {
+		return err
+	}
+	if err := client.Quit(); err != nil {
+		// quit only closes the connection if the server acknowledges it
+		client.Close()
+		return err
+	}
+	return nil
+}
+
+// Send sends the mail message using the open connection or a new one.
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) (err error) {
+	ctx, span := tracing.Start(ctx, "mailer.Send")
+	defer func() {
+		tracing.End(span, err)
+	}()
+	if msg.From == "" {
+		// the username is used as from address
+		withFrom := *msg
+		withFrom.From = m.username
+		msg = &withFrom
+	}
+	message, err := composeOrRaw(msg)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	client, err := m.connection(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.transaction(client, msg, message); err != nil {
+		// the state of the connection is unknown
+		m.client.Close()
+		m.conn, m.client = nil, nil
+		return err
+	}
+	if m.idleTimeout == 0 {
+		m.closeConnection()
+		return nil
+	}
+	m.lastUsed = time.Now()
+	time.AfterFunc(m.idleTimeout, m.closeIdle)
+	return nil
+}
+
+// transaction sends the given message using the given client.
+func (m *SMTPMailer) transaction(client *smtp.Client, msg *Message, message string) error {
+	// set Mail (from address)
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address %s: %w", msg.From, err)
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("unable to set from address %s: %w", from.Address, err)
+	}
+	// set RCPT (all recipients)
+	for _, recipients := range [][]string{msg.To, msg.Cc, msg.Bcc} {
+		for _, r := range recipients {
+			rcpt, err := mail.ParseAddress(r)
+			if err != nil {
+				return fmt.Errorf("invalid recipient %s: %w", r, err)
+			}
+			if err := client.Rcpt(rcpt.Address); err != nil {
+				return fmt.Errorf("unable to add recipient %s: %w", rcpt.Address, err)
+			}
+		}
+	}
+	// create writer for client data
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error while creating writer for smtp client data: %w", err)
+	}
+	// write message (headers + body)
+	if _, err := w.Write([]byte(message)); err != nil {
+		return fmt.Errorf("error while writing to smtp client data: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error while closing writer for smtp client data: %w", err)
+	}
+	return nil
+}
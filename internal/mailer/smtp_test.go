@@ -0,0 +1,450 @@
+package mailer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMail is a message received by the fake smtp server.
+type fakeMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer is a minimal in-process smtp server used for testing.
+type fakeSMTPServer struct {
+	listener    net.Listener
+	tlsConfig   *tls.Config
+	implicitTLS bool
+	// extensions advertised in addition to STARTTLS.
+	extensions []string
+	startTLS   bool
+	username   string
+	password   string
+	// greet is false for servers which never send their greeting.
+	greet bool
+	// rejectQuit makes the server reject QUIT and keep the connection open.
+	rejectQuit  bool
+	mu          sync.Mutex
+	connections int
+	disconnects int
+	helo        string
+	mechanism   string
+	mails       []fakeMail
+}
+
+// newTestTLSConfigs returns the tls configurations of a server with a self-signed
+// certificate for 127.0.0.1 and of a client trusting it.
+func newTestTLSConfigs(t *testing.T) (*tls.Config, *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %s", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+	return serverConfig, &tls.Config{RootCAs: pool}
+}
+
+// start starts listening on a random port and returns the port.
+func (s *fakeSMTPServer) start(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	s.listener = l
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.connections++
+			s.mu.Unlock()
+			go s.handle(conn)
+		}
+	}()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	return port
+}
+
+// handle serves a single connection.
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	defer func() {
+		s.mu.Lock()
+		s.disconnects++
+		s.mu.Unlock()
+	}()
+	if s.implicitTLS {
+		conn = tls.Server(conn, s.tlsConfig)
+	}
+	if !s.greet {
+		// wait for the client to give up
+		conn.Read(make([]byte, 1))
+		return
+	}
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 fake ESMTP")
+	encrypted := s.implicitTLS
+	var mail *fakeMail
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		arg := strings.TrimSpace(strings.TrimPrefix(line, strings.SplitN(line, " ", 2)[0]))
+		switch cmd {
+		case "EHLO", "HELO":
+			s.mu.Lock()
+			s.helo = arg
+			s.mu.Unlock()
+			exts := s.extensions
+			if s.startTLS && !encrypted {
+				exts = append([]string{"STARTTLS"}, exts...)
+			}
+			lines := append([]string{"fake"}, exts...)
+			for i, l := range lines {
+				sep := "-"
+				if i == len(lines)-1 {
+					sep = " "
+				}
+				tp.PrintfLine("250%s%s", sep, l)
+			}
+		case "STARTTLS":
+			tp.PrintfLine("220 ready to start tls")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			tp = textproto.NewConn(conn)
+			encrypted = true
+		case "AUTH":
+			if !s.auth(tp, arg) {
+				tp.PrintfLine("535 authentication failed")
+				continue
+			}
+			tp.PrintfLine("235 authenticated")
+		case "MAIL":
+			mail = &fakeMail{from: addressArg(arg)}
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			mail.to = append(mail.to, addressArg(arg))
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			b, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			mail.data = string(b)
+			s.mu.Lock()
+			s.mails = append(s.mails, *mail)
+			s.mu.Unlock()
+			tp.PrintfLine("250 queued")
+		case "RSET", "NOOP":
+			tp.PrintfLine("250 ok")
+		case "QUIT":
+			if s.rejectQuit {
+				tp.PrintfLine("500 not now")
+				continue
+			}
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 unknown command")
+		}
+	}
+}
+
+// auth runs the authentication exchange for the given AUTH arguments.
+func (s *fakeSMTPServer) auth(tp *textproto.Conn, arg string) bool {
+	ss := strings.Fields(arg)
+	mechanism := strings.ToUpper(ss[0])
+	challenge := func(c string) string {
+		tp.PrintfLine("334 %s", base64.StdEncoding.EncodeToString([]byte(c)))
+		line, _ := tp.ReadLine()
+		b, _ := base64.StdEncoding.DecodeString(line)
+		return string(b)
+	}
+	var ok bool
+	switch mechanism {
+	case "PLAIN":
+		if len(ss) < 2 {
+			return false
+		}
+		b, _ := base64.StdEncoding.DecodeString(ss[1])
+		ok = string(b) == "\x00"+s.username+"\x00"+s.password
+	case "LOGIN":
+		ok = challenge("Username:") == s.username && challenge("Password:") == s.password
+	case "CRAM-MD5":
+		c := "<1234@fake>"
+		mac := hmac.New(md5.New, []byte(s.password))
+		mac.Write([]byte(c))
+		ok = challenge(c) == s.username+" "+hex.EncodeToString(mac.Sum(nil))
+	}
+	s.mu.Lock()
+	s.mechanism = mechanism
+	s.mu.Unlock()
+	return ok
+}
+
+// addressArg returns the address of a MAIL or RCPT argument.
+func addressArg(arg string) string {
+	a := arg[strings.Index(arg, "<")+1:]
+	return a[:strings.Index(a, ">")]
+}
+
+// TestSMTPMailer tests the security modes and the authentication mechanisms.
+func TestSMTPMailer(t *testing.T) {
+	serverTLS, clientTLS := newTestTLSConfigs(t)
+	tests := []struct {
+		name          string
+		server        *fakeSMTPServer
+		opts          []func(*SMTPMailer) error
+		wantMechanism string
+		wantErr       bool
+	}{
+		{
+			name: "starttls with plain auth",
+			server: &fakeSMTPServer{
+				startTLS:   true,
+				extensions: []string{"AUTH CRAM-MD5 LOGIN PLAIN"},
+			},
+			opts:          []func(*SMTPMailer) error{WithSMTPAuth("user", "secret")},
+			wantMechanism: "PLAIN",
+		},
+		{
+			name: "implicit tls with login auth",
+			server: &fakeSMTPServer{
+				implicitTLS: true,
+				extensions:  []string{"AUTH LOGIN"},
+			},
+			opts:          []func(*SMTPMailer) error{WithSMTPSecurity(SMTPSecurityTLS), WithSMTPAuth("user", "secret")},
+			wantMechanism: "LOGIN",
+		},
+		{
+			name: "plaintext with cram-md5 auth",
+			server: &fakeSMTPServer{
+				extensions: []string{"AUTH PLAIN CRAM-MD5"},
+			},
+			opts:          []func(*SMTPMailer) error{WithSMTPSecurity(SMTPSecurityNone), WithSMTPAuth("user", "secret")},
+			wantMechanism: "CRAM-MD5",
+		},
+		{
+			name:   "plaintext without auth",
+			server: &fakeSMTPServer{},
+			opts:   []func(*SMTPMailer) error{WithSMTPSecurity(SMTPSecurityNone)},
+		},
+		{
+			name: "plaintext refuses to send password",
+			server: &fakeSMTPServer{
+				extensions: []string{"AUTH PLAIN LOGIN"},
+			},
+			opts:    []func(*SMTPMailer) error{WithSMTPSecurity(SMTPSecurityNone), WithSMTPAuth("user", "secret")},
+			wantErr: true,
+		},
+		{
+			name:    "starttls not supported",
+			server:  &fakeSMTPServer{},
+			wantErr: true,
+		},
+		{
+			name: "wrong password",
+			server: &fakeSMTPServer{
+				startTLS:   true,
+				extensions: []string{"AUTH PLAIN"},
+			},
+			opts:          []func(*SMTPMailer) error{WithSMTPAuth("user", "wrong")},
+			wantMechanism: "PLAIN",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			s := tt.server
+			s.greet = true
+			s.tlsConfig = serverTLS
+			s.username, s.password = "user", "secret"
+			port := s.start(t)
+			defer s.listener.Close()
+			opts := append([]func(*SMTPMailer) error{WithSMTPTLSConfig(clientTLS), WithSMTPHelo("mail.example.com")}, tt.opts...)
+			m, err := NewSMTPMailer("127.0.0.1", port, opts...)
+			if err != nil {
+				t.Fatalf("unable to create smtp mailer: %s", err)
+			}
+			defer m.Close()
+			err = m.Send(context.Background(), &Message{
+				From:    "gooser@example.com",
+				To:      []string{"user1@example.com"},
+				Bcc:     []string{"audit@example.com"},
+				Subject: "hello",
+				Text:    "hello user1",
+			})
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			assert.Equal(tt.wantMechanism, s.mechanism, "authentication mechanism mismatch")
+			if tt.wantErr {
+				assert.Error(err)
+				assert.Len(s.mails, 0, "no mail should be sent")
+				return
+			}
+			assert.NoError(err)
+			assert.Equal("mail.example.com", s.helo, "helo mismatch")
+			if assert.Len(s.mails, 1) {
+				assert.Equal("gooser@example.com", s.mails[0].from, "from mismatch")
+				assert.Equal([]string{"user1@example.com", "audit@example.com"}, s.mails[0].to, "recipients mismatch")
+				assert.Contains(s.mails[0].data, "Subject: hello", "subject mismatch")
+				assert.NotContains(s.mails[0].data, "audit@example.com", "bcc must not be part of the message")
+			}
+		})
+	}
+}
+
+// TestSMTPMailerReuse tests that connections are reused within the idle timeout.
+func TestSMTPMailerReuse(t *testing.T) {
+	tests := []struct {
+		name            string
+		idleTimeout     time.Duration
+		pause           time.Duration
+		wantConnections int
+	}{
+		{
+			name:            "reuse",
+			idleTimeout:     time.Minute,
+			wantConnections: 1,
+		},
+		{
+			name:            "reuse disabled",
+			wantConnections: 3,
+		},
+		{
+			name:            "idle connection is closed",
+			idleTimeout:     20 * time.Millisecond,
+			pause:           50 * time.Millisecond,
+			wantConnections: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeSMTPServer{greet: true}
+			port := s.start(t)
+			defer s.listener.Close()
+			m, err := NewSMTPMailer("127.0.0.1", port, WithSMTPSecurity(SMTPSecurityNone), WithSMTPIdleTimeout(tt.idleTimeout))
+			if err != nil {
+				t.Fatalf("unable to create smtp mailer: %s", err)
+			}
+			defer m.Close()
+			for i := 0; i < 3; i++ {
+				err := m.Send(context.Background(), &Message{
+					From: "gooser@example.com",
+					To:   []string{fmt.Sprintf("user%d@example.com", i)},
+					Text: "hello",
+				})
+				if err != nil {
+					t.Fatalf("unable to send mail %d: %s", i, err)
+				}
+				time.Sleep(tt.pause)
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			assert.Len(t, s.mails, 3, "number of mails mismatch")
+			assert.Equal(t, tt.wantConnections, s.connections, "number of connections mismatch")
+		})
+	}
+}
+
+// TestSMTPMailerTimeout tests that a server which does not respond does not block.
+func TestSMTPMailerTimeout(t *testing.T) {
+	s := &fakeSMTPServer{}
+	port := s.start(t)
+	defer s.listener.Close()
+	m, err := NewSMTPMailer("127.0.0.1", port, WithSMTPSecurity(SMTPSecurityNone), WithSMTPTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unable to create smtp mailer: %s", err)
+	}
+	start := time.Now()
+	err = m.Send(context.Background(), &Message{To: []string{"user1@example.com"}, Text: "hello"})
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second, "send should time out")
+	// ping waits for the greeting as well
+	assert.Error(t, m.Ping(context.Background()))
+}
+
+// TestSMTPMailerPing tests that ping closes the connection, even if the server rejects QUIT.
+func TestSMTPMailerPing(t *testing.T) {
+	tests := []struct {
+		name       string
+		rejectQuit bool
+		wantErr    bool
+	}{
+		{
+			name: "ping",
+		},
+		{
+			name:       "quit rejected",
+			rejectQuit: true,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			s := &fakeSMTPServer{greet: true, rejectQuit: tt.rejectQuit}
+			port := s.start(t)
+			defer s.listener.Close()
+			m, err := NewSMTPMailer("127.0.0.1", port, WithSMTPSecurity(SMTPSecurityNone))
+			if err != nil {
+				t.Fatalf("unable to create smtp mailer: %s", err)
+			}
+			err = m.Ping(context.Background())
+			assert.Equal(tt.wantErr, err != nil, "error mismatch")
+			// the server notices the closed connection
+			assert.Eventually(func() bool {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				return s.disconnects == 1
+			}, time.Second, 10*time.Millisecond, "connection should be closed")
+		})
+	}
+}
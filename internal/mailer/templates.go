@@ -0,0 +1,260 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/rbicker/gooser/internal/store"
+	"golang.org/x/text/language"
+)
+
+// names of the templates, one for each type of message.
+const (
+	TemplateConfirm         = "confirm"
+	TemplatePasswordReset   = "password_reset"
+	TemplateAccountDeletion = "account_deletion"
+	TemplateInvitation      = "invitation"
+	TemplatePasswordChanged = "password_changed"
+	TemplateMailChanged     = "mail_changed"
+	TemplateGroupGranted    = "group_granted"
+)
+
+// template parts, every message consists of a subject, a text and a html body.
+const (
+	partSubject = "subject"
+	partText    = "txt"
+	partHTML    = "html"
+)
+
+// templateNames contains the names of all templates.
+var templateNames = []string{
+	TemplateConfirm,
+	TemplatePasswordReset,
+	TemplateAccountDeletion,
+	TemplateInvitation,
+	TemplatePasswordChanged,
+	TemplateMailChanged,
+	TemplateGroupGranted,
+}
+
+// templateParts contains the parts every template consists of.
+var templateParts = []string{partSubject, partText, partHTML}
+
+// templateFuncs are the functions available in all templates.
+var templateFuncs = map[string]interface{}{
+	"formatTime": formatTime,
+}
+
+// formatTime formats the given time for messages.
+func formatTime(t time.Time) string {
+	return t.Format("2006-01-02 15:04 MST")
+}
+
+// Branding contains the site specific values available in all templates.
+type Branding struct {
+	SiteName       string
+	LogoUrl        string
+	SupportAddress string
+}
+
+// TemplateData is the data a template is rendered with.
+type TemplateData struct {
+	Branding
+	User *store.User
+	// link to confirm the mail address, reset the password, accept the invitation
+	// or revert a mail address change, empty for messages without link.
+	Link string
+	// localized sentences of the message, used by the default templates.
+	Notice Notice
+	// mail address before it was changed, only set for mail changed notifications.
+	PreviousMail string
+	// group and roles granted to the user, only set for group granted notifications.
+	Group *store.Group
+	Roles []string
+}
+
+// Notice contains the sentences of a message, translated
+// into the language of the user by the message catalog.
+type Notice struct {
+	Subject  string
+	Greeting string
+	Message  string
+	Advice   string
+	// text of the link, empty for messages without link.
+	LinkText string
+}
+
+// Content is a rendered template.
+type Content struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// executor is implemented by text and html templates.
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// Templates renders the mail messages. Every part of a template can be
+// overridden per language, the embedded defaults are used as fallback.
+type Templates struct {
+	// templates by language, template name and part,
+	// the language is empty for templates used for all languages.
+	templates map[string]map[string]map[string]executor
+}
+
+// DefaultTemplates returns the embedded default templates.
+func DefaultTemplates() *Templates {
+	t, err := LoadTemplates("")
+	if err != nil {
+		// the embedded templates are covered by tests
+		panic(err)
+	}
+	return t
+}
+
+// LoadTemplates loads the templates from the given directory. The files are named
+// <template>.<part>.tmpl, where part is one of subject, txt or html. Files in a
+// subdirectory named after a language, for example de or de-CH, are only used for that
+// language. Missing files fall back to the embedded defaults. If the directory is empty,
+// only the defaults are loaded.
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{
+		templates: make(map[string]map[string]map[string]executor),
+	}
+	for name, parts := range defaultTemplates {
+		for part, s := range parts {
+			if err := t.add("", name, part, s); err != nil {
+				return nil, fmt.Errorf("unable to parse default template %s.%s: %w", name, part, err)
+			}
+		}
+	}
+	if dir == "" {
+		return t, nil
+	}
+	if err := t.loadDir(dir, ""); err != nil {
+		return nil, err
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read template directory %s: %w", dir, err)
+	}
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		tag, err := language.Parse(info.Name())
+		if err != nil {
+			return nil, fmt.Errorf("template directory %s is not named after a language: %w", info.Name(), err)
+		}
+		if err := t.loadDir(filepath.Join(dir, info.Name()), tag.String()); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// loadDir loads the template files in the given directory for the given language.
+func (t *Templates) loadDir(dir, lang string) error {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("unable to read template directory %s: %w", dir, err)
+	}
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".tmpl") {
+			continue
+		}
+		ss := strings.Split(strings.TrimSuffix(info.Name(), ".tmpl"), ".")
+		if len(ss) != 2 || !contains(templateNames, ss[0]) || !contains(templateParts, ss[1]) {
+			return fmt.Errorf("unknown template file %s, expected <template>.<part>.tmpl", filepath.Join(dir, info.Name()))
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return fmt.Errorf("unable to read template file: %w", err)
+		}
+		if err := t.add(lang, ss[0], ss[1], string(b)); err != nil {
+			return fmt.Errorf("unable to parse template file %s: %w", filepath.Join(dir, info.Name()), err)
+		}
+	}
+	return nil
+}
+
+// add parses the given template part and adds it for the given language.
+func (t *Templates) add(lang, name, part, s string) error {
+	var e executor
+	var err error
+	if part == partHTML {
+		// html templates escape the given data
+		e, err = htmltemplate.New(name).Funcs(templateFuncs).Parse(s)
+	} else {
+		e, err = texttemplate.New(name).Funcs(templateFuncs).Parse(s)
+	}
+	if err != nil {
+		return err
+	}
+	if t.templates[lang] == nil {
+		t.templates[lang] = make(map[string]map[string]executor)
+	}
+	if t.templates[lang][name] == nil {
+		t.templates[lang][name] = make(map[string]executor)
+	}
+	t.templates[lang][name][part] = e
+	return nil
+}
+
+// lookup returns the given template part for the given language. The language
+// is matched exactly first, then by its base language, then the default is used.
+func (t *Templates) lookup(name, part, lang string) executor {
+	var langs []string
+	if tag, err := language.Parse(lang); err == nil {
+		langs = append(langs, tag.String())
+		if base, conf := tag.Base(); conf != language.No {
+			langs = append(langs, base.String())
+		}
+	}
+	for _, l := range append(langs, "") {
+		if e, ok := t.templates[l][name][part]; ok {
+			return e
+		}
+	}
+	return nil
+}
+
+// Render renders the template with the given name in the given language.
+func (t *Templates) Render(name, lang string, data *TemplateData) (*Content, error) {
+	if !contains(templateNames, name) {
+		return nil, fmt.Errorf("unknown template %s", name)
+	}
+	res := make(map[string]string, len(templateParts))
+	for _, part := range templateParts {
+		var buf bytes.Buffer
+		if err := t.lookup(name, part, lang).Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("unable to render %s of template %s: %w", part, name, err)
+		}
+		res[part] = buf.String()
+	}
+	return &Content{
+		// the subject must fit into a single header line
+		Subject: strings.Join(strings.Fields(res[partSubject]), " "),
+		Text:    res[partText],
+		HTML:    res[partHTML],
+	}, nil
+}
+
+// contains checks if the given slice contains the given string.
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package mailer
+
+// htmlHeader and htmlFooter frame the html bodies of the default templates.
+const (
+	htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body style="font-family: sans-serif; line-height: 1.5; color: #222222;">
+{{if .LogoUrl}}<p><img src="{{.LogoUrl}}" alt="{{.SiteName}}" style="max-height: 48px;"></p>{{end}}
+`
+	htmlFooter = `
+<hr style="border: none; border-top: 1px solid #dddddd;">
+<p style="font-size: small; color: #777777;">{{.SiteName}}{{if .SupportAddress}} &middot; <a href="mailto:{{.SupportAddress}}">{{.SupportAddress}}</a>{{end}}</p>
+</body>
+</html>
+`
+	textFooter = `{{if .SupportAddress}}
+
+--
+{{.SiteName}} · {{.SupportAddress}}{{end}}
+`
+)
+
+// noticeTemplate contains the parts shared by all security notifications,
+// the sentences are translated by the message catalog.
+var noticeTemplate = map[string]string{
+	partSubject: `{{.SiteName}}: {{.Notice.Subject}}`,
+	partText: `{{.Notice.Greeting}} {{.Notice.Message}}
+
+{{.Notice.Advice}}{{if .Link}}
+{{.Link}}{{end}}` + textFooter,
+	partHTML: htmlHeader + `<p>{{.Notice.Greeting}}</p>
+<p>{{.Notice.Message}}</p>
+<p>{{.Notice.Advice}}</p>{{if .Link}}
+<p><a href="{{.Link}}">{{.Notice.LinkText}}</a></p>{{end}}` + htmlFooter,
+}
+
+// defaultTemplates contains the parts of all templates by name,
+// they are used if no template directory is given or a file is missing.
+var defaultTemplates = map[string]map[string]string{
+	TemplateConfirm: {
+		partSubject: `{{.SiteName}}: {{.Notice.Subject}}`,
+		partText: `{{.Notice.Greeting}} {{.Notice.Message}}
+{{.Link}}` + textFooter,
+		partHTML: htmlHeader + `<p>{{.Notice.Greeting}}</p>
+<p>{{.Notice.Message}}</p>
+<p><a href="{{.Link}}">{{.Notice.LinkText}}</a></p>` + htmlFooter,
+	},
+	TemplatePasswordReset: {
+		partSubject: `{{.SiteName}}: {{.Notice.Subject}}`,
+		partText: `{{.Notice.Greeting}} {{.Notice.Message}}
+{{.Link}}
+
+{{.Notice.Advice}}` + textFooter,
+		partHTML: htmlHeader + `<p>{{.Notice.Greeting}}</p>
+<p>{{.Notice.Message}}</p>
+<p><a href="{{.Link}}">{{.Notice.LinkText}}</a></p>
+<p>{{.Notice.Advice}}</p>` + htmlFooter,
+	},
+	TemplateAccountDeletion: {
+		partSubject: `{{.SiteName}}: {{.Notice.Subject}}`,
+		partText: `{{.Notice.Greeting}} {{.Notice.Message}}
+
+{{.Notice.Advice}}` + textFooter,
+		partHTML: htmlHeader + `<p>{{.Notice.Greeting}}</p>
+<p>{{.Notice.Message}}</p>
+<p>{{.Notice.Advice}}</p>` + htmlFooter,
+	},
+	TemplateInvitation: {
+		partSubject: `{{.SiteName}}: {{.Notice.Subject}}`,
+		partText: `{{.Notice.Greeting}} {{.Notice.Message}}
+{{.Link}}
+
+{{.Notice.Advice}}` + textFooter,
+		partHTML: htmlHeader + `<p>{{.Notice.Greeting}}</p>
+<p>{{.Notice.Message}}</p>
+<p><a href="{{.Link}}">{{.Notice.LinkText}}</a></p>
+<p>{{.Notice.Advice}}</p>` + htmlFooter,
+	},
+	TemplatePasswordChanged: noticeTemplate,
+	TemplateMailChanged:     noticeTemplate,
+	TemplateGroupGranted:    noticeTemplate,
+}
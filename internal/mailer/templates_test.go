@@ -0,0 +1,238 @@
+package mailer_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbicker/gooser/internal/mailer"
+	"github.com/rbicker/gooser/internal/store"
+	_ "github.com/rbicker/gooser/internal/translations"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeFiles writes the given files relative to the given directory.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("unable to create directory: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("unable to write file: %s", err)
+		}
+	}
+}
+
+// TestDefaultTemplates tests that all default templates can be rendered.
+func TestDefaultTemplates(t *testing.T) {
+	templates := mailer.DefaultTemplates()
+	data := &mailer.TemplateData{
+		Branding: mailer.Branding{SiteName: "gooser", LogoUrl: "https://example.com/logo.png", SupportAddress: "support@example.com"},
+		User: &store.User{
+			Username:            "user1",
+			DeletionScheduledAt: time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC),
+			InvitationExpiresAt: time.Date(2020, 9, 2, 12, 0, 0, 0, time.UTC),
+		},
+		Link: "https://example.com/?token=abc&x=<y>",
+		Notice: mailer.Notice{
+			Subject:  "password changed",
+			Greeting: "Hi user1!",
+			Message:  "The password of your account was changed.",
+			Advice:   "If you did not change your password, please reset it.",
+			LinkText: "Reset password",
+		},
+	}
+	for _, name := range []string{
+		mailer.TemplateConfirm,
+		mailer.TemplatePasswordReset,
+		mailer.TemplateAccountDeletion,
+		mailer.TemplateInvitation,
+		mailer.TemplatePasswordChanged,
+		mailer.TemplateMailChanged,
+		mailer.TemplateGroupGranted,
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			content, err := templates.Render(name, "en", data)
+			if err != nil {
+				t.Fatalf("unable to render template: %s", err)
+			}
+			assert.True(strings.HasPrefix(content.Subject, "gooser: "), "subject mismatch")
+			assert.NotContains(content.Subject, "\n", "subject must be a single line")
+			assert.Contains(content.Text, "support@example.com", "text should contain support address")
+			assert.Contains(content.HTML, `src="https://example.com/logo.png"`, "html should contain logo")
+			if name != mailer.TemplateAccountDeletion {
+				assert.Contains(content.Text, data.Link, "text should contain the unescaped link")
+				assert.Contains(content.HTML, "&amp;x=%3cy%3e", "html should contain the escaped link")
+			}
+		})
+	}
+}
+
+// TestDefaultTemplatesTranslated tests that the default templates are rendered
+// with the sentences translated into the language of the user.
+func TestDefaultTemplatesTranslated(t *testing.T) {
+	m, err := mailer.NewMailer(nil, "gooser@example.com", "gooser", "https://example.com/confirm", "https://example.com/reset")
+	if err != nil {
+		t.Fatalf("unable to create mailer: %s", err)
+	}
+	user := &store.User{Username: "user1", Language: "de"}
+	tests := []struct {
+		template    string
+		wantSubject string
+		wantText    []string
+		wantHTML    []string
+	}{
+		{
+			template:    mailer.TemplateConfirm,
+			wantSubject: "gooser: Mail-Adresse bestätigen",
+			wantText: []string{
+				"Hallo user1! Bitte bestätige deine Mail-Adresse, indem du auf den folgenden Link klickst. Danke!",
+				"https://example.com/confirm?token=preview",
+			},
+			wantHTML: []string{">Mail-Adresse bestätigen</a>"},
+		},
+		{
+			template:    mailer.TemplatePasswordReset,
+			wantSubject: "gooser: Passwort zurücksetzen",
+			wantText: []string{
+				"Hallo user1! Um dein Passwort zurückzusetzen, klicke den folgenden Link:",
+				"https://example.com/reset?token=preview",
+				"Falls du das Zurücksetzen des Passworts nicht angefordert hast, ignoriere bitte diese Nachricht.",
+			},
+			wantHTML: []string{">Passwort zurücksetzen</a>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			assert := assert.New(t)
+			content, err := m.PreviewMail(context.Background(), tt.template, user)
+			if err != nil {
+				t.Fatalf("unable to render template: %s", err)
+			}
+			assert.Equal(tt.wantSubject, content.Subject, "subject mismatch")
+			for _, want := range tt.wantText {
+				assert.Contains(content.Text, want)
+			}
+			for _, want := range tt.wantHTML {
+				assert.Contains(content.HTML, want)
+			}
+		})
+	}
+}
+
+// TestLoadTemplates tests loading templates from a directory with language overrides.
+func TestLoadTemplates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "templates")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"confirm.subject.tmpl":       "Welcome to {{.SiteName}}",
+		"de/confirm.subject.tmpl":    "Willkommen bei {{.SiteName}}",
+		"de/confirm.txt.tmpl":        "Hallo {{.User.Username}}",
+		"de-CH/confirm.subject.tmpl": "Grüezi",
+		"README.md":                  "not a template",
+	})
+	templates, err := mailer.LoadTemplates(dir)
+	if err != nil {
+		t.Fatalf("unable to load templates: %s", err)
+	}
+	data := &mailer.TemplateData{
+		Branding: mailer.Branding{SiteName: "gooser"},
+		User:     &store.User{Username: "user1"},
+		Link:     "https://example.com",
+		Notice:   mailer.Notice{Greeting: "Hi user1!", Message: "Please confirm your mail address."},
+	}
+	tests := []struct {
+		lang        string
+		wantSubject string
+		wantText    string
+	}{
+		{
+			lang:        "en",
+			wantSubject: "Welcome to gooser",
+			wantText:    "Hi user1! Please confirm",
+		},
+		{
+			lang:        "de",
+			wantSubject: "Willkommen bei gooser",
+			wantText:    "Hallo user1",
+		},
+		{
+			lang:        "de-AT",
+			wantSubject: "Willkommen bei gooser",
+			wantText:    "Hallo user1",
+		},
+		{
+			lang:        "de-CH",
+			wantSubject: "Grüezi",
+			wantText:    "Hallo user1",
+		},
+		{
+			lang:        "invalid language",
+			wantSubject: "Welcome to gooser",
+			wantText:    "Hi user1! Please confirm",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			assert := assert.New(t)
+			content, err := templates.Render(mailer.TemplateConfirm, tt.lang, data)
+			if err != nil {
+				t.Fatalf("unable to render template: %s", err)
+			}
+			assert.Equal(tt.wantSubject, content.Subject, "subject mismatch")
+			assert.True(strings.HasPrefix(content.Text, tt.wantText), "text mismatch: %s", content.Text)
+			assert.Contains(content.HTML, "<html>", "html should fall back to the default")
+		})
+	}
+	// unknown templates
+	if _, err := templates.Render("unknown", "en", data); err == nil {
+		t.Errorf("expected error when rendering unknown template")
+	}
+}
+
+// TestLoadTemplatesErrors tests that invalid template directories are rejected.
+func TestLoadTemplatesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{
+			name:  "unknown template",
+			files: map[string]string{"welcome.txt.tmpl": "hi"},
+		},
+		{
+			name:  "unknown part",
+			files: map[string]string{"confirm.body.tmpl": "hi"},
+		},
+		{
+			name:  "invalid syntax",
+			files: map[string]string{"confirm.txt.tmpl": "{{.User.Username"},
+		},
+		{
+			name:  "directory not named after a language",
+			files: map[string]string{"not a language/confirm.txt.tmpl": "hi"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "templates")
+			if err != nil {
+				t.Fatalf("unable to create temp dir: %s", err)
+			}
+			defer os.RemoveAll(dir)
+			writeFiles(t, dir, tt.files)
+			if _, err := mailer.LoadTemplates(dir); err == nil {
+				t.Errorf("LoadTemplates() expected error")
+			}
+		})
+	}
+}
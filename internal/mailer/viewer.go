@@ -0,0 +1,325 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CapturedMail is a mail written by the FileMailer or the MaildirMailer.
+type CapturedMail struct {
+	Id          string
+	Date        time.Time
+	From        string
+	To          string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []string
+}
+
+// Viewer lists and shows the mails captured by the FileMailer or
+// the MaildirMailer in a directory. It is meant for development and testing.
+type Viewer struct {
+	dir    string
+	logger *zap.Logger
+}
+
+// NewViewer creates a new viewer for the mails in the given directory,
+// either .eml files or a Maildir.
+// It takes functional parameters to change default options.
+func NewViewer(dir string, opts ...func(*Viewer) error) (*Viewer, error) {
+	var v = Viewer{
+		dir:    dir,
+		logger: zap.NewNop(),
+	}
+	// run functional options
+	for _, op := range opts {
+		err := op(&v)
+		if err != nil {
+			return nil, fmt.Errorf("setting option failed: %w", err)
+		}
+	}
+	return &v, nil
+}
+
+// WithViewerLogger sets the logger of the viewer.
+func WithViewerLogger(logger *zap.Logger) func(*Viewer) error {
+	return func(v *Viewer) error {
+		v.logger = logger
+		return nil
+	}
+}
+
+// files returns the paths of the captured mails by their ids. The id is the name of the file.
+func (v *Viewer) files() (map[string]string, error) {
+	files := make(map[string]string)
+	for _, pattern := range []string{"*.eml", filepath.Join("new", "*"), filepath.Join("cur", "*")} {
+		matches, err := filepath.Glob(filepath.Join(v.dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if name := filepath.Base(m); !strings.HasPrefix(name, ".") {
+				files[name] = m
+			}
+		}
+	}
+	return files, nil
+}
+
+// List returns the captured mails, the newest first.
+// The bodies of the mails are not returned.
+func (v *Viewer) List() ([]*CapturedMail, error) {
+	files, err := v.files()
+	if err != nil {
+		return nil, err
+	}
+	var mails []*CapturedMail
+	for id, file := range files {
+		m, err := readCapturedMail(id, file, false)
+		if err != nil {
+			v.logger.Warn("unable to read captured mail", zap.String("file", file), zap.Error(err))
+			continue
+		}
+		mails = append(mails, m)
+	}
+	sort.Slice(mails, func(i, j int) bool {
+		if mails[i].Date.Equal(mails[j].Date) {
+			return mails[i].Id > mails[j].Id
+		}
+		return mails[i].Date.After(mails[j].Date)
+	})
+	return mails, nil
+}
+
+// Get returns the captured mail with the given id, including its bodies.
+func (v *Viewer) Get(id string) (*CapturedMail, error) {
+	files, err := v.files()
+	if err != nil {
+		return nil, err
+	}
+	file, ok := files[id]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return readCapturedMail(id, file, true)
+}
+
+// Handler returns a http handler listing the captured mails. A mail is shown
+// using the id query parameter, the raw message is returned if raw is set as well.
+func (v *Viewer) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			mails, err := v.List()
+			if err != nil {
+				v.logger.Error("unable to list captured mails", zap.Error(err))
+				http.Error(w, "unable to list mails", http.StatusInternalServerError)
+				return
+			}
+			v.render(w, listTemplate, mails)
+			return
+		}
+		if r.URL.Query().Get("raw") != "" {
+			files, err := v.files()
+			if err != nil || files[id] == "" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			http.ServeFile(w, r, files[id])
+			return
+		}
+		m, err := v.Get(id)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				v.logger.Error("unable to read captured mail", zap.String("id", id), zap.Error(err))
+			}
+			http.NotFound(w, r)
+			return
+		}
+		v.render(w, mailTemplate, m)
+	})
+}
+
+// render renders the given template.
+func (v *Viewer) render(w http.ResponseWriter, t *template.Template, data interface{}) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		v.logger.Error("unable to render mail viewer", zap.Error(err))
+		http.Error(w, "unable to render page", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// readCapturedMail reads the mail in the given file. The bodies are only read if requested.
+func readCapturedMail(id, file string, bodies bool) (*CapturedMail, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	msg, err := mail.ReadMessage(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse mail: %w", err)
+	}
+	dec := new(mime.WordDecoder)
+	subject, err := dec.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+	m := &CapturedMail{
+		Id:      id,
+		From:    decodeAddresses(msg.Header, "From"),
+		To:      decodeAddresses(msg.Header, "To"),
+		Subject: subject,
+	}
+	m.Date, _ = msg.Header.Date()
+	if !bodies {
+		return m, nil
+	}
+	if err := readEntity(m, msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), "", msg.Body); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// decodeAddresses returns the addresses of the given header, with decoded display names.
+func decodeAddresses(header mail.Header, name string) string {
+	addresses, err := header.AddressList(name)
+	if err != nil {
+		return header.Get(name)
+	}
+	var ss []string
+	for _, a := range addresses {
+		if a.Name == "" {
+			ss = append(ss, a.Address)
+			continue
+		}
+		ss = append(ss, fmt.Sprintf("%s <%s>", a.Name, a.Address))
+	}
+	return strings.Join(ss, ", ")
+}
+
+// readEntity reads the given MIME entity into the given mail. The first plain text and html
+// bodies are used, the filenames of attachments are collected.
+func readEntity(m *CapturedMail, contentType, encoding, disposition string, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		r := multipart.NewReader(body, params["boundary"])
+		for {
+			p, err := r.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("unable to read mail part: %w", err)
+			}
+			// quoted-printable parts are decoded by the multipart reader
+			err = readEntity(m, p.Header.Get("Content-Type"), p.Header.Get("Content-Transfer-Encoding"), p.Header.Get("Content-Disposition"), p)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if d, dParams, err := mime.ParseMediaType(disposition); err == nil && d == "attachment" {
+		m.Attachments = append(m.Attachments, dParams["filename"])
+		return nil
+	}
+	switch strings.ToLower(encoding) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("unable to read mail body: %w", err)
+	}
+	switch {
+	case mediaType == "text/plain" && m.Text == "":
+		m.Text = string(b)
+	case mediaType == "text/html" && m.HTML == "":
+		m.HTML = string(b)
+	}
+	return nil
+}
+
+// link matches urls in plain text bodies.
+var link = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// linkify escapes the given text and turns the urls it contains into links.
+func linkify(text string) template.HTML {
+	var b strings.Builder
+	last := 0
+	for _, loc := range link.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		url := html.EscapeString(text[loc[0]:loc[1]])
+		b.WriteString(`<a href="` + url + `" target="_blank" rel="noopener">` + url + `</a>`)
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return template.HTML(b.String())
+}
+
+// viewerStyle is the style of the viewer pages.
+const viewerStyle = `<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { text-align: left; padding: .3em 1em .3em 0; }
+pre { white-space: pre-wrap; }
+iframe { width: 100%; height: 40em; border: 1px solid #ccc; }
+</style>`
+
+// listTemplate renders the list of captured mails.
+var listTemplate = template.Must(template.New("list").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>captured mails</title>` + viewerStyle + `</head><body>
+<h1>captured mails</h1>
+{{if .}}<table>
+<tr><th>date</th><th>from</th><th>to</th><th>subject</th></tr>
+{{range .}}<tr><td>{{.Date.Format "2006-01-02 15:04:05"}}</td><td>{{.From}}</td><td>{{.To}}</td><td><a href="?id={{.Id}}">{{if .Subject}}{{.Subject}}{{else}}(no subject){{end}}</a></td></tr>
+{{end}}</table>{{else}}<p>no mails have been captured yet.</p>{{end}}
+</body></html>
+`))
+
+// mailTemplate renders a captured mail. The html body is shown in a sandboxed
+// frame without scripts, its links are opened in a new window.
+var mailTemplate = template.Must(template.New("mail").Funcs(template.FuncMap{"linkify": linkify}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Subject}}</title>` + viewerStyle + `</head><body>
+<p><a href="?">all mails</a> | <a href="?id={{.Id}}&raw=1">raw message</a></p>
+<table>
+<tr><th>date</th><td>{{.Date.Format "2006-01-02 15:04:05"}}</td></tr>
+<tr><th>from</th><td>{{.From}}</td></tr>
+<tr><th>to</th><td>{{.To}}</td></tr>
+<tr><th>subject</th><td>{{.Subject}}</td></tr>
+{{if .Attachments}}<tr><th>attachments</th><td>{{range $i, $a := .Attachments}}{{if $i}}, {{end}}{{$a}}{{end}}</td></tr>{{end}}
+</table>
+{{if .HTML}}<h2>html</h2>
+<iframe sandbox="allow-popups allow-popups-to-escape-sandbox" srcdoc="<base target=&quot;_blank&quot;>{{.HTML}}"></iframe>{{end}}
+{{if .Text}}<h2>text</h2>
+<pre>{{linkify .Text}}</pre>{{end}}
+</body></html>
+`))
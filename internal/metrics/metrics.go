@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// namespace is the prefix of all the gooser metrics.
+const namespace = "gooser"
+
+// results used as label values.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Metrics contains the prometheus collectors of gooser.
+// All the functions are safe to call on a nil Metrics, which
+// makes it possible to use the instrumented types without metrics.
+type Metrics struct {
+	registry           *prometheus.Registry
+	requestsTotal      *prometheus.CounterVec
+	requestDuration    *prometheus.HistogramVec
+	storeDuration      *prometheus.HistogramVec
+	mailsTotal         *prometheus.CounterVec
+	authLookupDuration *prometheus.HistogramVec
+}
+
+// NewMetrics creates the gooser collectors and registers them, together with the
+// go runtime and process collectors, in a new registry.
+func NewMetrics() (*Metrics, error) {
+	m := Metrics{
+		registry: prometheus.NewRegistry(),
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "requests_total",
+			Help:      "Total number of handled grpc requests by method and status code.",
+		}, []string{"method", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the handled grpc requests by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		storeDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "store",
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of the store operations by operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		mailsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "mailer",
+			Name:      "mails_total",
+			Help:      "Total number of sent mails by kind and result.",
+		}, []string{"kind", "result"}),
+		authLookupDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "lookup_duration_seconds",
+			Help:      "Latency of the user lookups by access token by result.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"result"}),
+	}
+	collectors := []prometheus.Collector{
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.requestsTotal,
+		m.requestDuration,
+		m.storeDuration,
+		m.mailsTotal,
+		m.authLookupDuration,
+	}
+	for _, c := range collectors {
+		if err := m.registry.Register(c); err != nil {
+			return nil, fmt.Errorf("unable to register collector: %w", err)
+		}
+	}
+	return &m, nil
+}
+
+// Handler returns the http handler exposing the metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// UnaryServerInterceptor returns a grpc interceptor which counts the requests
+// by method and status code and observes their latency.
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if m == nil {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.requestDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		m.requestsTotal.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		return resp, err
+	}
+}
+
+// ObserveStore observes the latency of the given store operation
+// which was started at the given time. It is meant to be deferred.
+func (m *Metrics) ObserveStore(operation string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.storeDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// ObserveMail counts a mail of the given kind. The mail is counted
+// as failed if the given error is not nil.
+func (m *Metrics) ObserveMail(kind string, err error) {
+	if m == nil {
+		return
+	}
+	m.mailsTotal.WithLabelValues(kind, result(err)).Inc()
+}
+
+// ObserveAuthLookup observes the latency of a user lookup
+// which was started at the given time.
+func (m *Metrics) ObserveAuthLookup(start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	m.authLookupDuration.WithLabelValues(result(err)).Observe(time.Since(start).Seconds())
+}
+
+// result returns the result label value for the given error.
+func result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
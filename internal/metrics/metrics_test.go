@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	m, err := NewMetrics()
+	if err != nil {
+		t.Fatalf("unable to create metrics: %s", err)
+	}
+	interceptor := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/gooser.v1.Gooser/GetUser"}
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	notFound := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Errorf(codes.NotFound, "not found")
+	}
+	for _, h := range []grpc.UnaryHandler{ok, ok, notFound} {
+		interceptor(context.Background(), nil, info, h)
+	}
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.requestsTotal.WithLabelValues(info.FullMethod, "OK")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.requestsTotal.WithLabelValues(info.FullMethod, "NotFound")))
+	// metrics are exposed by the handler
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.True(t, strings.Contains(rec.Body.String(), `gooser_grpc_request_duration_seconds_count{method="/gooser.v1.Gooser/GetUser"} 3`))
+}
+
+func TestObserve(t *testing.T) {
+	m, err := NewMetrics()
+	if err != nil {
+		t.Fatalf("unable to create metrics: %s", err)
+	}
+	m.ObserveMail("confirm", nil)
+	m.ObserveMail("confirm", errors.New("connection refused"))
+	m.ObserveMail("password_reset", nil)
+	m.ObserveStore("GetUser", time.Now())
+	m.ObserveAuthLookup(time.Now(), nil)
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.mailsTotal.WithLabelValues("confirm", ResultSuccess)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.mailsTotal.WithLabelValues("confirm", ResultFailure)))
+	assert.Equal(t, 3, testutil.CollectAndCount(m.mailsTotal))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.storeDuration))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.authLookupDuration))
+	// functions need to be safe to call without metrics
+	var disabled *Metrics
+	disabled.ObserveMail("confirm", nil)
+	disabled.ObserveStore("GetUser", time.Now())
+	disabled.ObserveAuthLookup(time.Now(), nil)
+	res, err := disabled.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", res)
+}
@@ -0,0 +1,165 @@
+// Code generated by mockery v1.1.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	store "github.com/rbicker/gooser/internal/store"
+	mock "github.com/stretchr/testify/mock"
+	message "golang.org/x/text/message"
+)
+
+// ApiKeyStore is an autogenerated mock type for the ApiKeyStore type
+type ApiKeyStore struct {
+	mock.Mock
+}
+
+// DeleteApiKey provides a mock function with given fields: ctx, printer, id
+func (_m *ApiKeyStore) DeleteApiKey(ctx context.Context, printer *message.Printer, id string) error {
+	ret := _m.Called(ctx, printer, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) error); ok {
+		r0 = rf(ctx, printer, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteApiKeysOfServiceAccount provides a mock function with given fields: ctx, printer, serviceAccountId
+func (_m *ApiKeyStore) DeleteApiKeysOfServiceAccount(ctx context.Context, printer *message.Printer, serviceAccountId string) error {
+	ret := _m.Called(ctx, printer, serviceAccountId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) error); ok {
+		r0 = rf(ctx, printer, serviceAccountId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetApiKey provides a mock function with given fields: ctx, printer, id
+func (_m *ApiKeyStore) GetApiKey(ctx context.Context, printer *message.Printer, id string) (*store.ApiKey, error) {
+	ret := _m.Called(ctx, printer, id)
+
+	var r0 *store.ApiKey
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) *store.ApiKey); ok {
+		r0 = rf(ctx, printer, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.ApiKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string) error); ok {
+		r1 = rf(ctx, printer, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetApiKeyByPrefix provides a mock function with given fields: ctx, printer, prefix
+func (_m *ApiKeyStore) GetApiKeyByPrefix(ctx context.Context, printer *message.Printer, prefix string) (*store.ApiKey, error) {
+	ret := _m.Called(ctx, printer, prefix)
+
+	var r0 *store.ApiKey
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) *store.ApiKey); ok {
+		r0 = rf(ctx, printer, prefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.ApiKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string) error); ok {
+		r1 = rf(ctx, printer, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListApiKeys provides a mock function with given fields: ctx, printer, filterString, orderBy, token, size
+func (_m *ApiKeyStore) ListApiKeys(ctx context.Context, printer *message.Printer, filterString string, orderBy string, token string, size int32) (*[]store.ApiKey, int32, string, error) {
+	ret := _m.Called(ctx, printer, filterString, orderBy, token, size)
+
+	var r0 *[]store.ApiKey
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string, string, string, int32) *[]store.ApiKey); ok {
+		r0 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]store.ApiKey)
+		}
+	}
+
+	var r1 int32
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string, string, string, int32) int32); ok {
+		r1 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r1 = ret.Get(1).(int32)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, *message.Printer, string, string, string, int32) string); ok {
+		r2 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(context.Context, *message.Printer, string, string, string, int32) error); ok {
+		r3 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// SaveApiKey provides a mock function with given fields: ctx, printer, apiKey
+func (_m *ApiKeyStore) SaveApiKey(ctx context.Context, printer *message.Printer, apiKey *store.ApiKey) (*store.ApiKey, error) {
+	ret := _m.Called(ctx, printer, apiKey)
+
+	var r0 *store.ApiKey
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, *store.ApiKey) *store.ApiKey); ok {
+		r0 = rf(ctx, printer, apiKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.ApiKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, *store.ApiKey) error); ok {
+		r1 = rf(ctx, printer, apiKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TouchApiKey provides a mock function with given fields: ctx, printer, id, lastUsedAt
+func (_m *ApiKeyStore) TouchApiKey(ctx context.Context, printer *message.Printer, id string, lastUsedAt time.Time) error {
+	ret := _m.Called(ctx, printer, id, lastUsedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string, time.Time) error); ok {
+		r0 = rf(ctx, printer, id, lastUsedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
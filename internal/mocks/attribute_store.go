@@ -0,0 +1,136 @@
+// Code generated by mockery v1.1.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	store "github.com/rbicker/gooser/internal/store"
+	mock "github.com/stretchr/testify/mock"
+	message "golang.org/x/text/message"
+)
+
+// AttributeStore is an autogenerated mock type for the AttributeStore type
+type AttributeStore struct {
+	mock.Mock
+}
+
+// DeleteAttributeDefinition provides a mock function with given fields: ctx, printer, id
+func (_m *AttributeStore) DeleteAttributeDefinition(ctx context.Context, printer *message.Printer, id string) error {
+	ret := _m.Called(ctx, printer, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) error); ok {
+		r0 = rf(ctx, printer, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAttributeDefinition provides a mock function with given fields: ctx, printer, id
+func (_m *AttributeStore) GetAttributeDefinition(ctx context.Context, printer *message.Printer, id string) (*store.AttributeDefinition, error) {
+	ret := _m.Called(ctx, printer, id)
+
+	var r0 *store.AttributeDefinition
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) *store.AttributeDefinition); ok {
+		r0 = rf(ctx, printer, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.AttributeDefinition)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string) error); ok {
+		r1 = rf(ctx, printer, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAttributeDefinitionByName provides a mock function with given fields: ctx, printer, name
+func (_m *AttributeStore) GetAttributeDefinitionByName(ctx context.Context, printer *message.Printer, name string) (*store.AttributeDefinition, error) {
+	ret := _m.Called(ctx, printer, name)
+
+	var r0 *store.AttributeDefinition
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) *store.AttributeDefinition); ok {
+		r0 = rf(ctx, printer, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.AttributeDefinition)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string) error); ok {
+		r1 = rf(ctx, printer, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAttributeDefinitions provides a mock function with given fields: ctx, printer, filterString, orderBy, token, size
+func (_m *AttributeStore) ListAttributeDefinitions(ctx context.Context, printer *message.Printer, filterString string, orderBy string, token string, size int32) (*[]store.AttributeDefinition, int32, string, error) {
+	ret := _m.Called(ctx, printer, filterString, orderBy, token, size)
+
+	var r0 *[]store.AttributeDefinition
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string, string, string, int32) *[]store.AttributeDefinition); ok {
+		r0 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]store.AttributeDefinition)
+		}
+	}
+
+	var r1 int32
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string, string, string, int32) int32); ok {
+		r1 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r1 = ret.Get(1).(int32)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, *message.Printer, string, string, string, int32) string); ok {
+		r2 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(context.Context, *message.Printer, string, string, string, int32) error); ok {
+		r3 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// SaveAttributeDefinition provides a mock function with given fields: ctx, printer, definition
+func (_m *AttributeStore) SaveAttributeDefinition(ctx context.Context, printer *message.Printer, definition *store.AttributeDefinition) (*store.AttributeDefinition, error) {
+	ret := _m.Called(ctx, printer, definition)
+
+	var r0 *store.AttributeDefinition
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, *store.AttributeDefinition) *store.AttributeDefinition); ok {
+		r0 = rf(ctx, printer, definition)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.AttributeDefinition)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, *store.AttributeDefinition) error); ok {
+		r1 = rf(ctx, printer, definition)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,76 @@
+// Code generated by mockery v1.1.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	store "github.com/rbicker/gooser/internal/store"
+	mock "github.com/stretchr/testify/mock"
+	message "golang.org/x/text/message"
+)
+
+// AuditStore is an autogenerated mock type for the AuditStore type
+type AuditStore struct {
+	mock.Mock
+}
+
+// ListAuditEvents provides a mock function with given fields: ctx, printer, filterString, orderBy, token, size
+func (_m *AuditStore) ListAuditEvents(ctx context.Context, printer *message.Printer, filterString string, orderBy string, token string, size int32) (*[]store.AuditEvent, int32, string, error) {
+	ret := _m.Called(ctx, printer, filterString, orderBy, token, size)
+
+	var r0 *[]store.AuditEvent
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string, string, string, int32) *[]store.AuditEvent); ok {
+		r0 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]store.AuditEvent)
+		}
+	}
+
+	var r1 int32
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string, string, string, int32) int32); ok {
+		r1 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r1 = ret.Get(1).(int32)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, *message.Printer, string, string, string, int32) string); ok {
+		r2 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(context.Context, *message.Printer, string, string, string, int32) error); ok {
+		r3 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// SaveAuditEvent provides a mock function with given fields: ctx, printer, event
+func (_m *AuditStore) SaveAuditEvent(ctx context.Context, printer *message.Printer, event *store.AuditEvent) (*store.AuditEvent, error) {
+	ret := _m.Called(ctx, printer, event)
+
+	var r0 *store.AuditEvent
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, *store.AuditEvent) *store.AuditEvent); ok {
+		r0 = rf(ctx, printer, event)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.AuditEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, *store.AuditEvent) error); ok {
+		r1 = rf(ctx, printer, event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,28 @@
+// Code generated by mockery v1.1.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Emitter is an autogenerated mock type for the Emitter type
+type Emitter struct {
+	mock.Mock
+}
+
+// Emit provides a mock function with given fields: ctx, event, data
+func (_m *Emitter) Emit(ctx context.Context, event string, data interface{}) error {
+	ret := _m.Called(ctx, event, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+		r0 = rf(ctx, event, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -2,20 +2,25 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mailer "github.com/rbicker/gooser/internal/mailer"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // MailClient is an autogenerated mock type for the MailClient type
 type MailClient struct {
 	mock.Mock
 }
 
-// Send provides a mock function with given fields: from, tos, subject, body, ccs, bccs
-func (_m *MailClient) Send(from string, tos []string, subject string, body string, ccs []string, bccs []string) error {
-	ret := _m.Called(from, tos, subject, body, ccs, bccs)
+// Send provides a mock function with given fields: ctx, msg
+func (_m *MailClient) Send(ctx context.Context, msg *mailer.Message) error {
+	ret := _m.Called(ctx, msg)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, []string, string, string, []string, []string) error); ok {
-		r0 = rf(from, tos, subject, body, ccs, bccs)
+	if rf, ok := ret.Get(0).(func(context.Context, *mailer.Message) error); ok {
+		r0 = rf(ctx, msg)
 	} else {
 		r0 = ret.Error(0)
 	}
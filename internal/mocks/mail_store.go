@@ -0,0 +1,144 @@
+// Code generated by mockery v1.1.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	store "github.com/rbicker/gooser/internal/store"
+	mock "github.com/stretchr/testify/mock"
+	message "golang.org/x/text/message"
+)
+
+// MailStore is an autogenerated mock type for the MailStore type
+type MailStore struct {
+	mock.Mock
+}
+
+// ClaimMail provides a mock function with given fields: ctx, printer, lease
+func (_m *MailStore) ClaimMail(ctx context.Context, printer *message.Printer, lease time.Duration) (*store.Mail, error) {
+	ret := _m.Called(ctx, printer, lease)
+
+	var r0 *store.Mail
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, time.Duration) *store.Mail); ok {
+		r0 = rf(ctx, printer, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.Mail)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, time.Duration) error); ok {
+		r1 = rf(ctx, printer, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMail provides a mock function with given fields: ctx, printer, id
+func (_m *MailStore) GetMail(ctx context.Context, printer *message.Printer, id string) (*store.Mail, error) {
+	ret := _m.Called(ctx, printer, id)
+
+	var r0 *store.Mail
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) *store.Mail); ok {
+		r0 = rf(ctx, printer, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.Mail)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string) error); ok {
+		r1 = rf(ctx, printer, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMails provides a mock function with given fields: ctx, printer, filterString, orderBy, token, size
+func (_m *MailStore) ListMails(ctx context.Context, printer *message.Printer, filterString string, orderBy string, token string, size int32) (*[]store.Mail, int32, string, error) {
+	ret := _m.Called(ctx, printer, filterString, orderBy, token, size)
+
+	var r0 *[]store.Mail
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string, string, string, int32) *[]store.Mail); ok {
+		r0 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]store.Mail)
+		}
+	}
+
+	var r1 int32
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string, string, string, int32) int32); ok {
+		r1 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r1 = ret.Get(1).(int32)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, *message.Printer, string, string, string, int32) string); ok {
+		r2 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(context.Context, *message.Printer, string, string, string, int32) error); ok {
+		r3 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// PurgeFailedMails provides a mock function with given fields: ctx, printer, failedBefore
+func (_m *MailStore) PurgeFailedMails(ctx context.Context, printer *message.Printer, failedBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, printer, failedBefore)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, time.Time) int64); ok {
+		r0 = rf(ctx, printer, failedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, time.Time) error); ok {
+		r1 = rf(ctx, printer, failedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveMail provides a mock function with given fields: ctx, printer, mail
+func (_m *MailStore) SaveMail(ctx context.Context, printer *message.Printer, mail *store.Mail) (*store.Mail, error) {
+	ret := _m.Called(ctx, printer, mail)
+
+	var r0 *store.Mail
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, *store.Mail) *store.Mail); ok {
+		r0 = rf(ctx, printer, mail)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.Mail)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, *store.Mail) error); ok {
+		r1 = rf(ctx, printer, mail)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	context "context"
+
+	mailer "github.com/rbicker/gooser/internal/mailer"
 	store "github.com/rbicker/gooser/internal/store"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +15,120 @@ type Messenger struct {
 	mock.Mock
 }
 
-// SendConfirmToken provides a mock function with given fields: user
-func (_m *Messenger) SendConfirmToken(user *store.User) error {
-	ret := _m.Called(user)
+// PreviewMail provides a mock function with given fields: ctx, template, user
+func (_m *Messenger) PreviewMail(ctx context.Context, template string, user *store.User) (*mailer.Content, error) {
+	ret := _m.Called(ctx, template, user)
+
+	var r0 *mailer.Content
+	if rf, ok := ret.Get(0).(func(context.Context, string, *store.User) *mailer.Content); ok {
+		r0 = rf(ctx, template, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mailer.Content)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *store.User) error); ok {
+		r1 = rf(ctx, template, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendAccountDeletionScheduled provides a mock function with given fields: ctx, user
+func (_m *Messenger) SendAccountDeletionScheduled(ctx context.Context, user *store.User) error {
+	ret := _m.Called(ctx, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *store.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendConfirmToken provides a mock function with given fields: ctx, user
+func (_m *Messenger) SendConfirmToken(ctx context.Context, user *store.User) error {
+	ret := _m.Called(ctx, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *store.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendGroupGranted provides a mock function with given fields: ctx, user, group, roles
+func (_m *Messenger) SendGroupGranted(ctx context.Context, user *store.User, group *store.Group, roles []string) error {
+	ret := _m.Called(ctx, user, group, roles)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *store.User, *store.Group, []string) error); ok {
+		r0 = rf(ctx, user, group, roles)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendInvitation provides a mock function with given fields: ctx, user
+func (_m *Messenger) SendInvitation(ctx context.Context, user *store.User) error {
+	ret := _m.Called(ctx, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *store.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendMailChangeToken provides a mock function with given fields: ctx, user
+func (_m *Messenger) SendMailChangeToken(ctx context.Context, user *store.User) error {
+	ret := _m.Called(ctx, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *store.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendMailChanged provides a mock function with given fields: ctx, user, previousMail
+func (_m *Messenger) SendMailChanged(ctx context.Context, user *store.User, previousMail string) error {
+	ret := _m.Called(ctx, user, previousMail)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *store.User, string) error); ok {
+		r0 = rf(ctx, user, previousMail)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendPasswordChanged provides a mock function with given fields: ctx, user
+func (_m *Messenger) SendPasswordChanged(ctx context.Context, user *store.User) error {
+	ret := _m.Called(ctx, user)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*store.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *store.User) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +136,13 @@ func (_m *Messenger) SendConfirmToken(user *store.User) error {
 	return r0
 }
 
-// SendPasswordResetToken provides a mock function with given fields: user
-func (_m *Messenger) SendPasswordResetToken(user *store.User) error {
-	ret := _m.Called(user)
+// SendPasswordResetToken provides a mock function with given fields: ctx, user
+func (_m *Messenger) SendPasswordResetToken(ctx context.Context, user *store.User) error {
+	ret := _m.Called(ctx, user)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*store.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *store.User) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
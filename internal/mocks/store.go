@@ -4,11 +4,11 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
+	store "github.com/rbicker/gooser/internal/store"
 	mock "github.com/stretchr/testify/mock"
 	message "golang.org/x/text/message"
-
-	store "github.com/rbicker/gooser/internal/store"
 )
 
 // Store is an autogenerated mock type for the Store type
@@ -58,6 +58,27 @@ func (_m *Store) CountUsers(ctx context.Context, printer *message.Printer, filte
 	return r0, r1
 }
 
+// CountUsersByAttribute provides a mock function with given fields: ctx, printer, name, value, excludeId
+func (_m *Store) CountUsersByAttribute(ctx context.Context, printer *message.Printer, name string, value interface{}, excludeId string) (int32, error) {
+	ret := _m.Called(ctx, printer, name, value, excludeId)
+
+	var r0 int32
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string, interface{}, string) int32); ok {
+		r0 = rf(ctx, printer, name, value, excludeId)
+	} else {
+		r0 = ret.Get(0).(int32)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string, interface{}, string) error); ok {
+		r1 = rf(ctx, printer, name, value, excludeId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteGroup provides a mock function with given fields: ctx, printer, id
 func (_m *Store) DeleteGroup(ctx context.Context, printer *message.Printer, id string) error {
 	ret := _m.Called(ctx, printer, id)
@@ -178,6 +199,29 @@ func (_m *Store) GetUserByConfirmToken(ctx context.Context, printer *message.Pri
 	return r0, r1
 }
 
+// GetUserByInvitationToken provides a mock function with given fields: ctx, printer, token
+func (_m *Store) GetUserByInvitationToken(ctx context.Context, printer *message.Printer, token string) (*store.User, error) {
+	ret := _m.Called(ctx, printer, token)
+
+	var r0 *store.User
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) *store.User); ok {
+		r0 = rf(ctx, printer, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string) error); ok {
+		r1 = rf(ctx, printer, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByMail provides a mock function with given fields: ctx, printer, mail
 func (_m *Store) GetUserByMail(ctx context.Context, printer *message.Printer, mail string) (*store.User, error) {
 	ret := _m.Called(ctx, printer, mail)
@@ -201,6 +245,29 @@ func (_m *Store) GetUserByMail(ctx context.Context, printer *message.Printer, ma
 	return r0, r1
 }
 
+// GetUserByMailRevertToken provides a mock function with given fields: ctx, printer, token
+func (_m *Store) GetUserByMailRevertToken(ctx context.Context, printer *message.Printer, token string) (*store.User, error) {
+	ret := _m.Called(ctx, printer, token)
+
+	var r0 *store.User
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) *store.User); ok {
+		r0 = rf(ctx, printer, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string) error); ok {
+		r1 = rf(ctx, printer, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByPasswordResetToken provides a mock function with given fields: ctx, printer, token
 func (_m *Store) GetUserByPasswordResetToken(ctx context.Context, printer *message.Printer, token string) (*store.User, error) {
 	ret := _m.Called(ctx, printer, token)
@@ -224,6 +291,29 @@ func (_m *Store) GetUserByPasswordResetToken(ctx context.Context, printer *messa
 	return r0, r1
 }
 
+// GetUserByPendingMailToken provides a mock function with given fields: ctx, printer, token
+func (_m *Store) GetUserByPendingMailToken(ctx context.Context, printer *message.Printer, token string) (*store.User, error) {
+	ret := _m.Called(ctx, printer, token)
+
+	var r0 *store.User
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) *store.User); ok {
+		r0 = rf(ctx, printer, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string) error); ok {
+		r1 = rf(ctx, printer, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByUsername provides a mock function with given fields: ctx, printer, username
 func (_m *Store) GetUserByUsername(ctx context.Context, printer *message.Printer, username string) (*store.User, error) {
 	ret := _m.Called(ctx, printer, username)
@@ -321,6 +411,110 @@ func (_m *Store) ListUsers(ctx context.Context, printer *message.Printer, filter
 	return r0, r1, r2, r3
 }
 
+// ListUsersScheduledForDeletion provides a mock function with given fields: ctx, printer, before
+func (_m *Store) ListUsersScheduledForDeletion(ctx context.Context, printer *message.Printer, before time.Time) (*[]store.User, error) {
+	ret := _m.Called(ctx, printer, before)
+
+	var r0 *[]store.User
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, time.Time) *[]store.User); ok {
+		r0 = rf(ctx, printer, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]store.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, time.Time) error); ok {
+		r1 = rf(ctx, printer, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeleted provides a mock function with given fields: ctx, printer, deletedBefore
+func (_m *Store) PurgeDeleted(ctx context.Context, printer *message.Printer, deletedBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, printer, deletedBefore)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, time.Time) int64); ok {
+		r0 = rf(ctx, printer, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, time.Time) error); ok {
+		r1 = rf(ctx, printer, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveUserAttribute provides a mock function with given fields: ctx, printer, name
+func (_m *Store) RemoveUserAttribute(ctx context.Context, printer *message.Printer, name string) error {
+	ret := _m.Called(ctx, printer, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) error); ok {
+		r0 = rf(ctx, printer, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreGroup provides a mock function with given fields: ctx, printer, id
+func (_m *Store) RestoreGroup(ctx context.Context, printer *message.Printer, id string) (*store.Group, error) {
+	ret := _m.Called(ctx, printer, id)
+
+	var r0 *store.Group
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) *store.Group); ok {
+		r0 = rf(ctx, printer, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.Group)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string) error); ok {
+		r1 = rf(ctx, printer, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreUser provides a mock function with given fields: ctx, printer, id
+func (_m *Store) RestoreUser(ctx context.Context, printer *message.Printer, id string) (*store.User, error) {
+	ret := _m.Called(ctx, printer, id)
+
+	var r0 *store.User
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) *store.User); ok {
+		r0 = rf(ctx, printer, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string) error); ok {
+		r1 = rf(ctx, printer, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveGroup provides a mock function with given fields: ctx, printer, group
 func (_m *Store) SaveGroup(ctx context.Context, printer *message.Printer, group *store.Group) (*store.Group, error) {
 	ret := _m.Called(ctx, printer, group)
@@ -2,27 +2,31 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // UserLookup is an autogenerated mock type for the UserLookup type
 type UserLookup struct {
 	mock.Mock
 }
 
-// GetUserIDbyToken provides a mock function with given fields: token
-func (_m *UserLookup) GetUserIDbyToken(token string) (string, error) {
-	ret := _m.Called(token)
+// GetUserIDbyToken provides a mock function with given fields: ctx, token
+func (_m *UserLookup) GetUserIDbyToken(ctx context.Context, token string) (string, error) {
+	ret := _m.Called(ctx, token)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(token)
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, token)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(token)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,197 @@
+// Code generated by mockery v1.1.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	store "github.com/rbicker/gooser/internal/store"
+	mock "github.com/stretchr/testify/mock"
+	message "golang.org/x/text/message"
+)
+
+// WebhookStore is an autogenerated mock type for the WebhookStore type
+type WebhookStore struct {
+	mock.Mock
+}
+
+// ClaimWebhookDelivery provides a mock function with given fields: ctx, printer, lease
+func (_m *WebhookStore) ClaimWebhookDelivery(ctx context.Context, printer *message.Printer, lease time.Duration) (*store.WebhookDelivery, error) {
+	ret := _m.Called(ctx, printer, lease)
+
+	var r0 *store.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, time.Duration) *store.WebhookDelivery); ok {
+		r0 = rf(ctx, printer, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.WebhookDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, time.Duration) error); ok {
+		r1 = rf(ctx, printer, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteWebhook provides a mock function with given fields: ctx, printer, id
+func (_m *WebhookStore) DeleteWebhook(ctx context.Context, printer *message.Printer, id string) error {
+	ret := _m.Called(ctx, printer, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) error); ok {
+		r0 = rf(ctx, printer, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetWebhook provides a mock function with given fields: ctx, printer, id
+func (_m *WebhookStore) GetWebhook(ctx context.Context, printer *message.Printer, id string) (*store.Webhook, error) {
+	ret := _m.Called(ctx, printer, id)
+
+	var r0 *store.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string) *store.Webhook); ok {
+		r0 = rf(ctx, printer, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string) error); ok {
+		r1 = rf(ctx, printer, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhookDeliveries provides a mock function with given fields: ctx, printer, filterString, orderBy, token, size
+func (_m *WebhookStore) ListWebhookDeliveries(ctx context.Context, printer *message.Printer, filterString string, orderBy string, token string, size int32) (*[]store.WebhookDelivery, int32, string, error) {
+	ret := _m.Called(ctx, printer, filterString, orderBy, token, size)
+
+	var r0 *[]store.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string, string, string, int32) *[]store.WebhookDelivery); ok {
+		r0 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]store.WebhookDelivery)
+		}
+	}
+
+	var r1 int32
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string, string, string, int32) int32); ok {
+		r1 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r1 = ret.Get(1).(int32)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, *message.Printer, string, string, string, int32) string); ok {
+		r2 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(context.Context, *message.Printer, string, string, string, int32) error); ok {
+		r3 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// ListWebhooks provides a mock function with given fields: ctx, printer, filterString, orderBy, token, size
+func (_m *WebhookStore) ListWebhooks(ctx context.Context, printer *message.Printer, filterString string, orderBy string, token string, size int32) (*[]store.Webhook, int32, string, error) {
+	ret := _m.Called(ctx, printer, filterString, orderBy, token, size)
+
+	var r0 *[]store.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, string, string, string, int32) *[]store.Webhook); ok {
+		r0 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]store.Webhook)
+		}
+	}
+
+	var r1 int32
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, string, string, string, int32) int32); ok {
+		r1 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r1 = ret.Get(1).(int32)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, *message.Printer, string, string, string, int32) string); ok {
+		r2 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(context.Context, *message.Printer, string, string, string, int32) error); ok {
+		r3 = rf(ctx, printer, filterString, orderBy, token, size)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// SaveWebhook provides a mock function with given fields: ctx, printer, webhook
+func (_m *WebhookStore) SaveWebhook(ctx context.Context, printer *message.Printer, webhook *store.Webhook) (*store.Webhook, error) {
+	ret := _m.Called(ctx, printer, webhook)
+
+	var r0 *store.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, *store.Webhook) *store.Webhook); ok {
+		r0 = rf(ctx, printer, webhook)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, *store.Webhook) error); ok {
+		r1 = rf(ctx, printer, webhook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveWebhookDelivery provides a mock function with given fields: ctx, printer, delivery
+func (_m *WebhookStore) SaveWebhookDelivery(ctx context.Context, printer *message.Printer, delivery *store.WebhookDelivery) (*store.WebhookDelivery, error) {
+	ret := _m.Called(ctx, printer, delivery)
+
+	var r0 *store.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Printer, *store.WebhookDelivery) *store.WebhookDelivery); ok {
+		r0 = rf(ctx, printer, delivery)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.WebhookDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Printer, *store.WebhookDelivery) error); ok {
+		r1 = rf(ctx, printer, delivery)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,175 @@
+package purge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/rbicker/gooser/internal/logging"
+	"github.com/rbicker/gooser/internal/store"
+	"go.uber.org/zap"
+)
+
+// AccountDeleter deletes the accounts whose deletion was requested by their users
+// and whose cooling-off period is over.
+type AccountDeleter interface {
+	DeleteScheduledAccounts(ctx context.Context) error
+}
+
+// Purger periodically deletes the accounts scheduled for deletion and removes
+// the users and groups which have been soft deleted longer ago than the retention period.
+// If a mail store is set, it also removes the contents of failed mails.
+type Purger struct {
+	store          store.Store
+	accountDeleter AccountDeleter
+	mailStore      store.MailStore
+	logger         *zap.Logger
+	retention      time.Duration
+	mailRetention  time.Duration
+	interval       time.Duration
+	stop           chan struct{}
+	wg             sync.WaitGroup
+}
+
+// NewPurger creates a new purger for the given store.
+// It takes functional parameters to change default options.
+func NewPurger(db store.Store, opts ...func(*Purger) error) (*Purger, error) {
+	var p = Purger{
+		store:     db,
+		retention: 30 * 24 * time.Hour,
+		interval:  time.Hour,
+	}
+	// run functional options
+	for _, op := range opts {
+		err := op(&p)
+		if err != nil {
+			return nil, fmt.Errorf("setting option failed: %w", err)
+		}
+	}
+	// default logger
+	if p.logger == nil {
+		p.logger = logging.Default()
+	}
+	return &p, nil
+}
+
+// PurgeOnce deletes the scheduled accounts and removes the documents whose retention period is over.
+func (p *Purger) PurgeOnce(ctx context.Context) error {
+	if p.accountDeleter != nil {
+		if err := p.accountDeleter.DeleteScheduledAccounts(ctx); err != nil {
+			return err
+		}
+	}
+	printer := message.NewPrinter(language.English)
+	// failed mails contain tokens, a retention of 0 keeps their contents forever
+	if p.mailStore != nil && p.mailRetention > 0 {
+		purged, err := p.mailStore.PurgeFailedMails(ctx, printer, time.Now().Add(-p.mailRetention))
+		if err != nil {
+			return err
+		}
+		if purged > 0 {
+			p.logger.Info("purged contents of failed mails", zap.Int64("count", purged))
+		}
+	}
+	// a retention of 0 keeps deleted documents forever
+	if p.retention == 0 {
+		return nil
+	}
+	purged, err := p.store.PurgeDeleted(ctx, printer, time.Now().Add(-p.retention))
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		p.logger.Info("purged deleted documents", zap.Int64("count", purged))
+	}
+	return nil
+}
+
+// Start purges immediately and then periodically in the background.
+func (p *Purger) Start() {
+	p.stop = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer cancel()
+		t := time.NewTicker(p.interval)
+		defer t.Stop()
+		for {
+			if err := p.PurgeOnce(ctx); err != nil {
+				p.logger.Error("unable to purge deleted documents", zap.Error(err))
+			}
+			select {
+			case <-p.stop:
+				return
+			case <-t.C:
+			}
+		}
+	}()
+}
+
+// Stop stops purging and waits for a running purge to finish.
+func (p *Purger) Stop() {
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	p.wg.Wait()
+}
+
+// WithLogger sets the logger of the purger.
+func WithLogger(logger *zap.Logger) func(*Purger) error {
+	return func(p *Purger) error {
+		p.logger = logger
+		return nil
+	}
+}
+
+// WithAccountDeleter sets the account deleter which is run before purging.
+func WithAccountDeleter(accountDeleter AccountDeleter) func(*Purger) error {
+	return func(p *Purger) error {
+		p.accountDeleter = accountDeleter
+		return nil
+	}
+}
+
+// WithFailedMails sets the mail store whose failed mails are purged once the given
+// retention has passed. Their bodies and attachments are removed, so they cannot be retried anymore.
+// A retention of 0 disables purging failed mails.
+func WithFailedMails(mailStore store.MailStore, retention time.Duration) func(*Purger) error {
+	return func(p *Purger) error {
+		if retention < 0 {
+			return fmt.Errorf("failed mail retention cannot be negative")
+		}
+		p.mailStore = mailStore
+		p.mailRetention = retention
+		return nil
+	}
+}
+
+// WithRetention changes how long deleted documents are kept before they are purged.
+// A retention of 0 disables purging.
+func WithRetention(retention time.Duration) func(*Purger) error {
+	return func(p *Purger) error {
+		if retention < 0 {
+			return fmt.Errorf("retention cannot be negative")
+		}
+		p.retention = retention
+		return nil
+	}
+}
+
+// WithInterval changes the interval in which deleted documents are purged.
+func WithInterval(interval time.Duration) func(*Purger) error {
+	return func(p *Purger) error {
+		if interval <= 0 {
+			return fmt.Errorf("interval needs to be greater than 0")
+		}
+		p.interval = interval
+		return nil
+	}
+}
@@ -0,0 +1,102 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestPurgeOnce(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{
+			name: "purge",
+		},
+		{
+			name:    "store error",
+			err:     errors.New("connection refused"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db := new(mocks.Store)
+			p, err := NewPurger(db, WithRetention(24*time.Hour), WithLogger(zap.NewNop()))
+			if err != nil {
+				t.Fatalf("unable to create purger: %s", err)
+			}
+			start := time.Now()
+			db.On("PurgeDeleted", mock.Anything, mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+				// only documents deleted before the retention period are purged
+				cutoff := start.Add(-24 * time.Hour)
+				return !before.Before(cutoff) && before.Sub(cutoff) < time.Minute
+			})).Return(int64(2), tt.err).Once()
+			err = p.PurgeOnce(context.Background())
+			assert.Equal(tt.wantErr, err != nil, "error mismatch")
+			db.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewPurger(t *testing.T) {
+	_, err := NewPurger(new(mocks.Store), WithRetention(-time.Hour))
+	assert.Error(t, err, "a negative retention should be rejected")
+	_, err = NewPurger(new(mocks.Store), WithInterval(-time.Second))
+	assert.Error(t, err, "a negative interval should be rejected")
+}
+
+// accountDeleter counts the calls of DeleteScheduledAccounts.
+type accountDeleter struct {
+	calls int
+}
+
+func (d *accountDeleter) DeleteScheduledAccounts(ctx context.Context) error {
+	d.calls++
+	return nil
+}
+
+func TestPurgeOnceWithoutRetention(t *testing.T) {
+	assert := assert.New(t)
+	db := new(mocks.Store)
+	deleter := &accountDeleter{}
+	p, err := NewPurger(db, WithRetention(0), WithAccountDeleter(deleter), WithLogger(zap.NewNop()))
+	if err != nil {
+		t.Fatalf("unable to create purger: %s", err)
+	}
+	err = p.PurgeOnce(context.Background())
+	assert.NoError(err)
+	// scheduled accounts are deleted even if purging is disabled
+	assert.Equal(1, deleter.calls, "account deleter calls mismatch")
+	db.AssertNotCalled(t, "PurgeDeleted", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPurgeFailedMails(t *testing.T) {
+	assert := assert.New(t)
+	db := new(mocks.Store)
+	mails := new(mocks.MailStore)
+	p, err := NewPurger(db, WithRetention(0), WithFailedMails(mails, 72*time.Hour), WithLogger(zap.NewNop()))
+	if err != nil {
+		t.Fatalf("unable to create purger: %s", err)
+	}
+	start := time.Now()
+	mails.On("PurgeFailedMails", mock.Anything, mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		// only mails which failed before the retention period are purged
+		cutoff := start.Add(-72 * time.Hour)
+		return !before.Before(cutoff) && before.Sub(cutoff) < time.Minute
+	})).Return(int64(1), nil).Once()
+	err = p.PurgeOnce(context.Background())
+	assert.NoError(err)
+	mails.AssertExpectations(t)
+	// failed mails are purged even if deleted documents are kept
+	db.AssertNotCalled(t, "PurgeDeleted", mock.Anything, mock.Anything, mock.Anything)
+}
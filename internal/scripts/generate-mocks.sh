@@ -6,4 +6,9 @@ fi
 mockery -case underscore -recursive -name Store -output ./internal/mocks
 mockery -case underscore -recursive -name UserLookup -output ./internal/mocks
 mockery -case underscore -recursive -name MailClient -output ./internal/mocks
-mockery -case underscore -recursive -name Messenger -output ./internal/mocks
\ No newline at end of file
+mockery -case underscore -recursive -name Messenger -output ./internal/mocks
+mockery -case underscore -recursive -name WebhookStore -output ./internal/mocks
+mockery -case underscore -recursive -name AuditStore -output ./internal/mocks
+mockery -case underscore -recursive -name Emitter -output ./internal/mocks
+mockery -case underscore -recursive -name ApiKeyStore -output ./internal/mocks
+mockery -case underscore -recursive -name AttributeStore -output ./internal/mocks
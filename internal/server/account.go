@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ExportMyData returns all the data gooser holds about the calling user as json document.
+func (srv *Server) ExportMyData(ctx context.Context, req *empty.Empty) (*gooserv1.ExportMyDataResponse, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	data := &gooserv1.PersonalData{
+		User:       u.ToPb(),
+		Roles:      u.Roles,
+		ExportedAt: ptypes.TimestampNow(),
+	}
+	groups, _, _, err := srv.store.ListGroups(ctx, printer, fmt.Sprintf(`members=="%s"`, u.Id), "", "", -1)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range *groups {
+		data.Groups = append(data.Groups, g.ToPb())
+	}
+	if srv.auditStore != nil {
+		filter := fmt.Sprintf(`actorId=="%s",resourceId=="%s"`, u.Id, u.Id)
+		events, _, _, err := srv.auditStore.ListAuditEvents(ctx, printer, filter, "", "", -1)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range *events {
+			data.AuditEvents = append(data.AuditEvents, e.ToPb())
+		}
+	}
+	marshaler := jsonpb.Marshaler{Indent: "  "}
+	res, err := marshaler.MarshalToString(data)
+	if err != nil {
+		srv.log(ctx).Error("unable to marshal personal data", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to export data"))
+	}
+	return &gooserv1.ExportMyDataResponse{Data: res}, nil
+}
+
+// DeleteMyAccount schedules the deletion of the calling user's account.
+// The password has to be given again. The account is deleted after the
+// cooling-off period, until then the deletion can be canceled.
+func (srv *Server) DeleteMyAccount(ctx context.Context, req *gooserv1.DeleteMyAccountRequest) (*empty.Empty, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if impersonatorFromContext(ctx) != "" {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to delete an account while impersonating"))
+	}
+	if u.ServiceAccount {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("service accounts cannot delete themselves"))
+	}
+	if !u.ValidatePassword(req.GetPassword()) {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("password mismatch"))
+	}
+	if !u.DeletionScheduledAt.IsZero() {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("account deletion is already scheduled"))
+	}
+	before := u.ToPb()
+	u.DeletionScheduledAt = time.Now().Add(srv.accountDeletionDelay)
+	// the deletion is saved first, so the user is not told about a deletion which is not scheduled
+	scheduled, err := srv.store.SaveUser(ctx, printer, u)
+	if err != nil {
+		return nil, err
+	}
+	srv.audit(ctx, scheduled, "DeleteMyAccount", auditResourceUser, scheduled.Id, auditChanges(before, scheduled.ToPb()))
+	if scheduled.Mail != "" {
+		if err := srv.mailer.SendAccountDeletionScheduled(ctx, scheduled); err != nil {
+			return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to send account deletion mail"))
+		}
+	}
+	return &empty.Empty{}, nil
+}
+
+// CancelMyAccountDeletion cancels the scheduled deletion of the calling user's account.
+func (srv *Server) CancelMyAccountDeletion(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if u.DeletionScheduledAt.IsZero() {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("account deletion is not scheduled"))
+	}
+	before := u.ToPb()
+	u.DeletionScheduledAt = time.Time{}
+	canceled, err := srv.store.SaveUser(ctx, printer, u)
+	if err != nil {
+		return nil, err
+	}
+	srv.audit(ctx, canceled, "CancelMyAccountDeletion", auditResourceUser, canceled.Id, auditChanges(before, canceled.ToPb()))
+	return &empty.Empty{}, nil
+}
+
+// DeleteScheduledAccounts deletes the accounts whose cooling-off period is over.
+// The users are deleted like by an admin, being the actor themselves. Like other
+// deleted users, they are only purged once the retention period of the purger has passed.
+func (srv *Server) DeleteScheduledAccounts(ctx context.Context) error {
+	printer := message.NewPrinter(language.English)
+	users, err := srv.store.ListUsersScheduledForDeletion(ctx, printer, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, u := range *users {
+		// the user is modified while being deleted, the actor is kept as is
+		existing, actor := u, u
+		if err := srv.deleteUser(ctx, printer, &actor, &existing); err != nil {
+			srv.log(ctx).Error("unable to delete scheduled account", zap.String("userId", actor.Id), zap.Error(err))
+			continue
+		}
+		srv.log(ctx).Info("deleted account as requested by the user", zap.String("userId", actor.Id))
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"golang.org/x/text/message"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (suite *Suite) TestExportMyData() {
+	t := suite.T()
+	assert := assert.New(t)
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	db := new(mocks.Store)
+	db.On("ListGroups", mock.Anything, mock.Anything, `members=="user1"`, "", "", int32(-1)).Return(
+		&[]store.Group{{Id: "testers", Name: "testers", Members: []string{"user1"}}},
+		int32(1),
+		"",
+		nil,
+	).Once()
+	audit := new(mocks.AuditStore)
+	audit.On("ListAuditEvents", mock.Anything, mock.Anything, `actorId=="user1",resourceId=="user1"`, "", "", int32(-1)).Return(
+		&[]store.AuditEvent{{Id: "event1", ActorId: "admin", Action: "UpdateUser", ResourceType: "user", ResourceId: "user1"}},
+		int32(1),
+		"",
+		nil,
+	).Once()
+	suite.srv.store = db
+	suite.srv.auditStore = audit
+	defer func() { suite.srv.auditStore = nil }()
+	ctx := context.WithValue(context.Background(), "access_token", "user1")
+	res, err := client.ExportMyData(ctx, &empty.Empty{})
+	if err != nil {
+		t.Fatalf("unable to export data: %s", err)
+	}
+	db.AssertExpectations(t)
+	audit.AssertExpectations(t)
+	var data struct {
+		User struct {
+			Id       string `json:"id"`
+			Password string `json:"password"`
+		} `json:"user"`
+		Groups      []map[string]interface{} `json:"groups"`
+		Roles       []string                 `json:"roles"`
+		AuditEvents []map[string]interface{} `json:"auditEvents"`
+	}
+	if err := json.Unmarshal([]byte(res.Data), &data); err != nil {
+		t.Fatalf("unable to unmarshal exported data: %s", err)
+	}
+	assert.Equal("user1", data.User.Id, "user id mismatch")
+	assert.Empty(data.User.Password, "the password must not be exported")
+	assert.Len(data.Groups, 1, "groups length mismatch")
+	assert.Equal([]string{"user1"}, data.Roles, "roles mismatch")
+	assert.Len(data.AuditEvents, 1, "audit events length mismatch")
+}
+
+func (suite *Suite) TestDeleteMyAccount() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.Store, mailer *mocks.Messenger)
+		accessToken string
+		impersonate string
+		password    string
+		wantCode    codes.Code
+	}{
+		{
+			name:     "unauthenticated",
+			password: "password",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:        "wrong password",
+			accessToken: "user1",
+			password:    "wrong",
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "impersonating",
+			accessToken: "admin",
+			impersonate: "user1",
+			password:    "password",
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(&store.User{Id: "user1"}, nil).Once()
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:        "schedule deletion",
+			accessToken: "user1",
+			password:    "password",
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				scheduled := mock.MatchedBy(func(u *store.User) bool {
+					return u.Id == "user1" && u.DeletionScheduledAt.After(time.Now().Add(6*24*time.Hour))
+				})
+				db.On("SaveUser", mock.Anything, mock.Anything, scheduled).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+				mailer.On("SendAccountDeletionScheduled", mock.Anything, scheduled).Return(nil).Once()
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:        "deletion not saved",
+			accessToken: "user1",
+			password:    "password",
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				// the user has been modified concurrently, no mail is sent
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+					nil,
+					status.Errorf(codes.Aborted, "user has been modified"),
+				).Once()
+			},
+			wantCode: codes.Aborted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mocks
+			db := new(mocks.Store)
+			mailer := new(mocks.Messenger)
+			if tt.prepare != nil {
+				tt.prepare(db, mailer)
+			}
+			suite.srv.store = db
+			suite.srv.mailer = mailer
+			// prepare context with access token
+			ctx := context.Background()
+			if tt.accessToken != "" {
+				ctx = context.WithValue(ctx, "access_token", tt.accessToken)
+			}
+			if tt.impersonate != "" {
+				ctx = context.WithValue(ctx, "impersonate_user", tt.impersonate)
+			}
+			// run function
+			_, err := client.DeleteMyAccount(ctx, &gooserv1.DeleteMyAccountRequest{Password: tt.password})
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			mailer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCancelMyAccountDeletion(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *store.User
+		wantCode codes.Code
+	}{
+		{
+			name:     "not scheduled",
+			user:     &store.User{Id: "user1"},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "cancel",
+			user:     &store.User{Id: "user1", DeletionScheduledAt: time.Now().Add(time.Hour)},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db := new(mocks.Store)
+			if tt.wantCode == codes.OK {
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+					return u.Id == "user1" && u.DeletionScheduledAt.IsZero()
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+			}
+			srv, err := NewServer("secret", db, nil, nil,
+				WithLogger(zap.NewNop()),
+				WithContextUserReceiver(func(ctx context.Context, db store.Store) (*store.User, error) {
+					return tt.user, nil
+				}),
+			)
+			if err != nil {
+				t.Fatalf("unable to create server: %s", err)
+			}
+			_, err = srv.CancelMyAccountDeletion(context.Background(), &empty.Empty{})
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+		})
+	}
+}
+
+func (suite *Suite) TestDeleteScheduledAccounts() {
+	t := suite.T()
+	db := new(mocks.Store)
+	db.On("ListUsersScheduledForDeletion", mock.Anything, mock.Anything, mock.Anything).Return(
+		&[]store.User{
+			{Id: "user1", DeletionScheduledAt: time.Now().Add(-time.Minute)},
+		},
+		nil,
+	).Once()
+	db.On("ListGroups", mock.Anything, mock.Anything, `members=="user1"`, "", "", int32(-1)).Return(
+		&[]store.Group{},
+		int32(0),
+		"",
+		nil,
+	).Once()
+	db.On("DeleteUser", mock.Anything, mock.Anything, "user1").Return(nil).Once()
+	suite.srv.store = db
+	err := suite.srv.DeleteScheduledAccounts(context.Background())
+	assert.NoError(t, err)
+	db.AssertExpectations(t)
+}
@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/rbicker/gooser/internal/utils"
+	"github.com/rbicker/gooser/internal/webhooks"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyHeader is the metadata key of the api key.
+const apiKeyHeader = "x-api-key"
+
+// apiKeyTouchInterval is the minimum interval between updates of the last usage of an api key.
+const apiKeyTouchInterval = time.Minute
+
+// apiKeyAdmin returns the user from the context and the corresponding printer
+// if the user is an admin and api keys are enabled.
+func (srv *Server) apiKeyAdmin(ctx context.Context) (*store.User, *message.Printer, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u == nil {
+		return nil, nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if !u.HasRole("admin") {
+		return nil, nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to manage service accounts"))
+	}
+	if srv.apiKeyStore == nil {
+		return nil, nil, status.Errorf(codes.Unimplemented, printer.Sprintf("service accounts are not enabled"))
+	}
+	return u, printer, nil
+}
+
+// hashApiKeySecret returns the hex encoded sha256 hash of the secret part of an api key.
+func hashApiKeySecret(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+// apiKeyUserFromContext returns the service account of the api key in the given context.
+// It returns nil if no api key was given.
+func (srv *Server) apiKeyUserFromContext(ctx context.Context) (*store.User, error) {
+	key, ok := ctx.Value("api_key").(string)
+	if !ok || key == "" {
+		return nil, nil
+	}
+	if srv.apiKeyStore == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "api keys are not enabled")
+	}
+	printer := message.NewPrinter(language.English)
+	ss := strings.SplitN(key, ".", 2)
+	if len(ss) != 2 || ss[0] == "" || ss[1] == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
+	}
+	k, err := srv.apiKeyStore.GetApiKeyByPrefix(ctx, printer, ss[0])
+	if code, _ := status.FromError(err); code.Code() == codes.NotFound {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashApiKeySecret(ss[1])), []byte(k.Hash)) != 1 {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
+	}
+	now := time.Now()
+	if k.Expired(now) {
+		return nil, status.Errorf(codes.Unauthenticated, "api key expired")
+	}
+	method, _ := grpc.Method(ctx)
+	if !k.HasScope(path.Base(method)) {
+		return nil, status.Errorf(codes.PermissionDenied, "api key is not allowed to call %s", path.Base(method))
+	}
+	u, err := srv.store.GetUser(ctx, printer, k.ServiceAccountId)
+	if code, _ := status.FromError(err); code.Code() == codes.NotFound {
+		srv.log(ctx).Error("service account of api key not found", zap.String("apiKeyId", k.Id), zap.String("serviceAccountId", k.ServiceAccountId))
+		return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !u.ServiceAccount {
+		srv.log(ctx).Error("api key does not belong to a service account", zap.String("apiKeyId", k.Id), zap.String("userId", u.Id))
+		return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
+	}
+	// the last usage is only updated once in a while to spare the database
+	if now.Sub(k.LastUsedAt) >= apiKeyTouchInterval {
+		if err := srv.apiKeyStore.TouchApiKey(ctx, printer, k.Id, now); err != nil {
+			srv.log(ctx).Error("unable to update last usage of api key", zap.String("apiKeyId", k.Id), zap.Error(err))
+		}
+	}
+	return u, nil
+}
+
+// CreateServiceAccount creates the given user as service account.
+// Service accounts do not have a password and authenticate using api keys.
+// Like any other user, they get roles by being member of groups.
+func (srv *Server) CreateServiceAccount(ctx context.Context, user *gooserv1.User) (*gooserv1.User, error) {
+	u, printer, err := srv.apiKeyAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user.Id = ""
+	user.DeletionScheduledAt = nil
+	if user.GetPassword() != "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("service accounts cannot have a password"))
+	}
+	if len(user.GetRoles()) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("roles cannot be assigned to users directly, use groups instead"))
+	}
+	if user.GetSuspended() || user.GetSuspensionReason() != "" || user.GetSuspendedUntil() != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("users cannot be created suspended"))
+	}
+	if user.GetLanguage() == "" {
+		user.Language = utils.LookupEnv("GOOSER_DEFAULT_LANGUAGE", "en")
+	}
+	user.Confirmed = true
+	user.ServiceAccount = true
+	if err := srv.ValidateUser(ctx, printer, user); err != nil {
+		return nil, err
+	}
+	newUser, err := srv.store.SaveUser(ctx, printer, store.PbToUser(user))
+	if err != nil {
+		return nil, err
+	}
+	srv.emit(ctx, webhooks.EventUserCreated, newUser.ToPb())
+	srv.audit(ctx, u, "CreateServiceAccount", auditResourceUser, newUser.Id, auditChanges(nil, newUser.ToPb()))
+	return newUser.ToPb(), nil
+}
+
+// ValidateApiKeyScopes checks if every given scope is the name of a method
+// of the gooser service or * for all methods.
+func (srv *Server) ValidateApiKeyScopes(printer *message.Printer, scopes []string) error {
+	if len(scopes) == 0 {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("at least one scope is required"))
+	}
+	methods := make(map[string]struct{})
+	for _, m := range srv.grpcServer.GetServiceInfo()["gooser.v1.Gooser"].Methods {
+		methods[m.Name] = struct{}{}
+	}
+	for _, s := range scopes {
+		if s == "*" {
+			continue
+		}
+		if _, ok := methods[s]; !ok {
+			return status.Errorf(codes.InvalidArgument, printer.Sprintf("unknown scope %s", s))
+		}
+	}
+	return nil
+}
+
+// CreateApiKey creates a new api key for a service account.
+// The key is only returned by this function, afterwards only its prefix is known.
+func (srv *Server) CreateApiKey(ctx context.Context, apiKey *gooserv1.ApiKey) (*gooserv1.ApiKey, error) {
+	u, printer, err := srv.apiKeyAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.GetName() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("name not set"))
+	}
+	apiKey.Scopes, _ = utils.UniqueStringSlice(apiKey.Scopes)
+	if err := srv.ValidateApiKeyScopes(printer, apiKey.Scopes); err != nil {
+		return nil, err
+	}
+	k := &store.ApiKey{
+		ServiceAccountId: apiKey.GetServiceAccountId(),
+		Name:             apiKey.GetName(),
+		Scopes:           apiKey.GetScopes(),
+	}
+	if apiKey.GetExpiresAt() != nil {
+		k.ExpiresAt, err = ptypes.Timestamp(apiKey.GetExpiresAt())
+		if err != nil || !k.ExpiresAt.After(time.Now()) {
+			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("expiry must be in the future"))
+		}
+	}
+	if k.ServiceAccountId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("service account id not set"))
+	}
+	serviceAccount, err := srv.store.GetUser(ctx, printer, k.ServiceAccountId)
+	if err != nil {
+		return nil, err
+	}
+	if !serviceAccount.ServiceAccount {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("api keys can only be created for service accounts"))
+	}
+	k.Prefix, err = utils.RandomToken(8)
+	if err != nil {
+		srv.log(ctx).Error("unable to generate api key prefix", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to generate api key"))
+	}
+	secret, err := utils.RandomToken(32)
+	if err != nil {
+		srv.log(ctx).Error("unable to generate api key secret", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to generate api key"))
+	}
+	k.Hash = hashApiKeySecret(secret)
+	newKey, err := srv.apiKeyStore.SaveApiKey(ctx, printer, k)
+	if err != nil {
+		return nil, err
+	}
+	res := newKey.ToPb()
+	res.Key = newKey.Prefix + "." + secret
+	srv.audit(ctx, u, "CreateApiKey", auditResourceApiKey, newKey.Id, auditChanges(nil, res))
+	return res, nil
+}
+
+// ListApiKeys lists the api keys.
+func (srv *Server) ListApiKeys(ctx context.Context, req *gooserv1.ListRequest) (*gooserv1.ListApiKeysResponse, error) {
+	_, printer, err := srv.apiKeyAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys, totalSize, token, err := srv.apiKeyStore.ListApiKeys(ctx, printer, req.GetFilter(), "", req.GetPageToken(), req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	var pbKeys []*gooserv1.ApiKey
+	var pageSize int32
+	if keys != nil {
+		pageSize = int32(len(*keys))
+		for _, k := range *keys {
+			pbKeys = append(pbKeys, k.ToPb())
+		}
+	}
+	return &gooserv1.ListApiKeysResponse{
+		ApiKeys:       pbKeys,
+		NextPageToken: token,
+		PageSize:      pageSize,
+		TotalSize:     totalSize,
+	}, nil
+}
+
+// DeleteApiKey deletes the api key with the given id.
+// The key cannot be used anymore afterwards.
+func (srv *Server) DeleteApiKey(ctx context.Context, req *gooserv1.IdRequest) (*empty.Empty, error) {
+	u, printer, err := srv.apiKeyAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := req.GetId()
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("empty id given"))
+	}
+	if err := srv.apiKeyStore.DeleteApiKey(ctx, printer, id); err != nil {
+		return nil, err
+	}
+	srv.audit(ctx, u, "DeleteApiKey", auditResourceApiKey, id, nil)
+	return &empty.Empty{}, nil
+}
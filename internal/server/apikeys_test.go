@@ -0,0 +1,336 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/message"
+
+	"github.com/golang/protobuf/ptypes"
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (suite *Suite) TestCreateServiceAccount() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name         string
+		prepare      func(db *mocks.Store)
+		accessToken  string
+		withApiKeys  bool
+		req          *gooserv1.User
+		wantCode     codes.Code
+		wantUsername string
+	}{
+		{
+			name:        "unauthenticated",
+			withApiKeys: true,
+			req:         &gooserv1.User{Username: "billing"},
+			wantCode:    codes.Unauthenticated,
+		},
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			withApiKeys: true,
+			req:         &gooserv1.User{Username: "billing"},
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "api keys not enabled",
+			accessToken: "admin",
+			req:         &gooserv1.User{Username: "billing"},
+			wantCode:    codes.Unimplemented,
+		},
+		{
+			name:        "with password",
+			accessToken: "admin",
+			withApiKeys: true,
+			req:         &gooserv1.User{Username: "billing", Password: "password"},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "valid service account",
+			accessToken: "admin",
+			withApiKeys: true,
+			req:         &gooserv1.User{Username: "billing"},
+			prepare: func(db *mocks.Store) {
+				db.On("CountUsers", mock.Anything, mock.Anything, mock.Anything).Return(int32(0), nil).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+					return u.Username == "billing" && u.ServiceAccount && u.Confirmed && u.Password == ""
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, u *store.User) *store.User {
+						u.Id = "billing"
+						return u
+					},
+					nil,
+				).Once()
+			},
+			wantCode:     codes.OK,
+			wantUsername: "billing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.Store)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.store = db
+			if tt.withApiKeys {
+				suite.srv.apiKeyStore = new(mocks.ApiKeyStore)
+			}
+			defer func() { suite.srv.apiKeyStore = nil }()
+			// prepare context with access token
+			ctx := context.Background()
+			if tt.accessToken != "" {
+				ctx = context.WithValue(ctx, "access_token", tt.accessToken)
+			}
+			// run function
+			res, err := client.CreateServiceAccount(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			// check result
+			assert.Equal(tt.wantUsername, res.Username, "username mismatch")
+			assert.True(res.ServiceAccount, "service account not set")
+		})
+	}
+}
+
+func (suite *Suite) TestCreateApiKey() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	past, _ := ptypes.TimestampProto(time.Now().Add(-time.Hour))
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.Store, keys *mocks.ApiKeyStore)
+		accessToken string
+		req         *gooserv1.ApiKey
+		wantCode    codes.Code
+	}{
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			req:         &gooserv1.ApiKey{ServiceAccountId: "billing", Name: "jobs", Scopes: []string{"*"}},
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "without scopes",
+			accessToken: "admin",
+			req:         &gooserv1.ApiKey{ServiceAccountId: "billing", Name: "jobs"},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "unknown scope",
+			accessToken: "admin",
+			req:         &gooserv1.ApiKey{ServiceAccountId: "billing", Name: "jobs", Scopes: []string{"DropDatabase"}},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "expiry in the past",
+			accessToken: "admin",
+			req:         &gooserv1.ApiKey{ServiceAccountId: "billing", Name: "jobs", Scopes: []string{"*"}, ExpiresAt: past},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "not a service account",
+			accessToken: "admin",
+			req:         &gooserv1.ApiKey{ServiceAccountId: "user", Name: "jobs", Scopes: []string{"*"}},
+			prepare: func(db *mocks.Store, keys *mocks.ApiKeyStore) {
+				db.On("GetUser", mock.Anything, mock.Anything, "user").Return(&store.User{Id: "user"}, nil).Once()
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:        "valid api key",
+			accessToken: "admin",
+			req:         &gooserv1.ApiKey{ServiceAccountId: "billing", Name: "jobs", Scopes: []string{"GetUser", "ListUsers"}},
+			prepare: func(db *mocks.Store, keys *mocks.ApiKeyStore) {
+				db.On("GetUser", mock.Anything, mock.Anything, "billing").Return(&store.User{Id: "billing", ServiceAccount: true}, nil).Once()
+				keys.On("SaveApiKey", mock.Anything, mock.Anything, mock.MatchedBy(func(k *store.ApiKey) bool {
+					return k.ServiceAccountId == "billing" && k.Prefix != "" && k.Hash != "" && len(k.Scopes) == 2
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, k *store.ApiKey) *store.ApiKey {
+						k.Id = "key1"
+						return k
+					},
+					nil,
+				).Once()
+			},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.Store)
+			keys := new(mocks.ApiKeyStore)
+			if tt.prepare != nil {
+				tt.prepare(db, keys)
+			}
+			suite.srv.store = db
+			suite.srv.apiKeyStore = keys
+			defer func() { suite.srv.apiKeyStore = nil }()
+			// prepare context with access token
+			ctx := context.Background()
+			if tt.accessToken != "" {
+				ctx = context.WithValue(ctx, "access_token", tt.accessToken)
+			}
+			// run function
+			res, err := client.CreateApiKey(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			keys.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			// the key consists of the prefix and the secret, only the hash of the secret is stored
+			saved := keys.Calls[0].Arguments.Get(2).(*store.ApiKey)
+			ss := strings.SplitN(res.Key, ".", 2)
+			assert.Len(ss, 2, "invalid key format")
+			assert.Equal(res.Prefix, ss[0], "prefix mismatch")
+			assert.Equal(hashApiKeySecret(ss[1]), saved.Hash, "hash mismatch")
+		})
+	}
+}
+
+func (suite *Suite) TestApiKeyAuthentication() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	apiKey := func(scopes []string, expiresAt time.Time) *store.ApiKey {
+		return &store.ApiKey{
+			Id:               "key1",
+			ServiceAccountId: "billing",
+			Prefix:           "prefix",
+			Hash:             hashApiKeySecret("secret"),
+			Scopes:           scopes,
+			ExpiresAt:        expiresAt,
+		}
+	}
+	serviceAccount := &store.User{Id: "billing", Username: "billing", ServiceAccount: true}
+	// tests
+	tests := []struct {
+		name     string
+		prepare  func(db *mocks.Store, keys *mocks.ApiKeyStore)
+		apiKey   string
+		wantCode codes.Code
+	}{
+		{
+			name:     "invalid format",
+			apiKey:   "secret",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:   "unknown prefix",
+			apiKey: "unknown.secret",
+			prepare: func(db *mocks.Store, keys *mocks.ApiKeyStore) {
+				keys.On("GetApiKeyByPrefix", mock.Anything, mock.Anything, "unknown").Return(nil, status.Errorf(codes.NotFound, "not found")).Once()
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:   "wrong secret",
+			apiKey: "prefix.wrong",
+			prepare: func(db *mocks.Store, keys *mocks.ApiKeyStore) {
+				keys.On("GetApiKeyByPrefix", mock.Anything, mock.Anything, "prefix").Return(apiKey([]string{"*"}, time.Time{}), nil).Once()
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:   "expired",
+			apiKey: "prefix.secret",
+			prepare: func(db *mocks.Store, keys *mocks.ApiKeyStore) {
+				keys.On("GetApiKeyByPrefix", mock.Anything, mock.Anything, "prefix").Return(apiKey([]string{"*"}, time.Now().Add(-time.Minute)), nil).Once()
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:   "method not in scope",
+			apiKey: "prefix.secret",
+			prepare: func(db *mocks.Store, keys *mocks.ApiKeyStore) {
+				keys.On("GetApiKeyByPrefix", mock.Anything, mock.Anything, "prefix").Return(apiKey([]string{"ListUsers"}, time.Time{}), nil).Once()
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "valid key",
+			apiKey: "prefix.secret",
+			prepare: func(db *mocks.Store, keys *mocks.ApiKeyStore) {
+				keys.On("GetApiKeyByPrefix", mock.Anything, mock.Anything, "prefix").Return(apiKey([]string{"GetUser"}, time.Now().Add(time.Hour)), nil).Once()
+				db.On("GetUser", mock.Anything, mock.Anything, "billing").Return(serviceAccount, nil).Once()
+				keys.On("TouchApiKey", mock.Anything, mock.Anything, "key1", mock.Anything).Return(nil).Once()
+			},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.Store)
+			keys := new(mocks.ApiKeyStore)
+			if tt.prepare != nil {
+				tt.prepare(db, keys)
+			}
+			suite.srv.store = db
+			suite.srv.apiKeyStore = keys
+			defer func() { suite.srv.apiKeyStore = nil }()
+			// prepare context with api key
+			ctx := context.WithValue(context.Background(), "api_key", tt.apiKey)
+			// run function
+			res, err := client.GetUser(ctx, &gooserv1.IdRequest{})
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			keys.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			assert.Equal("billing", res.Id, "id mismatch")
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package server
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/generator"
+	"github.com/golang/protobuf/ptypes/empty"
+	fieldmaskutils "github.com/mennanov/fieldmask-utils"
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/rbicker/gooser/internal/utils"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reAttributeName matches valid names of custom attributes. The names are used
+// as keys in rsql filters and mongodb documents and therefore must not contain dots.
+var reAttributeName = regexp.MustCompile(`^[a-z][a-zA-Z0-9_]*$`)
+
+// attributeUser returns the user from the context and the corresponding printer
+// if custom attributes are enabled. If admin is true, the user needs to be an admin.
+func (srv *Server) attributeUser(ctx context.Context, admin bool) (*store.User, *message.Printer, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u == nil {
+		return nil, nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if admin && !u.HasRole("admin") {
+		return nil, nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to manage attribute definitions"))
+	}
+	if srv.attributeStore == nil {
+		return nil, nil, status.Errorf(codes.Unimplemented, printer.Sprintf("custom attributes are not enabled"))
+	}
+	return u, printer, nil
+}
+
+// ListAttributeDefinitions lists the definitions of the custom user attributes.
+func (srv *Server) ListAttributeDefinitions(ctx context.Context, req *gooserv1.ListRequest) (*gooserv1.ListAttributeDefinitionsResponse, error) {
+	_, printer, err := srv.attributeUser(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	definitions, totalSize, token, err := srv.attributeStore.ListAttributeDefinitions(ctx, printer, req.GetFilter(), "", req.GetPageToken(), req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	var pbDefinitions []*gooserv1.AttributeDefinition
+	var pageSize int32
+	if definitions != nil {
+		pageSize = int32(len(*definitions))
+		for _, d := range *definitions {
+			pbDefinitions = append(pbDefinitions, d.ToPb())
+		}
+	}
+	return &gooserv1.ListAttributeDefinitionsResponse{
+		AttributeDefinitions: pbDefinitions,
+		NextPageToken:        token,
+		PageSize:             pageSize,
+		TotalSize:            totalSize,
+	}, nil
+}
+
+// GetAttributeDefinition returns the attribute definition with the given id.
+func (srv *Server) GetAttributeDefinition(ctx context.Context, req *gooserv1.IdRequest) (*gooserv1.AttributeDefinition, error) {
+	_, printer, err := srv.attributeUser(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	d, err := srv.attributeStore.GetAttributeDefinition(ctx, printer, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return d.ToPb(), nil
+}
+
+// ValidateAttributeDefinition validates the given attribute definition. This function
+// should be run before storing the attribute definition.
+func (srv *Server) ValidateAttributeDefinition(printer *message.Printer, definition *gooserv1.AttributeDefinition) error {
+	if !reAttributeName.MatchString(definition.GetName()) {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid attribute name, it needs to start with a lowercase letter followed by letters, numbers or underscores"))
+	}
+	switch definition.GetType() {
+	case store.AttributeTypeString, store.AttributeTypeNumber, store.AttributeTypeBoolean:
+	default:
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid attribute type, one of string, number or boolean is required"))
+	}
+	if definition.GetRegex() != "" {
+		if definition.GetType() != store.AttributeTypeString {
+			return status.Errorf(codes.InvalidArgument, printer.Sprintf("a regex can only be set for attributes of type string"))
+		}
+		if _, err := regexp.Compile(definition.GetRegex()); err != nil {
+			return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid regex: %s", err))
+		}
+	}
+	switch definition.GetEditableBy() {
+	case store.AttributeEditableByAdmin, store.AttributeEditableBySelf:
+	default:
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid editable by, one of admin or self is required"))
+	}
+	return nil
+}
+
+// CreateAttributeDefinition creates the given attribute definition.
+// By default, attributes can only be edited by admins.
+func (srv *Server) CreateAttributeDefinition(ctx context.Context, definition *gooserv1.AttributeDefinition) (*gooserv1.AttributeDefinition, error) {
+	u, printer, err := srv.attributeUser(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	definition.Id = ""
+	if definition.GetEditableBy() == "" {
+		definition.EditableBy = store.AttributeEditableByAdmin
+	}
+	if err := srv.ValidateAttributeDefinition(printer, definition); err != nil {
+		return nil, err
+	}
+	_, err = srv.attributeStore.GetAttributeDefinitionByName(ctx, printer, definition.GetName())
+	if err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, printer.Sprintf("attribute %s is already defined", definition.GetName()))
+	}
+	if code, _ := status.FromError(err); code.Code() != codes.NotFound {
+		return nil, err
+	}
+	newDefinition, err := srv.attributeStore.SaveAttributeDefinition(ctx, printer, store.PbToAttributeDefinition(definition))
+	if err != nil {
+		return nil, err
+	}
+	srv.audit(ctx, u, "CreateAttributeDefinition", auditResourceAttributeDefinition, newDefinition.Id, auditChanges(nil, newDefinition.ToPb()))
+	return newDefinition.ToPb(), nil
+}
+
+// UpdateAttributeDefinition changes the given attribute definition in the database.
+// The name and the type cannot be changed as users might already have values for the attribute.
+// Changed rules are applied when users are saved the next time.
+func (srv *Server) UpdateAttributeDefinition(ctx context.Context, req *gooserv1.UpdateAttributeDefinitionRequest) (*gooserv1.AttributeDefinition, error) {
+	u, printer, err := srv.attributeUser(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	mask, err := fieldmaskutils.MaskFromProtoFieldMask(req.GetFieldMask(), generator.CamelCase)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("unable to create generate field mask: %s", err))
+	}
+	for _, f := range []string{"Name", "Type"} {
+		if _, ok := mask.Get(f); ok {
+			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("name and type of an attribute cannot be changed"))
+		}
+	}
+	definition := req.GetAttributeDefinition()
+	existing, err := srv.attributeStore.GetAttributeDefinition(ctx, printer, definition.GetId())
+	if err != nil {
+		return nil, err
+	}
+	res := existing.ToPb()
+	// keep a copy of the existing attribute definition for the audit log
+	before := proto.Clone(res)
+	// copy given attribute definition to existing attribute definition with field mask applied
+	err = fieldmaskutils.StructToStruct(mask, definition, res)
+	if err != nil {
+		srv.log(ctx).Error("unable to merge attribute definitions", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to merge attribute definitions"))
+	}
+	if err := srv.ValidateAttributeDefinition(printer, res); err != nil {
+		return nil, err
+	}
+	updated, err := srv.attributeStore.SaveAttributeDefinition(ctx, printer, store.PbToAttributeDefinition(res))
+	if err != nil {
+		return nil, err
+	}
+	srv.audit(ctx, u, "UpdateAttributeDefinition", auditResourceAttributeDefinition, updated.Id, auditChanges(before, updated.ToPb()))
+	return updated.ToPb(), nil
+}
+
+// DeleteAttributeDefinition deletes the attribute definition with the given id.
+// The attribute is removed from all users.
+func (srv *Server) DeleteAttributeDefinition(ctx context.Context, req *gooserv1.IdRequest) (*empty.Empty, error) {
+	u, printer, err := srv.attributeUser(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("empty id given"))
+	}
+	existing, err := srv.attributeStore.GetAttributeDefinition(ctx, printer, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	// remove the values first, users with unknown attributes could not be saved anymore
+	if err := srv.store.RemoveUserAttribute(ctx, printer, existing.Name); err != nil {
+		return nil, err
+	}
+	if err := srv.attributeStore.DeleteAttributeDefinition(ctx, printer, existing.Id); err != nil {
+		return nil, err
+	}
+	srv.audit(ctx, u, "DeleteAttributeDefinition", auditResourceAttributeDefinition, existing.Id, auditChanges(existing.ToPb(), nil))
+	return &empty.Empty{}, nil
+}
+
+// attributeDefinitions returns the attribute definitions by name.
+// Without attribute store, no attributes are defined.
+func (srv *Server) attributeDefinitions(ctx context.Context, printer *message.Printer) (map[string]store.AttributeDefinition, error) {
+	res := make(map[string]store.AttributeDefinition)
+	if srv.attributeStore == nil {
+		return res, nil
+	}
+	definitions, _, _, err := srv.attributeStore.ListAttributeDefinitions(ctx, printer, "", "", "", -1)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range *definitions {
+		res[d.Name] = d
+	}
+	return res, nil
+}
+
+// validateAttributes validates the given custom attributes of the user with the given id
+// against their definitions. If required is true, all required attributes need to be set.
+func (srv *Server) validateAttributes(ctx context.Context, printer *message.Printer, id string, attributes map[string]*gooserv1.AttributeValue, required bool) error {
+	if len(attributes) == 0 && srv.attributeStore == nil {
+		return nil
+	}
+	definitions, err := srv.attributeDefinitions(ctx, printer)
+	if err != nil {
+		return err
+	}
+	// validate in a stable order
+	names := attributeNames(attributes)
+	sort.Strings(names)
+	for _, name := range names {
+		d, ok := definitions[name]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, printer.Sprintf("unknown attribute %s", name))
+		}
+		value := store.PbToAttributeValue(attributes[name])
+		switch value := value.(type) {
+		case string:
+			if d.Type != store.AttributeTypeString {
+				return status.Errorf(codes.InvalidArgument, printer.Sprintf("attribute %s needs to be of type %s", name, d.Type))
+			}
+			if d.Regex != "" {
+				re, err := regexp.Compile(d.Regex)
+				if err != nil {
+					srv.log(ctx).Error("invalid regex in attribute definition", zap.String("attribute", name), zap.Error(err))
+					return status.Errorf(codes.Internal, printer.Sprintf("invalid regex in definition of attribute %s", name))
+				}
+				if !re.MatchString(value) {
+					return status.Errorf(codes.InvalidArgument, printer.Sprintf("attribute %s does not match %s", name, d.Regex))
+				}
+			}
+		case float64:
+			if d.Type != store.AttributeTypeNumber {
+				return status.Errorf(codes.InvalidArgument, printer.Sprintf("attribute %s needs to be of type %s", name, d.Type))
+			}
+		case bool:
+			if d.Type != store.AttributeTypeBoolean {
+				return status.Errorf(codes.InvalidArgument, printer.Sprintf("attribute %s needs to be of type %s", name, d.Type))
+			}
+		default:
+			return status.Errorf(codes.InvalidArgument, printer.Sprintf("attribute %s has no value", name))
+		}
+		if d.Unique {
+			// the value is not part of a rsql filter, as it is chosen by the user
+			size, err := srv.store.CountUsersByAttribute(ctx, printer, name, value, id)
+			if err != nil {
+				return err
+			}
+			if size > 0 {
+				return status.Errorf(codes.AlreadyExists, printer.Sprintf("attribute %s is already taken", name))
+			}
+		}
+	}
+	if required {
+		for name, d := range definitions {
+			if _, ok := attributes[name]; d.Required && !ok {
+				return status.Errorf(codes.InvalidArgument, printer.Sprintf("attribute %s is required", name))
+			}
+		}
+	}
+	return nil
+}
+
+// checkAttributesEditable returns a permission denied error if the given user is not
+// allowed to set the attributes with the given names. Admins may set all attributes.
+func (srv *Server) checkAttributesEditable(ctx context.Context, printer *message.Printer, u *store.User, names []string) error {
+	if len(names) == 0 || (u != nil && u.HasRole("admin")) {
+		return nil
+	}
+	definitions, err := srv.attributeDefinitions(ctx, printer)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		d, ok := definitions[name]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, printer.Sprintf("unknown attribute %s", name))
+		}
+		if d.EditableBy != store.AttributeEditableBySelf {
+			return status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to edit attribute %s", name))
+		}
+	}
+	return nil
+}
+
+// attributeNames returns the names of the given attributes.
+func attributeNames(attributes map[string]*gooserv1.AttributeValue) []string {
+	var names []string
+	for name := range attributes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// attributePaths separates the field mask paths of custom attributes from the other paths.
+// The path attributes selects all attributes, attributes.<name> a single one. The attribute
+// paths need to be kept apart as field masks would camel case the names of the attributes.
+func attributePaths(paths []string) (others []string, names []string, all bool) {
+	for _, p := range paths {
+		switch {
+		case p == "attributes":
+			all = true
+		case strings.HasPrefix(p, "attributes."):
+			names, _ = utils.AppendUniqueString(names, strings.TrimPrefix(p, "attributes."))
+		default:
+			others = append(others, p)
+		}
+	}
+	return others, names, all
+}
+
+// mergeAttributes copies the attributes with the given names from src to dst. Attributes
+// without value in src are removed from dst. If all is true, all attributes are replaced.
+// The names of the changed attributes are returned.
+func mergeAttributes(dst, src *gooserv1.User, names []string, all bool) []string {
+	if all {
+		names, _ = utils.UniqueStringSlice(append(attributeNames(src.GetAttributes()), attributeNames(dst.GetAttributes())...))
+	}
+	var changed []string
+	for _, name := range names {
+		existing, ok := dst.Attributes[name]
+		v := src.GetAttributes()[name]
+		if store.PbToAttributeValue(v) == nil {
+			if ok {
+				delete(dst.Attributes, name)
+				changed = append(changed, name)
+			}
+			continue
+		}
+		if ok && proto.Equal(existing, v) {
+			continue
+		}
+		if dst.Attributes == nil {
+			dst.Attributes = make(map[string]*gooserv1.AttributeValue)
+		}
+		dst.Attributes[name] = v
+		changed = append(changed, name)
+	}
+	return changed
+}
@@ -0,0 +1,364 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/text/message"
+
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (suite *Suite) TestCreateAttributeDefinition() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name           string
+		prepare        func(attributes *mocks.AttributeStore)
+		accessToken    string
+		req            *gooserv1.AttributeDefinition
+		wantCode       codes.Code
+		wantEditableBy string
+	}{
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			req:         &gooserv1.AttributeDefinition{Name: "department", Type: store.AttributeTypeString},
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "invalid name",
+			accessToken: "admin",
+			req:         &gooserv1.AttributeDefinition{Name: "cost.center", Type: store.AttributeTypeString},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "invalid type",
+			accessToken: "admin",
+			req:         &gooserv1.AttributeDefinition{Name: "department", Type: "date"},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "regex for number",
+			accessToken: "admin",
+			req:         &gooserv1.AttributeDefinition{Name: "employeeId", Type: store.AttributeTypeNumber, Regex: "^[0-9]+$"},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "already defined",
+			accessToken: "admin",
+			req:         &gooserv1.AttributeDefinition{Name: "department", Type: store.AttributeTypeString},
+			prepare: func(attributes *mocks.AttributeStore) {
+				attributes.On("GetAttributeDefinitionByName", mock.Anything, mock.Anything, "department").Return(&store.AttributeDefinition{Id: "department"}, nil).Once()
+			},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:        "create",
+			accessToken: "admin",
+			req:         &gooserv1.AttributeDefinition{Name: "department", Type: store.AttributeTypeString, Regex: "^[a-z]+$"},
+			prepare: func(attributes *mocks.AttributeStore) {
+				attributes.On("GetAttributeDefinitionByName", mock.Anything, mock.Anything, "department").Return(
+					nil,
+					status.Errorf(codes.NotFound, "unable to find attribute definition"),
+				).Once()
+				attributes.On("SaveAttributeDefinition", mock.Anything, mock.Anything, mock.Anything).Return(
+					func(ctx context.Context, printer *message.Printer, definition *store.AttributeDefinition) *store.AttributeDefinition {
+						definition.Id = "department"
+						return definition
+					},
+					nil,
+				).Once()
+			},
+			wantCode:       codes.OK,
+			wantEditableBy: store.AttributeEditableByAdmin,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			attributes := new(mocks.AttributeStore)
+			if tt.prepare != nil {
+				tt.prepare(attributes)
+			}
+			suite.srv.attributeStore = attributes
+			defer func() { suite.srv.attributeStore = nil }()
+			// prepare context with access token
+			ctx := context.WithValue(context.Background(), "access_token", tt.accessToken)
+			// run function
+			res, err := client.CreateAttributeDefinition(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			attributes.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			assert.Equal(tt.wantEditableBy, res.EditableBy, "editable by mismatch")
+		})
+	}
+}
+
+func (suite *Suite) TestUpdateUserAttributes() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	definitions := &[]store.AttributeDefinition{
+		{Name: "department", Type: store.AttributeTypeString, Regex: "^[a-z]+$", EditableBy: store.AttributeEditableBySelf},
+		{Name: "employeeId", Type: store.AttributeTypeNumber, Unique: true, EditableBy: store.AttributeEditableByAdmin},
+		{Name: "newsletter", Type: store.AttributeTypeBoolean, Required: true, EditableBy: store.AttributeEditableBySelf},
+		{Name: "nickname", Type: store.AttributeTypeString, Unique: true, EditableBy: store.AttributeEditableBySelf},
+	}
+	stringValue := func(s string) *gooserv1.AttributeValue {
+		return &gooserv1.AttributeValue{Value: &gooserv1.AttributeValue_StringValue{StringValue: s}}
+	}
+	numberValue := func(f float64) *gooserv1.AttributeValue {
+		return &gooserv1.AttributeValue{Value: &gooserv1.AttributeValue_NumberValue{NumberValue: f}}
+	}
+	// existing returns the user to update
+	existing := func(db *mocks.Store) {
+		db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(
+			func(ctx context.Context, printer *message.Printer, id string) *store.User {
+				return &store.User{
+					Id:         "user1",
+					Username:   "user1",
+					Mail:       "user1@testing.com",
+					Language:   "en",
+					Confirmed:  true,
+					Attributes: map[string]interface{}{"employeeId": float64(1), "newsletter": true},
+				}
+			},
+			nil,
+		).Once()
+	}
+	// tests
+	tests := []struct {
+		name           string
+		prepare        func(db *mocks.Store)
+		accessToken    string
+		attributes     map[string]*gooserv1.AttributeValue
+		paths          []string
+		wantCode       codes.Code
+		wantAttributes map[string]*gooserv1.AttributeValue
+	}{
+		{
+			name:        "unknown attribute",
+			accessToken: "user1",
+			attributes:  map[string]*gooserv1.AttributeValue{"unknown": stringValue("value")},
+			paths:       []string{"attributes.unknown"},
+			prepare:     existing,
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "not editable by self",
+			accessToken: "user1",
+			attributes:  map[string]*gooserv1.AttributeValue{"employeeId": numberValue(2)},
+			paths:       []string{"attributes.employeeId"},
+			prepare:     existing,
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "wrong type",
+			accessToken: "user1",
+			attributes:  map[string]*gooserv1.AttributeValue{"department": numberValue(2)},
+			paths:       []string{"attributes.department"},
+			prepare: func(db *mocks.Store) {
+				existing(db)
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(usernameKey=="user1",mailKey=="user1@testing.com")`).Return(int32(0), nil).Once()
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:        "regex mismatch",
+			accessToken: "user1",
+			attributes:  map[string]*gooserv1.AttributeValue{"department": stringValue("Sales")},
+			paths:       []string{"attributes.department"},
+			prepare: func(db *mocks.Store) {
+				existing(db)
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(usernameKey=="user1",mailKey=="user1@testing.com")`).Return(int32(0), nil).Once()
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:        "removing required attribute",
+			accessToken: "user1",
+			paths:       []string{"attributes.newsletter"},
+			prepare: func(db *mocks.Store) {
+				existing(db)
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(usernameKey=="user1",mailKey=="user1@testing.com")`).Return(int32(0), nil).Once()
+				db.On("CountUsersByAttribute", mock.Anything, mock.Anything, "employeeId", float64(1), "user1").Return(int32(0), nil).Once()
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:        "unique value taken",
+			accessToken: "admin",
+			attributes:  map[string]*gooserv1.AttributeValue{"employeeId": numberValue(2)},
+			paths:       []string{"attributes.employeeId"},
+			prepare: func(db *mocks.Store) {
+				existing(db)
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(usernameKey=="user1",mailKey=="user1@testing.com")`).Return(int32(0), nil).Once()
+				db.On("CountUsersByAttribute", mock.Anything, mock.Anything, "employeeId", float64(2), "user1").Return(int32(1), nil).Once()
+			},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:        "unique value with filter syntax",
+			accessToken: "user1",
+			attributes:  map[string]*gooserv1.AttributeValue{"nickname": stringValue(`x",passwordResetToken=="abc`)},
+			paths:       []string{"attributes.nickname"},
+			prepare: func(db *mocks.Store) {
+				existing(db)
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(usernameKey=="user1",mailKey=="user1@testing.com")`).Return(int32(0), nil).Once()
+				db.On("CountUsersByAttribute", mock.Anything, mock.Anything, "employeeId", float64(1), "user1").Return(int32(0), nil).Once()
+				// the value is compared as is
+				db.On("CountUsersByAttribute", mock.Anything, mock.Anything, "nickname", `x",passwordResetToken=="abc`, "user1").Return(int32(0), nil).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+			},
+			wantCode: codes.OK,
+			wantAttributes: map[string]*gooserv1.AttributeValue{
+				"employeeId": numberValue(1),
+				"newsletter": {Value: &gooserv1.AttributeValue_BoolValue{BoolValue: true}},
+				"nickname":   stringValue(`x",passwordResetToken=="abc`),
+			},
+		},
+		{
+			name:        "update",
+			accessToken: "user1",
+			attributes:  map[string]*gooserv1.AttributeValue{"department": stringValue("sales")},
+			paths:       []string{"attributes.department"},
+			prepare: func(db *mocks.Store) {
+				existing(db)
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(usernameKey=="user1",mailKey=="user1@testing.com")`).Return(int32(0), nil).Once()
+				db.On("CountUsersByAttribute", mock.Anything, mock.Anything, "employeeId", float64(1), "user1").Return(int32(0), nil).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+			},
+			wantCode: codes.OK,
+			wantAttributes: map[string]*gooserv1.AttributeValue{
+				"department": stringValue("sales"),
+				"employeeId": numberValue(1),
+				"newsletter": {Value: &gooserv1.AttributeValue_BoolValue{BoolValue: true}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mocks
+			db := new(mocks.Store)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			attributes := new(mocks.AttributeStore)
+			attributes.On("ListAttributeDefinitions", mock.Anything, mock.Anything, "", "", "", int32(-1)).Return(definitions, int32(len(*definitions)), "", nil)
+			suite.srv.store = db
+			suite.srv.attributeStore = attributes
+			defer func() { suite.srv.attributeStore = nil }()
+			// prepare context with access token
+			ctx := context.WithValue(context.Background(), "access_token", tt.accessToken)
+			// run function
+			res, err := client.UpdateUser(ctx, &gooserv1.UpdateUserRequest{
+				User:      &gooserv1.User{Id: "user1", Attributes: tt.attributes},
+				FieldMask: &field_mask.FieldMask{Paths: tt.paths},
+			})
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			assert.Len(res.Attributes, len(tt.wantAttributes), "attributes length mismatch")
+			for name, want := range tt.wantAttributes {
+				assert.Equal(want.GetValue(), res.Attributes[name].GetValue(), "attribute %s mismatch", name)
+			}
+		})
+	}
+}
+
+func TestMergeAttributes(t *testing.T) {
+	value := func(s string) *gooserv1.AttributeValue {
+		return &gooserv1.AttributeValue{Value: &gooserv1.AttributeValue_StringValue{StringValue: s}}
+	}
+	tests := []struct {
+		name        string
+		paths       []string
+		src         map[string]*gooserv1.AttributeValue
+		wantPaths   []string
+		wantChanged []string
+		wantNames   []string
+	}{
+		{
+			name:        "single attribute",
+			paths:       []string{"mail", "attributes.a"},
+			src:         map[string]*gooserv1.AttributeValue{"a": value("new"), "c": value("ignored")},
+			wantPaths:   []string{"mail"},
+			wantChanged: []string{"a"},
+			wantNames:   []string{"a", "b"},
+		},
+		{
+			name:      "unchanged attribute",
+			paths:     []string{"attributes.b"},
+			src:       map[string]*gooserv1.AttributeValue{"b": value("b")},
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name:        "removed attribute",
+			paths:       []string{"attributes.a"},
+			wantChanged: []string{"a"},
+			wantNames:   []string{"b"},
+		},
+		{
+			name:        "all attributes",
+			paths:       []string{"attributes"},
+			src:         map[string]*gooserv1.AttributeValue{"c": value("c")},
+			wantChanged: []string{"a", "b", "c"},
+			wantNames:   []string{"c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			dst := &gooserv1.User{Attributes: map[string]*gooserv1.AttributeValue{"a": value("a"), "b": value("b")}}
+			paths, names, all := attributePaths(tt.paths)
+			assert.Equal(tt.wantPaths, paths, "paths mismatch")
+			changed := mergeAttributes(dst, &gooserv1.User{Attributes: tt.src}, names, all)
+			assert.ElementsMatch(tt.wantChanged, changed, "changed attributes mismatch")
+			assert.ElementsMatch(tt.wantNames, attributeNames(dst.Attributes), "attributes mismatch")
+		})
+	}
+}
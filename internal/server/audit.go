@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/golang/protobuf/proto"
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// resource types of audit events.
+const (
+	auditResourceUser                = "user"
+	auditResourceGroup               = "group"
+	auditResourceWebhook             = "webhook"
+	auditResourceApiKey              = "apiKey"
+	auditResourceAttributeDefinition = "attributeDefinition"
+	auditResourceMail                = "mail"
+)
+
+// redacted replaces the values of secret fields in audit events.
+const redacted = "[redacted]"
+
+// secretFields contains the fields which values must never be part of an audit event.
+var secretFields = map[string]struct{}{
+	"password":             {},
+	"secret":               {},
+	"key":                  {},
+	"confirm_token":        {},
+	"password_reset_token": {},
+}
+
+// ignoredFields contains the fields which are not considered when comparing resources.
+var ignoredFields = map[string]struct{}{
+	"created_at": {},
+	"updated_at": {},
+	"etag":       {},
+}
+
+// audit records the given action in the audit store. The actor might be nil
+// for anonymous actions. Failing to record an audit event is logged but does not fail the request.
+func (srv *Server) audit(ctx context.Context, actor *store.User, action, resourceType, resourceId string, changes []store.AuditChange) {
+	if srv.auditStore == nil {
+		return
+	}
+	event := &store.AuditEvent{
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceId:   resourceId,
+		Changes:      changes,
+	}
+	if actor != nil {
+		event.ActorId = actor.Id
+	}
+	// actions taken while impersonating a user are marked with the real actor
+	event.ImpersonatorId = impersonatorFromContext(ctx)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		event.PeerAddress = p.Addr.String()
+	}
+	printer := message.NewPrinter(language.English)
+	if _, err := srv.auditStore.SaveAuditEvent(ctx, printer, event); err != nil {
+		srv.log(ctx).Error("unable to save audit event", zap.String("action", action), zap.String("resourceType", resourceType), zap.String("resourceId", resourceId), zap.Error(err))
+	}
+}
+
+// auditChanges compares the given protobuf messages of the same type and returns
+// a change for every field which differs. Either message might be nil, for example
+// when a resource was created. The values of secret fields are redacted.
+func auditChanges(before, after proto.Message) []store.AuditChange {
+	var changes []store.AuditChange
+	vb, va := reflect.ValueOf(before), reflect.ValueOf(after)
+	var t reflect.Type
+	switch {
+	case va.IsValid() && !va.IsNil():
+		t = va.Type().Elem()
+	case vb.IsValid() && !vb.IsNil():
+		t = vb.Type().Elem()
+	default:
+		return nil
+	}
+	field := func(v reflect.Value, i int) reflect.Value {
+		if !v.IsValid() || v.IsNil() {
+			return reflect.Zero(t.Field(i).Type)
+		}
+		return v.Elem().Field(i)
+	}
+	for i := 0; i < t.NumField(); i++ {
+		name := protoFieldName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		if _, ok := ignoredFields[name]; ok {
+			continue
+		}
+		oldValue := auditValue(field(vb, i))
+		newValue := auditValue(field(va, i))
+		if oldValue == newValue {
+			continue
+		}
+		if _, ok := secretFields[name]; ok {
+			if oldValue != "" {
+				oldValue = redacted
+			}
+			if newValue != "" {
+				newValue = redacted
+			}
+		}
+		changes = append(changes, store.AuditChange{
+			Field:    name,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+	}
+	return changes
+}
+
+// protoFieldName returns the name of the protobuf field (as used in field masks)
+// for the given struct field or an empty string if it is not a protobuf field.
+func protoFieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("protobuf")
+	if tag == "" {
+		return ""
+	}
+	for _, s := range strings.Split(tag, ",") {
+		if strings.HasPrefix(s, "name=") {
+			return strings.TrimPrefix(s, "name=")
+		}
+	}
+	return ""
+}
+
+// auditValue formats the given field value for an audit event.
+func auditValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		var ss []string
+		for i := 0; i < v.Len(); i++ {
+			ss = append(ss, auditValue(v.Index(i)))
+		}
+		return strings.Join(ss, ",")
+	case reflect.Map:
+		// sort the keys to get a stable representation
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		var ss []string
+		for _, k := range keys {
+			ss = append(ss, fmt.Sprintf("%v=%s", k.Interface(), auditValue(v.MapIndex(k))))
+		}
+		return strings.Join(ss, ",")
+	case reflect.Ptr:
+		if v.IsNil() {
+			return ""
+		}
+		if m, ok := v.Interface().(proto.Message); ok {
+			return proto.CompactTextString(m)
+		}
+		return auditValue(v.Elem())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// ListAuditEvents lists the audit events.
+func (srv *Server) ListAuditEvents(ctx context.Context, req *gooserv1.ListRequest) (*gooserv1.ListAuditEventsResponse, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if !u.HasRole("admin") {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to list audit events"))
+	}
+	if srv.auditStore == nil {
+		return nil, status.Errorf(codes.Unimplemented, printer.Sprintf("audit log is not enabled"))
+	}
+	events, totalSize, token, err := srv.auditStore.ListAuditEvents(ctx, printer, req.GetFilter(), "", req.GetPageToken(), req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	var pbEvents []*gooserv1.AuditEvent
+	var pageSize int32
+	if events != nil {
+		pageSize = int32(len(*events))
+		for _, e := range *events {
+			pbEvents = append(pbEvents, e.ToPb())
+		}
+	}
+	return &gooserv1.ListAuditEventsResponse{
+		AuditEvents:   pbEvents,
+		NextPageToken: token,
+		PageSize:      pageSize,
+		TotalSize:     totalSize,
+	}, nil
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+
+	mock "github.com/stretchr/testify/mock"
+
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (suite *Suite) TestAuditChanges() {
+	t := suite.T()
+	tests := []struct {
+		name        string
+		before      *gooserv1.User
+		after       *gooserv1.User
+		wantChanges []store.AuditChange
+	}{
+		{
+			name: "created",
+			after: &gooserv1.User{
+				Username: "new",
+				Password: "hashed",
+			},
+			wantChanges: []store.AuditChange{
+				{Field: "username", NewValue: "new"},
+				{Field: "password", NewValue: redacted},
+			},
+		},
+		{
+			name: "updated",
+			before: &gooserv1.User{
+				Id:       "user1",
+				Username: "user1",
+				Mail:     "user1@testing.com",
+				Password: "old",
+				Roles:    []string{"tester"},
+			},
+			after: &gooserv1.User{
+				Id:       "user1",
+				Username: "user1",
+				Mail:     "new@testing.com",
+				Password: "new",
+				Roles:    []string{"tester", "worker"},
+			},
+			wantChanges: []store.AuditChange{
+				{Field: "mail", OldValue: "user1@testing.com", NewValue: "new@testing.com"},
+				{Field: "password", OldValue: redacted, NewValue: redacted},
+				{Field: "roles", OldValue: "tester", NewValue: "tester,worker"},
+			},
+		},
+		{
+			name: "unchanged",
+			before: &gooserv1.User{
+				Id:       "user1",
+				Username: "user1",
+			},
+			after: &gooserv1.User{
+				Id:       "user1",
+				Username: "user1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := auditChanges(tt.before, tt.after)
+			assert.Equal(t, tt.wantChanges, got)
+		})
+	}
+}
+
+func (suite *Suite) TestListAuditEvents() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.AuditStore)
+		disabled    bool
+		accessToken string
+		req         *gooserv1.ListRequest
+		wantCode    codes.Code
+		wantLen     int
+		wantAction  string
+	}{
+		{
+			name:        "unauthenticated",
+			accessToken: "",
+			req:         &gooserv1.ListRequest{},
+			wantCode:    codes.Unauthenticated,
+		},
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			req:         &gooserv1.ListRequest{},
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "audit log disabled",
+			accessToken: "admin",
+			disabled:    true,
+			req:         &gooserv1.ListRequest{},
+			wantCode:    codes.Unimplemented,
+		},
+		{
+			name:        "list events of actor",
+			accessToken: "admin",
+			req: &gooserv1.ListRequest{
+				Filter:   `actorId=="admin"`,
+				PageSize: 1,
+			},
+			prepare: func(db *mocks.AuditStore) {
+				db.On("ListAuditEvents", mock.Anything, mock.Anything, `actorId=="admin"`, "", "", int32(1)).Return(
+					&[]store.AuditEvent{
+						{
+							Id:           "event1",
+							ActorId:      "admin",
+							Action:       "DeleteUser",
+							ResourceType: "user",
+							ResourceId:   "user1",
+						},
+					},
+					int32(3),
+					"token",
+					nil,
+				).Once()
+			},
+			wantCode:   codes.OK,
+			wantLen:    1,
+			wantAction: "DeleteUser",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.AuditStore)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.auditStore = db
+			if tt.disabled {
+				suite.srv.auditStore = nil
+			}
+			defer func() { suite.srv.auditStore = nil }()
+			// prepare context with access token
+			ctx := context.Background()
+			if tt.accessToken != "" {
+				ctx = context.WithValue(ctx, "access_token", tt.accessToken)
+			}
+			// run function
+			res, err := client.ListAuditEvents(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			// check result
+			assert.Equal(tt.wantLen, len(res.AuditEvents), "length mismatch")
+			assert.Equal(tt.wantAction, res.AuditEvents[0].Action, "action mismatch")
+		})
+	}
+}
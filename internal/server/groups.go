@@ -8,13 +8,16 @@ import (
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/protoc-gen-go/generator"
 	fieldmaskutils "github.com/mennanov/fieldmask-utils"
 
 	"github.com/rbicker/gooser/internal/store"
 
 	"github.com/rbicker/gooser/internal/utils"
+	"github.com/rbicker/gooser/internal/webhooks"
 
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -106,7 +109,7 @@ func (srv *Server) AddRolesToMembers(ctx context.Context, printer *message.Print
 		filterString := fmt.Sprintf("_id=oid=(%s)", strings.Join(filterIds, ","))
 		members, size, _, err := srv.store.ListUsers(ctx, printer, filterString, "", "", int32(len(memberIds)))
 		if err != nil {
-			srv.errorLogger.Printf("unable to query members: %s", err)
+			srv.log(ctx).Error("unable to query members", zap.Error(err))
 			return status.Errorf(codes.Internal, printer.Sprintf("unable to query members"))
 		}
 		if int(size) != len(memberIds) {
@@ -147,7 +150,7 @@ func (srv *Server) RemoveRolesFromMembers(ctx context.Context, printer *message.
 				if user == nil {
 					user, err = srv.store.GetUser(ctx, printer, userId)
 					if err != nil {
-						srv.errorLogger.Printf("error while getting user with id %s: %s", userId, err)
+						srv.log(ctx).Error("error while getting user", zap.String("userId", userId), zap.Error(err))
 						return status.Errorf(codes.Internal, printer.Sprintf("error while querying member"))
 					}
 				}
@@ -197,6 +200,9 @@ func (srv *Server) CreateGroup(ctx context.Context, group *gooserv1.Group) (*goo
 	if err != nil {
 		return nil, err
 	}
+	srv.emitMemberships(ctx, webhooks.EventGroupMemberAdded, newGroup, newGroup.Members)
+	srv.notifyGranted(ctx, printer, newGroup, newGroup.Members, newGroup.Roles)
+	srv.audit(ctx, u, "CreateGroup", auditResourceGroup, newGroup.Id, auditChanges(nil, newGroup.ToPb()))
 	return newGroup.ToPb(), nil
 }
 
@@ -224,10 +230,15 @@ func (srv *Server) UpdateGroup(ctx context.Context, req *gooserv1.UpdateGroupReq
 	if err != nil {
 		return nil, err
 	}
+	if etag := req.GetEtag(); etag != "" && etag != store.Etag(existing.Revision) {
+		return nil, status.Errorf(codes.Aborted, printer.Sprintf("group was modified in the meantime"))
+	}
 	// make sure roles are unique
 	group.Roles, _ = utils.UniqueStringSlice(group.Roles)
 	// make sure members are unique
 	group.Members, _ = utils.UniqueStringSlice(group.Members)
+	// keep a copy of the existing group for the audit log
+	before := proto.Clone(existing.ToPb())
 	// store existing slices values in vars
 	existingMembers := make([]string, len(existing.Members))
 	existingRoles := make([]string, len(existing.Roles))
@@ -238,9 +249,11 @@ func (srv *Server) UpdateGroup(ctx context.Context, req *gooserv1.UpdateGroupReq
 	// copy given group to existing group with field mask applied
 	err = fieldmaskutils.StructToStruct(mask, group, res)
 	if err != nil {
-		srv.errorLogger.Printf("unable to merge groups: %s", err)
+		srv.log(ctx).Error("unable to merge groups", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to merge groups"))
 	}
+	// the etag of the request group must not bypass the revision check
+	res.Etag = store.Etag(existing.Revision)
 	// validate group
 	err = srv.ValidateGroup(ctx, printer, res)
 	if err != nil {
@@ -248,6 +261,9 @@ func (srv *Server) UpdateGroup(ctx context.Context, req *gooserv1.UpdateGroupReq
 	}
 	// save group
 	updated, err := srv.store.SaveGroup(ctx, printer, store.PbToGroup(res))
+	if err != nil {
+		return nil, err
+	}
 	// handle changes to roles
 	if _, ok := mask.Get("Roles"); ok {
 		addedRoles, removedRoles := utils.StringSlicesDiff(existingRoles, group.Roles)
@@ -256,6 +272,10 @@ func (srv *Server) UpdateGroup(ctx context.Context, req *gooserv1.UpdateGroupReq
 			if err := srv.AddRolesToMembers(ctx, printer, res.Members, addedRoles); err != nil {
 				return nil, err
 			}
+			// members added by this request are informed about all roles of the group below
+			addedMembers, _ := utils.StringSlicesDiff(existingMembers, res.Members)
+			keptMembers, _ := utils.StringSlicesDiff(addedMembers, res.Members)
+			srv.notifyGranted(ctx, printer, updated, keptMembers, addedRoles)
 		}
 		if len(removedRoles) > 0 {
 			// update members, remove roles from existing group members
@@ -272,6 +292,7 @@ func (srv *Server) UpdateGroup(ctx context.Context, req *gooserv1.UpdateGroupReq
 			if err := srv.AddRolesToMembers(ctx, printer, addedMembers, res.Roles); err != nil {
 				return nil, err
 			}
+			srv.notifyGranted(ctx, printer, updated, addedMembers, res.Roles)
 		}
 		if len(removedMembers) > 0 {
 			// update members, remove existing roles from removed members
@@ -279,7 +300,10 @@ func (srv *Server) UpdateGroup(ctx context.Context, req *gooserv1.UpdateGroupReq
 				return nil, err
 			}
 		}
+		srv.emitMemberships(ctx, webhooks.EventGroupMemberAdded, updated, addedMembers)
+		srv.emitMemberships(ctx, webhooks.EventGroupMemberRemoved, updated, removedMembers)
 	}
+	srv.audit(ctx, u, "UpdateGroup", auditResourceGroup, updated.Id, auditChanges(before, updated.ToPb()))
 	return updated.ToPb(), nil
 }
 
@@ -306,5 +330,85 @@ func (srv *Server) DeleteGroup(ctx context.Context, req *gooserv1.IdRequest) (*e
 	srv.RemoveRolesFromMembers(ctx, printer, id, group.Members, group.Roles)
 	// delete group
 	err = srv.store.DeleteGroup(ctx, printer, req.GetId())
-	return &empty.Empty{}, err
+	if err != nil {
+		return nil, err
+	}
+	srv.emitMemberships(ctx, webhooks.EventGroupMemberRemoved, group, group.Members)
+	srv.audit(ctx, u, "DeleteGroup", auditResourceGroup, id, nil)
+	return &empty.Empty{}, nil
+}
+
+// RestoreGroup restores the soft deleted group with the given id.
+// Its members get the group's roles back, members which have
+// been deleted in the meantime are dropped.
+func (srv *Server) RestoreGroup(ctx context.Context, req *gooserv1.IdRequest) (*gooserv1.Group, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if !u.HasRole("admin") {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to restore groups"))
+	}
+	id := req.GetId()
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("empty id given"))
+	}
+	restored, err := srv.store.RestoreGroup(ctx, printer, id)
+	if err != nil {
+		return nil, err
+	}
+	var members []string
+	for _, memberId := range restored.Members {
+		_, err := srv.store.GetUser(ctx, printer, memberId)
+		if code, _ := status.FromError(err); code.Code() == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, memberId)
+	}
+	if len(members) != len(restored.Members) {
+		restored.Members = members
+		restored, err = srv.store.SaveGroup(ctx, printer, restored)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := srv.AddRolesToMembers(ctx, printer, restored.Members, restored.Roles); err != nil {
+		return nil, err
+	}
+	srv.emitMemberships(ctx, webhooks.EventGroupMemberAdded, restored, restored.Members)
+	srv.audit(ctx, u, "RestoreGroup", auditResourceGroup, restored.Id, auditChanges(nil, restored.ToPb()))
+	return restored.ToPb(), nil
+}
+
+// emitMemberships emits the given membership event for every given member of the group.
+func (srv *Server) emitMemberships(ctx context.Context, event string, group *store.Group, memberIds []string) {
+	for _, id := range memberIds {
+		srv.emit(ctx, event, &webhooks.Membership{
+			GroupId:   group.Id,
+			GroupName: group.Name,
+			UserId:    id,
+		})
+	}
+}
+
+// notifyGranted informs the given members about being added to the group or, if roles are given,
+// about being granted the roles through the group. Errors are logged, as the group was saved anyway.
+func (srv *Server) notifyGranted(ctx context.Context, printer *message.Printer, group *store.Group, memberIds []string, roles []string) {
+	for _, id := range memberIds {
+		member, err := srv.store.GetUser(ctx, printer, id)
+		if err != nil || member == nil {
+			srv.log(ctx).Error("unable to query member to notify", zap.String("userId", id), zap.Error(err))
+			continue
+		}
+		if err := srv.mailer.SendGroupGranted(ctx, member, group, roles); err != nil {
+			srv.log(ctx).Error("unable to send group granted notification", zap.String("userId", id), zap.Error(err))
+		}
+	}
 }
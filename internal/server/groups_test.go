@@ -8,6 +8,7 @@ import (
 
 	"github.com/rbicker/gooser/internal/mocks"
 	"github.com/rbicker/gooser/internal/store"
+	"github.com/rbicker/gooser/internal/webhooks"
 
 	"google.golang.org/genproto/protobuf/field_mask"
 
@@ -218,7 +219,7 @@ func (suite *Suite) TestCreateGroup() {
 	// tests
 	tests := []struct {
 		name        string
-		prepare     func(db *mocks.Store)
+		prepare     func(db *mocks.Store, mailer *mocks.Messenger)
 		accessToken string
 		req         *gooserv1.Group
 		wantCode    codes.Code
@@ -252,7 +253,7 @@ func (suite *Suite) TestCreateGroup() {
 				Members: []string{"user1", "user2"},
 				Roles:   []string{"tester"},
 			},
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
 				// checking for existing group
 				db.On("CountGroups", mock.Anything, mock.Anything, `(name=="testers")`).Return(
 					int32(0),
@@ -290,6 +291,14 @@ func (suite *Suite) TestCreateGroup() {
 					},
 					nil,
 				).Once()
+				// both members are informed about the granted role
+				for _, id := range []string{"user1", "user2"} {
+					member := &store.User{Id: id, Username: id, Mail: id + "@testing.com"}
+					db.On("GetUser", mock.Anything, mock.Anything, id).Return(member, nil).Once()
+					mailer.On("SendGroupGranted", mock.Anything, member, mock.MatchedBy(func(group *store.Group) bool {
+						return group.Name == "testers"
+					}), []string{"tester"}).Return(nil).Once()
+				}
 			},
 			wantCode:    codes.OK,
 			wantId:      "", // id should be reset
@@ -305,7 +314,7 @@ func (suite *Suite) TestCreateGroup() {
 				Members: []string{"user1", "user2"},
 				Roles:   []string{"tester"},
 			},
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
 				// checking for existing group
 				db.On("CountGroups", mock.Anything, mock.Anything, `(name=="testers")`).Return(
 					int32(1),
@@ -323,7 +332,7 @@ func (suite *Suite) TestCreateGroup() {
 				Members: []string{"user1", "user2"},
 				Roles:   []string{"tester"},
 			},
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
 				// checking for existing group
 				db.On("CountGroups", mock.Anything, mock.Anything, `(name=="testers")`).Return(
 					int32(0),
@@ -350,10 +359,12 @@ func (suite *Suite) TestCreateGroup() {
 			assert := assert.New(t)
 			// prepare mock
 			db := new(mocks.Store)
+			mailer := new(mocks.Messenger)
 			if tt.prepare != nil {
-				tt.prepare(db)
+				tt.prepare(db, mailer)
 			}
 			suite.srv.store = db
+			suite.srv.mailer = mailer
 			// prepare context with access token
 			ctx := context.Background()
 			if tt.accessToken != "" {
@@ -365,6 +376,7 @@ func (suite *Suite) TestCreateGroup() {
 			code, _ := status.FromError(err)
 			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
 			db.AssertExpectations(t)
+			mailer.AssertExpectations(t)
 			if code.Code() != codes.OK {
 				// if status code is not ok, response should be nil
 				assert.Nil(res)
@@ -391,7 +403,7 @@ func (suite *Suite) TestUpdateGroup() {
 	// tests
 	tests := []struct {
 		name        string
-		prepare     func(db *mocks.Store)
+		prepare     func(db *mocks.Store, mailer *mocks.Messenger)
 		accessToken string
 		req         *gooserv1.UpdateGroupRequest
 		wantCode    codes.Code
@@ -436,7 +448,7 @@ func (suite *Suite) TestUpdateGroup() {
 				},
 				FieldMask: &field_mask.FieldMask{Paths: []string{"name", "roles", "members"}},
 			},
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
 				db.On("GetGroup", mock.Anything, mock.Anything, "testers").Return(
 					func(ctx context.Context, printer *message.Printer, id string) *store.Group {
 						return &store.Group{
@@ -503,6 +515,10 @@ func (suite *Suite) TestUpdateGroup() {
 					},
 					nil,
 				).Twice()
+				// only the added member is informed, the removed member is not granted the new role
+				user2 := &store.User{Id: "user2", Username: "user2", Mail: "user2@testing.com"}
+				db.On("GetUser", mock.Anything, mock.Anything, "user2").Return(user2, nil).Once()
+				mailer.On("SendGroupGranted", mock.Anything, user2, mock.Anything, []string{"validator"}).Return(nil).Once()
 			},
 			wantCode:    codes.OK,
 			wantId:      "testers",
@@ -510,16 +526,67 @@ func (suite *Suite) TestUpdateGroup() {
 			wantMembers: []string{"user2"},
 			wantRoles:   []string{"validator"},
 		},
+		{
+			name:        "etag mismatch",
+			accessToken: "admin",
+			req: &gooserv1.UpdateGroupRequest{
+				Group: &gooserv1.Group{
+					Id:   "testers",
+					Name: "validators",
+				},
+				FieldMask: &field_mask.FieldMask{Paths: []string{"name"}},
+				Etag:      store.Etag(2),
+			},
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("GetGroup", mock.Anything, mock.Anything, "testers").Return(&store.Group{
+					Id:       "testers",
+					Name:     "testers",
+					Revision: 3,
+				}, nil).Once()
+			},
+			wantCode: codes.Aborted,
+		},
+		{
+			name:        "modified concurrently",
+			accessToken: "admin",
+			req: &gooserv1.UpdateGroupRequest{
+				Group: &gooserv1.Group{
+					Id:   "testers",
+					Name: "validators",
+					Etag: store.Etag(5),
+				},
+				FieldMask: &field_mask.FieldMask{Paths: []string{"name", "etag"}},
+				Etag:      store.Etag(3),
+			},
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("GetGroup", mock.Anything, mock.Anything, "testers").Return(&store.Group{
+					Id:       "testers",
+					Name:     "testers",
+					Revision: 3,
+				}, nil).Once()
+				db.On("CountGroups", mock.Anything, mock.Anything, `(_id!oid="testers");(name=="validators")`).Return(int32(0), nil).Once()
+				// the revision of the request group must be ignored
+				db.On("SaveGroup", mock.Anything, mock.Anything, mock.MatchedBy(func(group *store.Group) bool {
+					return group.Revision == 3
+				})).Return(
+					nil,
+					status.Errorf(codes.Aborted, "group was modified or deleted in the meantime"),
+				).Once()
+			},
+			wantCode: codes.Aborted,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			assert := assert.New(t)
 			// prepare mock
 			db := new(mocks.Store)
+			mailer := new(mocks.Messenger)
 			if tt.prepare != nil {
-				tt.prepare(db)
+				tt.prepare(db, mailer)
 			}
 			suite.srv.store = db
+			suite.srv.mailer = mailer
 			// prepare context with access token
 			ctx := context.Background()
 			if tt.accessToken != "" {
@@ -531,6 +598,7 @@ func (suite *Suite) TestUpdateGroup() {
 			code, _ := status.FromError(err)
 			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
 			db.AssertExpectations(t)
+			mailer.AssertExpectations(t)
 			if code.Code() != codes.OK {
 				// if status code is not ok, response should be nil
 				assert.Nil(res)
@@ -557,7 +625,7 @@ func (suite *Suite) TestDeleteGroup() {
 	// tests
 	tests := []struct {
 		name        string
-		prepare     func(db *mocks.Store)
+		prepare     func(db *mocks.Store, events *mocks.Emitter)
 		accessToken string
 		req         *gooserv1.IdRequest
 		wantCode    codes.Code
@@ -588,7 +656,7 @@ func (suite *Suite) TestDeleteGroup() {
 			req: &gooserv1.IdRequest{
 				Id: "testers",
 			},
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, events *mocks.Emitter) {
 				db.On("GetGroup", mock.Anything, mock.Anything, "testers").Return(
 					func(ctx context.Context, printer *message.Printer, id string) *store.Group {
 						return &store.Group{
@@ -630,6 +698,12 @@ func (suite *Suite) TestDeleteGroup() {
 				db.On("DeleteGroup", mock.Anything, mock.Anything, "testers").Return(
 					nil,
 				)
+				// removing the group removes its members
+				events.On("Emit", mock.Anything, webhooks.EventGroupMemberRemoved, &webhooks.Membership{
+					GroupId:   "testers",
+					GroupName: "testers",
+					UserId:    "user1",
+				}).Return(nil).Once()
 			},
 			wantCode: codes.OK,
 		},
@@ -639,10 +713,13 @@ func (suite *Suite) TestDeleteGroup() {
 			assert := assert.New(t)
 			// prepare mock
 			db := new(mocks.Store)
+			events := new(mocks.Emitter)
 			if tt.prepare != nil {
-				tt.prepare(db)
+				tt.prepare(db, events)
 			}
 			suite.srv.store = db
+			suite.srv.events = events
+			defer func() { suite.srv.events = nil }()
 			// prepare context with access token
 			ctx := context.Background()
 			if tt.accessToken != "" {
@@ -654,6 +731,7 @@ func (suite *Suite) TestDeleteGroup() {
 			code, _ := status.FromError(err)
 			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
 			db.AssertExpectations(t)
+			events.AssertExpectations(t)
 			if code.Code() != codes.OK {
 				// if status code is not ok, response should be nil
 				assert.Nil(res)
@@ -662,3 +740,115 @@ func (suite *Suite) TestDeleteGroup() {
 		})
 	}
 }
+
+func (suite *Suite) TestRestoreGroup() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.Store, events *mocks.Emitter)
+		accessToken string
+		wantCode    codes.Code
+		wantMembers []string
+	}{
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "not deleted",
+			accessToken: "admin",
+			prepare: func(db *mocks.Store, events *mocks.Emitter) {
+				db.On("RestoreGroup", mock.Anything, mock.Anything, "testers").Return(
+					nil,
+					status.Errorf(codes.NotFound, "unable to find deleted group"),
+				).Once()
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:        "restore roles of remaining members",
+			accessToken: "admin",
+			prepare: func(db *mocks.Store, events *mocks.Emitter) {
+				db.On("RestoreGroup", mock.Anything, mock.Anything, "testers").Return(&store.Group{
+					Id:      "testers",
+					Name:    "testers",
+					Members: []string{"user1", "user2"},
+					Roles:   []string{"tester"},
+				}, nil).Once()
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(&store.User{Id: "user1"}, nil).Once()
+				// user2 has been deleted in the meantime
+				db.On("GetUser", mock.Anything, mock.Anything, "user2").Return(
+					nil,
+					status.Errorf(codes.NotFound, "unable to find user"),
+				).Once()
+				db.On("SaveGroup", mock.Anything, mock.Anything, mock.MatchedBy(func(g *store.Group) bool {
+					return g.Id == "testers" && len(g.Members) == 1 && g.Members[0] == "user1"
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, group *store.Group) *store.Group {
+						return group
+					},
+					nil,
+				).Once()
+				db.On("ListUsers", mock.Anything, mock.Anything, `_id=oid=("user1")`, "", "", int32(1)).Return(
+					&[]store.User{{Id: "user1"}},
+					int32(1),
+					"",
+					nil,
+				).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(user *store.User) bool {
+					return user.Id == "user1" && len(user.Roles) == 1 && user.Roles[0] == "tester"
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+				events.On("Emit", mock.Anything, webhooks.EventGroupMemberAdded, &webhooks.Membership{
+					GroupId:   "testers",
+					GroupName: "testers",
+					UserId:    "user1",
+				}).Return(nil).Once()
+			},
+			wantCode:    codes.OK,
+			wantMembers: []string{"user1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.Store)
+			events := new(mocks.Emitter)
+			if tt.prepare != nil {
+				tt.prepare(db, events)
+			}
+			suite.srv.store = db
+			suite.srv.events = events
+			defer func() { suite.srv.events = nil }()
+			// prepare context with access token
+			ctx := context.WithValue(context.Background(), "access_token", tt.accessToken)
+			// run function
+			res, err := client.RestoreGroup(ctx, &gooserv1.IdRequest{Id: "testers"})
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			events.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			assert.ElementsMatch(tt.wantMembers, res.Members, "members mismatch")
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/rbicker/gooser/internal/logging"
+	"github.com/rbicker/gooser/internal/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// impersonateHeader is the metadata key of the id of the user to impersonate.
+const impersonateHeader = "x-impersonate-user"
+
+// impersonation holds the id of the user to impersonate during a request and,
+// as soon as the impersonation was permitted, the id of the impersonating admin.
+type impersonation struct {
+	userId         string
+	impersonatorId string
+}
+
+// impersonate returns the user to impersonate if the request asks for it, otherwise the given actor.
+// Only admins are allowed to impersonate users and admins cannot be impersonated.
+// The actor is recorded as impersonator, which marks the logs and audit events of the request.
+func (srv *Server) impersonate(ctx context.Context, actor *store.User) (*store.User, error) {
+	imp, ok := ctx.Value("impersonation").(*impersonation)
+	if !ok || imp.userId == "" {
+		return actor, nil
+	}
+	if actor == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(actor.Language))
+	if !actor.HasRole("admin") {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to impersonate users"))
+	}
+	target, err := srv.store.GetUser(ctx, printer, imp.userId)
+	if err != nil {
+		return nil, err
+	}
+	if target.HasRole("admin") {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("admins cannot be impersonated"))
+	}
+	if imp.impersonatorId == "" {
+		imp.impersonatorId = actor.Id
+		logging.SetImpersonatorId(ctx, actor.Id)
+		srv.log(ctx).Info("impersonating user", zap.String("userId", target.Id))
+	}
+	return target, nil
+}
+
+// impersonatorFromContext returns the id of the admin impersonating the
+// user of the request or an empty string if no user is impersonated.
+func impersonatorFromContext(ctx context.Context) string {
+	if imp, ok := ctx.Value("impersonation").(*impersonation); ok {
+		return imp.impersonatorId
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/text/message"
+
+	"google.golang.org/genproto/protobuf/field_mask"
+
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (suite *Suite) TestImpersonation() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.Store)
+		accessToken string
+		impersonate string
+		wantCode    codes.Code
+		wantId      string
+	}{
+		{
+			name:        "without impersonation",
+			accessToken: "admin",
+			wantCode:    codes.OK,
+			wantId:      "admin",
+		},
+		{
+			name:        "unauthenticated",
+			impersonate: "user1",
+			wantCode:    codes.Unauthenticated,
+		},
+		{
+			name:        "not an admin",
+			accessToken: "support",
+			impersonate: "user1",
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "unknown user",
+			accessToken: "admin",
+			impersonate: "unknown",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUser", mock.Anything, mock.Anything, "unknown").Return(nil, status.Errorf(codes.NotFound, "not found")).Once()
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:        "impersonate admin",
+			accessToken: "admin",
+			impersonate: "admin2",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUser", mock.Anything, mock.Anything, "admin2").Return(&store.User{Id: "admin2", Roles: []string{"admin"}}, nil).Once()
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:        "impersonate user",
+			accessToken: "admin",
+			impersonate: "user1",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(&store.User{Id: "user1", Roles: []string{"user"}}, nil).Once()
+			},
+			wantCode: codes.OK,
+			wantId:   "user1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.Store)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.store = db
+			// prepare context with access token and user to impersonate
+			ctx := context.Background()
+			if tt.accessToken != "" {
+				ctx = context.WithValue(ctx, "access_token", tt.accessToken)
+			}
+			if tt.impersonate != "" {
+				ctx = context.WithValue(ctx, "impersonate_user", tt.impersonate)
+			}
+			// run function
+			res, err := client.GetUser(ctx, &gooserv1.IdRequest{})
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			assert.Equal(tt.wantId, res.Id, "id mismatch")
+		})
+	}
+}
+
+func (suite *Suite) TestImpersonatedAuditEvent() {
+	t := suite.T()
+	assert := assert.New(t)
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// prepare mocks
+	db := new(mocks.Store)
+	audit := new(mocks.AuditStore)
+	user := func(ctx context.Context, printer *message.Printer, id string) *store.User {
+		return &store.User{Id: "user1", Username: "user1", Language: "en", Roles: []string{"user"}}
+	}
+	db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(user, nil)
+	db.On("CountUsers", mock.Anything, mock.Anything, mock.Anything).Return(int32(0), nil).Once()
+	db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, printer *message.Printer, u *store.User) *store.User {
+			return u
+		},
+		nil,
+	).Once()
+	// the action is recorded for the impersonated user, marked with the admin
+	audit.On("SaveAuditEvent", mock.Anything, mock.Anything, mock.MatchedBy(func(e *store.AuditEvent) bool {
+		return e.Action == "UpdateUser" && e.ActorId == "user1" && e.ImpersonatorId == "admin"
+	})).Return(&store.AuditEvent{}, nil).Once()
+	suite.srv.store = db
+	suite.srv.auditStore = audit
+	defer func() { suite.srv.auditStore = nil }()
+	// run function
+	ctx := context.WithValue(context.Background(), "access_token", "admin")
+	ctx = context.WithValue(ctx, "impersonate_user", "user1")
+	_, err = client.UpdateUser(ctx, &gooserv1.UpdateUserRequest{
+		User:      &gooserv1.User{Language: "de"},
+		FieldMask: &field_mask.FieldMask{Paths: []string{"language"}},
+	})
+	assert.Nil(err)
+	db.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/rbicker/gooser/internal/utils"
+	"github.com/rbicker/gooser/internal/webhooks"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// invitationAdmin returns the user from the context and the corresponding printer
+// if the user is allowed to manage invitations.
+func (srv *Server) invitationAdmin(ctx context.Context) (*store.User, *message.Printer, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u == nil {
+		return nil, nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if !u.HasRole("admin") {
+		return nil, nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to manage invitations"))
+	}
+	return u, printer, nil
+}
+
+// pendingInvitation returns the invited user with the given id.
+// It fails if the user already accepted the invitation.
+func (srv *Server) pendingInvitation(ctx context.Context, printer *message.Printer, id string) (*store.User, error) {
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("empty id given"))
+	}
+	existing, err := srv.store.GetUser(ctx, printer, id)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.InvitationPending() {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("user does not have a pending invitation"))
+	}
+	return existing, nil
+}
+
+// InviteUser creates a pending user with the given mail address and sends an invitation.
+// The invited user chooses username and password when accepting the invitation and
+// becomes member of the given groups.
+func (srv *Server) InviteUser(ctx context.Context, req *gooserv1.InviteUserRequest) (*gooserv1.User, error) {
+	u, printer, err := srv.invitationAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mail := req.GetMail()
+	if !utils.IsMailAddress(mail) {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid mail address"))
+	}
+	lang := req.GetLanguage()
+	if lang == "" {
+		lang = utils.LookupEnv("GOOSER_DEFAULT_LANGUAGE", "en")
+	}
+	if _, err := language.Parse(lang); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("could not parse given language"))
+	}
+	size, err := srv.store.CountUsers(ctx, printer, fmt.Sprintf(`mailKey=="%s"`, store.LookupKey(mail)))
+	if err != nil {
+		return nil, err
+	}
+	if size > 0 {
+		return nil, status.Errorf(codes.AlreadyExists, printer.Sprintf("mail address is already taken"))
+	}
+	// required attributes can still be set when accepting the invitation
+	if err := srv.validateAttributes(ctx, printer, "", req.GetAttributes(), false); err != nil {
+		return nil, err
+	}
+	groupIds, _ := utils.UniqueStringSlice(req.GetGroupIds())
+	for _, id := range groupIds {
+		if _, err := srv.store.GetGroup(ctx, printer, id); err != nil {
+			if code, _ := status.FromError(err); code.Code() == codes.NotFound {
+				return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("unable to find group with id %s", id))
+			}
+			return nil, err
+		}
+	}
+	invited := &store.User{
+		Mail:     mail,
+		Language: lang,
+		// accepting the invitation proves the mail address
+		Confirmed:        true,
+		InvitationGroups: groupIds,
+		InvitedBy:        u.Id,
+		Attributes:       store.PbToAttributes(req.GetAttributes()),
+	}
+	if err := invited.GenerateInvitationToken(printer, srv.secret, time.Now().Add(srv.invitationTTL)); err != nil {
+		return nil, err
+	}
+	// the user is saved first, so no token is sent for a user which does not exist
+	newUser, err := srv.store.SaveUser(ctx, printer, invited)
+	if err != nil {
+		return nil, err
+	}
+	srv.emit(ctx, webhooks.EventUserInvited, newUser.ToPb())
+	srv.audit(ctx, u, "InviteUser", auditResourceUser, newUser.Id, auditChanges(nil, newUser.ToPb()))
+	if err := srv.mailer.SendInvitation(ctx, newUser); err != nil {
+		// the invitation can be sent again using ResendInvitation
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to send invitation mail"))
+	}
+	return newUser.ToPb(), nil
+}
+
+// AcceptInvitation completes the invitation with the given token. The invited
+// user sets username and password and becomes member of the groups of the invitation.
+func (srv *Server) AcceptInvitation(ctx context.Context, req *gooserv1.AcceptInvitationRequest) (*gooserv1.User, error) {
+	printer := message.NewPrinter(language.Make(utils.LookupEnv("GOOSER_DEFAULT_LANGUAGE", "en")))
+	token := req.GetToken()
+	if token == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("no token given"))
+	}
+	user, err := srv.store.GetUserByInvitationToken(ctx, printer, token)
+	if code, _ := status.FromError(err); code.Code() == codes.NotFound {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid token"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	printer = message.NewPrinter(language.Make(user.Language))
+	if err := user.ValidateInvitationToken(printer, srv.secret, token, time.Now()); err != nil {
+		return nil, err
+	}
+	if len(req.GetPassword()) < 7 {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("password must have a length of at least 7"))
+	}
+	user.Username = req.GetUsername()
+	if err := srv.checkAttributesEditable(ctx, printer, user, attributeNames(req.GetAttributes())); err != nil {
+		return nil, err
+	}
+	for name, v := range store.PbToAttributes(req.GetAttributes()) {
+		if user.Attributes == nil {
+			user.Attributes = make(map[string]interface{})
+		}
+		user.Attributes[name] = v
+	}
+	if err := srv.ValidateUser(ctx, printer, user.ToPb()); err != nil {
+		return nil, err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.GetPassword()), bcrypt.DefaultCost)
+	if err != nil {
+		srv.log(ctx).Error("error while creating password hash", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to hash given password"))
+	}
+	user.Password = string(hashed)
+	var groups []*store.Group
+	for _, groupId := range user.InvitationGroups {
+		g, err := srv.store.GetGroup(ctx, printer, groupId)
+		if code, _ := status.FromError(err); code.Code() == codes.NotFound {
+			// the group has been deleted in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	user.InvitationToken = ""
+	user.InvitationExpiresAt = time.Time{}
+	user.InvitationGroups = nil
+	// the user is saved before the memberships are added, so the token is used up
+	// and no group lists a member which does not exist yet
+	accepted, err := srv.store.SaveUser(ctx, printer, user)
+	if err != nil {
+		return nil, err
+	}
+	// the roles of a group are only granted once the user is a member of it
+	var granted bool
+	for _, g := range groups {
+		var added bool
+		g.Members, added = utils.AppendUniqueString(g.Members, accepted.Id)
+		if added {
+			if _, err := srv.store.SaveGroup(ctx, printer, g); err != nil {
+				// the invitation has been accepted, an admin has to add the membership
+				srv.log(ctx).Error("unable to add user to group", zap.String("userId", accepted.Id), zap.String("groupId", g.Id), zap.Error(err))
+				continue
+			}
+			srv.emit(ctx, webhooks.EventGroupMemberAdded, &webhooks.Membership{GroupId: g.Id, GroupName: g.Name, UserId: accepted.Id})
+		}
+		for _, r := range g.Roles {
+			var c bool
+			accepted.Roles, c = utils.AppendUniqueString(accepted.Roles, r)
+			granted = granted || c
+		}
+	}
+	if granted {
+		accepted, err = srv.store.SaveUser(ctx, printer, accepted)
+		if err != nil {
+			return nil, err
+		}
+	}
+	srv.emit(ctx, webhooks.EventUserCreated, accepted.ToPb())
+	srv.audit(ctx, accepted, "AcceptInvitation", auditResourceUser, accepted.Id, []store.AuditChange{
+		{Field: "username", NewValue: accepted.Username},
+		{Field: "password", NewValue: redacted},
+	})
+	return accepted.ToPb(), nil
+}
+
+// ResendInvitation sends the invitation of the user with the given id again.
+// A new token is generated, the previous one cannot be used anymore.
+func (srv *Server) ResendInvitation(ctx context.Context, req *gooserv1.IdRequest) (*gooserv1.User, error) {
+	u, printer, err := srv.invitationAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := srv.pendingInvitation(ctx, printer, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	before := existing.ToPb()
+	if err := existing.GenerateInvitationToken(printer, srv.secret, time.Now().Add(srv.invitationTTL)); err != nil {
+		return nil, err
+	}
+	// the new token is saved first, so no token is sent which cannot be accepted
+	resent, err := srv.store.SaveUser(ctx, printer, existing)
+	if err != nil {
+		return nil, err
+	}
+	srv.audit(ctx, u, "ResendInvitation", auditResourceUser, resent.Id, auditChanges(before, resent.ToPb()))
+	if err := srv.mailer.SendInvitation(ctx, resent); err != nil {
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to send invitation mail"))
+	}
+	return resent.ToPb(), nil
+}
+
+// RevokeInvitation revokes the invitation of the user with the given id.
+// The pending user is deleted.
+func (srv *Server) RevokeInvitation(ctx context.Context, req *gooserv1.IdRequest) (*empty.Empty, error) {
+	u, printer, err := srv.invitationAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := srv.pendingInvitation(ctx, printer, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	id := existing.Id
+	// the token must not be usable, even if the user gets restored
+	existing.InvitationToken = ""
+	existing.InvitationExpiresAt = time.Time{}
+	if _, err := srv.store.SaveUser(ctx, printer, existing); err != nil {
+		return nil, err
+	}
+	if err := srv.store.DeleteUser(ctx, printer, id); err != nil {
+		return nil, err
+	}
+	srv.audit(ctx, u, "RevokeInvitation", auditResourceUser, id, nil)
+	return &empty.Empty{}, nil
+}
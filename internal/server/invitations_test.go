@@ -0,0 +1,468 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (suite *Suite) TestInviteUser() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.Store, mailer *mocks.Messenger)
+		accessToken string
+		req         *gooserv1.InviteUserRequest
+		wantCode    codes.Code
+	}{
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			req:         &gooserv1.InviteUserRequest{Mail: "new@testing.com"},
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "invalid mail",
+			accessToken: "admin",
+			req:         &gooserv1.InviteUserRequest{Mail: "new"},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "mail taken",
+			accessToken: "admin",
+			req:         &gooserv1.InviteUserRequest{Mail: "new@testing.com"},
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("CountUsers", mock.Anything, mock.Anything, `mailKey=="new@testing.com"`).Return(int32(1), nil).Once()
+			},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:        "unknown group",
+			accessToken: "admin",
+			req:         &gooserv1.InviteUserRequest{Mail: "new@testing.com", GroupIds: []string{"unknown"}},
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("CountUsers", mock.Anything, mock.Anything, `mailKey=="new@testing.com"`).Return(int32(0), nil).Once()
+				db.On("GetGroup", mock.Anything, mock.Anything, "unknown").Return(
+					nil,
+					status.Errorf(codes.NotFound, "unable to find group"),
+				).Once()
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:        "invite",
+			accessToken: "admin",
+			req:         &gooserv1.InviteUserRequest{Mail: "new@testing.com", GroupIds: []string{"testers"}},
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("CountUsers", mock.Anything, mock.Anything, `mailKey=="new@testing.com"`).Return(int32(0), nil).Once()
+				db.On("GetGroup", mock.Anything, mock.Anything, "testers").Return(&store.Group{Id: "testers"}, nil).Once()
+				invited := mock.MatchedBy(func(u *store.User) bool {
+					return u.Mail == "new@testing.com" && u.InvitationToken != "" && u.InvitedBy == "admin" &&
+						len(u.InvitationGroups) == 1 && u.InvitationGroups[0] == "testers"
+				})
+				db.On("SaveUser", mock.Anything, mock.Anything, invited).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						user.Id = "new"
+						return user
+					},
+					nil,
+				).Once()
+				// the invitation is sent for the saved user
+				mailer.On("SendInvitation", mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+					return u.Id == "new" && u.InvitationToken != ""
+				})).Return(nil).Once()
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:        "user not saved",
+			accessToken: "admin",
+			req:         &gooserv1.InviteUserRequest{Mail: "new@testing.com"},
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("CountUsers", mock.Anything, mock.Anything, `mailKey=="new@testing.com"`).Return(int32(0), nil).Once()
+				// the mail address has been taken concurrently, no invitation is sent
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+					nil,
+					status.Errorf(codes.AlreadyExists, "mail address is already taken"),
+				).Once()
+			},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:        "invitation not sent",
+			accessToken: "admin",
+			req:         &gooserv1.InviteUserRequest{Mail: "new@testing.com"},
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("CountUsers", mock.Anything, mock.Anything, `mailKey=="new@testing.com"`).Return(int32(0), nil).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						user.Id = "new"
+						return user
+					},
+					nil,
+				).Once()
+				mailer.On("SendInvitation", mock.Anything, mock.Anything).Return(errors.New("unable to queue mail")).Once()
+			},
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mocks
+			db := new(mocks.Store)
+			mailer := new(mocks.Messenger)
+			if tt.prepare != nil {
+				tt.prepare(db, mailer)
+			}
+			suite.srv.store = db
+			suite.srv.mailer = mailer
+			// prepare context with access token
+			ctx := context.WithValue(context.Background(), "access_token", tt.accessToken)
+			// run function
+			res, err := client.InviteUser(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			mailer.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			assert.True(res.InvitationPending, "invitation pending mismatch")
+			assert.NotNil(res.InvitationExpiresAt, "invitation expiry not set")
+		})
+	}
+}
+
+func (suite *Suite) TestAcceptInvitation() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	printer := message.NewPrinter(language.English)
+	// invitedUser returns a pending user with a token expiring at the given time
+	invitedUser := func(expiresAt time.Time) *store.User {
+		u := &store.User{
+			Id:               "new",
+			Mail:             "new@testing.com",
+			Language:         "en",
+			Confirmed:        true,
+			InvitationGroups: []string{"testers"},
+		}
+		if err := u.GenerateInvitationToken(printer, suite.srv.secret, expiresAt); err != nil {
+			t.Fatalf("unable to generate invitation token: %s", err)
+		}
+		return u
+	}
+	valid := invitedUser(time.Now().Add(time.Hour))
+	unsaved := invitedUser(time.Now().Add(time.Hour))
+	conflicting := invitedUser(time.Now().Add(time.Hour))
+	expired := invitedUser(time.Now().Add(-time.Hour))
+	// a token signed with another secret
+	forged := invitedUser(time.Now().Add(time.Hour))
+	if err := forged.GenerateInvitationToken(printer, "another secret", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("unable to generate invitation token: %s", err)
+	}
+	// tests
+	tests := []struct {
+		name      string
+		prepare   func(db *mocks.Store)
+		req       *gooserv1.AcceptInvitationRequest
+		wantCode  codes.Code
+		wantRoles []string
+	}{
+		{
+			name:     "no token",
+			req:      &gooserv1.AcceptInvitationRequest{Username: "new", Password: "password"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "unknown token",
+			req:  &gooserv1.AcceptInvitationRequest{Token: "unknown", Username: "new", Password: "password"},
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByInvitationToken", mock.Anything, mock.Anything, "unknown").Return(
+					nil,
+					status.Errorf(codes.NotFound, "unable to find user"),
+				).Once()
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "forged token",
+			req:  &gooserv1.AcceptInvitationRequest{Token: forged.InvitationToken, Username: "new", Password: "password"},
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByInvitationToken", mock.Anything, mock.Anything, forged.InvitationToken).Return(forged, nil).Once()
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "expired",
+			req:  &gooserv1.AcceptInvitationRequest{Token: expired.InvitationToken, Username: "new", Password: "password"},
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByInvitationToken", mock.Anything, mock.Anything, expired.InvitationToken).Return(expired, nil).Once()
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "accept",
+			req:  &gooserv1.AcceptInvitationRequest{Token: valid.InvitationToken, Username: "new", Password: "password"},
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByInvitationToken", mock.Anything, mock.Anything, valid.InvitationToken).Return(valid, nil).Once()
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="new");(usernameKey=="new",mailKey=="new@testing.com")`).Return(int32(0), nil).Once()
+				db.On("GetGroup", mock.Anything, mock.Anything, "testers").Return(&store.Group{
+					Id:    "testers",
+					Name:  "testers",
+					Roles: []string{"tester"},
+				}, nil).Once()
+				var userSaved, memberAdded bool
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+					return u.Username == "new" && u.ValidatePassword("password") && !u.InvitationPending() && len(u.InvitationGroups) == 0 &&
+						len(u.Roles) == 0
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						userSaved = true
+						return user
+					},
+					nil,
+				).Once()
+				// the membership is only added after the user has been saved
+				db.On("SaveGroup", mock.Anything, mock.Anything, mock.MatchedBy(func(g *store.Group) bool {
+					return userSaved && g.Id == "testers" && len(g.Members) == 1 && g.Members[0] == "new"
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, group *store.Group) *store.Group {
+						memberAdded = true
+						return group
+					},
+					nil,
+				).Once()
+				// the roles are only granted after the membership has been added
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+					return memberAdded && len(u.Roles) == 1 && u.Roles[0] == "tester"
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+			},
+			wantCode:  codes.OK,
+			wantRoles: []string{"tester"},
+		},
+		{
+			name: "group not saved",
+			req:  &gooserv1.AcceptInvitationRequest{Token: conflicting.InvitationToken, Username: "new", Password: "password"},
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByInvitationToken", mock.Anything, mock.Anything, conflicting.InvitationToken).Return(conflicting, nil).Once()
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="new");(usernameKey=="new",mailKey=="new@testing.com")`).Return(int32(0), nil).Once()
+				db.On("GetGroup", mock.Anything, mock.Anything, "testers").Return(&store.Group{
+					Id:    "testers",
+					Name:  "testers",
+					Roles: []string{"admin"},
+				}, nil).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+					return len(u.Roles) == 0
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+				// the group has been modified concurrently, its roles are not granted
+				db.On("SaveGroup", mock.Anything, mock.Anything, mock.Anything).Return(
+					nil,
+					status.Errorf(codes.Aborted, "group has been modified"),
+				).Once()
+			},
+			wantCode:  codes.OK,
+			wantRoles: []string{},
+		},
+		{
+			name: "user not saved",
+			req:  &gooserv1.AcceptInvitationRequest{Token: unsaved.InvitationToken, Username: "new", Password: "password"},
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByInvitationToken", mock.Anything, mock.Anything, unsaved.InvitationToken).Return(unsaved, nil).Once()
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="new");(usernameKey=="new",mailKey=="new@testing.com")`).Return(int32(0), nil).Once()
+				db.On("GetGroup", mock.Anything, mock.Anything, "testers").Return(&store.Group{
+					Id:    "testers",
+					Name:  "testers",
+					Roles: []string{"tester"},
+				}, nil).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+					nil,
+					status.Errorf(codes.Internal, "unable to save user"),
+				).Once()
+				// no group is saved with a member which does not exist
+			},
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.Store)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.store = db
+			// run function
+			res, err := client.AcceptInvitation(context.Background(), tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			assert.False(res.InvitationPending, "invitation pending mismatch")
+			assert.ElementsMatch(tt.wantRoles, res.Roles, "roles mismatch")
+		})
+	}
+}
+
+func (suite *Suite) TestResendInvitation() {
+	t := suite.T()
+	assert := assert.New(t)
+	printer := message.NewPrinter(language.English)
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	invited := &store.User{
+		Id:       "new",
+		Mail:     "new@testing.com",
+		Language: "en",
+	}
+	if err := invited.GenerateInvitationToken(printer, suite.srv.secret, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("unable to generate invitation token: %s", err)
+	}
+	// the token sent with the first invitation
+	oldToken := invited.InvitationToken
+	db := new(mocks.Store)
+	mailer := new(mocks.Messenger)
+	suite.srv.store = db
+	suite.srv.mailer = mailer
+	// the stored user
+	saved := invited
+	db.On("GetUser", mock.Anything, mock.Anything, "new").Return(invited, nil).Once()
+	mailer.On("SendInvitation", mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+		return u.InvitationToken != "" && u.InvitationToken != oldToken
+	})).Return(nil).Once()
+	db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+			saved = user
+			return user
+		},
+		nil,
+	).Once()
+	// the lookup finds the stored user, even if it still matched the old token
+	db.On("GetUserByInvitationToken", mock.Anything, mock.Anything, oldToken).Return(
+		func(ctx context.Context, printer *message.Printer, token string) *store.User {
+			return saved
+		},
+		nil,
+	).Once()
+	// resend the invitation
+	ctx := context.WithValue(context.Background(), "access_token", "admin")
+	res, err := client.ResendInvitation(ctx, &gooserv1.IdRequest{Id: "new"})
+	assert.NoError(err)
+	assert.True(res.GetInvitationPending(), "invitation pending mismatch")
+	assert.NotEqual(oldToken, saved.InvitationToken, "token should be replaced")
+	// accepting the invitation using the old token fails
+	_, err = client.AcceptInvitation(context.Background(), &gooserv1.AcceptInvitationRequest{Token: oldToken, Username: "new", Password: "password"})
+	code, _ := status.FromError(err)
+	assert.Equal(codes.InvalidArgument, code.Code(), "response statuscode mismatch")
+	db.AssertExpectations(t)
+	mailer.AssertExpectations(t)
+}
+
+func (suite *Suite) TestRevokeInvitation() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name     string
+		prepare  func(db *mocks.Store)
+		wantCode codes.Code
+	}{
+		{
+			name: "not invited",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(&store.User{Id: "user1"}, nil).Once()
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "revoke",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(&store.User{Id: "user1", InvitationToken: "token"}, nil).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+					return !u.InvitationPending()
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+				db.On("DeleteUser", mock.Anything, mock.Anything, "user1").Return(nil).Once()
+			},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.Store)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.store = db
+			ctx := context.WithValue(context.Background(), "access_token", "admin")
+			// run function
+			_, err := client.RevokeInvitation(ctx, &gooserv1.IdRequest{Id: "user1"})
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PreviewMail renders the given mail template for the user with the given id
+// without sending it. It allows admins to check their templates.
+func (srv *Server) PreviewMail(ctx context.Context, req *gooserv1.PreviewMailRequest) (*gooserv1.PreviewMailResponse, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if !u.HasRole("admin") {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to preview mails"))
+	}
+	if req.GetUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("empty id given"))
+	}
+	user, err := srv.store.GetUser(ctx, printer, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	content, err := srv.mailer.PreviewMail(ctx, req.GetTemplate(), user)
+	if err != nil {
+		return nil, err
+	}
+	return &gooserv1.PreviewMailResponse{
+		Subject: content.Subject,
+		Text:    content.Text,
+		Html:    content.HTML,
+	}, nil
+}
+
+// mailAdmin returns the user from the context and the corresponding printer
+// if the user is an admin and the mail queue is enabled.
+func (srv *Server) mailAdmin(ctx context.Context) (*store.User, *message.Printer, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u == nil {
+		return nil, nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if !u.HasRole("admin") {
+		return nil, nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to manage mails"))
+	}
+	if srv.mailStore == nil {
+		return nil, nil, status.Errorf(codes.Unimplemented, printer.Sprintf("the mail queue is not enabled"))
+	}
+	return u, printer, nil
+}
+
+// ListMails lists the mails of the outbound mail queue.
+func (srv *Server) ListMails(ctx context.Context, req *gooserv1.ListRequest) (*gooserv1.ListMailsResponse, error) {
+	_, printer, err := srv.mailAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mails, totalSize, token, err := srv.mailStore.ListMails(ctx, printer, req.GetFilter(), "", req.GetPageToken(), req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	var pbMails []*gooserv1.Mail
+	var pageSize int32
+	if mails != nil {
+		pageSize = int32(len(*mails))
+		for _, m := range *mails {
+			pbMails = append(pbMails, m.ToPb())
+		}
+	}
+	return &gooserv1.ListMailsResponse{
+		Mails:         pbMails,
+		NextPageToken: token,
+		PageSize:      pageSize,
+		TotalSize:     totalSize,
+	}, nil
+}
+
+// RetryMail queues a failed mail for delivery again, resetting its attempts.
+// The mail is sent as it was composed, links it contains might have expired or
+// been replaced by newer tokens in the meantime. Failed mails whose contents have
+// been purged cannot be retried.
+func (srv *Server) RetryMail(ctx context.Context, req *gooserv1.IdRequest) (*gooserv1.Mail, error) {
+	u, printer, err := srv.mailAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("empty id given"))
+	}
+	mail, err := srv.mailStore.GetMail(ctx, printer, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if mail.State != store.MailFailed {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("only failed mails can be retried"))
+	}
+	if mail.Text == "" && mail.HTML == "" && mail.Raw == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("the contents of the mail have been purged"))
+	}
+	mail.State = store.MailPending
+	mail.Attempts = 0
+	mail.NextAttemptAt = time.Now()
+	mail.LastError = ""
+	mail, err = srv.mailStore.SaveMail(ctx, printer, mail)
+	if err != nil {
+		return nil, err
+	}
+	srv.audit(ctx, u, "RetryMail", auditResourceMail, mail.Id, nil)
+	return mail.ToPb(), nil
+}
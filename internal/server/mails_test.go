@@ -0,0 +1,298 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/mailer"
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"golang.org/x/text/message"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (suite *Suite) TestPreviewMail() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.Store, m *mocks.Messenger)
+		accessToken string
+		req         *gooserv1.PreviewMailRequest
+		wantCode    codes.Code
+		wantSubject string
+	}{
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			req:         &gooserv1.PreviewMailRequest{Template: mailer.TemplateConfirm, UserId: "user1"},
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "no user",
+			accessToken: "admin",
+			req:         &gooserv1.PreviewMailRequest{Template: mailer.TemplateConfirm},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "unknown template",
+			accessToken: "admin",
+			req:         &gooserv1.PreviewMailRequest{Template: "unknown", UserId: "user1"},
+			prepare: func(db *mocks.Store, m *mocks.Messenger) {
+				user := &store.User{Id: "user1", Username: "user1"}
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(user, nil).Once()
+				m.On("PreviewMail", mock.Anything, "unknown", user).Return(
+					nil,
+					status.Errorf(codes.InvalidArgument, "unable to render template unknown"),
+				).Once()
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:        "preview",
+			accessToken: "admin",
+			req:         &gooserv1.PreviewMailRequest{Template: mailer.TemplateConfirm, UserId: "user1"},
+			prepare: func(db *mocks.Store, m *mocks.Messenger) {
+				user := &store.User{Id: "user1", Username: "user1"}
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(user, nil).Once()
+				m.On("PreviewMail", mock.Anything, mailer.TemplateConfirm, user).Return(&mailer.Content{
+					Subject: "gooser: confirm mail address",
+					Text:    "Hi user1!",
+					HTML:    "<p>Hi user1!</p>",
+				}, nil).Once()
+			},
+			wantCode:    codes.OK,
+			wantSubject: "gooser: confirm mail address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mocks
+			db := new(mocks.Store)
+			m := new(mocks.Messenger)
+			if tt.prepare != nil {
+				tt.prepare(db, m)
+			}
+			suite.srv.store = db
+			suite.srv.mailer = m
+			ctx := context.WithValue(context.Background(), "access_token", tt.accessToken)
+			// run function
+			res, err := client.PreviewMail(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			m.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				assert.Nil(res)
+				return
+			}
+			assert.Equal(tt.wantSubject, res.Subject, "subject mismatch")
+		})
+	}
+}
+
+func (suite *Suite) TestListMails() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.MailStore)
+		disabled    bool
+		accessToken string
+		req         *gooserv1.ListRequest
+		wantCode    codes.Code
+		wantLen     int
+	}{
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			req:         &gooserv1.ListRequest{},
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "mail queue disabled",
+			accessToken: "admin",
+			disabled:    true,
+			req:         &gooserv1.ListRequest{},
+			wantCode:    codes.Unimplemented,
+		},
+		{
+			name:        "list failed mails",
+			accessToken: "admin",
+			req: &gooserv1.ListRequest{
+				Filter:   `state=="failed"`,
+				PageSize: 10,
+			},
+			prepare: func(db *mocks.MailStore) {
+				db.On("ListMails", mock.Anything, mock.Anything, `state=="failed"`, "", "", int32(10)).Return(
+					&[]store.Mail{
+						{
+							Id:        "mail1",
+							To:        []string{"user1@example.com"},
+							Subject:   "gooser: password reset",
+							Text:      "secret token",
+							State:     store.MailFailed,
+							Attempts:  10,
+							LastError: "connection refused",
+						},
+					},
+					int32(1),
+					"",
+					nil,
+				).Once()
+			},
+			wantCode: codes.OK,
+			wantLen:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.MailStore)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.mailStore = db
+			if tt.disabled {
+				suite.srv.mailStore = nil
+			}
+			defer func() { suite.srv.mailStore = nil }()
+			ctx := context.WithValue(context.Background(), "access_token", tt.accessToken)
+			// run function
+			res, err := client.ListMails(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				assert.Nil(res)
+				return
+			}
+			assert.Len(res.Mails, tt.wantLen, "number of mails mismatch")
+			assert.Equal(store.MailFailed, res.Mails[0].State, "state mismatch")
+			assert.Equal("connection refused", res.Mails[0].LastError, "last error mismatch")
+		})
+	}
+}
+
+func (suite *Suite) TestRetryMail() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.MailStore)
+		accessToken string
+		req         *gooserv1.IdRequest
+		wantCode    codes.Code
+	}{
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			req:         &gooserv1.IdRequest{Id: "mail1"},
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "empty id",
+			accessToken: "admin",
+			req:         &gooserv1.IdRequest{},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "mail not failed",
+			accessToken: "admin",
+			req:         &gooserv1.IdRequest{Id: "mail1"},
+			prepare: func(db *mocks.MailStore) {
+				db.On("GetMail", mock.Anything, mock.Anything, "mail1").Return(&store.Mail{
+					Id:    "mail1",
+					State: store.MailSent,
+				}, nil).Once()
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:        "contents purged",
+			accessToken: "admin",
+			req:         &gooserv1.IdRequest{Id: "mail1"},
+			prepare: func(db *mocks.MailStore) {
+				db.On("GetMail", mock.Anything, mock.Anything, "mail1").Return(&store.Mail{
+					Id:       "mail1",
+					State:    store.MailFailed,
+					Attempts: 10,
+				}, nil).Once()
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:        "retry failed mail",
+			accessToken: "admin",
+			req:         &gooserv1.IdRequest{Id: "mail1"},
+			prepare: func(db *mocks.MailStore) {
+				db.On("GetMail", mock.Anything, mock.Anything, "mail1").Return(&store.Mail{
+					Id:        "mail1",
+					State:     store.MailFailed,
+					Text:      "Hi user1!",
+					Attempts:  10,
+					LastError: "connection refused",
+				}, nil).Once()
+				db.On("SaveMail", mock.Anything, mock.Anything, mock.MatchedBy(func(m *store.Mail) bool {
+					return m.State == store.MailPending && m.Attempts == 0 && m.LastError == "" && !m.NextAttemptAt.IsZero()
+				})).Return(func(ctx context.Context, printer *message.Printer, m *store.Mail) *store.Mail {
+					return m
+				}, nil).Once()
+			},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.MailStore)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.mailStore = db
+			defer func() { suite.srv.mailStore = nil }()
+			ctx := context.WithValue(context.Background(), "access_token", tt.accessToken)
+			// run function
+			res, err := client.RetryMail(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				assert.Nil(res)
+				return
+			}
+			assert.Equal(store.MailPending, res.State, "state mismatch")
+		})
+	}
+}
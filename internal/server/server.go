@@ -5,15 +5,19 @@ package server
 import (
 	"context"
 	"crypto/md5"
+	"crypto/x509"
 	"fmt"
 	"io"
-	"log"
 	"net"
 	"os"
 	"strconv"
 	"time"
 
+	"github.com/rbicker/gooser/internal/logging"
 	"github.com/rbicker/gooser/internal/mailer"
+	"github.com/rbicker/gooser/internal/metrics"
+	"github.com/rbicker/gooser/internal/tracing"
+	"github.com/rbicker/gooser/internal/webhooks"
 
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -25,26 +29,45 @@ import (
 	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
 	"github.com/rbicker/gooser/internal/store"
 	"github.com/rbicker/gooser/internal/utils"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 )
 
 // Server implements the gooser server.
 type Server struct {
-	secret              string
-	port                string
-	store               store.Store
-	mailer              mailer.Messenger
-	grpcServer          *grpc.Server
-	useReflection       bool
-	listener            net.Listener
-	authClient          auth.UserLookup
-	errorLogger         *log.Logger
-	infoLogger          *log.Logger
-	contextUserReceiver func(ctx context.Context, db store.Store) (*store.User, error)
+	secret               string
+	port                 string
+	store                store.Store
+	webhookStore         store.WebhookStore
+	auditStore           store.AuditStore
+	apiKeyStore          store.ApiKeyStore
+	attributeStore       store.AttributeStore
+	mailStore            store.MailStore
+	mailQueue            *mailer.Queue
+	events               webhooks.Emitter
+	metrics              *metrics.Metrics
+	mailer               mailer.Messenger
+	grpcServer           *grpc.Server
+	healthServer         *grpchealth.Server
+	useReflection        bool
+	listener             net.Listener
+	tlsCertFile          string
+	tlsKeyFile           string
+	clientCAs            *x509.CertPool
+	servicePrincipals    map[string]string
+	accountDeletionDelay time.Duration
+	invitationTTL        time.Duration
+	mailRevertTTL        time.Duration
+	authClient           auth.UserLookup
+	logger               *zap.Logger
+	contextUserReceiver  func(ctx context.Context, db store.Store) (*store.User, error)
 }
 
 // PageToken represents a pagination token.
@@ -61,12 +84,13 @@ func NewServer(secret string, db store.Store, authClient auth.UserLookup, mailer
 	printer := message.NewPrinter(language.English)
 	// create server
 	var srv = Server{
-		infoLogger:  log.New(os.Stdout, "INFO: ", log.Lmsgprefix+log.LstdFlags),
-		errorLogger: log.New(os.Stderr, "ERROR: ", log.Lmsgprefix+log.LstdFlags),
-		port:        "50051", // default port
-		authClient:  authClient,
-		store:       db,
-		mailer:      mailer,
+		port:                 "50051", // default port
+		authClient:           authClient,
+		store:                db,
+		mailer:               mailer,
+		accountDeletionDelay: 7 * 24 * time.Hour,
+		invitationTTL:        7 * 24 * time.Hour,
+		mailRevertTTL:        7 * 24 * time.Hour,
 	}
 	// run functional options
 	for _, op := range opts {
@@ -75,6 +99,10 @@ func NewServer(secret string, db store.Store, authClient auth.UserLookup, mailer
 			return nil, fmt.Errorf("setting option failed: %w", err)
 		}
 	}
+	// default logger
+	if srv.logger == nil {
+		srv.logger = logging.Default()
+	}
 	// hash secret key
 	h := md5.New()
 	if _, err := io.WriteString(h, secret); err != nil {
@@ -88,7 +116,7 @@ func NewServer(secret string, db store.Store, authClient auth.UserLookup, mailer
 			if !ok || accessToken == "" {
 				return nil, nil
 			}
-			id, err := srv.authClient.GetUserIDbyToken(accessToken)
+			id, err := srv.authClient.GetUserIDbyToken(ctx, accessToken)
 			if err != nil {
 				return nil, err
 			}
@@ -110,12 +138,37 @@ func NewServer(secret string, db store.Store, authClient auth.UserLookup, mailer
 			token := header[0]
 			ctx = context.WithValue(ctx, "access_token", token)
 		}
+		if header, ok := md[apiKeyHeader]; ok {
+			ctx = context.WithValue(ctx, "api_key", header[0])
+		}
+		if header, ok := md[impersonateHeader]; ok {
+			ctx = context.WithValue(ctx, "impersonation", &impersonation{userId: header[0]})
+		}
 		return handler(ctx, req)
 	}
-	// register grpc server
-	srv.grpcServer = grpc.NewServer(
-		grpc.UnaryInterceptor(unaryInterceptor),
-	)
+	// register grpc server, the metrics, tracing and logging interceptors
+	// run first to observe the complete request
+	grpcOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			srv.metrics.UnaryServerInterceptor(),
+			tracing.UnaryServerInterceptor(),
+			logging.UnaryServerInterceptor(srv.logger),
+			unaryInterceptor,
+		),
+	}
+	tlsConfig, err := srv.tlsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure tls: %w", err)
+	}
+	if tlsConfig != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	srv.grpcServer = grpc.NewServer(grpcOpts...)
+	// register health service, which reports serving by default
+	if srv.healthServer == nil {
+		srv.healthServer = grpchealth.NewServer()
+	}
+	healthpb.RegisterHealthServer(srv.grpcServer, srv.healthServer)
 	// enable reflection
 	if srv.useReflection {
 		reflection.Register(srv.grpcServer)
@@ -137,8 +190,11 @@ func (srv *Server) Serve() error {
 	return srv.grpcServer.Serve(srv.listener)
 }
 
-// Stop stops the gooser server.
+// Stop stops the gooser server. The health service reports
+// not serving while the server is shutting down. If a mail queue
+// is set, it is drained after the last request has been handled.
 func (srv *Server) Stop() error {
+	srv.healthServer.Shutdown()
 	stopped := make(chan struct{})
 	go func() {
 		srv.grpcServer.GracefulStop()
@@ -151,10 +207,15 @@ func (srv *Server) Stop() error {
 	case <-stopped:
 		t.Stop()
 	}
+	// no more mails are queued once the grpc server has stopped
+	if srv.mailQueue != nil {
+		srv.mailQueue.Stop()
+	}
 	return nil
 }
 
-// InitCollections initializes the users and groups collections if necessary.
+// InitCollections makes sure the admin user and the admins group exist.
+// Changes to the schema or the stored data belong to the store migrations.
 func (srv *Server) InitCollections(ctx context.Context) error {
 	printer := message.NewPrinter(language.Make(utils.LookupEnv("GOOSER_DEFAULT_LANGUAGE", "en")))
 	// handle admin user
@@ -166,7 +227,13 @@ func (srv *Server) InitCollections(ctx context.Context) error {
 			return fmt.Errorf("unable to get admin user '%s': %w", username, err)
 		}
 		// user does not exist, create
-		plain := utils.RandomString(15)
+		plain := utils.LookupEnv("GOOSER_ADMIN_PASSWORD", "")
+		generated := plain == ""
+		if generated {
+			plain = utils.RandomString(15)
+		} else if len(plain) < 7 {
+			return fmt.Errorf("GOOSER_ADMIN_PASSWORD must have a length of at least 7")
+		}
 		hashed, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
 		if err != nil {
 			return fmt.Errorf("unable to hash password: %w", err)
@@ -180,10 +247,14 @@ func (srv *Server) InitCollections(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("unable to create admin user '%s': %w", username, err)
 		}
-		srv.infoLogger.Printf("created admin user '%s' with password '%s' - change now", username, plain)
+		srv.logger.Info("created admin user", zap.String("username", username))
+		if generated {
+			// the password is never logged, it is shown once on stderr instead
+			fmt.Fprintf(os.Stderr, "created admin user '%s' with password '%s' - change now\n", username, plain)
+		}
 	} else if !u.HasRole("admin") {
 		// fix user
-		srv.infoLogger.Printf("adding role admin directly to user %s", username)
+		srv.logger.Info("adding role admin directly to user", zap.String("username", username))
 		u.Roles = append(u.Roles, "admin")
 		u, err = srv.store.SaveUser(ctx, printer, u)
 	}
@@ -202,7 +273,7 @@ func (srv *Server) InitCollections(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("unable to create admins-group: %w", err)
 		}
-		srv.infoLogger.Println("created admins group")
+		srv.logger.Info("created admins group")
 	} else {
 		// group exists, check role
 		var changed, foundRole, foundMember bool
@@ -213,7 +284,7 @@ func (srv *Server) InitCollections(ctx context.Context) error {
 			}
 		}
 		if !foundRole {
-			srv.infoLogger.Println("adding admin role to group admins")
+			srv.logger.Info("adding admin role to group admins")
 			g.Roles = append(g.Roles, "admins")
 			changed = true
 		}
@@ -224,7 +295,7 @@ func (srv *Server) InitCollections(ctx context.Context) error {
 			}
 		}
 		if !foundMember {
-			srv.infoLogger.Printf("adding user '%s' to group admins", username)
+			srv.logger.Info("adding user to group admins", zap.String("username", username))
 			g.Members = append(g.Members, u.Id)
 			changed = true
 		}
@@ -239,9 +310,67 @@ func (srv *Server) InitCollections(ctx context.Context) error {
 }
 
 // GetUserInfoFromContext returns the user corresponding
-// to the access token in the given context.
-func (srv *Server) GetUserFromContext(ctx context.Context) (*store.User, error) {
-	return srv.contextUserReceiver(ctx, srv.store)
+// to the access token in the given context. Without access token, the
+// service account of the api key or the service principal of the
+// client certificate is returned if configured. If an admin asks to
+// impersonate a user, the impersonated user is returned.
+func (srv *Server) GetUserFromContext(ctx context.Context) (u *store.User, err error) {
+	ctx, span := tracing.Start(ctx, "server.GetUserFromContext")
+	defer func() {
+		tracing.End(span, err)
+	}()
+	u, err = srv.contextUserReceiver(ctx, srv.store)
+	// without access token, an api key of a service account might be given
+	if err == nil && u == nil {
+		u, err = srv.apiKeyUserFromContext(ctx)
+	}
+	// otherwise, the client certificate might belong to a service principal
+	if err == nil && u == nil {
+		u, err = srv.servicePrincipalFromContext(ctx)
+	}
+	// suspended users are not allowed to act, neither themselves nor impersonated by an admin
+	if err == nil {
+		err = checkSuspended(u)
+	}
+	// admins might act as another user
+	if err == nil {
+		u, err = srv.impersonate(ctx, u)
+	}
+	if err == nil {
+		err = checkSuspended(u)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if u != nil {
+		logging.SetUserId(ctx, u.Id)
+	}
+	return u, nil
+}
+
+// checkSuspended returns a permission denied error if the given user is suspended.
+func checkSuspended(u *store.User) error {
+	if u == nil || !u.IsSuspended(time.Now()) {
+		return nil
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	return status.Errorf(codes.PermissionDenied, printer.Sprintf("user is suspended"))
+}
+
+// log returns the logger of the request belonging to the given context.
+func (srv *Server) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, srv.logger)
+}
+
+// emit publishes the given event if an event emitter is configured.
+// Failing to publish an event is logged but does not fail the request.
+func (srv *Server) emit(ctx context.Context, event string, data interface{}) {
+	if srv.events == nil {
+		return
+	}
+	if err := srv.events.Emit(ctx, event, data); err != nil {
+		srv.log(ctx).Error("unable to emit event", zap.String("event", event), zap.Error(err))
+	}
 }
 
 // SetPort sets the gooser server port.
@@ -284,3 +413,124 @@ func EnableReflection() func(*Server) error {
 		return nil
 	}
 }
+
+// WithLogger sets the logger of the server.
+func WithLogger(logger *zap.Logger) func(*Server) error {
+	return func(srv *Server) error {
+		srv.logger = logger
+		return nil
+	}
+}
+
+// WithHealthServer sets the grpc health server, which allows
+// to change the reported serving status from outside of the server.
+func WithHealthServer(healthServer *grpchealth.Server) func(*Server) error {
+	return func(srv *Server) error {
+		srv.healthServer = healthServer
+		return nil
+	}
+}
+
+// WithWebhookStore sets the store used to manage webhooks.
+// The webhook functions are not available if no webhook store is set.
+func WithWebhookStore(webhookStore store.WebhookStore) func(*Server) error {
+	return func(srv *Server) error {
+		srv.webhookStore = webhookStore
+		return nil
+	}
+}
+
+// WithAuditStore sets the store used to record audit events.
+// No audit events are recorded if no audit store is set.
+func WithAuditStore(auditStore store.AuditStore) func(*Server) error {
+	return func(srv *Server) error {
+		srv.auditStore = auditStore
+		return nil
+	}
+}
+
+// WithApiKeyStore sets the store used to manage the api keys of service accounts.
+// Service accounts and api keys are not available if no api key store is set.
+func WithApiKeyStore(apiKeyStore store.ApiKeyStore) func(*Server) error {
+	return func(srv *Server) error {
+		srv.apiKeyStore = apiKeyStore
+		return nil
+	}
+}
+
+// WithAttributeStore sets the store used to manage the definitions of custom user attributes.
+// Custom attributes are not available if no attribute store is set.
+func WithAttributeStore(attributeStore store.AttributeStore) func(*Server) error {
+	return func(srv *Server) error {
+		srv.attributeStore = attributeStore
+		return nil
+	}
+}
+
+// WithMailStore sets the store of the outbound mail queue.
+// Queued mails cannot be managed if no mail store is set.
+func WithMailStore(mailStore store.MailStore) func(*Server) error {
+	return func(srv *Server) error {
+		srv.mailStore = mailStore
+		return nil
+	}
+}
+
+// WithMailQueue sets the mail queue which is stopped with the server.
+func WithMailQueue(queue *mailer.Queue) func(*Server) error {
+	return func(srv *Server) error {
+		srv.mailQueue = queue
+		return nil
+	}
+}
+
+// WithAccountDeletionDelay changes the cooling-off period between
+// a user requesting the deletion of the account and the actual deletion.
+func WithAccountDeletionDelay(delay time.Duration) func(*Server) error {
+	return func(srv *Server) error {
+		if delay < 0 {
+			return fmt.Errorf("account deletion delay cannot be negative")
+		}
+		srv.accountDeletionDelay = delay
+		return nil
+	}
+}
+
+// WithInvitationTTL changes how long invitations can be accepted.
+func WithInvitationTTL(ttl time.Duration) func(*Server) error {
+	return func(srv *Server) error {
+		if ttl <= 0 {
+			return fmt.Errorf("invitation ttl needs to be greater than 0")
+		}
+		srv.invitationTTL = ttl
+		return nil
+	}
+}
+
+// WithMailRevertTTL changes how long a mail address change can be reverted
+// using the link sent to the previous address.
+func WithMailRevertTTL(ttl time.Duration) func(*Server) error {
+	return func(srv *Server) error {
+		if ttl <= 0 {
+			return fmt.Errorf("mail revert ttl needs to be greater than 0")
+		}
+		srv.mailRevertTTL = ttl
+		return nil
+	}
+}
+
+// WithEventEmitter sets the emitter used to publish user and group events.
+func WithEventEmitter(emitter webhooks.Emitter) func(*Server) error {
+	return func(srv *Server) error {
+		srv.events = emitter
+		return nil
+	}
+}
+
+// WithMetrics sets the metrics used to instrument the grpc requests.
+func WithMetrics(m *metrics.Metrics) func(*Server) error {
+	return func(srv *Server) error {
+		srv.metrics = m
+		return nil
+	}
+}
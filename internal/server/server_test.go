@@ -1,9 +1,14 @@
 package server
 
 import (
+	"context"
 	"testing"
 
+	"github.com/rbicker/gooser/internal/logging"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func (suite *Suite) TestSetPort() {
@@ -54,3 +59,54 @@ func (suite *Suite) TestSetPort() {
 		})
 	}
 }
+
+func (suite *Suite) TestHealth() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	defer suite.srv.healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	tests := []struct {
+		name       string
+		status     healthpb.HealthCheckResponse_ServingStatus
+		wantStatus healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{
+			name:       "serving",
+			status:     healthpb.HealthCheckResponse_SERVING,
+			wantStatus: healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name:       "not serving",
+			status:     healthpb.HealthCheckResponse_NOT_SERVING,
+			wantStatus: healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite.srv.healthServer.SetServingStatus("", tt.status)
+			res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantStatus, res.GetStatus())
+		})
+	}
+}
+
+func (suite *Suite) TestRequestId() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	var header metadata.MD
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, header.Get(logging.RequestIdKey), "request id should be returned")
+}
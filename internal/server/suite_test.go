@@ -12,6 +12,7 @@ import (
 	"github.com/rbicker/gooser/internal/store"
 	mock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/test/bufconn"
@@ -61,6 +62,8 @@ func (suite *Suite) SetupSuite() {
 			Confirmed: true,
 		}, nil
 	}))
+	// discard logs
+	srvOpts = append(srvOpts, WithLogger(zap.NewNop()))
 	// create in-memory listener
 	suite.listener = bufconn.Listen(1024 * 1024)
 	srvOpts = append(srvOpts, WithListener(suite.listener))
@@ -94,6 +97,14 @@ func (suite *Suite) NewClientConnection() (*grpc.ClientConn, error) {
 		if ok && accessToken != "" {
 			meta["access_token"] = accessToken
 		}
+		apiKey, ok := ctx.Value("api_key").(string)
+		if ok && apiKey != "" {
+			meta[apiKeyHeader] = apiKey
+		}
+		impersonate, ok := ctx.Value("impersonate_user").(string)
+		if ok && impersonate != "" {
+			meta[impersonateHeader] = impersonate
+		}
 		md := metadata.New(meta)
 		ctx = metadata.NewOutgoingContext(context.TODO(), md)
 		return invoker(ctx, method, req, reply, cc, opts...)
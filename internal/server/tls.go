@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/rbicker/gooser/internal/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// certReloader serves a certificate loaded from the given files
+// and reloads it whenever one of the files changes.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	logger   *zap.Logger
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	modTime  time.Time
+}
+
+// newCertReloader creates a new certificate reloader and loads the certificate.
+func newCertReloader(certFile, keyFile string, logger *zap.Logger) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		logger:   logger,
+	}
+	modTime, err := r.lastModified()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// lastModified returns the latest modification time of the certificate and key file.
+func (r *certReloader) lastModified() (time.Time, error) {
+	var latest time.Time
+	for _, f := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(f)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unable to stat %s: %w", f, err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// load loads the certificate from the files.
+func (r *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load certificate: %w", err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+// GetCertificate returns the current certificate. It is used as tls.Config.GetCertificate.
+// If loading a changed certificate fails, the previous certificate is kept.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	modTime, err := r.lastModified()
+	if err == nil && !modTime.Equal(r.modTime) {
+		if err := r.load(modTime); err != nil {
+			r.logger.Error("unable to reload certificate, keeping the previous one", zap.Error(err))
+		} else {
+			r.logger.Info("reloaded certificate", zap.String("certFile", r.certFile))
+		}
+	}
+	return r.cert, nil
+}
+
+// servicePrincipalFromContext returns the user configured as service principal for the
+// common name of the verified client certificate of the request. It returns nil
+// if the request was not authenticated by a client certificate.
+func (srv *Server) servicePrincipalFromContext(ctx context.Context) (*store.User, error) {
+	if len(srv.servicePrincipals) == 0 {
+		return nil, nil
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+	commonName := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+	username, ok := srv.servicePrincipals[commonName]
+	if !ok {
+		return nil, nil
+	}
+	printer := message.NewPrinter(language.English)
+	u, err := srv.store.GetUserByUsername(ctx, printer, username)
+	if code, _ := status.FromError(err); code.Code() == codes.NotFound {
+		srv.log(ctx).Error("service principal not found", zap.String("commonName", commonName), zap.String("username", username))
+		return nil, status.Errorf(codes.Unauthenticated, "service principal not found")
+	}
+	return u, err
+}
+
+// WithTLS instructs the server to serve using TLS with the certificate and key from the given files.
+// The certificate is reloaded whenever the files change.
+func WithTLS(certFile, keyFile string) func(*Server) error {
+	return func(srv *Server) error {
+		srv.tlsCertFile = certFile
+		srv.tlsKeyFile = keyFile
+		return nil
+	}
+}
+
+// WithClientCA instructs the server to require client certificates
+// signed by one of the certificate authorities from the given pem file.
+// It requires TLS to be enabled.
+func WithClientCA(caFile string) func(*Server) error {
+	return func(srv *Server) error {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("unable to read client ca file %s: %w", caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in client ca file %s", caFile)
+		}
+		srv.clientCAs = pool
+		return nil
+	}
+}
+
+// WithServicePrincipal lets services authenticated by a client certificate with the given
+// common name act as the user with the given username if no access token is given.
+func WithServicePrincipal(commonName, username string) func(*Server) error {
+	return func(srv *Server) error {
+		if commonName == "" || username == "" {
+			return fmt.Errorf("common name and username of a service principal cannot be empty")
+		}
+		if srv.servicePrincipals == nil {
+			srv.servicePrincipals = make(map[string]string)
+		}
+		srv.servicePrincipals[commonName] = username
+		return nil
+	}
+}
+
+// tlsConfig returns the tls config of the server or nil if TLS is not enabled.
+func (srv *Server) tlsConfig() (*tls.Config, error) {
+	if srv.tlsCertFile == "" && srv.tlsKeyFile == "" {
+		if srv.clientCAs != nil {
+			return nil, fmt.Errorf("client certificates require TLS to be enabled")
+		}
+		return nil, nil
+	}
+	reloader, err := newCertReloader(srv.tlsCertFile, srv.tlsKeyFile, srv.logger)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if srv.clientCAs != nil {
+		config.ClientCAs = srv.clientCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
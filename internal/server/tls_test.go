@@ -0,0 +1,276 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// testCert is a certificate with its private key used for testing.
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert creates a certificate with the given common name. The certificate
+// is self-signed if no parent is given.
+func newTestCert(t *testing.T, commonName string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signerCert, signerKey := template, key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %s", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %s", err)
+	}
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// write writes the certificate and key to the given files and sets their modification time.
+func (c *testCert) write(t *testing.T, certFile, keyFile string, modTime time.Time) {
+	for f, b := range map[string][]byte{certFile: c.certPEM, keyFile: c.keyPEM} {
+		if err := ioutil.WriteFile(f, b, 0600); err != nil {
+			t.Fatalf("unable to write %s: %s", f, err)
+		}
+		if err := os.Chtimes(f, modTime, modTime); err != nil {
+			t.Fatalf("unable to change times of %s: %s", f, err)
+		}
+	}
+}
+
+func (suite *Suite) TestCertReloader() {
+	t := suite.T()
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "gooser-tls")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	now := time.Now()
+	newTestCert(t, "first", nil).write(t, certFile, keyFile, now)
+	r, err := newCertReloader(certFile, keyFile, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unable to create cert reloader: %s", err)
+	}
+	commonName := func() string {
+		c, err := r.GetCertificate(nil)
+		if err != nil {
+			t.Fatalf("unable to get certificate: %s", err)
+		}
+		leaf, _ := x509.ParseCertificate(c.Certificate[0])
+		return leaf.Subject.CommonName
+	}
+	assert.Equal("first", commonName())
+	// changed files are reloaded
+	newTestCert(t, "second", nil).write(t, certFile, keyFile, now.Add(time.Minute))
+	assert.Equal("second", commonName())
+	// invalid files are ignored
+	if err := ioutil.WriteFile(certFile, []byte("invalid"), 0600); err != nil {
+		t.Fatalf("unable to write cert file: %s", err)
+	}
+	os.Chtimes(certFile, now.Add(2*time.Minute), now.Add(2*time.Minute))
+	assert.Equal("second", commonName())
+}
+
+func (suite *Suite) TestServicePrincipalFromContext() {
+	t := suite.T()
+	ca := newTestCert(t, "ca", nil)
+	tlsPeer := func(commonName string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{
+			AuthInfo: credentials.TLSInfo{
+				State: tls.ConnectionState{
+					VerifiedChains: [][]*x509.Certificate{{newTestCert(t, commonName, ca).cert, ca.cert}},
+				},
+			},
+		})
+	}
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		prepare  func(db *mocks.Store)
+		wantCode codes.Code
+		wantId   string
+	}{
+		{
+			name:     "without peer",
+			ctx:      context.Background(),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "unknown common name",
+			ctx:      tlsPeer("unknown"),
+			wantCode: codes.OK,
+		},
+		{
+			name: "service principal",
+			ctx:  tlsPeer("billing"),
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByUsername", mock.Anything, mock.Anything, "billing-service").Return(&store.User{
+					Id:       "service1",
+					Username: "billing-service",
+				}, nil).Once()
+			},
+			wantCode: codes.OK,
+			wantId:   "service1",
+		},
+		{
+			name: "service principal user missing",
+			ctx:  tlsPeer("billing"),
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByUsername", mock.Anything, mock.Anything, "billing-service").Return(
+					nil,
+					status.Errorf(codes.NotFound, "not found"),
+				).Once()
+			},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db := new(mocks.Store)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			srv, err := NewServer("secret", db, nil, nil,
+				WithLogger(zap.NewNop()),
+				WithServicePrincipal("billing", "billing-service"),
+			)
+			if err != nil {
+				t.Fatalf("unable to create server: %s", err)
+			}
+			u, err := srv.GetUserFromContext(tt.ctx)
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "statuscode mismatch")
+			db.AssertExpectations(t)
+			if tt.wantId == "" {
+				assert.Nil(u)
+				return
+			}
+			assert.Equal(tt.wantId, u.Id)
+		})
+	}
+}
+
+func (suite *Suite) TestMutualTLS() {
+	t := suite.T()
+	dir, err := ioutil.TempDir("", "gooser-tls")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	ca := newTestCert(t, "ca", nil)
+	caFile, certFile, keyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	if err := ioutil.WriteFile(caFile, ca.certPEM, 0600); err != nil {
+		t.Fatalf("unable to write ca file: %s", err)
+	}
+	newTestCert(t, "gooser", ca).write(t, certFile, keyFile, time.Now())
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	srv, err := NewServer("secret", new(mocks.Store), nil, nil,
+		WithLogger(zap.NewNop()),
+		WithListener(lis),
+		WithTLS(certFile, keyFile),
+		WithClientCA(caFile),
+	)
+	if err != nil {
+		t.Fatalf("unable to create server: %s", err)
+	}
+	go srv.Serve()
+	defer srv.Stop()
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := newTestCert(t, "billing", ca)
+	clientCert, err := tls.X509KeyPair(client.certPEM, client.keyPEM)
+	if err != nil {
+		t.Fatalf("unable to load client certificate: %s", err)
+	}
+	tests := []struct {
+		name         string
+		certificates []tls.Certificate
+		wantErr      bool
+	}{
+		{
+			name:    "without client certificate",
+			wantErr: true,
+		},
+		{
+			name:         "with client certificate",
+			certificates: []tls.Certificate{clientCert},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			conn, err := grpc.DialContext(ctx, lis.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+				RootCAs:      roots,
+				Certificates: tt.certificates,
+			})))
+			if err != nil {
+				t.Fatalf("unable to dial: %s", err)
+			}
+			defer conn.Close()
+			_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
@@ -5,17 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"time"
 
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/protoc-gen-go/generator"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	fieldmask_utils "github.com/mennanov/fieldmask-utils"
 	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
 	"github.com/rbicker/gooser/internal/store"
 	"github.com/rbicker/gooser/internal/utils"
+	"github.com/rbicker/gooser/internal/webhooks"
 	"golang.org/x/crypto/bcrypt"
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -92,14 +97,14 @@ func (srv *Server) ValidateUser(ctx context.Context, printer *message.Printer, u
 	if _, err := language.Parse(user.GetLanguage()); err != nil {
 		return status.Errorf(codes.InvalidArgument, printer.Sprintf("could not parse given language"))
 	}
-	// rsql filter string for existing users
-	filterString := fmt.Sprintf(`username=="%s"`, username)
+	// rsql filter string for existing users, compared by their lookup keys
+	filterString := fmt.Sprintf(`usernameKey=="%s"`, store.LookupKey(username))
 	if mail != "" {
 		// validate mail address
 		if !utils.IsMailAddress(mail) {
 			return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid mail address"))
 		}
-		filterString = fmt.Sprintf(`%s,mail=="%s"`, filterString, mail)
+		filterString = fmt.Sprintf(`%s,mailKey=="%s"`, filterString, store.LookupKey(mail))
 	}
 	if id != "" {
 		filterString = fmt.Sprintf(`(_id!oid="%s");(%s)`, id, filterString)
@@ -109,9 +114,10 @@ func (srv *Server) ValidateUser(ctx context.Context, printer *message.Printer, u
 		return err
 	}
 	if size > 0 {
-		return status.Errorf(codes.InvalidArgument, "username or mail address is already taken")
+		return status.Errorf(codes.AlreadyExists, printer.Sprintf("username or mail address is already taken"))
 	}
-	return nil
+	// service accounts do not need to have the required attributes
+	return srv.validateAttributes(ctx, printer, id, user.GetAttributes(), !user.GetServiceAccount())
 }
 
 // CreateUser creates the given user.
@@ -134,18 +140,28 @@ func (srv *Server) CreateUser(ctx context.Context, user *gooserv1.User) (*gooser
 	}
 	printer := message.NewPrinter(language.Make(lang))
 	user.Id = ""
+	user.DeletionScheduledAt = nil
 	if len(user.GetPassword()) < 7 {
 		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("password must have a length of at least 7"))
 	}
 	if len(user.GetRoles()) > 0 {
 		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("roles cannot be assigned to users directly, use groups instead"))
 	}
+	if user.GetServiceAccount() {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("service accounts cannot be created using the CreateUser function, use CreateServiceAccount instead"))
+	}
+	if user.GetSuspended() || user.GetSuspensionReason() != "" || user.GetSuspendedUntil() != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("users cannot be created suspended"))
+	}
 	if user.GetMail() == "" && !isAdmin {
 		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("mail address not set"))
 	}
 	if user.GetConfirmed() && !isAdmin {
 		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to set confirmed"))
 	}
+	if err := srv.checkAttributesEditable(ctx, printer, u, attributeNames(user.GetAttributes())); err != nil {
+		return nil, err
+	}
 	// validate
 	if err := srv.ValidateUser(ctx, printer, user); err != nil {
 		return nil, err
@@ -153,7 +169,7 @@ func (srv *Server) CreateUser(ctx context.Context, user *gooserv1.User) (*gooser
 	// hash password
 	hashed, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
-		srv.errorLogger.Printf("error while creating password hash: %s", err)
+		srv.log(ctx).Error("error while creating password hash", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to hash given password"))
 	}
 	user.Password = string(hashed)
@@ -163,7 +179,7 @@ func (srv *Server) CreateUser(ctx context.Context, user *gooserv1.User) (*gooser
 		if err := storeUser.GenerateConfirmToken(printer, srv.secret); err != nil {
 			return nil, err
 		}
-		if err := srv.mailer.SendConfirmToken(storeUser); err != nil {
+		if err := srv.mailer.SendConfirmToken(ctx, storeUser); err != nil {
 			return nil, err
 		}
 	}
@@ -171,6 +187,8 @@ func (srv *Server) CreateUser(ctx context.Context, user *gooserv1.User) (*gooser
 	if err != nil {
 		return nil, err
 	}
+	srv.emit(ctx, webhooks.EventUserCreated, newUser.ToPb())
+	srv.audit(ctx, u, "CreateUser", auditResourceUser, newUser.Id, auditChanges(nil, newUser.ToPb()))
 	return newUser.ToPb(), nil
 }
 
@@ -192,7 +210,9 @@ func (srv *Server) UpdateUser(ctx context.Context, req *gooserv1.UpdateUserReque
 	if id != u.Id && !u.HasRole("admin") {
 		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to edit other users"))
 	}
-	mask, err := fieldmask_utils.MaskFromProtoFieldMask(req.GetFieldMask(), generator.CamelCase)
+	// custom attributes are merged separately
+	paths, attributes, allAttributes := attributePaths(req.GetFieldMask().GetPaths())
+	mask, err := fieldmask_utils.MaskFromPaths(paths, generator.CamelCase)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "unable to create generate field mask: %s", err)
 	}
@@ -204,6 +224,20 @@ func (srv *Server) UpdateUser(ctx context.Context, req *gooserv1.UpdateUserReque
 	if _, ok := mask.Get("Password"); ok {
 		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("password cannot be changed using the UpdateUser function, use ChangePassword instead"))
 	}
+	// if service account was set
+	if _, ok := mask.Get("ServiceAccount"); ok {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("service account cannot be changed"))
+	}
+	// if the suspension was changed
+	for _, f := range []string{"Suspended", "SuspensionReason", "SuspendedUntil"} {
+		if _, ok := mask.Get(f); ok {
+			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("suspension cannot be changed using the UpdateUser function, use SuspendUser or ReactivateUser instead"))
+		}
+	}
+	// if the account deletion was changed
+	if _, ok := mask.Get("DeletionScheduledAt"); ok {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("account deletion cannot be changed using the UpdateUser function, use DeleteMyAccount or CancelMyAccountDeletion instead"))
+	}
 	// if confirmed was set and user is not admin
 	if _, ok := mask.Get("Confirmed"); ok && !u.HasRole("admin") && req.GetUser().GetConfirmed() {
 		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to set confirmed"))
@@ -213,45 +247,95 @@ func (srv *Server) UpdateUser(ctx context.Context, req *gooserv1.UpdateUserReque
 	if err != nil {
 		return nil, err
 	}
+	if existing.InvitationPending() {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("invited users cannot be updated before accepting the invitation"))
+	}
+	if etag := req.GetEtag(); etag != "" && etag != store.Etag(existing.Revision) {
+		return nil, status.Errorf(codes.Aborted, printer.Sprintf("user was modified in the meantime"))
+	}
 	user := existing.ToPb()
-	// copy request user to existing user with field mask applied
-	err = fieldmask_utils.StructToStruct(mask, req.GetUser(), user)
-	if err != nil {
-		srv.errorLogger.Printf("unable to merge users: %s", err)
-		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to merge users"))
+	// keep a copy of the existing user for the audit log
+	before := proto.Clone(user)
+	// copy request user to existing user with field mask applied,
+	// an empty mask would copy the whole user if only attributes are updated
+	if len(paths) > 0 || (len(attributes) == 0 && !allAttributes) {
+		err = fieldmask_utils.StructToStruct(mask, req.GetUser(), user)
+		if err != nil {
+			srv.log(ctx).Error("unable to merge users", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to merge users"))
+		}
+	}
+	// the etag of the request user must not bypass the revision check
+	user.Etag = store.Etag(existing.Revision)
+	changed := mergeAttributes(user, req.GetUser(), attributes, allAttributes)
+	if err := srv.checkAttributesEditable(ctx, printer, u, changed); err != nil {
+		return nil, err
 	}
 	// validate
 	if err := srv.ValidateUser(ctx, printer, user); err != nil {
 		return nil, err
 	}
-	// if mail address is about to be updated
-	if _, ok := mask.Get("Mail"); ok && !u.HasRole("admin") {
-		// if mail address was really changed
-		if existing.Mail != req.GetUser().GetMail() {
-			// if new mail is not set by a non-admin
-			if req.GetUser().GetMail() == "" && !u.HasRole("admin") {
-				return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("mail address not set"))
+	// if mail address is about to be changed by a non-admin
+	var requestedMail string
+	if _, ok := mask.Get("Mail"); ok && !u.HasRole("admin") && existing.Mail != req.GetUser().GetMail() {
+		if req.GetUser().GetMail() == "" {
+			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("mail address not set"))
+		}
+		// the new mail address is pending until it is confirmed,
+		// so a mistyped address does not lock out the user
+		requestedMail = user.Mail
+		user.Mail = existing.Mail
+	}
+	toSave := store.PbToUser(user)
+	// the tokens and the pending mail address are not part of the protobuf user
+	toSave.ConfirmToken = existing.ConfirmToken
+	toSave.PasswordResetToken = existing.PasswordResetToken
+	toSave.MailRevertToken = existing.MailRevertToken
+	toSave.PendingMail = existing.PendingMail
+	toSave.PendingMailToken = existing.PendingMailToken
+	// the previous mail address is informed about the change and can revert or cancel it
+	var previousMail string
+	switch {
+	case requestedMail != "":
+		if err := toSave.GeneratePendingMailToken(printer, srv.secret, requestedMail); err != nil {
+			return nil, err
+		}
+		if existing.Mail != "" {
+			previousMail = existing.Mail
+			if err := toSave.GenerateMailRevertToken(printer, srv.secret, existing.Mail, existing.Confirmed, time.Now().Add(srv.mailRevertTTL)); err != nil {
+				return nil, err
 			}
-			if !u.HasRole("admin") {
-				// if mail was updated by a non-admin,
-				// confirmed is reset
-				user.Confirmed = false
-				// generate confirmation
-				if err := u.GenerateConfirmToken(printer, srv.secret); err != nil {
-					return nil, err
-				}
-				if err := srv.mailer.SendConfirmToken(u); err != nil {
-					// unable to send confirmation token
-					// this should not be a terminating error
-					srv.errorLogger.Printf("unable to send confirmation token for user %s: %s", user.Username, err)
-				}
+		}
+	case existing.Mail != toSave.Mail:
+		// a mail address set by an admin replaces a pending one
+		toSave.PendingMail = ""
+		toSave.PendingMailToken = ""
+		if existing.Mail != "" {
+			previousMail = existing.Mail
+			if err := toSave.GenerateMailRevertToken(printer, srv.secret, existing.Mail, existing.Confirmed, time.Now().Add(srv.mailRevertTTL)); err != nil {
+				return nil, err
 			}
 		}
 	}
-	updated, err := srv.store.SaveUser(ctx, printer, store.PbToUser(user))
+	updated, err := srv.store.SaveUser(ctx, printer, toSave)
 	if err != nil {
 		return nil, err
 	}
+	if requestedMail != "" {
+		if err := srv.mailer.SendMailChangeToken(ctx, updated); err != nil {
+			// unable to send confirmation token
+			// this should not be a terminating error
+			srv.log(ctx).Error("unable to send mail change confirmation", zap.String("userId", updated.Id), zap.Error(err))
+		}
+	}
+	if previousMail != "" {
+		if err := srv.mailer.SendMailChanged(ctx, updated, previousMail); err != nil {
+			// the mail address was changed anyway
+			srv.log(ctx).Error("unable to send mail changed notification", zap.String("userId", updated.Id), zap.Error(err))
+		}
+	}
+	srv.emit(ctx, webhooks.EventUserUpdated, updated.ToPb())
+	srv.audit(ctx, u, "UpdateUser", auditResourceUser, updated.Id, auditChanges(before, updated.ToPb()))
 	return updated.ToPb(), nil
 }
 
@@ -272,18 +356,112 @@ func (srv *Server) DeleteUser(ctx context.Context, req *gooserv1.IdRequest) (*em
 	if id == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "empty id given")
 	}
+	existing, err := srv.store.GetUser(ctx, printer, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := srv.deleteUser(ctx, printer, u, existing); err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+// deleteUser removes the given user from its groups, deletes its api keys and
+// finally the user itself. The memberships are remembered to be able to restore the user.
+func (srv *Server) deleteUser(ctx context.Context, printer *message.Printer, actor, existing *store.User) error {
+	id := existing.Id
 	filter := fmt.Sprintf(`members=="%s"`, id)
 	groups, _, _, err := srv.store.ListGroups(ctx, printer, filter, "", "", -1)
+	if err != nil {
+		return err
+	}
+	var groupIds []string
 	for _, g := range *groups {
 		g.Members = utils.RemoveFromStringSlice(g.Members, id)
 		_, err := srv.store.SaveGroup(ctx, printer, &g)
 		if err != nil {
-			srv.errorLogger.Printf("unable to remove user with id %s from group %s with id %s: %s", id, g.Name, g.Id, err)
-			return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to remove user from group %s", g.Name))
+			srv.log(ctx).Error("unable to remove user from group", zap.String("userId", id), zap.String("groupId", g.Id), zap.Error(err))
+			return status.Errorf(codes.Internal, printer.Sprintf("unable to remove user from group %s", g.Name))
+		}
+		groupIds = append(groupIds, g.Id)
+		srv.emit(ctx, webhooks.EventGroupMemberRemoved, &webhooks.Membership{GroupId: g.Id, GroupName: g.Name, UserId: id})
+	}
+	// remember the memberships in case the user gets restored
+	if len(groupIds) > 0 {
+		existing.DeletedFromGroups = groupIds
+		if _, err := srv.store.SaveUser(ctx, printer, existing); err != nil {
+			return err
+		}
+	}
+	// api keys of service accounts must not outlive them
+	if srv.apiKeyStore != nil {
+		if err := srv.apiKeyStore.DeleteApiKeysOfServiceAccount(ctx, printer, id); err != nil {
+			return err
+		}
+	}
+	if err := srv.store.DeleteUser(ctx, printer, id); err != nil {
+		return err
+	}
+	srv.emit(ctx, webhooks.EventUserDeleted, &gooserv1.User{Id: id})
+	srv.audit(ctx, actor, "DeleteUser", auditResourceUser, id, nil)
+	return nil
+}
+
+// RestoreUser restores the soft deleted user with the given id.
+// The user is added to the groups it was member of again
+// and gets their roles back. Api keys are not restored.
+func (srv *Server) RestoreUser(ctx context.Context, req *gooserv1.IdRequest) (*gooserv1.User, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if !u.HasRole("admin") {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to restore users"))
+	}
+	id := req.GetId()
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("empty id given"))
+	}
+	restored, err := srv.store.RestoreUser(ctx, printer, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, groupId := range restored.DeletedFromGroups {
+		g, err := srv.store.GetGroup(ctx, printer, groupId)
+		if code, _ := status.FromError(err); code.Code() == codes.NotFound {
+			// the group has been deleted in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var added bool
+		g.Members, added = utils.AppendUniqueString(g.Members, id)
+		if added {
+			if _, err := srv.store.SaveGroup(ctx, printer, g); err != nil {
+				srv.log(ctx).Error("unable to add user to group", zap.String("userId", id), zap.String("groupId", g.Id), zap.Error(err))
+				return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to add user to group %s", g.Name))
+			}
+			srv.emit(ctx, webhooks.EventGroupMemberAdded, &webhooks.Membership{GroupId: g.Id, GroupName: g.Name, UserId: id})
+		}
+		for _, r := range g.Roles {
+			restored.Roles, _ = utils.AppendUniqueString(restored.Roles, r)
 		}
 	}
-	err = srv.store.DeleteUser(ctx, printer, id)
-	return &empty.Empty{}, err
+	restored.DeletedFromGroups = nil
+	// a restored account must not be deleted again by a former request of the user
+	restored.DeletionScheduledAt = time.Time{}
+	restored, err = srv.store.SaveUser(ctx, printer, restored)
+	if err != nil {
+		return nil, err
+	}
+	srv.emit(ctx, webhooks.EventUserRestored, restored.ToPb())
+	srv.audit(ctx, u, "RestoreUser", auditResourceUser, restored.Id, auditChanges(nil, restored.ToPb()))
+	return restored.ToPb(), nil
 }
 
 // ChangePassword can be used to change own password. The old and the new password need to be provided.
@@ -298,6 +476,7 @@ func (srv *Server) ChangePassword(ctx context.Context, req *gooserv1.ChangePassw
 		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
 	}
 	printer := message.NewPrinter(language.Make(u.Language))
+	actor := u
 	isAdmin := u.HasRole("admin")
 	newPassword := req.GetNewPassword()
 	if len(newPassword) < 7 {
@@ -324,23 +503,34 @@ func (srv *Server) ChangePassword(ctx context.Context, req *gooserv1.ChangePassw
 		}
 	}
 	// hash password
-	hashed, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
-		srv.errorLogger.Printf("error while creating password hash: %s", err)
+		srv.log(ctx).Error("error while creating password hash", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to hash given password"))
 	}
 	u.Password = string(hashed)
-	_, err = srv.store.SaveUser(ctx, printer, u)
+	changed, err := srv.store.SaveUser(ctx, printer, u)
 	if err != nil {
 		return nil, err
 	}
+	if err := srv.mailer.SendPasswordChanged(ctx, changed); err != nil {
+		// the password was changed anyway
+		srv.log(ctx).Error("unable to send password changed notification", zap.String("userId", changed.Id), zap.Error(err))
+	}
+	srv.audit(ctx, actor, "ChangePassword", auditResourceUser, u.Id, []store.AuditChange{
+		{Field: "password", OldValue: redacted, NewValue: redacted},
+	})
 	return &empty.Empty{}, nil
 }
 
 // ConfirmMail tries to confirm the given mail address.
+// If the token confirms a pending mail address, the mail address of the user is replaced by it.
 func (srv *Server) ConfirmMail(ctx context.Context, req *gooserv1.ConfirmMailRequest) (*empty.Empty, error) {
 	printer := message.NewPrinter(language.Make(utils.LookupEnv("GOOSER_DEFAULT_LANGUAGE", "en")))
 	user, err := srv.store.GetUserByConfirmToken(ctx, printer, req.GetToken())
+	if status.Code(err) == codes.NotFound {
+		return srv.confirmPendingMail(ctx, printer, req.GetToken())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -350,10 +540,43 @@ func (srv *Server) ConfirmMail(ctx context.Context, req *gooserv1.ConfirmMailReq
 	}
 	user.Confirmed = true
 	user.ConfirmToken = ""
-	_, err = srv.store.SaveUser(ctx, printer, user)
+	confirmed, err := srv.store.SaveUser(ctx, printer, user)
+	if err != nil {
+		return nil, err
+	}
+	srv.emit(ctx, webhooks.EventUserConfirmed, confirmed.ToPb())
+	srv.audit(ctx, confirmed, "ConfirmMail", auditResourceUser, confirmed.Id, []store.AuditChange{
+		{Field: "confirmed", OldValue: "false", NewValue: "true"},
+	})
+	return &empty.Empty{}, nil
+}
+
+// confirmPendingMail replaces the mail address of the user matching with the given token
+// by the pending mail address. The previous address was informed when the change was requested,
+// the mail revert token sent to it stays valid to revert the change.
+func (srv *Server) confirmPendingMail(ctx context.Context, printer *message.Printer, token string) (*empty.Empty, error) {
+	user, err := srv.store.GetUserByPendingMailToken(ctx, printer, token)
+	if err != nil {
+		return nil, err
+	}
+	printer = message.NewPrinter(language.Make(user.Language))
+	if err := user.ValidatePendingMailToken(printer, srv.secret, token); err != nil {
+		return nil, err
+	}
+	before := user.ToPb()
+	user.Mail = user.PendingMail
+	user.Confirmed = true
+	user.PendingMail = ""
+	user.PendingMailToken = ""
+	// a pending confirmation of the previous address must not be completed anymore
+	user.ConfirmToken = ""
+	// fails if the mail address was taken in the meantime
+	confirmed, err := srv.store.SaveUser(ctx, printer, user)
 	if err != nil {
 		return nil, err
 	}
+	srv.emit(ctx, webhooks.EventUserUpdated, confirmed.ToPb())
+	srv.audit(ctx, confirmed, "ConfirmMail", auditResourceUser, confirmed.Id, auditChanges(before, confirmed.ToPb()))
 	return &empty.Empty{}, nil
 }
 
@@ -375,13 +598,25 @@ func (srv *Server) ForgotPassword(ctx context.Context, req *gooserv1.ForgotPassw
 	if user.Mail == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "user does not have a mail address")
 	}
+	if user.ServiceAccount {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("service accounts do not have a password"))
+	}
+	if user.IsSuspended(time.Now()) {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("user is suspended"))
+	}
+	if user.InvitationPending() {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("the invitation has not been accepted yet"))
+	}
 	user.GeneratePasswordResetToken(printer, srv.secret)
 	if _, err := srv.store.SaveUser(ctx, printer, user); err != nil {
 		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to save user"))
 	}
-	if err := srv.mailer.SendPasswordResetToken(user); err != nil {
+	if err := srv.mailer.SendPasswordResetToken(ctx, user); err != nil {
 		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to send reset password mail"))
 	}
+	srv.audit(ctx, nil, "ForgotPassword", auditResourceUser, user.Id, []store.AuditChange{
+		{Field: "password_reset_token", NewValue: redacted},
+	})
 	return &empty.Empty{}, nil
 }
 
@@ -397,6 +632,9 @@ func (srv *Server) ResetPassword(ctx context.Context, req *gooserv1.ResetPasswor
 		return nil, err
 	}
 	printer = message.NewPrinter(language.Make(user.Language))
+	if user.IsSuspended(time.Now()) {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("user is suspended"))
+	}
 	if len(password) < 7 {
 		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("password must have a length of at least 7"))
 	}
@@ -411,13 +649,159 @@ func (srv *Server) ResetPassword(ctx context.Context, req *gooserv1.ResetPasswor
 	}
 	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		srv.errorLogger.Printf("error while creating password hash: %s", err)
+		srv.log(ctx).Error("error while creating password hash", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to hash given password"))
 	}
 	user.PasswordResetToken = ""
 	user.Password = string(hashed)
-	if _, err := srv.store.SaveUser(ctx, printer, user); err != nil {
+	reset, err := srv.store.SaveUser(ctx, printer, user)
+	if err != nil {
 		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to save user"))
 	}
+	if err := srv.mailer.SendPasswordChanged(ctx, reset); err != nil {
+		// the password was reset anyway
+		srv.log(ctx).Error("unable to send password changed notification", zap.String("userId", reset.Id), zap.Error(err))
+	}
+	srv.audit(ctx, user, "ResetPassword", auditResourceUser, user.Id, []store.AuditChange{
+		{Field: "password", OldValue: redacted, NewValue: redacted},
+		{Field: "password_reset_token", OldValue: redacted},
+	})
 	return &empty.Empty{}, nil
 }
+
+// RevertMailChange restores the previous mail address of the user matching with the given token,
+// which was sent to the previous address when it was changed. A change which has not been
+// confirmed yet is cancelled.
+func (srv *Server) RevertMailChange(ctx context.Context, req *gooserv1.RevertMailChangeRequest) (*empty.Empty, error) {
+	printer := message.NewPrinter(language.Make(utils.LookupEnv("GOOSER_DEFAULT_LANGUAGE", "en")))
+	token := req.GetToken()
+	if token == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("no token given"))
+	}
+	user, err := srv.store.GetUserByMailRevertToken(ctx, printer, token)
+	if err != nil {
+		return nil, err
+	}
+	printer = message.NewPrinter(language.Make(user.Language))
+	r, err := user.ValidateMailRevertToken(printer, srv.secret, token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if user.PendingMail != "" && user.PendingMail == r.Mail {
+		pendingMail := user.PendingMail
+		user.PendingMail = ""
+		user.PendingMailToken = ""
+		user.MailRevertToken = ""
+		cancelled, err := srv.store.SaveUser(ctx, printer, user)
+		if err != nil {
+			return nil, err
+		}
+		srv.emit(ctx, webhooks.EventUserUpdated, cancelled.ToPb())
+		srv.audit(ctx, cancelled, "RevertMailChange", auditResourceUser, cancelled.Id, []store.AuditChange{
+			{Field: "pending_mail", OldValue: pendingMail},
+			{Field: "mail_revert_token", OldValue: redacted},
+		})
+		return &empty.Empty{}, nil
+	}
+	changedMail := user.Mail
+	user.Mail = r.PreviousMail
+	user.Confirmed = r.PreviousConfirmed
+	// a pending confirmation of the changed address must not be completed anymore
+	user.ConfirmToken = ""
+	user.MailRevertToken = ""
+	reverted, err := srv.store.SaveUser(ctx, printer, user)
+	if err != nil {
+		return nil, err
+	}
+	srv.emit(ctx, webhooks.EventUserUpdated, reverted.ToPb())
+	srv.audit(ctx, reverted, "RevertMailChange", auditResourceUser, reverted.Id, []store.AuditChange{
+		{Field: "mail", OldValue: changedMail, NewValue: reverted.Mail},
+		{Field: "mail_revert_token", OldValue: redacted},
+	})
+	return &empty.Empty{}, nil
+}
+
+// SuspendUser suspends the user with the given id until the given time or, without end date,
+// until the user is reactivated. In contrast to deleting, the user and its group memberships are kept.
+func (srv *Server) SuspendUser(ctx context.Context, req *gooserv1.SuspendUserRequest) (*gooserv1.User, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if !u.HasRole("admin") {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to suspend users"))
+	}
+	id := req.GetId()
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("empty id given"))
+	}
+	if id == u.Id {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("not allowed to suspend yourself"))
+	}
+	if req.GetReason() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("reason not set"))
+	}
+	var until time.Time
+	if req.GetUntil() != nil {
+		until, err = ptypes.Timestamp(req.GetUntil())
+		if err != nil || !until.After(time.Now()) {
+			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("end of suspension must be in the future"))
+		}
+	}
+	existing, err := srv.store.GetUser(ctx, printer, id)
+	if err != nil {
+		return nil, err
+	}
+	before := existing.ToPb()
+	existing.Suspended = true
+	existing.SuspensionReason = req.GetReason()
+	existing.SuspendedUntil = until
+	suspended, err := srv.store.SaveUser(ctx, printer, existing)
+	if err != nil {
+		return nil, err
+	}
+	srv.emit(ctx, webhooks.EventUserSuspended, suspended.ToPb())
+	srv.audit(ctx, u, "SuspendUser", auditResourceUser, suspended.Id, auditChanges(before, suspended.ToPb()))
+	return suspended.ToPb(), nil
+}
+
+// ReactivateUser ends the suspension of the user with the given id.
+func (srv *Server) ReactivateUser(ctx context.Context, req *gooserv1.IdRequest) (*gooserv1.User, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if !u.HasRole("admin") {
+		return nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to reactivate users"))
+	}
+	id := req.GetId()
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("empty id given"))
+	}
+	existing, err := srv.store.GetUser(ctx, printer, id)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.Suspended {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("user is not suspended"))
+	}
+	before := existing.ToPb()
+	existing.Suspended = false
+	existing.SuspensionReason = ""
+	existing.SuspendedUntil = time.Time{}
+	reactivated, err := srv.store.SaveUser(ctx, printer, existing)
+	if err != nil {
+		return nil, err
+	}
+	srv.emit(ctx, webhooks.EventUserReactivated, reactivated.ToPb())
+	srv.audit(ctx, u, "ReactivateUser", auditResourceUser, reactivated.Id, auditChanges(before, reactivated.ToPb()))
+	return reactivated.ToPb(), nil
+}
@@ -3,10 +3,12 @@ package server
 import (
 	"context"
 	"testing"
+	"time"
 
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
+	"github.com/golang/protobuf/ptypes"
 	"google.golang.org/genproto/protobuf/field_mask"
 
 	"github.com/rbicker/gooser/internal/mocks"
@@ -16,6 +18,7 @@ import (
 
 	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -296,12 +299,47 @@ func (suite *Suite) TestCreateUser() {
 				Password: "password1234",
 			},
 			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
-				db.On("CountUsers", mock.Anything, mock.Anything, `username=="user1",mail=="new@testing.com"`).Return(
+				db.On("CountUsers", mock.Anything, mock.Anything, `usernameKey=="user1",mailKey=="new@testing.com"`).Return(
 					int32(1),
 					nil,
 				).Once()
 			},
-			wantCode: codes.InvalidArgument,
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name: "duplicate mail differing in case",
+			req: &gooserv1.User{
+				Username: "new",
+				Mail:     "User1@Testing.com",
+				Password: "password1234",
+			},
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("CountUsers", mock.Anything, mock.Anything, `usernameKey=="new",mailKey=="user1@testing.com"`).Return(
+					int32(1),
+					nil,
+				).Once()
+			},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name: "taken in the meantime",
+			req: &gooserv1.User{
+				Username: "new",
+				Mail:     "new@testing.com",
+				Password: "password1234",
+			},
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("CountUsers", mock.Anything, mock.Anything, `usernameKey=="new",mailKey=="new@testing.com"`).Return(
+					int32(0),
+					nil,
+				).Once()
+				mailer.On("SendConfirmToken", mock.Anything, mock.Anything).Return(nil).Maybe()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+					nil,
+					status.Errorf(codes.AlreadyExists, "username or mail address is already taken"),
+				).Once()
+			},
+			wantCode: codes.AlreadyExists,
 		},
 		{
 			name: "valid user",
@@ -311,7 +349,7 @@ func (suite *Suite) TestCreateUser() {
 				Password: "password1234",
 			},
 			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
-				db.On("CountUsers", mock.Anything, mock.Anything, `username=="new",mail=="new@testing.com"`).Return(
+				db.On("CountUsers", mock.Anything, mock.Anything, `usernameKey=="new",mailKey=="new@testing.com"`).Return(
 					int32(0),
 					nil,
 				).Once()
@@ -321,7 +359,7 @@ func (suite *Suite) TestCreateUser() {
 					},
 					nil,
 				).Once()
-				mailer.On("SendConfirmToken", mock.Anything).Return(nil).Once()
+				mailer.On("SendConfirmToken", mock.Anything, mock.Anything).Return(nil).Once()
 			},
 			wantCode:      codes.OK,
 			wantId:        "", // should not be set by CreateUser function
@@ -338,7 +376,7 @@ func (suite *Suite) TestCreateUser() {
 				Confirmed: true,
 			},
 			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
-				db.On("CountUsers", mock.Anything, mock.Anything, `username=="new",mail=="new@testing.com"`).Return(
+				db.On("CountUsers", mock.Anything, mock.Anything, `usernameKey=="new",mailKey=="new@testing.com"`).Return(
 					int32(0),
 					nil,
 				).Once()
@@ -400,15 +438,16 @@ func (suite *Suite) TestUpdateUser() {
 	client := gooserv1.NewGooserClient(conn)
 	// tests
 	tests := []struct {
-		name          string
-		prepare       func(db *mocks.Store, mailer *mocks.Messenger)
-		accessToken   string
-		req           *gooserv1.UpdateUserRequest
-		wantCode      codes.Code
-		wantId        string
-		wantUsername  string
-		wantMail      string
-		wantConfirmed bool
+		name            string
+		prepare         func(db *mocks.Store, mailer *mocks.Messenger)
+		accessToken     string
+		req             *gooserv1.UpdateUserRequest
+		wantCode        codes.Code
+		wantId          string
+		wantUsername    string
+		wantMail        string
+		wantPendingMail string
+		wantConfirmed   bool
 	}{
 		{
 			name: "unauthenticated",
@@ -491,23 +530,33 @@ func (suite *Suite) TestUpdateUser() {
 						}
 					},
 					nil).Once()
-				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(username=="new",mail=="new@testing.com")`).Return(
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(usernameKey=="new",mailKey=="new@testing.com")`).Return(
 					int32(0),
 					nil,
 				).Once()
-				db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+				// the new mail address is pending and the previous one can cancel the change
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(user *store.User) bool {
+					return user.Mail == "user1@testing.com" && user.PendingMail == "new@testing.com" &&
+						user.PendingMailToken != "" && user.MailRevertToken != ""
+				})).Return(
 					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
 						return user
 					},
 					nil,
 				).Once()
-				mailer.On("SendConfirmToken", mock.Anything).Return(nil).Once()
+				mailer.On("SendMailChangeToken", mock.Anything, mock.MatchedBy(func(user *store.User) bool {
+					return user.PendingMail == "new@testing.com"
+				})).Return(nil).Once()
+				mailer.On("SendMailChanged", mock.Anything, mock.MatchedBy(func(user *store.User) bool {
+					return user.PendingMail == "new@testing.com"
+				}), "user1@testing.com").Return(nil).Once()
 			},
-			wantCode:      codes.OK,
-			wantUsername:  "new",
-			wantId:        "user1",
-			wantConfirmed: false, // changing mail address should reset confirmed
-			wantMail:      "new@testing.com",
+			wantCode:        codes.OK,
+			wantUsername:    "new",
+			wantId:          "user1",
+			wantConfirmed:   true, // the mail address is not changed before it is confirmed
+			wantMail:        "user1@testing.com",
+			wantPendingMail: "new@testing.com",
 		},
 		{
 			name:        "changing other user as admin",
@@ -526,25 +575,31 @@ func (suite *Suite) TestUpdateUser() {
 				db.On("GetUser", mock.Anything, mock.Anything, mock.Anything).Return(
 					func(ctx context.Context, printer *message.Printer, id string) *store.User {
 						return &store.User{
-							Id:        "user1",
-							Username:  "user1",
-							Mail:      "user1@testing.com",
-							Language:  "en",
-							Roles:     []string{"tester"},
-							Confirmed: true,
+							Id:               "user1",
+							Username:         "user1",
+							Mail:             "user1@testing.com",
+							Language:         "en",
+							Roles:            []string{"tester"},
+							Confirmed:        true,
+							PendingMail:      "typo@testing.com",
+							PendingMailToken: "pending",
 						}
 					},
 					nil).Once()
-				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(username=="new",mail=="new@testing.com")`).Return(
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(usernameKey=="new",mailKey=="new@testing.com")`).Return(
 					int32(0),
 					nil,
 				).Once()
-				db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+				// the mail address set by the admin replaces the pending one
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(user *store.User) bool {
+					return user.Mail == "new@testing.com" && user.PendingMail == "" && user.PendingMailToken == ""
+				})).Return(
 					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
 						return user
 					},
 					nil,
 				).Once()
+				mailer.On("SendMailChanged", mock.Anything, mock.Anything, "user1@testing.com").Return(nil).Once()
 			},
 			wantCode:      codes.OK,
 			wantUsername:  "new",
@@ -595,7 +650,7 @@ func (suite *Suite) TestUpdateUser() {
 						}
 					},
 					nil).Once()
-				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(username=="user1")`).Return(
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(usernameKey=="user1")`).Return(
 					int32(0),
 					nil,
 				).Once()
@@ -605,6 +660,7 @@ func (suite *Suite) TestUpdateUser() {
 					},
 					nil,
 				).Once()
+				mailer.On("SendMailChanged", mock.Anything, mock.Anything, "user1@testing.com").Return(nil).Once()
 			},
 			req: &gooserv1.UpdateUserRequest{
 				User: &gooserv1.User{
@@ -637,7 +693,7 @@ func (suite *Suite) TestUpdateUser() {
 						}
 					},
 					nil).Once()
-				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(username=="user1",mail=="user2@testing.com")`).Return(
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(usernameKey=="user1",mailKey=="user2@testing.com")`).Return(
 					int32(1),
 					nil,
 				).Once()
@@ -651,7 +707,78 @@ func (suite *Suite) TestUpdateUser() {
 					Paths: []string{"mail"},
 				},
 			},
-			wantCode: codes.InvalidArgument,
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:        "etag mismatch",
+			accessToken: "user1",
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(&store.User{
+					Id:       "user1",
+					Username: "user1",
+					Mail:     "user1@testing.com",
+					Language: "en",
+					Revision: 7,
+				}, nil).Once()
+			},
+			req: &gooserv1.UpdateUserRequest{
+				User: &gooserv1.User{
+					Id:       "user1",
+					Language: "de",
+				},
+				FieldMask: &field_mask.FieldMask{
+					Paths: []string{"language"},
+				},
+				Etag: store.Etag(6),
+			},
+			wantCode: codes.Aborted,
+		},
+		{
+			name:        "etag match",
+			accessToken: "user1",
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(&store.User{
+					Id:               "user1",
+					Username:         "user1",
+					Mail:             "user1@testing.com",
+					Language:         "en",
+					ConfirmToken:     "confirm",
+					MailRevertToken:  "revert",
+					PendingMail:      "new@testing.com",
+					PendingMailToken: "pending",
+					Revision:         7,
+				}, nil).Once()
+				db.On("CountUsers", mock.Anything, mock.Anything, `(_id!oid="user1");(usernameKey=="user1",mailKey=="user1@testing.com")`).Return(
+					int32(0),
+					nil,
+				).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(user *store.User) bool {
+					// tokens and the pending mail address are kept
+					return user.Revision == 7 && user.Language == "de" && user.ConfirmToken == "confirm" &&
+						user.MailRevertToken == "revert" && user.PendingMail == "new@testing.com" && user.PendingMailToken == "pending"
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						user.Revision++
+						return user
+					},
+					nil,
+				).Once()
+			},
+			req: &gooserv1.UpdateUserRequest{
+				User: &gooserv1.User{
+					Id:       "user1",
+					Language: "de",
+				},
+				FieldMask: &field_mask.FieldMask{
+					Paths: []string{"language"},
+				},
+				Etag: store.Etag(7),
+			},
+			wantCode:        codes.OK,
+			wantId:          "user1",
+			wantUsername:    "user1",
+			wantMail:        "user1@testing.com",
+			wantPendingMail: "new@testing.com",
 		},
 	}
 	for _, tt := range tests {
@@ -676,6 +803,7 @@ func (suite *Suite) TestUpdateUser() {
 			code, _ := status.FromError(err)
 			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
 			db.AssertExpectations(t)
+			mailer.AssertExpectations(t)
 			if code.Code() != codes.OK {
 				// if status code is not ok, response should be nil
 				assert.Nil(res)
@@ -685,6 +813,7 @@ func (suite *Suite) TestUpdateUser() {
 			assert.Equal(tt.wantId, res.Id, "id mismatch")
 			assert.Equal(tt.wantUsername, res.Username, "username mismatch")
 			assert.Equal(tt.wantMail, res.Mail, "mail mismatch")
+			assert.Equal(tt.wantPendingMail, res.PendingMail, "pending mail mismatch")
 			assert.Equal(tt.wantConfirmed, res.Confirmed, "confirmed mismatch")
 		})
 	}
@@ -734,6 +863,13 @@ func (suite *Suite) TestDeleteUser() {
 				Id: "user1",
 			},
 			prepare: func(db *mocks.Store) {
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(
+					&store.User{
+						Id:       "user1",
+						Username: "user1",
+					},
+					nil,
+				).Once()
 				db.On("ListGroups", mock.Anything, mock.Anything, `members=="user1"`, mock.Anything, mock.Anything, mock.Anything).Return(
 					&[]store.Group{
 						{
@@ -755,6 +891,15 @@ func (suite *Suite) TestDeleteUser() {
 					},
 					nil,
 				).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(
+					func(user *store.User) bool {
+						return user.Id == "user1" && len(user.DeletedFromGroups) == 1 && user.DeletedFromGroups[0] == "testers"
+					})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
 				db.On("DeleteUser", mock.Anything, mock.Anything, "user1").Return(
 					nil,
 				).Once()
@@ -803,7 +948,7 @@ func (suite *Suite) TestChangePassword() {
 	// tests
 	tests := []struct {
 		name        string
-		prepare     func(db *mocks.Store)
+		prepare     func(db *mocks.Store, mailer *mocks.Messenger)
 		accessToken string
 		req         *gooserv1.ChangePasswordRequest
 		wantCode    codes.Code
@@ -831,13 +976,18 @@ func (suite *Suite) TestChangePassword() {
 		{
 			name:        "valid request",
 			accessToken: "user1",
-			prepare: func(db *mocks.Store) {
-				db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(user *store.User) bool {
+					return user.ValidatePassword("newPassword")
+				})).Return(
 					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
 						return user
 					},
 					nil,
 				).Once()
+				mailer.On("SendPasswordChanged", mock.Anything, mock.MatchedBy(func(user *store.User) bool {
+					return user.Id == "user1"
+				})).Return(nil).Once()
 			},
 			req: &gooserv1.ChangePasswordRequest{
 				Id:          "user1",
@@ -849,7 +999,7 @@ func (suite *Suite) TestChangePassword() {
 		{
 			name:        "reset password as admin",
 			accessToken: "admin",
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
 				db.On("GetUser", mock.Anything, mock.Anything, mock.Anything).Return(
 					func(ctx context.Context, printer *message.Printer, id string) *store.User {
 						return &store.User{
@@ -867,6 +1017,8 @@ func (suite *Suite) TestChangePassword() {
 					},
 					nil,
 				).Once()
+				// failing notifications do not fail the request
+				mailer.On("SendPasswordChanged", mock.Anything, mock.Anything).Return(status.Error(codes.Internal, "unable to send")).Once()
 			},
 			req: &gooserv1.ChangePasswordRequest{
 				Id:          "user1",
@@ -881,10 +1033,12 @@ func (suite *Suite) TestChangePassword() {
 			assert := assert.New(t)
 			// prepare mock
 			db := new(mocks.Store)
+			mailer := new(mocks.Messenger)
 			if tt.prepare != nil {
-				tt.prepare(db)
+				tt.prepare(db, mailer)
 			}
 			suite.srv.store = db
+			suite.srv.mailer = mailer
 			// prepare context with access token
 			ctx := context.Background()
 			if tt.accessToken != "" {
@@ -896,6 +1050,7 @@ func (suite *Suite) TestChangePassword() {
 			code, _ := status.FromError(err)
 			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
 			db.AssertExpectations(t)
+			mailer.AssertExpectations(t)
 			if code.Code() != codes.OK {
 				// if status code is not ok, response should be nil
 				assert.Nil(res)
@@ -921,20 +1076,30 @@ func (suite *Suite) TestConfirmMail() {
 	if err := user.GenerateConfirmToken(printer, suite.srv.secret); err != nil {
 		t.Fatalf("unable to generate confirm token: %s", err)
 	}
+	pending := store.User{
+		Mail: "user1@testing.com",
+	}
+	if err := pending.GeneratePendingMailToken(printer, suite.srv.secret, "new@testing.com"); err != nil {
+		t.Fatalf("unable to generate pending mail token: %s", err)
+	}
 	// tests
 	tests := []struct {
 		name     string
-		prepare  func(db *mocks.Store)
+		prepare  func(db *mocks.Store, mailer *mocks.Messenger)
 		req      *gooserv1.ConfirmMailRequest
 		wantCode codes.Code
 	}{
 		{
 			name: "not found",
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
 				db.On("GetUserByConfirmToken", mock.Anything, mock.Anything, mock.Anything).Return(
 					nil,
 					status.Errorf(codes.NotFound, "user not found"),
 				)
+				db.On("GetUserByPendingMailToken", mock.Anything, mock.Anything, mock.Anything).Return(
+					nil,
+					status.Errorf(codes.NotFound, "user not found"),
+				)
 			},
 			req: &gooserv1.ConfirmMailRequest{
 				Token: user.ConfirmToken,
@@ -943,7 +1108,7 @@ func (suite *Suite) TestConfirmMail() {
 		},
 		{
 			name: "valid request",
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
 				db.On("GetUserByConfirmToken", mock.Anything, mock.Anything, mock.Anything).Return(
 					func(ctx context.Context, printer *message.Printer, token string) *store.User {
 						u := &store.User{
@@ -974,7 +1139,7 @@ func (suite *Suite) TestConfirmMail() {
 		},
 		{
 			name: "mail address changed",
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
 				db.On("GetUserByConfirmToken", mock.Anything, mock.Anything, mock.Anything).Return(
 					func(ctx context.Context, printer *message.Printer, token string) *store.User {
 						u := &store.User{
@@ -995,22 +1160,84 @@ func (suite *Suite) TestConfirmMail() {
 			},
 			wantCode: codes.InvalidArgument,
 		},
+		{
+			name: "pending mail address",
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("GetUserByConfirmToken", mock.Anything, mock.Anything, pending.PendingMailToken).Return(
+					nil,
+					status.Errorf(codes.NotFound, "user not found"),
+				)
+				db.On("GetUserByPendingMailToken", mock.Anything, mock.Anything, pending.PendingMailToken).Return(
+					&store.User{
+						Id:               "user1",
+						Username:         "user1",
+						Mail:             "user1@testing.com",
+						Confirmed:        false,
+						ConfirmToken:     user.ConfirmToken,
+						MailRevertToken:  "revert",
+						PendingMail:      pending.PendingMail,
+						PendingMailToken: pending.PendingMailToken,
+					},
+					nil,
+				)
+				// the pending mail address replaces the previous one, the revert token
+				// sent to the previous address when the change was requested is kept
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(user *store.User) bool {
+					return user.Mail == "new@testing.com" && user.Confirmed && user.ConfirmToken == "" &&
+						user.PendingMail == "" && user.PendingMailToken == "" && user.MailRevertToken == "revert"
+				})).Return(func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+					return user
+				},
+					nil,
+				)
+			},
+			req: &gooserv1.ConfirmMailRequest{
+				Token: pending.PendingMailToken,
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "pending mail address changed",
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("GetUserByConfirmToken", mock.Anything, mock.Anything, pending.PendingMailToken).Return(
+					nil,
+					status.Errorf(codes.NotFound, "user not found"),
+				)
+				db.On("GetUserByPendingMailToken", mock.Anything, mock.Anything, pending.PendingMailToken).Return(
+					&store.User{
+						Id:               "user1",
+						Username:         "user1",
+						Mail:             "user1@testing.com",
+						PendingMail:      "other@testing.com",
+						PendingMailToken: pending.PendingMailToken,
+					},
+					nil,
+				)
+			},
+			req: &gooserv1.ConfirmMailRequest{
+				Token: pending.PendingMailToken,
+			},
+			wantCode: codes.InvalidArgument,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			assert := assert.New(t)
 			// prepare mock
 			db := new(mocks.Store)
+			mailer := new(mocks.Messenger)
 			if tt.prepare != nil {
-				tt.prepare(db)
+				tt.prepare(db, mailer)
 			}
 			suite.srv.store = db
+			suite.srv.mailer = mailer
 			// run function
 			res, err := client.ConfirmMail(context.Background(), tt.req)
 			// check status code
 			code, _ := status.FromError(err)
 			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
 			db.AssertExpectations(t)
+			mailer.AssertExpectations(t)
 			if code.Code() != codes.OK {
 				// if status code is not ok, response should be nil
 				assert.Nil(res)
@@ -1049,6 +1276,24 @@ func (suite *Suite) TestForgotPassword() {
 			},
 			wantCode: codes.NotFound,
 		},
+		{
+			name: "suspended user",
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
+				db.On("GetUserByUsername", mock.Anything, mock.Anything, "user1").Return(
+					&store.User{
+						Username:         "user1",
+						Mail:             "user1@testing.com",
+						Suspended:        true,
+						SuspensionReason: "spam",
+					},
+					nil,
+				).Once()
+			},
+			req: &gooserv1.ForgotPasswordRequest{
+				Username: "user1",
+			},
+			wantCode: codes.PermissionDenied,
+		},
 		{
 			name: "by username",
 			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
@@ -1065,7 +1310,7 @@ func (suite *Suite) TestForgotPassword() {
 					},
 					nil,
 				).Once()
-				mailer.On("SendPasswordResetToken", mock.Anything).Return(nil).Once()
+				mailer.On("SendPasswordResetToken", mock.Anything, mock.Anything).Return(nil).Once()
 			},
 			req: &gooserv1.ForgotPasswordRequest{
 				Username: "user1",
@@ -1120,7 +1365,7 @@ func (suite *Suite) TestResetPassword() {
 	// tests
 	tests := []struct {
 		name     string
-		prepare  func(db *mocks.Store)
+		prepare  func(db *mocks.Store, mailer *mocks.Messenger)
 		req      *gooserv1.ResetPasswordRequest
 		wantCode codes.Code
 	}{
@@ -1133,7 +1378,7 @@ func (suite *Suite) TestResetPassword() {
 		},
 		{
 			name: "user not found",
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
 				db.On("GetUserByPasswordResetToken", mock.Anything, mock.Anything, "xxx").Return(
 					nil,
 					status.Errorf(codes.NotFound, "user not found"),
@@ -1146,7 +1391,7 @@ func (suite *Suite) TestResetPassword() {
 		},
 		{
 			name: "short password",
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
 				db.On("GetUserByPasswordResetToken", mock.Anything, mock.Anything, "xxx").Return(
 					&store.User{
 						Username:           "user1",
@@ -1164,7 +1409,7 @@ func (suite *Suite) TestResetPassword() {
 		},
 		{
 			name: "valid",
-			prepare: func(db *mocks.Store) {
+			prepare: func(db *mocks.Store, mailer *mocks.Messenger) {
 				db.On("GetUserByPasswordResetToken", mock.Anything, mock.Anything, user.PasswordResetToken).Return(
 					user,
 					nil,
@@ -1175,6 +1420,9 @@ func (suite *Suite) TestResetPassword() {
 					},
 					nil,
 				)
+				mailer.On("SendPasswordChanged", mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+					return u.Mail == "user1@testing.com" && u.PasswordResetToken == ""
+				})).Return(nil).Once()
 			},
 			req: &gooserv1.ResetPasswordRequest{
 				Token:    user.PasswordResetToken,
@@ -1188,16 +1436,19 @@ func (suite *Suite) TestResetPassword() {
 			assert := assert.New(t)
 			// prepare mock
 			db := new(mocks.Store)
+			mailer := new(mocks.Messenger)
 			if tt.prepare != nil {
-				tt.prepare(db)
+				tt.prepare(db, mailer)
 			}
 			suite.srv.store = db
+			suite.srv.mailer = mailer
 			// run function
 			res, err := client.ResetPassword(context.Background(), tt.req)
 			// check status code
 			code, _ := status.FromError(err)
 			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
 			db.AssertExpectations(t)
+			mailer.AssertExpectations(t)
 			if code.Code() != codes.OK {
 				// if status code is not ok, response should be nil
 				assert.Nil(res)
@@ -1206,3 +1457,512 @@ func (suite *Suite) TestResetPassword() {
 		})
 	}
 }
+
+func (suite *Suite) TestRevertMailChange() {
+	t := suite.T()
+	printer := message.NewPrinter(language.English)
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// users whose mail address was changed from user1@testing.com
+	changedUser := func(expiresAt time.Time) *store.User {
+		u := &store.User{
+			Id:           "user1",
+			Username:     "user1",
+			Mail:         "attacker@testing.com",
+			ConfirmToken: "confirm",
+		}
+		if err := u.GenerateMailRevertToken(printer, suite.srv.secret, "user1@testing.com", true, expiresAt); err != nil {
+			t.Fatalf("unable to generate mail revert token: %s", err)
+		}
+		return u
+	}
+	valid := changedUser(time.Now().Add(time.Hour))
+	expired := changedUser(time.Now().Add(-time.Hour))
+	// the mail address was changed again after the token was generated
+	changedAgain := changedUser(time.Now().Add(time.Hour))
+	changedAgain.Mail = "other@testing.com"
+	// the change to attacker@testing.com has not been confirmed yet
+	requested := &store.User{
+		Id:               "user1",
+		Username:         "user1",
+		Mail:             "user1@testing.com",
+		Confirmed:        true,
+		PendingMail:      "attacker@testing.com",
+		PendingMailToken: "pending",
+	}
+	if err := requested.GenerateMailRevertToken(printer, suite.srv.secret, "user1@testing.com", true, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("unable to generate mail revert token: %s", err)
+	}
+	// tests
+	tests := []struct {
+		name     string
+		prepare  func(db *mocks.Store)
+		token    string
+		wantCode codes.Code
+	}{
+		{
+			name:     "empty token",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "unknown token",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByMailRevertToken", mock.Anything, mock.Anything, "unknown").Return(
+					nil,
+					status.Errorf(codes.NotFound, "user not found"),
+				).Once()
+			},
+			token:    "unknown",
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "expired token",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByMailRevertToken", mock.Anything, mock.Anything, expired.MailRevertToken).Return(expired, nil).Once()
+			},
+			token:    expired.MailRevertToken,
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "mail changed again",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByMailRevertToken", mock.Anything, mock.Anything, changedAgain.MailRevertToken).Return(changedAgain, nil).Once()
+			},
+			token:    changedAgain.MailRevertToken,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "valid token",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByMailRevertToken", mock.Anything, mock.Anything, valid.MailRevertToken).Return(valid, nil).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(user *store.User) bool {
+					return user.Mail == "user1@testing.com" && user.Confirmed && user.ConfirmToken == "" && user.MailRevertToken == ""
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+			},
+			token:    valid.MailRevertToken,
+			wantCode: codes.OK,
+		},
+		{
+			name: "pending change",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUserByMailRevertToken", mock.Anything, mock.Anything, requested.MailRevertToken).Return(requested, nil).Once()
+				// the pending change is cancelled
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(user *store.User) bool {
+					return user.Mail == "user1@testing.com" && user.Confirmed && user.PendingMail == "" &&
+						user.PendingMailToken == "" && user.MailRevertToken == ""
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+			},
+			token:    requested.MailRevertToken,
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.Store)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.store = db
+			// run function
+			res, err := client.RevertMailChange(context.Background(), &gooserv1.RevertMailChangeRequest{Token: tt.token})
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+			}
+		})
+	}
+}
+
+func (suite *Suite) TestConfirmAndRevertMailChange() {
+	t := suite.T()
+	assert := assert.New(t)
+	printer := message.NewPrinter(language.English)
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// the change to new@testing.com was requested as done by UpdateUser
+	user := &store.User{
+		Id:        "user1",
+		Username:  "user1",
+		Mail:      "user1@testing.com",
+		Confirmed: true,
+	}
+	if err := user.GeneratePendingMailToken(printer, suite.srv.secret, "new@testing.com"); err != nil {
+		t.Fatalf("unable to generate pending mail token: %s", err)
+	}
+	if err := user.GenerateMailRevertToken(printer, suite.srv.secret, "user1@testing.com", true, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("unable to generate mail revert token: %s", err)
+	}
+	// the token sent to the previous address with the notice about the requested change
+	revertToken := user.MailRevertToken
+	db := new(mocks.Store)
+	mailer := new(mocks.Messenger)
+	suite.srv.store = db
+	suite.srv.mailer = mailer
+	// the stored user
+	saved := user
+	save := func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+		saved = user
+		return user
+	}
+	db.On("GetUserByConfirmToken", mock.Anything, mock.Anything, user.PendingMailToken).Return(
+		nil,
+		status.Errorf(codes.NotFound, "user not found"),
+	).Once()
+	db.On("GetUserByPendingMailToken", mock.Anything, mock.Anything, user.PendingMailToken).Return(user, nil).Once()
+	db.On("SaveUser", mock.Anything, mock.Anything, mock.Anything).Return(save, nil).Twice()
+	db.On("GetUserByMailRevertToken", mock.Anything, mock.Anything, revertToken).Return(
+		func(ctx context.Context, printer *message.Printer, token string) *store.User {
+			return saved
+		},
+		nil,
+	).Once()
+	// confirm the new address
+	_, err = client.ConfirmMail(context.Background(), &gooserv1.ConfirmMailRequest{Token: user.PendingMailToken})
+	assert.NoError(err)
+	assert.Equal("new@testing.com", saved.Mail, "mail should be changed")
+	// revert using the token from the notice about the requested change
+	_, err = client.RevertMailChange(context.Background(), &gooserv1.RevertMailChangeRequest{Token: revertToken})
+	assert.NoError(err)
+	assert.Equal("user1@testing.com", saved.Mail, "mail should be reverted")
+	assert.True(saved.Confirmed, "previous confirmation should be restored")
+	db.AssertExpectations(t)
+	// no further notice is sent to the previous address
+	mailer.AssertExpectations(t)
+}
+
+func (suite *Suite) TestSuspendUser() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	past, _ := ptypes.TimestampProto(time.Now().Add(-time.Hour))
+	future, _ := ptypes.TimestampProto(time.Now().Add(time.Hour))
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.Store)
+		accessToken string
+		req         *gooserv1.SuspendUserRequest
+		wantCode    codes.Code
+	}{
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			req:         &gooserv1.SuspendUserRequest{Id: "user1", Reason: "spam"},
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "suspend yourself",
+			accessToken: "admin",
+			req:         &gooserv1.SuspendUserRequest{Id: "admin", Reason: "holidays"},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "without reason",
+			accessToken: "admin",
+			req:         &gooserv1.SuspendUserRequest{Id: "user1"},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "end date in the past",
+			accessToken: "admin",
+			req:         &gooserv1.SuspendUserRequest{Id: "user1", Reason: "spam", Until: past},
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "suspend until end date",
+			accessToken: "admin",
+			req:         &gooserv1.SuspendUserRequest{Id: "user1", Reason: "spam", Until: future},
+			prepare: func(db *mocks.Store) {
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(&store.User{Id: "user1", Username: "user1"}, nil).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+					return u.Suspended && u.SuspensionReason == "spam" && !u.SuspendedUntil.IsZero()
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+			},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.Store)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.store = db
+			// prepare context with access token
+			ctx := context.WithValue(context.Background(), "access_token", tt.accessToken)
+			// run function
+			res, err := client.SuspendUser(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			assert.True(res.Suspended, "suspended mismatch")
+			assert.Equal(tt.req.Until.GetSeconds(), res.SuspendedUntil.GetSeconds(), "end date mismatch")
+		})
+	}
+}
+
+func (suite *Suite) TestReactivateUser() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.Store)
+		accessToken string
+		wantCode    codes.Code
+	}{
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "not suspended",
+			accessToken: "admin",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(&store.User{Id: "user1"}, nil).Once()
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:        "reactivate",
+			accessToken: "admin",
+			prepare: func(db *mocks.Store) {
+				db.On("GetUser", mock.Anything, mock.Anything, "user1").Return(&store.User{
+					Id:               "user1",
+					Suspended:        true,
+					SuspensionReason: "spam",
+					SuspendedUntil:   time.Now().Add(time.Hour),
+				}, nil).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+					return !u.Suspended && u.SuspensionReason == "" && u.SuspendedUntil.IsZero()
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+			},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.Store)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.store = db
+			// prepare context with access token
+			ctx := context.WithValue(context.Background(), "access_token", tt.accessToken)
+			// run function
+			res, err := client.ReactivateUser(ctx, &gooserv1.IdRequest{Id: "user1"})
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			assert.False(res.Suspended, "suspended mismatch")
+		})
+	}
+}
+
+func (suite *Suite) TestSuspendedUserFromContext() {
+	t := suite.T()
+	tests := []struct {
+		name     string
+		user     *store.User
+		wantCode codes.Code
+	}{
+		{
+			name:     "active user",
+			user:     &store.User{Id: "user1"},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "suspended user",
+			user:     &store.User{Id: "user1", Suspended: true},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "suspension ended",
+			user:     &store.User{Id: "user1", Suspended: true, SuspendedUntil: time.Now().Add(-time.Minute)},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			srv, err := NewServer("secret", new(mocks.Store), nil, nil,
+				WithLogger(zap.NewNop()),
+				WithContextUserReceiver(func(ctx context.Context, db store.Store) (*store.User, error) {
+					return tt.user, nil
+				}),
+			)
+			if err != nil {
+				t.Fatalf("unable to create server: %s", err)
+			}
+			u, err := srv.GetUserFromContext(context.Background())
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "statuscode mismatch")
+			if tt.wantCode != codes.OK {
+				assert.Nil(u)
+			}
+		})
+	}
+}
+
+func (suite *Suite) TestRestoreUser() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.Store)
+		accessToken string
+		wantCode    codes.Code
+		wantRoles   []string
+	}{
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "username taken",
+			accessToken: "admin",
+			prepare: func(db *mocks.Store) {
+				db.On("RestoreUser", mock.Anything, mock.Anything, "user1").Return(
+					nil,
+					status.Errorf(codes.FailedPrecondition, "username or mail address was taken in the meantime"),
+				).Once()
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:        "restore memberships",
+			accessToken: "admin",
+			prepare: func(db *mocks.Store) {
+				db.On("RestoreUser", mock.Anything, mock.Anything, "user1").Return(&store.User{
+					Id:                "user1",
+					Roles:             []string{"tester"},
+					DeletedFromGroups: []string{"testers", "gone"},
+				}, nil).Once()
+				db.On("GetGroup", mock.Anything, mock.Anything, "testers").Return(&store.Group{
+					Id:    "testers",
+					Name:  "testers",
+					Roles: []string{"tester", "reader"},
+				}, nil).Once()
+				db.On("GetGroup", mock.Anything, mock.Anything, "gone").Return(
+					nil,
+					status.Errorf(codes.NotFound, "unable to find group"),
+				).Once()
+				db.On("SaveGroup", mock.Anything, mock.Anything, mock.MatchedBy(func(g *store.Group) bool {
+					return g.Id == "testers" && len(g.Members) == 1 && g.Members[0] == "user1"
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, group *store.Group) *store.Group {
+						return group
+					},
+					nil,
+				).Once()
+				db.On("SaveUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *store.User) bool {
+					return u.Id == "user1" && len(u.DeletedFromGroups) == 0
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, user *store.User) *store.User {
+						return user
+					},
+					nil,
+				).Once()
+			},
+			wantCode:  codes.OK,
+			wantRoles: []string{"tester", "reader"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.Store)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.store = db
+			// prepare context with access token
+			ctx := context.WithValue(context.Background(), "access_token", tt.accessToken)
+			// run function
+			res, err := client.RestoreUser(ctx, &gooserv1.IdRequest{Id: "user1"})
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			assert.ElementsMatch(tt.wantRoles, res.Roles, "roles mismatch")
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"net/url"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/generator"
+	"github.com/golang/protobuf/ptypes/empty"
+	fieldmaskutils "github.com/mennanov/fieldmask-utils"
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/rbicker/gooser/internal/utils"
+	"github.com/rbicker/gooser/internal/webhooks"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// webhookAdmin returns the user from the context and the corresponding printer
+// if the user is an admin and webhooks are enabled.
+func (srv *Server) webhookAdmin(ctx context.Context) (*store.User, *message.Printer, error) {
+	u, err := srv.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u == nil {
+		return nil, nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	printer := message.NewPrinter(language.Make(u.Language))
+	if !u.HasRole("admin") {
+		return nil, nil, status.Errorf(codes.PermissionDenied, printer.Sprintf("not allowed to manage webhooks"))
+	}
+	if srv.webhookStore == nil {
+		return nil, nil, status.Errorf(codes.Unimplemented, printer.Sprintf("webhooks are not enabled"))
+	}
+	return u, printer, nil
+}
+
+// ListWebhooks lists the webhooks.
+func (srv *Server) ListWebhooks(ctx context.Context, req *gooserv1.ListRequest) (*gooserv1.ListWebhooksResponse, error) {
+	_, printer, err := srv.webhookAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hooks, totalSize, token, err := srv.webhookStore.ListWebhooks(ctx, printer, req.GetFilter(), "", req.GetPageToken(), req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	var pbWebhooks []*gooserv1.Webhook
+	var pageSize int32
+	if hooks != nil {
+		pageSize = int32(len(*hooks))
+		for _, w := range *hooks {
+			pbWebhooks = append(pbWebhooks, w.ToPb())
+		}
+	}
+	return &gooserv1.ListWebhooksResponse{
+		Webhooks:      pbWebhooks,
+		NextPageToken: token,
+		PageSize:      pageSize,
+		TotalSize:     totalSize,
+	}, nil
+}
+
+// GetWebhook returns the webhook with the given id.
+func (srv *Server) GetWebhook(ctx context.Context, req *gooserv1.IdRequest) (*gooserv1.Webhook, error) {
+	_, printer, err := srv.webhookAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w, err := srv.webhookStore.GetWebhook(ctx, printer, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return w.ToPb(), nil
+}
+
+// ValidateWebhook validates the given webhook. This function should be run
+// before storing the webhook.
+func (srv *Server) ValidateWebhook(printer *message.Printer, webhook *gooserv1.Webhook) error {
+	u, err := url.Parse(webhook.GetUrl())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid webhook url, an absolute http or https url is required"))
+	}
+	if len(webhook.GetEvents()) == 0 {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("at least one event needs to be subscribed"))
+	}
+	for _, e := range webhook.GetEvents() {
+		var found bool
+		for _, known := range webhooks.Events {
+			if e == known {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return status.Errorf(codes.InvalidArgument, printer.Sprintf("unknown event %s", e))
+		}
+	}
+	return nil
+}
+
+// CreateWebhook creates the given webhook. If no secret is given, a random
+// secret is generated. The secret is only returned by this function.
+func (srv *Server) CreateWebhook(ctx context.Context, webhook *gooserv1.Webhook) (*gooserv1.Webhook, error) {
+	u, printer, err := srv.webhookAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Id = ""
+	// make sure events are unique
+	webhook.Events, _ = utils.UniqueStringSlice(webhook.Events)
+	if err := srv.ValidateWebhook(printer, webhook); err != nil {
+		return nil, err
+	}
+	if webhook.GetSecret() == "" {
+		webhook.Secret, err = utils.RandomToken(32)
+		if err != nil {
+			srv.log(ctx).Error("unable to generate webhook secret", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to generate webhook secret"))
+		}
+	}
+	newWebhook, err := srv.webhookStore.SaveWebhook(ctx, printer, store.PbToWebhook(webhook))
+	if err != nil {
+		return nil, err
+	}
+	res := newWebhook.ToPb()
+	res.Secret = newWebhook.Secret
+	srv.audit(ctx, u, "CreateWebhook", auditResourceWebhook, newWebhook.Id, auditChanges(nil, res))
+	return res, nil
+}
+
+// UpdateWebhook changes the given webhook in the database.
+func (srv *Server) UpdateWebhook(ctx context.Context, req *gooserv1.UpdateWebhookRequest) (*gooserv1.Webhook, error) {
+	u, printer, err := srv.webhookAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	webhook := req.GetWebhook()
+	mask, err := fieldmaskutils.MaskFromProtoFieldMask(req.GetFieldMask(), generator.CamelCase)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("unable to create generate field mask: %s", err))
+	}
+	existing, err := srv.webhookStore.GetWebhook(ctx, printer, webhook.GetId())
+	if err != nil {
+		return nil, err
+	}
+	// make sure events are unique
+	webhook.Events, _ = utils.UniqueStringSlice(webhook.Events)
+	res := existing.ToPb()
+	res.Secret = existing.Secret
+	// keep a copy of the existing webhook for the audit log
+	before := proto.Clone(res)
+	// copy given webhook to existing webhook with field mask applied
+	err = fieldmaskutils.StructToStruct(mask, webhook, res)
+	if err != nil {
+		srv.log(ctx).Error("unable to merge webhooks", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to merge webhooks"))
+	}
+	if res.Secret == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("webhook secret cannot be empty"))
+	}
+	if err := srv.ValidateWebhook(printer, res); err != nil {
+		return nil, err
+	}
+	updated, err := srv.webhookStore.SaveWebhook(ctx, printer, store.PbToWebhook(res))
+	if err != nil {
+		return nil, err
+	}
+	srv.audit(ctx, u, "UpdateWebhook", auditResourceWebhook, updated.Id, auditChanges(before, res))
+	return updated.ToPb(), nil
+}
+
+// DeleteWebhook deletes the webhook with the given id from the store.
+func (srv *Server) DeleteWebhook(ctx context.Context, req *gooserv1.IdRequest) (*empty.Empty, error) {
+	u, printer, err := srv.webhookAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "empty id given")
+	}
+	err = srv.webhookStore.DeleteWebhook(ctx, printer, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	srv.audit(ctx, u, "DeleteWebhook", auditResourceWebhook, req.GetId(), nil)
+	return &empty.Empty{}, nil
+}
+
+// ListWebhookDeliveries lists the webhook deliveries, which serve as delivery log.
+func (srv *Server) ListWebhookDeliveries(ctx context.Context, req *gooserv1.ListRequest) (*gooserv1.ListWebhookDeliveriesResponse, error) {
+	_, printer, err := srv.webhookAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, totalSize, token, err := srv.webhookStore.ListWebhookDeliveries(ctx, printer, req.GetFilter(), "", req.GetPageToken(), req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	var pbDeliveries []*gooserv1.WebhookDelivery
+	var pageSize int32
+	if deliveries != nil {
+		pageSize = int32(len(*deliveries))
+		for _, d := range *deliveries {
+			pbDeliveries = append(pbDeliveries, d.ToPb())
+		}
+	}
+	return &gooserv1.ListWebhookDeliveriesResponse{
+		Deliveries:    pbDeliveries,
+		NextPageToken: token,
+		PageSize:      pageSize,
+		TotalSize:     totalSize,
+	}, nil
+}
@@ -0,0 +1,364 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/text/message"
+
+	"google.golang.org/genproto/protobuf/field_mask"
+
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+
+	mock "github.com/stretchr/testify/mock"
+
+	gooserv1 "github.com/rbicker/gooser/api/proto/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (suite *Suite) TestCreateWebhook() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.WebhookStore, audit *mocks.AuditStore)
+		accessToken string
+		req         *gooserv1.Webhook
+		wantCode    codes.Code
+		wantUrl     string
+		wantSecret  string
+	}{
+		{
+			name:        "unauthenticated",
+			accessToken: "",
+			req: &gooserv1.Webhook{
+				Url:    "https://example.com/hook",
+				Events: []string{"user.created"},
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			req: &gooserv1.Webhook{
+				Url:    "https://example.com/hook",
+				Events: []string{"user.created"},
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:        "invalid url",
+			accessToken: "admin",
+			req: &gooserv1.Webhook{
+				Url:    "example.com/hook",
+				Events: []string{"user.created"},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:        "unknown event",
+			accessToken: "admin",
+			req: &gooserv1.Webhook{
+				Url:    "https://example.com/hook",
+				Events: []string{"user.exploded"},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:        "without events",
+			accessToken: "admin",
+			req: &gooserv1.Webhook{
+				Url: "https://example.com/hook",
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:        "valid webhook with given secret",
+			accessToken: "admin",
+			req: &gooserv1.Webhook{
+				Url:    "https://example.com/hook",
+				Events: []string{"user.created", "user.created", "group.member.added"},
+				Secret: "secret",
+				Active: true,
+			},
+			prepare: func(db *mocks.WebhookStore, audit *mocks.AuditStore) {
+				db.On("SaveWebhook", mock.Anything, mock.Anything, mock.MatchedBy(func(w *store.Webhook) bool {
+					return w.Id == "" && len(w.Events) == 2 && w.Active
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, w *store.Webhook) *store.Webhook {
+						w.Id = "hook1"
+						return w
+					},
+					nil,
+				).Once()
+				// the secret must not be part of the audit log
+				audit.On("SaveAuditEvent", mock.Anything, mock.Anything, mock.MatchedBy(func(e *store.AuditEvent) bool {
+					for _, c := range e.Changes {
+						if c.Field == "secret" && c.NewValue != "[redacted]" {
+							return false
+						}
+					}
+					return e.ActorId == "admin" && e.Action == "CreateWebhook" && e.ResourceId == "hook1"
+				})).Return(&store.AuditEvent{}, nil).Once()
+			},
+			wantCode:   codes.OK,
+			wantUrl:    "https://example.com/hook",
+			wantSecret: "secret",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.WebhookStore)
+			audit := new(mocks.AuditStore)
+			if tt.prepare != nil {
+				tt.prepare(db, audit)
+			}
+			suite.srv.webhookStore = db
+			suite.srv.auditStore = audit
+			defer func() { suite.srv.auditStore = nil }()
+			// prepare context with access token
+			ctx := context.Background()
+			if tt.accessToken != "" {
+				ctx = context.WithValue(ctx, "access_token", tt.accessToken)
+			}
+			// run function
+			res, err := client.CreateWebhook(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			audit.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			// check result
+			assert.Equal(tt.wantUrl, res.Url, "url mismatch")
+			assert.Equal(tt.wantSecret, res.Secret, "secret mismatch")
+		})
+	}
+}
+
+func (suite *Suite) TestUpdateWebhook() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	existing := func(ctx context.Context, printer *message.Printer, id string) *store.Webhook {
+		return &store.Webhook{
+			Id:     "hook1",
+			Url:    "https://example.com/hook",
+			Events: []string{"user.created"},
+			Secret: "secret",
+			Active: true,
+		}
+	}
+	// tests
+	tests := []struct {
+		name        string
+		prepare     func(db *mocks.WebhookStore)
+		accessToken string
+		req         *gooserv1.UpdateWebhookRequest
+		wantCode    codes.Code
+		wantActive  bool
+		wantEvents  []string
+	}{
+		{
+			name:        "permission denied",
+			accessToken: "user",
+			req: &gooserv1.UpdateWebhookRequest{
+				Webhook: &gooserv1.Webhook{
+					Id: "hook1",
+				},
+				FieldMask: &field_mask.FieldMask{
+					Paths: []string{"active"},
+				},
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:        "removing secret",
+			accessToken: "admin",
+			req: &gooserv1.UpdateWebhookRequest{
+				Webhook: &gooserv1.Webhook{
+					Id: "hook1",
+				},
+				FieldMask: &field_mask.FieldMask{
+					Paths: []string{"secret"},
+				},
+			},
+			prepare: func(db *mocks.WebhookStore) {
+				db.On("GetWebhook", mock.Anything, mock.Anything, "hook1").Return(existing, nil).Once()
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:        "deactivate and change events",
+			accessToken: "admin",
+			req: &gooserv1.UpdateWebhookRequest{
+				Webhook: &gooserv1.Webhook{
+					Id:     "hook1",
+					Events: []string{"user.deleted"},
+					Active: false,
+				},
+				FieldMask: &field_mask.FieldMask{
+					Paths: []string{"active", "events"},
+				},
+			},
+			prepare: func(db *mocks.WebhookStore) {
+				db.On("GetWebhook", mock.Anything, mock.Anything, "hook1").Return(existing, nil).Once()
+				// secret needs to be kept
+				db.On("SaveWebhook", mock.Anything, mock.Anything, mock.MatchedBy(func(w *store.Webhook) bool {
+					return w.Id == "hook1" && w.Secret == "secret" && !w.Active
+				})).Return(
+					func(ctx context.Context, printer *message.Printer, w *store.Webhook) *store.Webhook {
+						return w
+					},
+					nil,
+				).Once()
+			},
+			wantCode:   codes.OK,
+			wantActive: false,
+			wantEvents: []string{"user.deleted"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.WebhookStore)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.webhookStore = db
+			// prepare context with access token
+			ctx := context.Background()
+			if tt.accessToken != "" {
+				ctx = context.WithValue(ctx, "access_token", tt.accessToken)
+			}
+			// run function
+			res, err := client.UpdateWebhook(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			// check result
+			assert.Equal(tt.wantActive, res.Active, "active mismatch")
+			assert.Equal(tt.wantEvents, res.Events, "events mismatch")
+			assert.Empty(res.Secret, "secret should not be returned")
+		})
+	}
+}
+
+func (suite *Suite) TestListWebhookDeliveries() {
+	t := suite.T()
+	// client connection
+	conn, err := suite.NewClientConnection()
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+	defer conn.Close()
+	client := gooserv1.NewGooserClient(conn)
+	// tests
+	tests := []struct {
+		name         string
+		prepare      func(db *mocks.WebhookStore)
+		disabled     bool
+		accessToken  string
+		req          *gooserv1.ListRequest
+		wantCode     codes.Code
+		wantLen      int
+		wantAttempts int32
+	}{
+		{
+			name:        "webhooks disabled",
+			accessToken: "admin",
+			disabled:    true,
+			req:         &gooserv1.ListRequest{},
+			wantCode:    codes.Unimplemented,
+		},
+		{
+			name:        "list failed deliveries",
+			accessToken: "admin",
+			req: &gooserv1.ListRequest{
+				Filter:   `state=="failed"`,
+				PageSize: 10,
+			},
+			prepare: func(db *mocks.WebhookStore) {
+				db.On("ListWebhookDeliveries", mock.Anything, mock.Anything, `state=="failed"`, "", "", int32(10)).Return(
+					&[]store.WebhookDelivery{
+						{
+							Id:        "delivery1",
+							WebhookId: "hook1",
+							Event:     "user.created",
+							State:     store.WebhookDeliveryFailed,
+							Attempts:  10,
+							LastError: "unexpected status code 500",
+						},
+					},
+					int32(1),
+					"",
+					nil,
+				).Once()
+			},
+			wantCode:     codes.OK,
+			wantLen:      1,
+			wantAttempts: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// prepare mock
+			db := new(mocks.WebhookStore)
+			if tt.prepare != nil {
+				tt.prepare(db)
+			}
+			suite.srv.webhookStore = db
+			if tt.disabled {
+				suite.srv.webhookStore = nil
+			}
+			// prepare context with access token
+			ctx := context.Background()
+			if tt.accessToken != "" {
+				ctx = context.WithValue(ctx, "access_token", tt.accessToken)
+			}
+			// run function
+			res, err := client.ListWebhookDeliveries(ctx, tt.req)
+			// check status code
+			code, _ := status.FromError(err)
+			assert.Equal(tt.wantCode, code.Code(), "response statuscode mismatch")
+			db.AssertExpectations(t)
+			if code.Code() != codes.OK {
+				// if status code is not ok, response should be nil
+				assert.Nil(res)
+				return
+			}
+			// check result
+			assert.Equal(tt.wantLen, len(res.Deliveries), "length mismatch")
+			assert.Equal(tt.wantAttempts, res.Deliveries[0].Attempts, "attempts mismatch")
+		})
+	}
+}
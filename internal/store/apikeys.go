@@ -0,0 +1,183 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/text/message"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListApiKeys lists api keys from the mongo db.
+// It returns the documents, the total size of documents for the given filter and a grpc status type error if anything goes wrong.
+func (m *MGO) ListApiKeys(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (apiKeys *[]ApiKey, totalSize int32, nextToken string, err error) {
+	ctx, end := m.instrument(ctx, "ListApiKeys")
+	defer end()
+	cur, total, err := m.queryDocuments(
+		ctx,
+		printer,
+		m.apiKeysCollection,
+		filterString,
+		orderBy,
+		token,
+		size,
+	)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	defer cur.Close(ctx)
+	apiKeys = &[]ApiKey{}
+	var k ApiKey
+	for cur.Next(ctx) {
+		k = ApiKey{}
+		err = cur.Decode(&k)
+		if err != nil {
+			return nil, 0, "", status.Errorf(codes.Internal, printer.Sprintf("unable to decode api key: %s", err))
+		}
+		*apiKeys = append(*apiKeys, k)
+	}
+	// if there might be more results
+	l := int32(len(*apiKeys))
+	if size == l && total > l {
+		nextToken, err = m.NextPageToken(
+			ctx,
+			printer,
+			m.apiKeysCollection,
+			filterString,
+			orderBy,
+			k,
+		)
+		if err != nil {
+			return nil, 0, "", err
+		}
+	}
+	return apiKeys, total, nextToken, nil
+}
+
+// getApiKey gets one api key based on the given filter.
+// It returns a grpc status type error if anything goes wrong.
+func (m *MGO) getApiKey(ctx context.Context, printer *message.Printer, filter bson.M) (*ApiKey, error) {
+	k := &ApiKey{}
+	if ctx.Err() == context.Canceled {
+		return nil, status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
+	}
+	if err := m.apiKeysCollection.FindOne(ctx, filter).Decode(k); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, printer.Sprintf("unable to find api key"))
+		}
+		return nil, err
+	}
+	return k, nil
+}
+
+// GetApiKey gets the api key with the given id from the mongo db.
+// It returns a grpc status type error if anything goes wrong.
+func (m *MGO) GetApiKey(ctx context.Context, printer *message.Printer, id string) (*ApiKey, error) {
+	ctx, end := m.instrument(ctx, "GetApiKey")
+	defer end()
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid id '%s'", id))
+	}
+	return m.getApiKey(ctx, printer, bson.M{"_id": oid})
+}
+
+// GetApiKeyByPrefix gets the api key with the given prefix from the mongo db.
+// It returns a grpc status type error if anything goes wrong.
+func (m *MGO) GetApiKeyByPrefix(ctx context.Context, printer *message.Printer, prefix string) (*ApiKey, error) {
+	ctx, end := m.instrument(ctx, "GetApiKeyByPrefix")
+	defer end()
+	return m.getApiKey(ctx, printer, bson.M{"prefix": prefix})
+}
+
+// SaveApiKey stores the given api key in the database.
+// The api key id will be used to determine if a new api key has to be created
+// or an existing one can be updated.
+func (m *MGO) SaveApiKey(ctx context.Context, printer *message.Printer, apiKey *ApiKey) (*ApiKey, error) {
+	ctx, end := m.instrument(ctx, "SaveApiKey")
+	defer end()
+	var err error
+	var oid primitive.ObjectID
+	apiKey.UpdatedAt = time.Now()
+	if apiKey.Id != "" {
+		oid, err = primitive.ObjectIDFromHex(apiKey.Id)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid api key id '%s'", apiKey.Id))
+		}
+		apiKey.Id = ""
+	} else {
+		oid = primitive.NewObjectID()
+		apiKey.CreatedAt = apiKey.UpdatedAt
+	}
+	opts := options.FindOneAndUpdate()
+	opts.SetUpsert(true)
+	opts.SetReturnDocument(options.After)
+	filter := bson.M{"_id": oid}
+	doc := bson.M{"$set": apiKey}
+	k := &ApiKey{}
+	err = m.apiKeysCollection.FindOneAndUpdate(ctx, filter, doc, opts).Decode(k)
+	if err != nil {
+		m.log(ctx).Error("error while saving api key", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while saving api key"))
+	}
+	return k, nil
+}
+
+// TouchApiKey sets the time the api key with the given id was last used.
+// The other fields of the api key are not changed.
+func (m *MGO) TouchApiKey(ctx context.Context, printer *message.Printer, id string, lastUsedAt time.Time) error {
+	ctx, end := m.instrument(ctx, "TouchApiKey")
+	defer end()
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid api key id"))
+	}
+	filter := bson.M{"_id": oid}
+	doc := bson.M{"$set": bson.M{"lastUsedAt": lastUsedAt}}
+	if _, err := m.apiKeysCollection.UpdateOne(ctx, filter, doc); err != nil {
+		m.log(ctx).Error("error while updating last usage of api key", zap.Error(err))
+		return status.Errorf(codes.Internal, printer.Sprintf("error while updating api key"))
+	}
+	return nil
+}
+
+// DeleteApiKey deletes the api key with the given id.
+func (m *MGO) DeleteApiKey(ctx context.Context, printer *message.Printer, id string) error {
+	ctx, end := m.instrument(ctx, "DeleteApiKey")
+	defer end()
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid api key id"))
+	}
+	filter := bson.M{"_id": oid}
+	if ctx.Err() == context.Canceled {
+		return status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
+	}
+	res, err := m.apiKeysCollection.DeleteOne(ctx, filter)
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to delete api key")
+	}
+	if res.DeletedCount != 1 {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("unable to find api key with id '%s'", id))
+	}
+	return nil
+}
+
+// DeleteApiKeysOfServiceAccount deletes all the api keys of the service account with the given id.
+func (m *MGO) DeleteApiKeysOfServiceAccount(ctx context.Context, printer *message.Printer, serviceAccountId string) error {
+	ctx, end := m.instrument(ctx, "DeleteApiKeysOfServiceAccount")
+	defer end()
+	filter := bson.M{"serviceAccountId": serviceAccountId}
+	if _, err := m.apiKeysCollection.DeleteMany(ctx, filter); err != nil {
+		m.log(ctx).Error("error while deleting api keys of service account", zap.Error(err))
+		return status.Errorf(codes.Internal, printer.Sprintf("unable to delete api keys"))
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/text/message"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListAttributeDefinitions lists attribute definitions from the mongo db.
+// It returns the documents, the total size of documents for the given filter and a grpc status type error if anything goes wrong.
+func (m *MGO) ListAttributeDefinitions(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (definitions *[]AttributeDefinition, totalSize int32, nextToken string, err error) {
+	ctx, end := m.instrument(ctx, "ListAttributeDefinitions")
+	defer end()
+	cur, total, err := m.queryDocuments(
+		ctx,
+		printer,
+		m.attributeDefinitionsCollection,
+		filterString,
+		orderBy,
+		token,
+		size,
+	)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	defer cur.Close(ctx)
+	definitions = &[]AttributeDefinition{}
+	var d AttributeDefinition
+	for cur.Next(ctx) {
+		d = AttributeDefinition{}
+		err = cur.Decode(&d)
+		if err != nil {
+			return nil, 0, "", status.Errorf(codes.Internal, printer.Sprintf("unable to decode attribute definition: %s", err))
+		}
+		*definitions = append(*definitions, d)
+	}
+	// if there might be more results
+	l := int32(len(*definitions))
+	if size == l && total > l {
+		nextToken, err = m.NextPageToken(
+			ctx,
+			printer,
+			m.attributeDefinitionsCollection,
+			filterString,
+			orderBy,
+			d,
+		)
+		if err != nil {
+			return nil, 0, "", err
+		}
+	}
+	return definitions, total, nextToken, nil
+}
+
+// GetAttributeDefinition gets the attribute definition with the given id from the mongo db.
+// It returns a grpc status type error if anything goes wrong.
+func (m *MGO) GetAttributeDefinition(ctx context.Context, printer *message.Printer, id string) (*AttributeDefinition, error) {
+	ctx, end := m.instrument(ctx, "GetAttributeDefinition")
+	defer end()
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid id '%s'", id))
+	}
+	return m.getAttributeDefinition(ctx, printer, bson.M{"_id": oid})
+}
+
+// GetAttributeDefinitionByName gets the attribute definition with the given name.
+// It returns a grpc status type error if anything goes wrong.
+func (m *MGO) GetAttributeDefinitionByName(ctx context.Context, printer *message.Printer, name string) (*AttributeDefinition, error) {
+	ctx, end := m.instrument(ctx, "GetAttributeDefinitionByName")
+	defer end()
+	return m.getAttributeDefinition(ctx, printer, bson.M{"name": name})
+}
+
+// getAttributeDefinition gets one attribute definition based on the given filter.
+// It returns a grpc status type error if anything goes wrong.
+func (m *MGO) getAttributeDefinition(ctx context.Context, printer *message.Printer, filter bson.M) (*AttributeDefinition, error) {
+	d := &AttributeDefinition{}
+	if ctx.Err() == context.Canceled {
+		return nil, status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
+	}
+	if err := m.attributeDefinitionsCollection.FindOne(ctx, filter).Decode(d); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, printer.Sprintf("unable to find attribute definition"))
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// SaveAttributeDefinition stores the given attribute definition in the database.
+// The id will be used to determine if a new attribute definition has to be created
+// or an existing one can be updated.
+func (m *MGO) SaveAttributeDefinition(ctx context.Context, printer *message.Printer, definition *AttributeDefinition) (*AttributeDefinition, error) {
+	ctx, end := m.instrument(ctx, "SaveAttributeDefinition")
+	defer end()
+	var err error
+	var oid primitive.ObjectID
+	definition.UpdatedAt = time.Now()
+	if definition.Id != "" {
+		oid, err = primitive.ObjectIDFromHex(definition.Id)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid attribute definition id '%s'", definition.Id))
+		}
+		definition.Id = ""
+	} else {
+		oid = primitive.NewObjectID()
+		definition.CreatedAt = definition.UpdatedAt
+	}
+	opts := options.FindOneAndUpdate()
+	opts.SetUpsert(true)
+	opts.SetReturnDocument(options.After)
+	filter := bson.M{"_id": oid}
+	doc := bson.M{"$set": definition}
+	d := &AttributeDefinition{}
+	err = m.attributeDefinitionsCollection.FindOneAndUpdate(ctx, filter, doc, opts).Decode(d)
+	if err != nil {
+		m.log(ctx).Error("error while saving attribute definition", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while saving attribute definition"))
+	}
+	return d, nil
+}
+
+// DeleteAttributeDefinition deletes the attribute definition with the given id.
+func (m *MGO) DeleteAttributeDefinition(ctx context.Context, printer *message.Printer, id string) error {
+	ctx, end := m.instrument(ctx, "DeleteAttributeDefinition")
+	defer end()
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid attribute definition id"))
+	}
+	filter := bson.M{"_id": oid}
+	if ctx.Err() == context.Canceled {
+		return status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
+	}
+	res, err := m.attributeDefinitionsCollection.DeleteOne(ctx, filter)
+	if err != nil {
+		m.log(ctx).Error("unable to delete attribute definition", zap.Error(err))
+		return status.Errorf(codes.Internal, "unable to delete attribute definition")
+	}
+	if res.DeletedCount != 1 {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("unable to find attribute definition with id '%s'", id))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/text/message"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListAuditEvents lists audit events from the mongo db.
+// It returns the documents, the total size of documents for the given filter and a grpc status type error if anything goes wrong.
+func (m *MGO) ListAuditEvents(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (events *[]AuditEvent, totalSize int32, nextToken string, err error) {
+	ctx, end := m.instrument(ctx, "ListAuditEvents")
+	defer end()
+	cur, total, err := m.queryDocuments(
+		ctx,
+		printer,
+		m.auditEventsCollection,
+		filterString,
+		orderBy,
+		token,
+		size,
+	)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	defer cur.Close(ctx)
+	events = &[]AuditEvent{}
+	var e AuditEvent
+	for cur.Next(ctx) {
+		e = AuditEvent{}
+		err = cur.Decode(&e)
+		if err != nil {
+			return nil, 0, "", status.Errorf(codes.Internal, printer.Sprintf("unable to decode audit event: %s", err))
+		}
+		*events = append(*events, e)
+	}
+	// if there might be more results
+	l := int32(len(*events))
+	if size == l && total > l {
+		nextToken, err = m.NextPageToken(
+			ctx,
+			printer,
+			m.auditEventsCollection,
+			filterString,
+			orderBy,
+			e,
+		)
+		if err != nil {
+			return nil, 0, "", err
+		}
+	}
+	return events, total, nextToken, nil
+}
+
+// SaveAuditEvent inserts the given audit event into the database.
+func (m *MGO) SaveAuditEvent(ctx context.Context, printer *message.Printer, event *AuditEvent) (*AuditEvent, error) {
+	ctx, end := m.instrument(ctx, "SaveAuditEvent")
+	defer end()
+	if event.Id != "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("audit events cannot be changed"))
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	res, err := m.auditEventsCollection.InsertOne(ctx, event)
+	if err != nil {
+		m.log(ctx).Error("error while saving audit event", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while saving audit event"))
+	}
+	e := *event
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		e.Id = oid.Hex()
+	}
+	return &e, nil
+}
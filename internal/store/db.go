@@ -5,8 +5,6 @@ import (
 	"crypto/md5"
 	"fmt"
 	"io"
-	"log"
-	"os"
 	"reflect"
 	"regexp"
 	"strings"
@@ -15,32 +13,65 @@ import (
 	"golang.org/x/text/message"
 
 	"github.com/rbicker/go-rsql"
+	"github.com/rbicker/gooser/internal/logging"
+	"github.com/rbicker/gooser/internal/metrics"
+	"github.com/rbicker/gooser/internal/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 // MGO implements the store interface using a mongodb.
 type MGO struct {
-	rsqlParser           *rsql.Parser
-	errorLogger          *log.Logger
-	infoLogger           *log.Logger
-	secret               string
-	url                  string
-	databaseName         string
-	usersCollectionName  string
-	groupsCollectionName string
-	mongoClient          *mongo.Client
-	usersCollection      *mongo.Collection
-	groupsCollection     *mongo.Collection
+	rsqlParser                         *rsql.Parser
+	logger                             *zap.Logger
+	metrics                            *metrics.Metrics
+	secret                             string
+	url                                string
+	databaseName                       string
+	usersCollectionName                string
+	groupsCollectionName               string
+	webhooksCollectionName             string
+	webhookDeliveriesCollectionName    string
+	auditEventsCollectionName          string
+	apiKeysCollectionName              string
+	attributeDefinitionsCollectionName string
+	migrationsCollectionName           string
+	mailsCollectionName                string
+	mongoClient                        *mongo.Client
+	usersCollection                    *mongo.Collection
+	groupsCollection                   *mongo.Collection
+	webhooksCollection                 *mongo.Collection
+	webhookDeliveriesCollection        *mongo.Collection
+	auditEventsCollection              *mongo.Collection
+	apiKeysCollection                  *mongo.Collection
+	attributeDefinitionsCollection     *mongo.Collection
+	migrationsCollection               *mongo.Collection
+	mailsCollection                    *mongo.Collection
 }
 
 // ensure MGO implements the store interface.
 var _ Store = &MGO{}
 
+// ensure MGO implements the webhook store interface.
+var _ WebhookStore = &MGO{}
+
+// ensure MGO implements the audit store interface.
+var _ AuditStore = &MGO{}
+
+// ensure MGO implements the api key store interface.
+var _ ApiKeyStore = &MGO{}
+
+// ensure MGO implements the attribute store interface.
+var _ AttributeStore = &MGO{}
+
+// ensure MGO implements the mail store interface.
+var _ MailStore = &MGO{}
+
 // NewMongoConnection creates a new mongo database connection.
 // It takes functional parameters to change default options
 // such as the mongo url
@@ -49,11 +80,18 @@ var _ Store = &MGO{}
 func NewMongoConnection(secret string, opts ...func(*MGO) error) (*MGO, error) {
 	// create server with default options
 	var m = MGO{
-		secret:               secret,
-		url:                  "mongodb://localhost:27017",
-		databaseName:         "db",
-		usersCollectionName:  "users",
-		groupsCollectionName: "groups",
+		secret:                             secret,
+		url:                                "mongodb://localhost:27017",
+		databaseName:                       "db",
+		usersCollectionName:                "users",
+		groupsCollectionName:               "groups",
+		webhooksCollectionName:             "webhooks",
+		webhookDeliveriesCollectionName:    "webhookDeliveries",
+		auditEventsCollectionName:          "auditEvents",
+		apiKeysCollectionName:              "apiKeys",
+		attributeDefinitionsCollectionName: "attributeDefinitions",
+		migrationsCollectionName:           "migrations",
+		mailsCollectionName:                "mails",
 	}
 	// run functional options
 	for _, op := range opts {
@@ -62,12 +100,9 @@ func NewMongoConnection(secret string, opts ...func(*MGO) error) (*MGO, error) {
 			return nil, fmt.Errorf("setting option: %w", err)
 		}
 	}
-	// default loggers
-	if m.infoLogger == nil {
-		m.infoLogger = log.New(os.Stdout, "INFO: ", log.Lmsgprefix+log.LstdFlags)
-	}
-	if m.errorLogger == nil {
-		m.errorLogger = log.New(os.Stdout, "ERROR: ", log.Lmsgprefix+log.LstdFlags)
+	// default logger
+	if m.logger == nil {
+		m.logger = logging.Default()
 	}
 	// handle secret
 	h := md5.New()
@@ -141,10 +176,27 @@ func (m *MGO) Connect() error {
 	err = m.mongoClient.Ping(ctx, readpref.Primary())
 	if err != nil {
 		return fmt.Errorf("unable to ping: %w", err)
-		return err
 	}
 	m.usersCollection = m.mongoClient.Database(m.databaseName).Collection(m.usersCollectionName)
 	m.groupsCollection = m.mongoClient.Database(m.databaseName).Collection(m.groupsCollectionName)
+	m.webhooksCollection = m.mongoClient.Database(m.databaseName).Collection(m.webhooksCollectionName)
+	m.webhookDeliveriesCollection = m.mongoClient.Database(m.databaseName).Collection(m.webhookDeliveriesCollectionName)
+	m.auditEventsCollection = m.mongoClient.Database(m.databaseName).Collection(m.auditEventsCollectionName)
+	m.apiKeysCollection = m.mongoClient.Database(m.databaseName).Collection(m.apiKeysCollectionName)
+	m.attributeDefinitionsCollection = m.mongoClient.Database(m.databaseName).Collection(m.attributeDefinitionsCollectionName)
+	m.migrationsCollection = m.mongoClient.Database(m.databaseName).Collection(m.migrationsCollectionName)
+	m.mailsCollection = m.mongoClient.Database(m.databaseName).Collection(m.mailsCollectionName)
+	return nil
+}
+
+// Ping checks if the mongodb server is reachable.
+func (m *MGO) Ping(ctx context.Context) error {
+	if m.mongoClient == nil {
+		return fmt.Errorf("not connected")
+	}
+	if err := m.mongoClient.Ping(ctx, readpref.Primary()); err != nil {
+		return fmt.Errorf("unable to ping: %w", err)
+	}
 	return nil
 }
 
@@ -153,6 +205,14 @@ func (m *MGO) Disconnect(ctx context.Context) error {
 	return m.mongoClient.Disconnect(ctx)
 }
 
+// WithLogger sets the logger of the store.
+func WithLogger(logger *zap.Logger) func(*MGO) error {
+	return func(m *MGO) error {
+		m.logger = logger
+		return nil
+	}
+}
+
 // WithURL changes the url to which the connection should be established.
 func WithURL(url string) func(*MGO) error {
 	return func(m *MGO) error {
@@ -185,17 +245,132 @@ func WithGroupsCollectionName(collectionName string) func(*MGO) error {
 	}
 }
 
+// WithWebhooksCollectionName changes the name of the mongodb webhooks collection.
+func WithWebhooksCollectionName(collectionName string) func(*MGO) error {
+	return func(m *MGO) error {
+		m.webhooksCollectionName = collectionName
+		return nil
+	}
+}
+
+// WithWebhookDeliveriesCollectionName changes the name of the mongodb webhook deliveries collection.
+func WithWebhookDeliveriesCollectionName(collectionName string) func(*MGO) error {
+	return func(m *MGO) error {
+		m.webhookDeliveriesCollectionName = collectionName
+		return nil
+	}
+}
+
+// WithAuditEventsCollectionName changes the name of the mongodb audit events collection.
+func WithAuditEventsCollectionName(collectionName string) func(*MGO) error {
+	return func(m *MGO) error {
+		m.auditEventsCollectionName = collectionName
+		return nil
+	}
+}
+
+// WithApiKeysCollectionName changes the name of the mongodb api keys collection.
+func WithApiKeysCollectionName(collectionName string) func(*MGO) error {
+	return func(m *MGO) error {
+		m.apiKeysCollectionName = collectionName
+		return nil
+	}
+}
+
+// WithAttributeDefinitionsCollectionName changes the name of the mongodb attribute definitions collection.
+func WithAttributeDefinitionsCollectionName(collectionName string) func(*MGO) error {
+	return func(m *MGO) error {
+		m.attributeDefinitionsCollectionName = collectionName
+		return nil
+	}
+}
+
+// WithMigrationsCollectionName changes the name of the mongodb collection recording the applied migrations.
+func WithMigrationsCollectionName(collectionName string) func(*MGO) error {
+	return func(m *MGO) error {
+		m.migrationsCollectionName = collectionName
+		return nil
+	}
+}
+
+// WithMailsCollectionName changes the name of the mongodb collection used as outbound mail queue.
+func WithMailsCollectionName(collectionName string) func(*MGO) error {
+	return func(m *MGO) error {
+		m.mailsCollectionName = collectionName
+		return nil
+	}
+}
+
+// WithMetrics sets the metrics used to observe the latency of the store operations.
+func WithMetrics(metrics *metrics.Metrics) func(*MGO) error {
+	return func(m *MGO) error {
+		m.metrics = metrics
+		return nil
+	}
+}
+
+// log returns the logger of the request belonging to the given context.
+func (m *MGO) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, m.logger)
+}
+
+// instrument starts a span for the given store operation and returns a function
+// which ends the span and observes the latency of the operation. It is meant to be deferred.
+func (m *MGO) instrument(ctx context.Context, operation string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "store."+operation)
+	return ctx, func() {
+		span.End()
+		m.metrics.ObserveStore(operation, start)
+	}
+}
+
+// notDeleted extends the given filter to exclude soft deleted documents.
+func notDeleted(filter interface{}) bson.D {
+	return bson.D{{
+		Key: "$and",
+		Value: bson.A{
+			filter,
+			bson.D{{Key: "deletedAt", Value: bson.D{{Key: "$exists", Value: false}}}},
+		},
+	}}
+}
+
+// isDuplicateKeyError checks if the given error was caused by the violation of a unique index.
+func isDuplicateKeyError(err error) bool {
+	isDuplicate := func(code int) bool {
+		return code == 11000 || code == 11001 || code == 12582
+	}
+	switch e := err.(type) {
+	case mongo.WriteException:
+		for _, we := range e.WriteErrors {
+			if isDuplicate(we.Code) {
+				return true
+			}
+		}
+	case mongo.BulkWriteException:
+		for _, we := range e.WriteErrors {
+			if isDuplicate(we.Code) {
+				return true
+			}
+		}
+	case mongo.CommandError:
+		return isDuplicate(int(e.Code))
+	}
+	return false
+}
+
 // paginatedFilterBuilder builds a filter which considers not only filter and orderBy which might
 // have been given by the user but also the pagination based on the given object.
 func (m *MGO) paginatedFilterBuilder(printer *message.Printer, filter bson.D, orderBy string, obj interface{}) (bson.D, error) {
 	v := reflect.ValueOf(obj)
 	if v.Kind() != reflect.Struct {
-		m.errorLogger.Printf("unexpected type of given object in filterBuilder(), expected Struct, got %s", v.Kind().String())
+		m.logger.Error("unexpected type of given object in filterBuilder(), expected Struct", zap.String("kind", v.Kind().String()))
 		return nil, status.Errorf(codes.Internal, printer.Sprintf("internal error while building filter"))
 	}
 	id := v.FieldByName("Id")
 	if !id.IsValid() {
-		m.errorLogger.Printf("given object does not have an 'Id' - %+v", obj)
+		m.logger.Error("given object does not have an 'Id'", zap.String("type", v.Type().String()))
 		return nil, status.Errorf(codes.Internal, printer.Sprintf("internal error while building filter"))
 	}
 	idFilter := bson.E{
@@ -344,13 +519,13 @@ func (m *MGO) NextPageToken(ctx context.Context, printer *message.Printer, colle
 		return "", err
 	}
 	var nextDoc bson.M
-	err = collection.FindOne(ctx, nextFilter).Decode(&nextDoc)
+	err = collection.FindOne(ctx, notDeleted(nextFilter)).Decode(&nextDoc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			// no more documents
 			return "", nil
 		}
-		m.errorLogger.Printf("error while creating pagination token, while searching for next document: %s", err)
+		m.log(ctx).Error("error while creating pagination token, while searching for next document", zap.Error(err))
 		return "", status.Errorf(codes.Internal, printer.Sprintf("unable to search next document while creating pagination token"))
 	}
 	next := &PageToken{
@@ -360,7 +535,7 @@ func (m *MGO) NextPageToken(ctx context.Context, printer *message.Printer, colle
 	}
 	res, err := next.EncryptedString(m.secret)
 	if err != nil {
-		m.errorLogger.Printf("unable to encrypt page token: %s", err)
+		m.log(ctx).Error("unable to encrypt page token", zap.Error(err))
 	}
 	return res, nil
 }
@@ -405,7 +580,8 @@ func bsonDocFromOrderByString(p *message.Printer, sort string) (bson.D, error) {
 }
 
 // queryDocuments queries the document from the given collection and returns a mongo cursor.
-// The function considers the given filter & order by. The query will be corresponding to the given pagination token.
+// The function considers the given filter & order by, soft deleted documents are excluded.
+// The query will be corresponding to the given pagination token.
 // It returns the total size for the query (not considering given skip or limit parameters),
 // the mongo cursor, and a protobuf type error if anything goes wrong.
 func (m *MGO) queryDocuments(ctx context.Context, printer *message.Printer, collection *mongo.Collection, filterString, orderBy, token string, size int32) (cur *mongo.Cursor, totalSize int32, err error) {
@@ -438,9 +614,9 @@ func (m *MGO) queryDocuments(ctx context.Context, printer *message.Printer, coll
 		return nil, 0, status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
 	}
 	// count total size of documents
-	count, err := collection.CountDocuments(ctx, filter, nil)
+	count, err := collection.CountDocuments(ctx, notDeleted(filter), nil)
 	if err != nil {
-		m.errorLogger.Printf("unable to count %s: %s", collection.Name(), err)
+		m.log(ctx).Error("unable to count documents", zap.String("collection", collection.Name()), zap.Error(err))
 		return nil, 0, status.Errorf(codes.Internal, printer.Sprintf("unable to count %s", collection.Name()))
 	}
 	// if page token is not nil,
@@ -456,10 +632,28 @@ func (m *MGO) queryDocuments(ctx context.Context, printer *message.Printer, coll
 	findOptions.SetSort(sortOption)
 	// query the documents, use the pagination filter
 	// to get results for the current page
-	cur, err = collection.Find(ctx, filter, findOptions)
+	cur, err = collection.Find(ctx, notDeleted(filter), findOptions)
 	if err != nil {
-		m.errorLogger.Printf("unable to query %s: %s", collection.Name(), err)
+		m.log(ctx).Error("unable to query documents", zap.String("collection", collection.Name()), zap.Error(err))
 		return nil, 0, status.Errorf(codes.Internal, printer.Sprintf("error while querying %s", collection.Name()))
 	}
 	return cur, totalSize, nil
 }
+
+// PurgeDeleted permanently removes the users and groups which have been
+// soft deleted before the given time. It returns the number of removed documents.
+func (m *MGO) PurgeDeleted(ctx context.Context, printer *message.Printer, deletedBefore time.Time) (int64, error) {
+	ctx, end := m.instrument(ctx, "PurgeDeleted")
+	defer end()
+	filter := bson.M{"deletedAt": bson.M{"$lt": deletedBefore}}
+	var purged int64
+	for _, collection := range []*mongo.Collection{m.usersCollection, m.groupsCollection} {
+		res, err := collection.DeleteMany(ctx, filter)
+		if err != nil {
+			m.log(ctx).Error("unable to purge deleted documents", zap.String("collection", collection.Name()), zap.Error(err))
+			return purged, status.Errorf(codes.Internal, printer.Sprintf("unable to purge deleted documents"))
+		}
+		purged += res.DeletedCount
+	}
+	return purged, nil
+}
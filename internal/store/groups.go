@@ -10,6 +10,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -17,10 +18,12 @@ import (
 // ListGroups lists groups from the mongo db.
 // It returns the documents, the total size of documents for the given filter and a grpc status type error if anything goes wrong.
 func (m *MGO) ListGroups(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (groups *[]Group, totalSize int32, nextToken string, err error) {
+	ctx, end := m.instrument(ctx, "ListGroups")
+	defer end()
 	cur, total, err := m.queryDocuments(
 		ctx,
 		printer,
-		m.usersCollection,
+		m.groupsCollection,
 		filterString,
 		orderBy,
 		token,
@@ -30,8 +33,10 @@ func (m *MGO) ListGroups(ctx context.Context, printer *message.Printer, filterSt
 		return nil, 0, "", err
 	}
 	defer cur.Close(ctx)
+	groups = &[]Group{}
 	var g Group
 	for cur.Next(ctx) {
+		g = Group{}
 		err = cur.Decode(&g)
 		if err != nil {
 			return nil, 0, "", status.Errorf(codes.Internal, printer.Sprintf("unable to decode group: %s", err))
@@ -40,11 +45,11 @@ func (m *MGO) ListGroups(ctx context.Context, printer *message.Printer, filterSt
 	}
 	// if there might be more results
 	l := int32(len(*groups))
-	if size == l && totalSize > l {
+	if size == l && total > l {
 		nextToken, err = m.NextPageToken(
 			ctx,
 			printer,
-			m.usersCollection,
+			m.groupsCollection,
 			filterString,
 			orderBy,
 			g,
@@ -56,18 +61,20 @@ func (m *MGO) ListGroups(ctx context.Context, printer *message.Printer, filterSt
 	return groups, total, nextToken, nil
 }
 
-// CountGroups returns the number of user documents corresponding to the given filter.
+// CountGroups returns the number of group documents corresponding to the given filter.
 func (m *MGO) CountGroups(ctx context.Context, printer *message.Printer, filterString string) (int32, error) {
+	ctx, end := m.instrument(ctx, "CountGroups")
+	defer end()
 	filter, err := m.bsonDocFromRsqlString(printer, filterString)
 	if err != nil {
 		return 0, err
 	}
-	count, err := m.groupsCollection.CountDocuments(ctx, filter, nil)
+	count, err := m.groupsCollection.CountDocuments(ctx, notDeleted(filter), nil)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return 0, nil
 		}
-		m.errorLogger.Printf("unable to count groups: %s", err)
+		m.log(ctx).Error("unable to count groups", zap.Error(err))
 		return 0, status.Errorf(codes.Internal, printer.Sprintf("unable to count groups"))
 	}
 	return int32(count), nil
@@ -76,6 +83,8 @@ func (m *MGO) CountGroups(ctx context.Context, printer *message.Printer, filterS
 // GetGroup gets the group with the given id from the mongo db.
 // It returns a grpc status type error if anything goes wrong.
 func (m *MGO) GetGroup(ctx context.Context, printer *message.Printer, id string) (*Group, error) {
+	ctx, end := m.instrument(ctx, "GetGroup")
+	defer end()
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid id '%s'", id))
@@ -85,7 +94,7 @@ func (m *MGO) GetGroup(ctx context.Context, printer *message.Printer, id string)
 	if ctx.Err() == context.Canceled {
 		return nil, status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
 	}
-	if err := m.groupsCollection.FindOne(ctx, filter).Decode(g); err != nil {
+	if err := m.groupsCollection.FindOne(ctx, notDeleted(filter)).Decode(g); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, status.Errorf(codes.NotFound, printer.Sprintf("unable to find group with id %s", id))
 		}
@@ -97,12 +106,14 @@ func (m *MGO) GetGroup(ctx context.Context, printer *message.Printer, id string)
 // GetGroupByName gets the group with the given name from the mongo db.
 // It returns a grpc status type error if anything goes wrong.
 func (m *MGO) GetGroupByName(ctx context.Context, printer *message.Printer, name string) (*Group, error) {
+	ctx, end := m.instrument(ctx, "GetGroupByName")
+	defer end()
 	filter := bson.M{"name": name}
 	g := &Group{}
 	if ctx.Err() == context.Canceled {
 		return nil, status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
 	}
-	if err := m.groupsCollection.FindOne(ctx, filter).Decode(g); err != nil {
+	if err := m.groupsCollection.FindOne(ctx, notDeleted(filter)).Decode(g); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, status.Errorf(codes.NotFound, printer.Sprintf("unable to find group named %s", name))
 		}
@@ -113,10 +124,16 @@ func (m *MGO) GetGroupByName(ctx context.Context, printer *message.Printer, name
 
 // SaveGroup stores the given group in the database.
 // The group id will be used to determine if a new group has to be created
-// or an existing one can be updated.
+// or an existing one can be updated. An existing group is only updated if its
+// revision matches the stored one, otherwise an aborted error is returned.
 func (m *MGO) SaveGroup(ctx context.Context, printer *message.Printer, group *Group) (*Group, error) {
+	ctx, end := m.instrument(ctx, "SaveGroup")
+	defer end()
 	var err error
 	var oid primitive.ObjectID
+	var filter bson.M
+	opts := options.FindOneAndUpdate()
+	opts.SetReturnDocument(options.After)
 	group.UpdatedAt = time.Now()
 	if group.Id != "" {
 		oid, err = primitive.ObjectIDFromHex(group.Id)
@@ -124,26 +141,33 @@ func (m *MGO) SaveGroup(ctx context.Context, printer *message.Printer, group *Gr
 			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid group id '%s'", group.Id))
 		}
 		group.Id = ""
+		filter = bson.M{"_id": oid, "revision": group.Revision}
+		group.Revision++
 	} else {
 		oid = primitive.NewObjectID()
 		group.CreatedAt = group.UpdatedAt
+		filter = bson.M{"_id": oid}
+		opts.SetUpsert(true)
+		group.Revision = 1
 	}
-	opts := options.FindOneAndUpdate()
-	opts.SetUpsert(true)
-	opts.SetReturnDocument(options.After)
-	filter := bson.M{"_id": oid}
 	doc := bson.M{"$set": group}
 	g := &Group{}
 	err = m.groupsCollection.FindOneAndUpdate(ctx, filter, doc, opts).Decode(g)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.Aborted, printer.Sprintf("group was modified or deleted in the meantime"))
+	}
 	if err != nil {
-		m.errorLogger.Printf("error while saving user: %s", err)
+		m.log(ctx).Error("error while saving group", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while saving group"))
 	}
 	return g, nil
 }
 
-// DeleteGroup deletes the group with the given id.
+// DeleteGroup soft deletes the group with the given id. The group is
+// excluded from all queries but can be restored until it is purged.
 func (m *MGO) DeleteGroup(ctx context.Context, printer *message.Printer, id string) error {
+	ctx, end := m.instrument(ctx, "DeleteGroup")
+	defer end()
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return status.Errorf(codes.InvalidArgument, "invalid group id")
@@ -152,12 +176,49 @@ func (m *MGO) DeleteGroup(ctx context.Context, printer *message.Printer, id stri
 	if ctx.Err() == context.Canceled {
 		return status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
 	}
-	res, err := m.usersCollection.DeleteOne(ctx, filter)
+	res, err := m.groupsCollection.UpdateOne(ctx, notDeleted(filter), bson.M{"$set": bson.M{"deletedAt": time.Now()}, "$inc": bson.M{"revision": 1}})
 	if err != nil {
+		m.log(ctx).Error("unable to delete group", zap.Error(err))
 		return status.Errorf(codes.Internal, "unable to delete group")
 	}
-	if res.DeletedCount != 1 {
+	if res.MatchedCount != 1 {
 		return status.Errorf(codes.InvalidArgument, printer.Sprintf("unable to find group with id '%s'", id))
 	}
 	return nil
 }
+
+// RestoreGroup restores the soft deleted group with the given id.
+// It fails if the name was taken in the meantime.
+func (m *MGO) RestoreGroup(ctx context.Context, printer *message.Printer, id string) (*Group, error) {
+	ctx, end := m.instrument(ctx, "RestoreGroup")
+	defer end()
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid group id"))
+	}
+	filter := bson.M{"_id": oid, "deletedAt": bson.M{"$exists": true}}
+	g := &Group{}
+	if err := m.groupsCollection.FindOne(ctx, filter).Decode(g); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, printer.Sprintf("unable to find deleted group with id %s", id))
+		}
+		return nil, err
+	}
+	count, err := m.groupsCollection.CountDocuments(ctx, notDeleted(bson.M{"name": g.Name}))
+	if err != nil {
+		m.log(ctx).Error("unable to count groups", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to count groups"))
+	}
+	if count > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("group name was taken in the meantime"))
+	}
+	opts := options.FindOneAndUpdate()
+	opts.SetReturnDocument(options.After)
+	doc := bson.M{"$unset": bson.M{"deletedAt": ""}, "$inc": bson.M{"revision": 1}}
+	g = &Group{}
+	if err := m.groupsCollection.FindOneAndUpdate(ctx, filter, doc, opts).Decode(g); err != nil {
+		m.log(ctx).Error("error while restoring group", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while restoring group"))
+	}
+	return g, nil
+}
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/text/message"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListMails lists the mails of the outbound mail queue from the mongo db.
+// It returns the documents, the total size of documents for the given filter and a grpc status type error if anything goes wrong.
+func (m *MGO) ListMails(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (mails *[]Mail, totalSize int32, nextToken string, err error) {
+	ctx, end := m.instrument(ctx, "ListMails")
+	defer end()
+	cur, total, err := m.queryDocuments(
+		ctx,
+		printer,
+		m.mailsCollection,
+		filterString,
+		orderBy,
+		token,
+		size,
+	)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	defer cur.Close(ctx)
+	mails = &[]Mail{}
+	var mail Mail
+	for cur.Next(ctx) {
+		mail = Mail{}
+		err = cur.Decode(&mail)
+		if err != nil {
+			return nil, 0, "", status.Errorf(codes.Internal, printer.Sprintf("unable to decode mail: %s", err))
+		}
+		*mails = append(*mails, mail)
+	}
+	// if there might be more results
+	l := int32(len(*mails))
+	if size == l && total > l {
+		nextToken, err = m.NextPageToken(
+			ctx,
+			printer,
+			m.mailsCollection,
+			filterString,
+			orderBy,
+			mail,
+		)
+		if err != nil {
+			return nil, 0, "", err
+		}
+	}
+	return mails, total, nextToken, nil
+}
+
+// GetMail returns the queued mail with the given id.
+func (m *MGO) GetMail(ctx context.Context, printer *message.Printer, id string) (*Mail, error) {
+	ctx, end := m.instrument(ctx, "GetMail")
+	defer end()
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid id '%s'", id))
+	}
+	filter := bson.M{"_id": oid}
+	mail := &Mail{}
+	if ctx.Err() == context.Canceled {
+		return nil, status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
+	}
+	if err := m.mailsCollection.FindOne(ctx, filter).Decode(mail); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, printer.Sprintf("unable to find mail with id %s", id))
+		}
+		return nil, err
+	}
+	return mail, nil
+}
+
+// SaveMail stores the given mail in the database.
+// The mail id will be used to determine if a new mail has to be queued
+// or an existing one can be updated.
+func (m *MGO) SaveMail(ctx context.Context, printer *message.Printer, mail *Mail) (*Mail, error) {
+	ctx, end := m.instrument(ctx, "SaveMail")
+	defer end()
+	var err error
+	var oid primitive.ObjectID
+	mail.UpdatedAt = time.Now()
+	if mail.Id != "" {
+		oid, err = primitive.ObjectIDFromHex(mail.Id)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid mail id '%s'", mail.Id))
+		}
+		mail.Id = ""
+	} else {
+		oid = primitive.NewObjectID()
+		mail.CreatedAt = mail.UpdatedAt
+	}
+	opts := options.FindOneAndUpdate()
+	opts.SetUpsert(true)
+	opts.SetReturnDocument(options.After)
+	filter := bson.M{"_id": oid}
+	doc := bson.M{"$set": mail}
+	res := &Mail{}
+	err = m.mailsCollection.FindOneAndUpdate(ctx, filter, doc, opts).Decode(res)
+	if err != nil {
+		m.log(ctx).Error("error while saving mail", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while saving mail"))
+	}
+	return res, nil
+}
+
+// ClaimMail returns the pending mail which is due the longest.
+// The next attempt of the returned mail is postponed by the given lease, which
+// prevents other workers from claiming the same mail while it is being sent.
+// It returns nil if no mail is due.
+func (m *MGO) ClaimMail(ctx context.Context, printer *message.Printer, lease time.Duration) (*Mail, error) {
+	ctx, end := m.instrument(ctx, "ClaimMail")
+	defer end()
+	now := time.Now()
+	filter := bson.M{
+		"state":         MailPending,
+		"nextAttemptAt": bson.M{"$lte": now},
+	}
+	doc := bson.M{"$set": bson.M{"nextAttemptAt": now.Add(lease)}}
+	opts := options.FindOneAndUpdate()
+	opts.SetSort(bson.M{"nextAttemptAt": 1})
+	opts.SetReturnDocument(options.After)
+	mail := &Mail{}
+	if err := m.mailsCollection.FindOneAndUpdate(ctx, filter, doc, opts).Decode(mail); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		m.log(ctx).Error("error while claiming mail", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while claiming mail"))
+	}
+	return mail, nil
+}
+
+// PurgeFailedMails removes the bodies and attachments of the failed mails which
+// have last been updated before the given time. They contain tokens which must not
+// be kept longer than necessary. It returns the number of purged mails.
+func (m *MGO) PurgeFailedMails(ctx context.Context, printer *message.Printer, failedBefore time.Time) (int64, error) {
+	ctx, end := m.instrument(ctx, "PurgeFailedMails")
+	defer end()
+	filter := bson.M{
+		"state":     MailFailed,
+		"updatedAt": bson.M{"$lt": failedBefore},
+		"$or": bson.A{
+			bson.M{"text": bson.M{"$ne": ""}},
+			bson.M{"html": bson.M{"$ne": ""}},
+			bson.M{"raw": bson.M{"$exists": true}},
+			bson.M{"attachments": bson.M{"$exists": true}},
+		},
+	}
+	doc := bson.M{
+		"$set":   bson.M{"text": "", "html": ""},
+		"$unset": bson.M{"raw": "", "attachments": ""},
+	}
+	res, err := m.mailsCollection.UpdateMany(ctx, filter, doc)
+	if err != nil {
+		m.log(ctx).Error("unable to purge failed mails", zap.Error(err))
+		return 0, status.Errorf(codes.Internal, printer.Sprintf("unable to purge failed mails"))
+	}
+	return res.ModifiedCount, nil
+}
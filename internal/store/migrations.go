@@ -0,0 +1,382 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	// migrationLockId is the id of the document in the migrations collection
+	// which is used as lock, the applied migrations are stored by their version.
+	migrationLockId = "lock"
+	// migrationLockTTL is the duration after which a lock is considered stale,
+	// for example because the replica holding it crashed.
+	migrationLockTTL = 10 * time.Minute
+	// migrationLockRenewInterval is the interval in which the replica holding
+	// the lock extends it while migrating.
+	migrationLockRenewInterval = migrationLockTTL / 5
+	// migrationLockRetryInterval is the duration to wait before trying
+	// to acquire a lock held by another replica again.
+	migrationLockRetryInterval = 2 * time.Second
+)
+
+// migration changes the schema or the data of the store.
+// Migrations must be idempotent, as a migration which failed halfway
+// is run again the next time.
+type migration struct {
+	version     int
+	description string
+	up          func(ctx context.Context, m *MGO) error
+}
+
+// migrations is the ordered list of all migrations.
+// New migrations are appended with the next version, existing ones must not be changed.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create indexes for username, mail, tokens and group members",
+		up:          createInitialIndexes,
+	},
+	{
+		version:     2,
+		description: "add case-insensitive lookup keys for username and mail",
+		up:          addLookupKeys,
+	},
+	{
+		version:     3,
+		description: "add revisions to users and groups",
+		up:          addRevisions,
+	},
+	{
+		version:     4,
+		description: "create index for the outbound mail queue",
+		up:          createMailQueueIndexes,
+	},
+	{
+		version:     5,
+		description: "create index for mail revert tokens",
+		up:          createMailRevertTokenIndex,
+	},
+	{
+		version:     6,
+		description: "create index for pending mail tokens",
+		up:          createPendingMailTokenIndex,
+	},
+}
+
+// MigrationStatus describes a migration and if it has been applied.
+type MigrationStatus struct {
+	Version     int
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+// appliedMigration is the record of an applied migration in the migrations collection.
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+// validateMigrations checks that the versions of the given migrations start at 1 and are consecutive.
+func validateMigrations(ms []migration) error {
+	for i, mig := range ms {
+		if mig.version != i+1 {
+			return fmt.Errorf("migration %d has version %d, expected %d", i, mig.version, i+1)
+		}
+		if mig.up == nil {
+			return fmt.Errorf("migration %d does not have an up function", mig.version)
+		}
+	}
+	return nil
+}
+
+// migrationStatus combines the given migrations with the applied ones.
+func migrationStatus(ms []migration, applied map[int]time.Time) []MigrationStatus {
+	var res []MigrationStatus
+	for _, mig := range ms {
+		appliedAt, ok := applied[mig.version]
+		res = append(res, MigrationStatus{
+			Version:     mig.version,
+			Description: mig.description,
+			Applied:     ok,
+			AppliedAt:   appliedAt,
+		})
+	}
+	return res
+}
+
+// appliedMigrations returns the time each applied migration was applied at by its version.
+func (m *MGO) appliedMigrations(ctx context.Context) (map[int]time.Time, error) {
+	cur, err := m.migrationsCollection.Find(ctx, bson.M{"_id": bson.M{"$ne": migrationLockId}})
+	if err != nil {
+		return nil, fmt.Errorf("unable to find applied migrations: %w", err)
+	}
+	var records []appliedMigration
+	if err := cur.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("unable to decode applied migrations: %w", err)
+	}
+	applied := make(map[int]time.Time)
+	for _, r := range records {
+		applied[r.Version] = r.AppliedAt
+	}
+	return applied, nil
+}
+
+// MigrationStatus returns the status of all known migrations.
+func (m *MGO) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	if err := validateMigrations(migrations); err != nil {
+		return nil, err
+	}
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return migrationStatus(migrations, applied), nil
+}
+
+// Migrate applies all pending migrations in order. A lock makes sure only one
+// replica migrates at a time, the others wait until the lock is released.
+// The lock is renewed while migrating, the migrations are stopped if it is lost.
+func (m *MGO) Migrate(ctx context.Context) error {
+	if err := validateMigrations(migrations); err != nil {
+		return err
+	}
+	owner := primitive.NewObjectID().Hex()
+	if err := m.lockMigrations(ctx, owner); err != nil {
+		return err
+	}
+	defer m.unlockMigrations(owner)
+	migrateCtx, cancel := context.WithCancel(ctx)
+	renewed := make(chan error, 1)
+	go func() {
+		err := m.renewMigrationLock(migrateCtx, owner)
+		if err != nil {
+			// another replica might take over the lock, so migrating has to stop
+			cancel()
+		}
+		renewed <- err
+	}()
+	err := m.applyMigrations(migrateCtx)
+	cancel()
+	if renewErr := <-renewed; renewErr != nil {
+		return renewErr
+	}
+	return err
+}
+
+// applyMigrations applies the migrations which have not been applied yet.
+func (m *MGO) applyMigrations(ctx context.Context) error {
+	// the migrations might have been applied while waiting for the lock
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	for _, mig := range migrations {
+		if _, ok := applied[mig.version]; ok {
+			continue
+		}
+		m.log(ctx).Info("applying migration", zap.Int("version", mig.version), zap.String("description", mig.description))
+		if err := mig.up(ctx, m); err != nil {
+			return fmt.Errorf("unable to apply migration %d: %w", mig.version, err)
+		}
+		_, err := m.migrationsCollection.InsertOne(ctx, appliedMigration{
+			Version:     mig.version,
+			Description: mig.description,
+			AppliedAt:   time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to record migration %d: %w", mig.version, err)
+		}
+	}
+	return nil
+}
+
+// lockMigrations acquires the migration lock for the given owner.
+// It blocks until the lock is acquired or the context is done.
+func (m *MGO) lockMigrations(ctx context.Context, owner string) error {
+	for {
+		now := time.Now()
+		// the upsert fails with a duplicate key if a valid lock exists
+		filter := bson.M{"_id": migrationLockId, "lockedUntil": bson.M{"$lt": now}}
+		doc := bson.M{"$set": bson.M{"owner": owner, "lockedUntil": now.Add(migrationLockTTL)}}
+		_, err := m.migrationsCollection.UpdateOne(ctx, filter, doc, options.Update().SetUpsert(true))
+		if err == nil {
+			return nil
+		}
+		if !isDuplicateKeyError(err) {
+			return fmt.Errorf("unable to acquire migration lock: %w", err)
+		}
+		m.log(ctx).Info("waiting for migrations of another replica to finish")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("unable to acquire migration lock: %w", ctx.Err())
+		case <-time.After(migrationLockRetryInterval):
+		}
+	}
+}
+
+// renewMigrationLock extends the migration lock of the given owner in regular intervals
+// until the context is done. It returns an error if the lock could not be extended.
+func (m *MGO) renewMigrationLock(ctx context.Context, owner string) error {
+	ticker := time.NewTicker(migrationLockRenewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		filter := bson.M{"_id": migrationLockId, "owner": owner}
+		doc := bson.M{"$set": bson.M{"lockedUntil": time.Now().Add(migrationLockTTL)}}
+		res, err := m.migrationsCollection.UpdateOne(ctx, filter, doc)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unable to renew migration lock: %w", err)
+		}
+		if res.MatchedCount == 0 {
+			return fmt.Errorf("unable to renew migration lock: lock was taken over by another replica")
+		}
+	}
+}
+
+// unlockMigrations releases the migration lock if it is held by the given owner.
+func (m *MGO) unlockMigrations(owner string) {
+	// the lock has to be released even if the context of the migration is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := m.migrationsCollection.DeleteOne(ctx, bson.M{"_id": migrationLockId, "owner": owner}); err != nil {
+		m.log(ctx).Error("unable to release migration lock", zap.Error(err))
+	}
+}
+
+// createIndexes creates the given indexes on the given collection.
+// Creating an index which already exists with the same options does nothing.
+func createIndexes(ctx context.Context, collection *mongo.Collection, models []mongo.IndexModel) error {
+	if _, err := collection.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("unable to create indexes on %s: %w", collection.Name(), err)
+	}
+	return nil
+}
+
+// createInitialIndexes creates the indexes used to look up users and groups.
+func createInitialIndexes(ctx context.Context, m *MGO) error {
+	// users without a token are not indexed
+	tokenIndex := func(key string) mongo.IndexModel {
+		return mongo.IndexModel{
+			Keys:    bson.D{{Key: key, Value: 1}},
+			Options: options.Index().SetName(key).SetPartialFilterExpression(bson.M{key: bson.M{"$gt": ""}}),
+		}
+	}
+	err := createIndexes(ctx, m.usersCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetName("username")},
+		{Keys: bson.D{{Key: "mail", Value: 1}}, Options: options.Index().SetName("mail")},
+		tokenIndex("confirmToken"),
+		tokenIndex("passwordResetToken"),
+	})
+	if err != nil {
+		return err
+	}
+	return createIndexes(ctx, m.groupsCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "members", Value: 1}}, Options: options.Index().SetName("members")},
+	})
+}
+
+// addLookupKeys computes the lookup keys of users stored before they were
+// introduced and creates unique indexes on them. It fails if two active users
+// share a username or mail address differing only in case.
+func addLookupKeys(ctx context.Context, m *MGO) error {
+	cur, err := m.usersCollection.Find(ctx, bson.M{"usernameKey": bson.M{"$exists": false}})
+	if err != nil {
+		return fmt.Errorf("unable to find users without lookup keys: %w", err)
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var u User
+		if err := cur.Decode(&u); err != nil {
+			return fmt.Errorf("unable to decode user: %w", err)
+		}
+		var usernameKey, mailKey string
+		if u.DeletedAt.IsZero() {
+			usernameKey, mailKey = LookupKey(u.Username), LookupKey(u.Mail)
+		}
+		oid, err := primitive.ObjectIDFromHex(u.Id)
+		if err != nil {
+			return fmt.Errorf("invalid user id %s: %w", u.Id, err)
+		}
+		doc := bson.M{"$set": bson.M{"usernameKey": usernameKey, "mailKey": mailKey}}
+		if _, err := m.usersCollection.UpdateOne(ctx, bson.M{"_id": oid}, doc); err != nil {
+			return fmt.Errorf("unable to set lookup keys of user %s: %w", u.Id, err)
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return fmt.Errorf("unable to iterate users: %w", err)
+	}
+	// empty keys belong to deleted users or pending invitations and are not unique
+	var models []mongo.IndexModel
+	for _, key := range []string{"usernameKey", "mailKey"} {
+		models = append(models, mongo.IndexModel{
+			Keys: bson.D{{Key: key, Value: 1}},
+			Options: options.Index().
+				SetName(key).
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{key: bson.M{"$gt": ""}}),
+		})
+	}
+	return createIndexes(ctx, m.usersCollection, models)
+}
+
+// addRevisions initializes the revision of users and groups stored before
+// revisions were introduced, the conditional updates would not match them otherwise.
+func addRevisions(ctx context.Context, m *MGO) error {
+	filter := bson.M{"revision": bson.M{"$exists": false}}
+	doc := bson.M{"$set": bson.M{"revision": 0}}
+	for _, collection := range []*mongo.Collection{m.usersCollection, m.groupsCollection} {
+		if _, err := collection.UpdateMany(ctx, filter, doc); err != nil {
+			return fmt.Errorf("unable to add revisions to %s: %w", collection.Name(), err)
+		}
+	}
+	return nil
+}
+
+// createMailQueueIndexes creates the index used by the workers to claim due mails.
+func createMailQueueIndexes(ctx context.Context, m *MGO) error {
+	return createIndexes(ctx, m.mailsCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "state", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+			Options: options.Index().SetName("state_nextAttemptAt"),
+		},
+	})
+}
+
+// createMailRevertTokenIndex creates the index used to look up users by their mail revert token.
+// Users without a token are not indexed.
+func createMailRevertTokenIndex(ctx context.Context, m *MGO) error {
+	return createIndexes(ctx, m.usersCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "mailRevertToken", Value: 1}},
+			Options: options.Index().SetName("mailRevertToken").SetPartialFilterExpression(bson.M{"mailRevertToken": bson.M{"$gt": ""}}),
+		},
+	})
+}
+
+// createPendingMailTokenIndex creates the index used to look up users by the token
+// confirming their pending mail address. Users without a token are not indexed.
+func createPendingMailTokenIndex(ctx context.Context, m *MGO) error {
+	return createIndexes(ctx, m.usersCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "pendingMailToken", Value: 1}},
+			Options: options.Index().SetName("pendingMailToken").SetPartialFilterExpression(bson.M{"pendingMailToken": bson.M{"$gt": ""}}),
+		},
+	})
+}
@@ -4,9 +4,19 @@ package store
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -24,34 +34,111 @@ import (
 type Store interface {
 	ListUsers(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (users *[]User, totalSize int32, nextToken string, err error)
 	CountUsers(ctx context.Context, printer *message.Printer, filterString string) (int32, error)
+	CountUsersByAttribute(ctx context.Context, printer *message.Printer, name string, value interface{}, excludeId string) (int32, error)
 	GetUser(ctx context.Context, printer *message.Printer, id string) (*User, error)
 	GetUserByUsername(ctx context.Context, printer *message.Printer, username string) (*User, error)
 	GetUserByMail(ctx context.Context, printer *message.Printer, mail string) (*User, error)
 	GetUserByConfirmToken(ctx context.Context, printer *message.Printer, token string) (*User, error)
 	GetUserByPasswordResetToken(ctx context.Context, printer *message.Printer, token string) (*User, error)
+	GetUserByInvitationToken(ctx context.Context, printer *message.Printer, token string) (*User, error)
+	GetUserByMailRevertToken(ctx context.Context, printer *message.Printer, token string) (*User, error)
+	GetUserByPendingMailToken(ctx context.Context, printer *message.Printer, token string) (*User, error)
 	SaveUser(ctx context.Context, printer *message.Printer, user *User) (*User, error)
 	DeleteUser(ctx context.Context, printer *message.Printer, id string) error
+	ListUsersScheduledForDeletion(ctx context.Context, printer *message.Printer, before time.Time) (*[]User, error)
+	RestoreUser(ctx context.Context, printer *message.Printer, id string) (*User, error)
+	RemoveUserAttribute(ctx context.Context, printer *message.Printer, name string) error
 	ListGroups(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (groups *[]Group, totalSize int32, nextToken string, err error)
 	CountGroups(ctx context.Context, printer *message.Printer, filterString string) (int32, error)
 	GetGroup(ctx context.Context, printer *message.Printer, id string) (*Group, error)
 	GetGroupByName(ctx context.Context, printer *message.Printer, name string) (*Group, error)
 	SaveGroup(ctx context.Context, printer *message.Printer, group *Group) (*Group, error)
 	DeleteGroup(ctx context.Context, printer *message.Printer, id string) error
+	RestoreGroup(ctx context.Context, printer *message.Printer, id string) (*Group, error)
+	PurgeDeleted(ctx context.Context, printer *message.Printer, deletedBefore time.Time) (int64, error)
+}
+
+// WebhookStore abstracts saving and receiving webhooks and their deliveries.
+type WebhookStore interface {
+	ListWebhooks(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (webhooks *[]Webhook, totalSize int32, nextToken string, err error)
+	GetWebhook(ctx context.Context, printer *message.Printer, id string) (*Webhook, error)
+	SaveWebhook(ctx context.Context, printer *message.Printer, webhook *Webhook) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, printer *message.Printer, id string) error
+	ListWebhookDeliveries(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (deliveries *[]WebhookDelivery, totalSize int32, nextToken string, err error)
+	SaveWebhookDelivery(ctx context.Context, printer *message.Printer, delivery *WebhookDelivery) (*WebhookDelivery, error)
+	ClaimWebhookDelivery(ctx context.Context, printer *message.Printer, lease time.Duration) (*WebhookDelivery, error)
+}
+
+// AuditStore abstracts saving and receiving audit events.
+type AuditStore interface {
+	ListAuditEvents(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (events *[]AuditEvent, totalSize int32, nextToken string, err error)
+	SaveAuditEvent(ctx context.Context, printer *message.Printer, event *AuditEvent) (*AuditEvent, error)
+}
+
+// ApiKeyStore abstracts saving and receiving api keys of service accounts.
+type ApiKeyStore interface {
+	ListApiKeys(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (apiKeys *[]ApiKey, totalSize int32, nextToken string, err error)
+	GetApiKey(ctx context.Context, printer *message.Printer, id string) (*ApiKey, error)
+	GetApiKeyByPrefix(ctx context.Context, printer *message.Printer, prefix string) (*ApiKey, error)
+	SaveApiKey(ctx context.Context, printer *message.Printer, apiKey *ApiKey) (*ApiKey, error)
+	TouchApiKey(ctx context.Context, printer *message.Printer, id string, lastUsedAt time.Time) error
+	DeleteApiKey(ctx context.Context, printer *message.Printer, id string) error
+	DeleteApiKeysOfServiceAccount(ctx context.Context, printer *message.Printer, serviceAccountId string) error
+}
+
+// AttributeStore abstracts saving and receiving the definitions of custom user attributes.
+type AttributeStore interface {
+	ListAttributeDefinitions(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (definitions *[]AttributeDefinition, totalSize int32, nextToken string, err error)
+	GetAttributeDefinition(ctx context.Context, printer *message.Printer, id string) (*AttributeDefinition, error)
+	GetAttributeDefinitionByName(ctx context.Context, printer *message.Printer, name string) (*AttributeDefinition, error)
+	SaveAttributeDefinition(ctx context.Context, printer *message.Printer, definition *AttributeDefinition) (*AttributeDefinition, error)
+	DeleteAttributeDefinition(ctx context.Context, printer *message.Printer, id string) error
+}
+
+// MailStore abstracts saving and receiving the mails of the outbound mail queue.
+type MailStore interface {
+	ListMails(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (mails *[]Mail, totalSize int32, nextToken string, err error)
+	GetMail(ctx context.Context, printer *message.Printer, id string) (*Mail, error)
+	SaveMail(ctx context.Context, printer *message.Printer, mail *Mail) (*Mail, error)
+	ClaimMail(ctx context.Context, printer *message.Printer, lease time.Duration) (*Mail, error)
+	PurgeFailedMails(ctx context.Context, printer *message.Printer, failedBefore time.Time) (int64, error)
 }
 
 // User represents a user document.
 type User struct {
-	Id                 string    `bson:"_id,omitempty"`
-	CreatedAt          time.Time `bson:"createdAt"`
-	UpdatedAt          time.Time `bson:"updatedAt"`
-	Username           string    `bson:"username"`
-	Mail               string    `bson:"mail"`
-	Password           string    `bson:"password,omitempty"`
-	Language           string    `bson:"language"`
-	Roles              []string  `bson:"roles,omitempty"`
-	Confirmed          bool      `bson:"confirmed"`
-	ConfirmToken       string    `bson:"confirmToken"`
-	PasswordResetToken string    `bson:"passwordResetToken"`
+	Id        string    `bson:"_id,omitempty"`
+	CreatedAt time.Time `bson:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+	Username  string    `bson:"username"`
+	Mail      string    `bson:"mail"`
+	// canonical forms of username and mail address used for lookups and unique indexes,
+	// they are set when saving the user and cleared when the user is deleted.
+	UsernameKey         string    `bson:"usernameKey"`
+	MailKey             string    `bson:"mailKey"`
+	Password            string    `bson:"password,omitempty"`
+	Language            string    `bson:"language"`
+	Roles               []string  `bson:"roles,omitempty"`
+	Confirmed           bool      `bson:"confirmed"`
+	ConfirmToken        string    `bson:"confirmToken"`
+	PasswordResetToken  string    `bson:"passwordResetToken"`
+	MailRevertToken     string    `bson:"mailRevertToken"`
+	PendingMail         string    `bson:"pendingMail"` // replaces the mail address once it is confirmed
+	PendingMailToken    string    `bson:"pendingMailToken"`
+	ServiceAccount      bool      `bson:"serviceAccount"`
+	Suspended           bool      `bson:"suspended"`
+	SuspensionReason    string    `bson:"suspensionReason"`
+	SuspendedUntil      time.Time `bson:"suspendedUntil"`
+	DeletionScheduledAt time.Time `bson:"deletionScheduledAt"`
+	InvitationToken     string    `bson:"invitationToken"`
+	InvitationExpiresAt time.Time `bson:"invitationExpiresAt"`
+	InvitationGroups    []string  `bson:"invitationGroups"`
+	InvitedBy           string    `bson:"invitedBy"`
+	// custom attributes, the values are strings, float64 or bools.
+	Attributes        map[string]interface{} `bson:"attributes"`
+	DeletedAt         time.Time              `bson:"deletedAt,omitempty"`
+	DeletedFromGroups []string               `bson:"deletedFromGroups"`
+	// incremented with every modification, used for optimistic concurrency control.
+	Revision int64 `bson:"revision"`
 }
 
 // Group represents a group document.
@@ -62,6 +149,150 @@ type Group struct {
 	Name      string    `bson:"name"`
 	Roles     []string  `bson:"roles,omitempty"`
 	Members   []string  `bson:"members,omitempty"`
+	DeletedAt time.Time `bson:"deletedAt,omitempty"`
+	// incremented with every modification, used for optimistic concurrency control.
+	Revision int64 `bson:"revision"`
+}
+
+// Webhook represents a webhook document.
+type Webhook struct {
+	Id        string    `bson:"_id,omitempty"`
+	CreatedAt time.Time `bson:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+	Url       string    `bson:"url"`
+	Events    []string  `bson:"events,omitempty"`
+	Secret    string    `bson:"secret"`
+	Active    bool      `bson:"active"`
+}
+
+// states of a webhook delivery.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery represents a webhook delivery document.
+// Every event results in one delivery per subscribed webhook.
+type WebhookDelivery struct {
+	Id            string    `bson:"_id,omitempty"`
+	CreatedAt     time.Time `bson:"createdAt"`
+	UpdatedAt     time.Time `bson:"updatedAt"`
+	WebhookId     string    `bson:"webhookId"`
+	Event         string    `bson:"event"`
+	Payload       string    `bson:"payload"`
+	State         string    `bson:"state"`
+	Attempts      int32     `bson:"attempts"`
+	NextAttemptAt time.Time `bson:"nextAttemptAt"`
+	ResponseCode  int32     `bson:"responseCode"`
+	LastError     string    `bson:"lastError"`
+}
+
+// ApiKey represents an api key document.
+// Only the hash of the secret part of the key is stored.
+type ApiKey struct {
+	Id               string    `bson:"_id,omitempty"`
+	CreatedAt        time.Time `bson:"createdAt"`
+	UpdatedAt        time.Time `bson:"updatedAt"`
+	ServiceAccountId string    `bson:"serviceAccountId"`
+	Name             string    `bson:"name"`
+	Prefix           string    `bson:"prefix"`
+	Hash             string    `bson:"hash"`
+	Scopes           []string  `bson:"scopes,omitempty"`
+	ExpiresAt        time.Time `bson:"expiresAt,omitempty"`
+	LastUsedAt       time.Time `bson:"lastUsedAt,omitempty"`
+}
+
+// types of custom attributes.
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+)
+
+// who is allowed to edit a custom attribute.
+const (
+	AttributeEditableByAdmin = "admin"
+	AttributeEditableBySelf  = "self"
+)
+
+// AttributeDefinition represents the definition of a custom user attribute.
+type AttributeDefinition struct {
+	Id         string    `bson:"_id,omitempty"`
+	CreatedAt  time.Time `bson:"createdAt"`
+	UpdatedAt  time.Time `bson:"updatedAt"`
+	Name       string    `bson:"name"`
+	Type       string    `bson:"type"`
+	Required   bool      `bson:"required"`
+	Unique     bool      `bson:"unique"`
+	Regex      string    `bson:"regex"`
+	EditableBy string    `bson:"editableBy"`
+}
+
+// states of a queued mail.
+const (
+	MailPending = "pending"
+	MailSent    = "sent"
+	MailFailed  = "failed"
+)
+
+// Mail represents a mail document of the outbound mail queue.
+// The bodies are removed once the mail has been sent, as they
+// might contain tokens.
+type Mail struct {
+	Id            string            `bson:"_id,omitempty"`
+	CreatedAt     time.Time         `bson:"createdAt"`
+	UpdatedAt     time.Time         `bson:"updatedAt"`
+	From          string            `bson:"from"`
+	To            []string          `bson:"to,omitempty"`
+	Cc            []string          `bson:"cc,omitempty"`
+	Bcc           []string          `bson:"bcc,omitempty"`
+	ReplyTo       string            `bson:"replyTo,omitempty"`
+	Subject       string            `bson:"subject"`
+	Text          string            `bson:"text"`
+	HTML          string            `bson:"html"`
+	Raw           string            `bson:"raw,omitempty"` // composed message, sent as is
+	Headers       map[string]string `bson:"headers,omitempty"`
+	Attachments   []MailAttachment  `bson:"attachments,omitempty"`
+	State         string            `bson:"state"`
+	Attempts      int32             `bson:"attempts"`
+	NextAttemptAt time.Time         `bson:"nextAttemptAt"`
+	LastError     string            `bson:"lastError"`
+}
+
+// MailAttachment represents a file attached to a queued mail.
+type MailAttachment struct {
+	Filename    string `bson:"filename"`
+	ContentType string `bson:"contentType"`
+	Data        []byte `bson:"data"`
+}
+
+// AuditEvent represents an audit event document.
+// Audit events are never updated.
+type AuditEvent struct {
+	Id             string        `bson:"_id,omitempty"`
+	CreatedAt      time.Time     `bson:"createdAt"`
+	ActorId        string        `bson:"actorId"`
+	Action         string        `bson:"action"`
+	ResourceType   string        `bson:"resourceType"`
+	ResourceId     string        `bson:"resourceId"`
+	Changes        []AuditChange `bson:"changes,omitempty"`
+	PeerAddress    string        `bson:"peerAddress"`
+	ImpersonatorId string        `bson:"impersonatorId,omitempty"`
+}
+
+// AuditChange represents a changed field of an audit event.
+type AuditChange struct {
+	Field    string `bson:"field"`
+	OldValue string `bson:"oldValue"`
+	NewValue string `bson:"newValue"`
+}
+
+// LookupKey returns the canonical form of the given username or mail address, which is used
+// to look up users and to ensure uniqueness. Unicode compatibility variants are normalised
+// and the case is folded, so John@Example.com and john@example.com have the same key.
+func LookupKey(s string) string {
+	return norm.NFKC.String(cases.Fold().String(norm.NFKC.String(strings.TrimSpace(s))))
 }
 
 // ValidatePassword checks if the given plain text password
@@ -74,6 +305,12 @@ func (u *User) ValidatePassword(plain string) bool {
 	return true
 }
 
+// IsSuspended checks if the user is suspended at the given time.
+// A suspension without end date lasts until the user is reactivated.
+func (u *User) IsSuspended(now time.Time) bool {
+	return u.Suspended && (u.SuspendedUntil.IsZero() || now.Before(u.SuspendedUntil))
+}
+
 // HasRole checks if the user has the given role.
 func (u *User) HasRole(role string) bool {
 	for _, r := range u.Roles {
@@ -132,6 +369,49 @@ func (u *User) ValidateConfirmToken(printer *message.Printer, key, token string)
 	return nil
 }
 
+// GeneratePendingMailToken sets the given mail address as pending mail address of the user
+// and generates a new token to confirm it. The token is assigned to the user, however
+// the user has to be saved to the database after generating the token.
+func (u *User) GeneratePendingMailToken(printer *message.Printer, key, mail string) error {
+	c := Confirmation{
+		Mail:      mail,
+		CreatedAt: time.Now(),
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return status.Errorf(codes.Internal, printer.Sprintf("unable to json marshal confirmation: %s", err))
+	}
+	enc, err := utils.Encrypt(key, string(b))
+	if err != nil {
+		return status.Errorf(codes.Internal, printer.Sprintf("unable to encrypt confirmation: %s", err))
+	}
+	u.PendingMail = mail
+	u.PendingMailToken = enc
+	return nil
+}
+
+// ValidatePendingMailToken checks if the given token confirms the pending mail address of the user.
+func (u *User) ValidatePendingMailToken(printer *message.Printer, key, token string) error {
+	if token == "" {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("no token given"))
+	}
+	if subtle.ConstantTimeCompare([]byte(u.PendingMailToken), []byte(token)) != 1 {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("token mismatch"))
+	}
+	msg, err := utils.Decrypt(key, token)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid token"))
+	}
+	c := &Confirmation{}
+	if err := json.Unmarshal([]byte(msg), c); err != nil {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid token"))
+	}
+	if u.PendingMail == "" || u.PendingMail != c.Mail {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid token"))
+	}
+	return nil
+}
+
 // ResetPassword provides the content for the reset password token.
 type ResetPassword struct {
 	CreatedAt time.Time
@@ -156,22 +436,202 @@ func (u *User) GeneratePasswordResetToken(printer *message.Printer, key string)
 	return nil
 }
 
+// MailRevert provides the content for the token to revert a mail address change.
+type MailRevert struct {
+	Mail              string
+	PreviousMail      string
+	PreviousConfirmed bool
+	ExpiresAt         time.Time
+}
+
+// GenerateMailRevertToken generates a new token to revert the change of the users
+// mail address from the given previous one, or to cancel the change to the pending
+// mail address if there is one. The token expires at the given time.
+// The token is assigned to the user, however the user has to be saved to the
+// database after generating the token.
+func (u *User) GenerateMailRevertToken(printer *message.Printer, key, previousMail string, previousConfirmed bool, expiresAt time.Time) error {
+	mail := u.Mail
+	if u.PendingMail != "" {
+		mail = u.PendingMail
+	}
+	r := MailRevert{
+		Mail:              mail,
+		PreviousMail:      previousMail,
+		PreviousConfirmed: previousConfirmed,
+		ExpiresAt:         expiresAt,
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return status.Errorf(codes.Internal, printer.Sprintf("unable to json marshal mail revert: %s", err))
+	}
+	enc, err := utils.Encrypt(key, string(b))
+	if err != nil {
+		return status.Errorf(codes.Internal, printer.Sprintf("unable to encrypt mail revert: %s", err))
+	}
+	u.MailRevertToken = enc
+	return nil
+}
+
+// ValidateMailRevertToken checks if the given mail revert token is valid for the user
+// and has not expired at the given time. It returns the content of the token.
+// The token is valid for a changed mail address as well as for a pending change.
+func (u *User) ValidateMailRevertToken(printer *message.Printer, key, token string, now time.Time) (*MailRevert, error) {
+	if token == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("no token given"))
+	}
+	if subtle.ConstantTimeCompare([]byte(u.MailRevertToken), []byte(token)) != 1 {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("token mismatch"))
+	}
+	msg, err := utils.Decrypt(key, token)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid token"))
+	}
+	r := &MailRevert{}
+	if err := json.Unmarshal([]byte(msg), r); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid token"))
+	}
+	changed := u.Mail == r.Mail
+	pending := u.PendingMail != "" && u.PendingMail == r.Mail && u.Mail == r.PreviousMail
+	if (!changed && !pending) || r.PreviousMail == "" {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid token"))
+	}
+	if !now.Before(r.ExpiresAt) {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("token expired"))
+	}
+	return r, nil
+}
+
+// Invitation provides the content for the invitation token.
+type Invitation struct {
+	Mail      string
+	ExpiresAt time.Time
+	Nonce     string
+}
+
+// InvitationPending checks if the user was invited and has not accepted the invitation yet.
+func (u *User) InvitationPending() bool {
+	return u.InvitationToken != ""
+}
+
+// signInvitation returns the hex encoded HMAC-SHA256 of the given payload.
+func signInvitation(key, payload string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateInvitationToken generates a new signed invitation token for the given user
+// which expires at the given time. The token is assigned to the user, however the
+// user has to be saved to the database after generating the token.
+func (u *User) GenerateInvitationToken(printer *message.Printer, key string, expiresAt time.Time) error {
+	nonce, err := utils.RandomToken(16)
+	if err != nil {
+		return status.Errorf(codes.Internal, printer.Sprintf("unable to generate invitation nonce: %s", err))
+	}
+	i := Invitation{
+		Mail:      u.Mail,
+		ExpiresAt: expiresAt,
+		Nonce:     nonce,
+	}
+	b, err := json.Marshal(i)
+	if err != nil {
+		return status.Errorf(codes.Internal, printer.Sprintf("unable to json marshal invitation: %s", err))
+	}
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	u.InvitationToken = payload + "." + signInvitation(key, payload)
+	u.InvitationExpiresAt = expiresAt
+	return nil
+}
+
+// ValidateInvitationToken checks if the given invitation token is valid
+// for the user and has not expired at the given time.
+func (u *User) ValidateInvitationToken(printer *message.Printer, key, token string, now time.Time) error {
+	if token == "" {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("no token given"))
+	}
+	if subtle.ConstantTimeCompare([]byte(u.InvitationToken), []byte(token)) != 1 {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("token mismatch"))
+	}
+	ss := strings.SplitN(token, ".", 2)
+	if len(ss) != 2 || !hmac.Equal([]byte(ss[1]), []byte(signInvitation(key, ss[0]))) {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid token"))
+	}
+	b, err := base64.RawURLEncoding.DecodeString(ss[0])
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid token"))
+	}
+	i := &Invitation{}
+	if err := json.Unmarshal(b, i); err != nil {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid token"))
+	}
+	if u.Mail != i.Mail {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid token"))
+	}
+	if !now.Before(i.ExpiresAt) {
+		return status.Errorf(codes.FailedPrecondition, printer.Sprintf("invitation expired"))
+	}
+	return nil
+}
+
+// Etag returns an opaque string representing the given revision.
+func Etag(revision int64) string {
+	return strconv.FormatInt(revision, 36)
+}
+
+// RevisionFromEtag returns the revision represented by the given etag.
+// It returns 0 for an empty etag and -1 if the etag is not valid.
+func RevisionFromEtag(etag string) int64 {
+	if etag == "" {
+		return 0
+	}
+	revision, err := strconv.ParseInt(etag, 36, 64)
+	if err != nil {
+		return -1
+	}
+	return revision
+}
+
 // ToPb returns a protobuf representation of the user.
 func (u *User) ToPb() *gooserv1.User {
 	createdAt, _ := ptypes.TimestampProto(u.CreatedAt)
 	updatedAt, _ := ptypes.TimestampProto(u.UpdatedAt)
-	return &gooserv1.User{
-		Id:        u.Id,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
-		Username:  u.Username,
-		Mail:      u.Mail,
-		Roles:     u.Roles,
-		Confirmed: u.Confirmed,
-		Language:  u.Language,
+	res := &gooserv1.User{
+		Id:               u.Id,
+		CreatedAt:        createdAt,
+		UpdatedAt:        updatedAt,
+		Username:         u.Username,
+		Mail:             u.Mail,
+		Roles:            u.Roles,
+		Confirmed:        u.Confirmed,
+		Language:         u.Language,
+		ServiceAccount:   u.ServiceAccount,
+		Suspended:        u.Suspended,
+		SuspensionReason: u.SuspensionReason,
+		InvitedBy:        u.InvitedBy,
+		PendingMail:      u.PendingMail,
+		Etag:             Etag(u.Revision),
 		// do not return password
 		// Password: u.Password,
 	}
+	if !u.SuspendedUntil.IsZero() {
+		res.SuspendedUntil, _ = ptypes.TimestampProto(u.SuspendedUntil)
+	}
+	if !u.DeletionScheduledAt.IsZero() {
+		res.DeletionScheduledAt, _ = ptypes.TimestampProto(u.DeletionScheduledAt)
+	}
+	if u.InvitationPending() {
+		res.InvitationPending = true
+		res.InvitationExpiresAt, _ = ptypes.TimestampProto(u.InvitationExpiresAt)
+	}
+	if len(u.Attributes) > 0 {
+		res.Attributes = make(map[string]*gooserv1.AttributeValue, len(u.Attributes))
+		for name, v := range u.Attributes {
+			if value := AttributeValueToPb(v); value != nil {
+				res.Attributes[name] = value
+			}
+		}
+	}
+	return res
 }
 
 // PbToUser converts the given protobuf user into a
@@ -179,17 +639,77 @@ func (u *User) ToPb() *gooserv1.User {
 func PbToUser(u *gooserv1.User) *User {
 	createdAt, _ := ptypes.Timestamp(u.CreatedAt)
 	updatedAt, _ := ptypes.Timestamp(u.UpdatedAt)
-	return &User{
-		Id:        u.GetId(),
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
-		Username:  u.GetUsername(),
-		Mail:      u.GetMail(),
-		Roles:     u.Roles,
-		Confirmed: u.GetConfirmed(),
-		Password:  u.GetPassword(),
-		Language:  u.Language,
+	res := &User{
+		Id:               u.GetId(),
+		CreatedAt:        createdAt,
+		UpdatedAt:        updatedAt,
+		Username:         u.GetUsername(),
+		Mail:             u.GetMail(),
+		Roles:            u.Roles,
+		Confirmed:        u.GetConfirmed(),
+		Password:         u.GetPassword(),
+		Language:         u.Language,
+		ServiceAccount:   u.GetServiceAccount(),
+		Suspended:        u.GetSuspended(),
+		SuspensionReason: u.GetSuspensionReason(),
+		InvitedBy:        u.GetInvitedBy(),
+		Revision:         RevisionFromEtag(u.GetEtag()),
 	}
+	if u.GetSuspendedUntil() != nil {
+		res.SuspendedUntil, _ = ptypes.Timestamp(u.GetSuspendedUntil())
+	}
+	if u.GetDeletionScheduledAt() != nil {
+		res.DeletionScheduledAt, _ = ptypes.Timestamp(u.GetDeletionScheduledAt())
+	}
+	res.Attributes = PbToAttributes(u.GetAttributes())
+	return res
+}
+
+// PbToAttributes converts the given protobuf attributes into store attributes.
+// Attributes without value are omitted.
+func PbToAttributes(attributes map[string]*gooserv1.AttributeValue) map[string]interface{} {
+	if len(attributes) == 0 {
+		return nil
+	}
+	res := make(map[string]interface{}, len(attributes))
+	for name, v := range attributes {
+		if value := PbToAttributeValue(v); value != nil {
+			res[name] = value
+		}
+	}
+	return res
+}
+
+// AttributeValueToPb converts the given attribute value into its protobuf representation.
+// It returns nil if the value is not of a supported type.
+func AttributeValueToPb(v interface{}) *gooserv1.AttributeValue {
+	switch value := v.(type) {
+	case string:
+		return &gooserv1.AttributeValue{Value: &gooserv1.AttributeValue_StringValue{StringValue: value}}
+	case float64:
+		return &gooserv1.AttributeValue{Value: &gooserv1.AttributeValue_NumberValue{NumberValue: value}}
+	case int32:
+		return &gooserv1.AttributeValue{Value: &gooserv1.AttributeValue_NumberValue{NumberValue: float64(value)}}
+	case int64:
+		return &gooserv1.AttributeValue{Value: &gooserv1.AttributeValue_NumberValue{NumberValue: float64(value)}}
+	case bool:
+		return &gooserv1.AttributeValue{Value: &gooserv1.AttributeValue_BoolValue{BoolValue: value}}
+	}
+	return nil
+}
+
+// PbToAttributeValue converts the given protobuf attribute value into
+// a string, float64 or bool. It returns nil if no value is set.
+func PbToAttributeValue(v *gooserv1.AttributeValue) interface{} {
+	switch value := v.GetValue().(type) {
+	case *gooserv1.AttributeValue_StringValue:
+		return value.StringValue
+	case *gooserv1.AttributeValue_NumberValue:
+		return value.NumberValue
+	case *gooserv1.AttributeValue_BoolValue:
+		return value.BoolValue
+	}
+	return nil
 }
 
 // ToPb returns a protobuf representation of the group.
@@ -203,6 +723,7 @@ func (g *Group) ToPb() *gooserv1.Group {
 		Name:      g.Name,
 		Roles:     g.Roles,
 		Members:   g.Members,
+		Etag:      Etag(g.Revision),
 	}
 }
 
@@ -218,5 +739,178 @@ func PbToGroup(g *gooserv1.Group) *Group {
 		Name:      g.GetName(),
 		Roles:     g.GetRoles(),
 		Members:   g.GetMembers(),
+		Revision:  RevisionFromEtag(g.GetEtag()),
+	}
+}
+
+// ToPb returns a protobuf representation of the webhook.
+// The secret is not part of the result.
+func (w *Webhook) ToPb() *gooserv1.Webhook {
+	createdAt, _ := ptypes.TimestampProto(w.CreatedAt)
+	updatedAt, _ := ptypes.TimestampProto(w.UpdatedAt)
+	return &gooserv1.Webhook{
+		Id:        w.Id,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		Url:       w.Url,
+		Events:    w.Events,
+		Active:    w.Active,
+		// do not return secret
+		// Secret: w.Secret,
+	}
+}
+
+// PbToWebhook converts the given protobuf webhook into
+// a store webhook.
+func PbToWebhook(w *gooserv1.Webhook) *Webhook {
+	createdAt, _ := ptypes.Timestamp(w.CreatedAt)
+	updatedAt, _ := ptypes.Timestamp(w.UpdatedAt)
+	return &Webhook{
+		Id:        w.GetId(),
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		Url:       w.GetUrl(),
+		Events:    w.GetEvents(),
+		Secret:    w.GetSecret(),
+		Active:    w.GetActive(),
+	}
+}
+
+// ToPb returns a protobuf representation of the webhook delivery.
+func (d *WebhookDelivery) ToPb() *gooserv1.WebhookDelivery {
+	createdAt, _ := ptypes.TimestampProto(d.CreatedAt)
+	updatedAt, _ := ptypes.TimestampProto(d.UpdatedAt)
+	nextAttemptAt, _ := ptypes.TimestampProto(d.NextAttemptAt)
+	return &gooserv1.WebhookDelivery{
+		Id:            d.Id,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+		WebhookId:     d.WebhookId,
+		Event:         d.Event,
+		Payload:       d.Payload,
+		State:         d.State,
+		Attempts:      d.Attempts,
+		NextAttemptAt: nextAttemptAt,
+		ResponseCode:  d.ResponseCode,
+		LastError:     d.LastError,
+	}
+}
+
+// ToPb returns a protobuf representation of the api key.
+// Neither the key nor its hash are part of the result.
+func (k *ApiKey) ToPb() *gooserv1.ApiKey {
+	createdAt, _ := ptypes.TimestampProto(k.CreatedAt)
+	updatedAt, _ := ptypes.TimestampProto(k.UpdatedAt)
+	res := &gooserv1.ApiKey{
+		Id:               k.Id,
+		CreatedAt:        createdAt,
+		UpdatedAt:        updatedAt,
+		ServiceAccountId: k.ServiceAccountId,
+		Name:             k.Name,
+		Prefix:           k.Prefix,
+		Scopes:           k.Scopes,
+	}
+	if !k.ExpiresAt.IsZero() {
+		res.ExpiresAt, _ = ptypes.TimestampProto(k.ExpiresAt)
+	}
+	if !k.LastUsedAt.IsZero() {
+		res.LastUsedAt, _ = ptypes.TimestampProto(k.LastUsedAt)
+	}
+	return res
+}
+
+// Expired checks if the api key has expired at the given time.
+func (k *ApiKey) Expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+// HasScope checks if the api key may be used to call the given rpc method.
+func (k *ApiKey) HasScope(method string) bool {
+	for _, s := range k.Scopes {
+		if s == "*" || s == method {
+			return true
+		}
+	}
+	return false
+}
+
+// ToPb returns a protobuf representation of the attribute definition.
+func (d *AttributeDefinition) ToPb() *gooserv1.AttributeDefinition {
+	createdAt, _ := ptypes.TimestampProto(d.CreatedAt)
+	updatedAt, _ := ptypes.TimestampProto(d.UpdatedAt)
+	return &gooserv1.AttributeDefinition{
+		Id:         d.Id,
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+		Name:       d.Name,
+		Type:       d.Type,
+		Required:   d.Required,
+		Unique:     d.Unique,
+		Regex:      d.Regex,
+		EditableBy: d.EditableBy,
+	}
+}
+
+// PbToAttributeDefinition converts the given protobuf attribute definition
+// into a store attribute definition.
+func PbToAttributeDefinition(d *gooserv1.AttributeDefinition) *AttributeDefinition {
+	createdAt, _ := ptypes.Timestamp(d.CreatedAt)
+	updatedAt, _ := ptypes.Timestamp(d.UpdatedAt)
+	return &AttributeDefinition{
+		Id:         d.GetId(),
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+		Name:       d.GetName(),
+		Type:       d.GetType(),
+		Required:   d.GetRequired(),
+		Unique:     d.GetUnique(),
+		Regex:      d.GetRegex(),
+		EditableBy: d.GetEditableBy(),
+	}
+}
+
+// ToPb returns a protobuf representation of the mail.
+// The bodies are not part of the result.
+func (m *Mail) ToPb() *gooserv1.Mail {
+	createdAt, _ := ptypes.TimestampProto(m.CreatedAt)
+	updatedAt, _ := ptypes.TimestampProto(m.UpdatedAt)
+	nextAttemptAt, _ := ptypes.TimestampProto(m.NextAttemptAt)
+	return &gooserv1.Mail{
+		Id:            m.Id,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+		From:          m.From,
+		To:            m.To,
+		Cc:            m.Cc,
+		Bcc:           m.Bcc,
+		Subject:       m.Subject,
+		State:         m.State,
+		Attempts:      m.Attempts,
+		NextAttemptAt: nextAttemptAt,
+		LastError:     m.LastError,
+	}
+}
+
+// ToPb returns a protobuf representation of the audit event.
+func (e *AuditEvent) ToPb() *gooserv1.AuditEvent {
+	createdAt, _ := ptypes.TimestampProto(e.CreatedAt)
+	var changes []*gooserv1.AuditChange
+	for _, c := range e.Changes {
+		changes = append(changes, &gooserv1.AuditChange{
+			Field:    c.Field,
+			OldValue: c.OldValue,
+			NewValue: c.NewValue,
+		})
+	}
+	return &gooserv1.AuditEvent{
+		Id:             e.Id,
+		CreatedAt:      createdAt,
+		ActorId:        e.ActorId,
+		Action:         e.Action,
+		ResourceType:   e.ResourceType,
+		ResourceId:     e.ResourceId,
+		Changes:        changes,
+		PeerAddress:    e.PeerAddress,
+		ImpersonatorId: e.ImpersonatorId,
 	}
 }
@@ -0,0 +1,205 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TestLookupKey tests the LookupKey function.
+func TestLookupKey(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{
+			name: "lowercase",
+			s:    "user1",
+			want: "user1",
+		},
+		{
+			name: "mixed case mail",
+			s:    "User1@Testing.COM",
+			want: "user1@testing.com",
+		},
+		{
+			name: "surrounding spaces",
+			s:    " user1@testing.com ",
+			want: "user1@testing.com",
+		},
+		{
+			name: "composed and decomposed",
+			s:    "José@testing.com",
+			want: "josé@testing.com",
+		},
+		{
+			name: "compatibility characters",
+			s:    "ｕser1",
+			want: "user1",
+		},
+		{
+			name: "sharp s",
+			s:    "STRASSE",
+			want: LookupKey("straße"),
+		},
+		{
+			name: "empty",
+			s:    "",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LookupKey(tt.s); got != tt.want {
+				t.Errorf("LookupKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIsDuplicateKeyError tests the isDuplicateKeyError function.
+func TestIsDuplicateKeyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  errors.New("some error"),
+			want: false,
+		},
+		{
+			name: "write exception",
+			err: mongo.WriteException{
+				WriteErrors: mongo.WriteErrors{{Code: 11000}},
+			},
+			want: true,
+		},
+		{
+			name: "other write exception",
+			err: mongo.WriteException{
+				WriteErrors: mongo.WriteErrors{{Code: 121}},
+			},
+			want: false,
+		},
+		{
+			name: "command error",
+			err:  mongo.CommandError{Code: 11000},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDuplicateKeyError(tt.err); got != tt.want {
+				t.Errorf("isDuplicateKeyError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMigrations tests that the versions of the known migrations are consecutive.
+func TestMigrations(t *testing.T) {
+	if err := validateMigrations(migrations); err != nil {
+		t.Errorf("validateMigrations() = %v", err)
+	}
+}
+
+// TestValidateMigrations tests the validateMigrations function.
+func TestValidateMigrations(t *testing.T) {
+	up := func(ctx context.Context, m *MGO) error { return nil }
+	tests := []struct {
+		name    string
+		ms      []migration
+		wantErr bool
+	}{
+		{
+			name: "consecutive",
+			ms:   []migration{{version: 1, up: up}, {version: 2, up: up}},
+		},
+		{
+			name:    "not starting at 1",
+			ms:      []migration{{version: 2, up: up}},
+			wantErr: true,
+		},
+		{
+			name:    "gap",
+			ms:      []migration{{version: 1, up: up}, {version: 3, up: up}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate",
+			ms:      []migration{{version: 1, up: up}, {version: 1, up: up}},
+			wantErr: true,
+		},
+		{
+			name:    "no up function",
+			ms:      []migration{{version: 1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateMigrations(tt.ms); (err != nil) != tt.wantErr {
+				t.Errorf("validateMigrations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestMigrationStatus tests the migrationStatus function.
+func TestMigrationStatus(t *testing.T) {
+	assert := assert.New(t)
+	appliedAt := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+	ms := []migration{
+		{version: 1, description: "first"},
+		{version: 2, description: "second"},
+	}
+	got := migrationStatus(ms, map[int]time.Time{1: appliedAt})
+	assert.Equal([]MigrationStatus{
+		{Version: 1, Description: "first", Applied: true, AppliedAt: appliedAt},
+		{Version: 2, Description: "second"},
+	}, got)
+}
+
+// TestRevisionFromEtag tests the RevisionFromEtag function.
+func TestRevisionFromEtag(t *testing.T) {
+	tests := []struct {
+		name string
+		etag string
+		want int64
+	}{
+		{
+			name: "empty",
+			etag: "",
+			want: 0,
+		},
+		{
+			name: "round trip",
+			etag: Etag(1234),
+			want: 1234,
+		},
+		{
+			name: "invalid",
+			etag: "not an etag",
+			want: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RevisionFromEtag(tt.etag); got != tt.want {
+				t.Errorf("RevisionFromEtag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -17,6 +18,8 @@ import (
 // ListUsers lists users from the mongo db.
 // It returns the documents, the total size of documents for the given filter and a grpc status type error if anything goes wrong.
 func (m *MGO) ListUsers(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (users *[]User, totalSize int32, nextToken string, err error) {
+	ctx, end := m.instrument(ctx, "ListUsers")
+	defer end()
 	cur, total, err := m.queryDocuments(
 		ctx,
 		printer,
@@ -30,9 +33,10 @@ func (m *MGO) ListUsers(ctx context.Context, printer *message.Printer, filterStr
 		return nil, 0, "", err
 	}
 	defer cur.Close(ctx)
+	users = &[]User{}
 	var u User
-
 	for cur.Next(ctx) {
+		u = User{}
 		err = cur.Decode(&u)
 		if err != nil {
 			return nil, 0, "", status.Errorf(codes.Internal, printer.Sprintf("unable to decode user: %s", err))
@@ -41,7 +45,7 @@ func (m *MGO) ListUsers(ctx context.Context, printer *message.Printer, filterStr
 	}
 	// if there might be more results
 	l := int32(len(*users))
-	if size == l && totalSize > l {
+	if size == l && total > l {
 		nextToken, err = m.NextPageToken(
 			ctx,
 			printer,
@@ -54,21 +58,44 @@ func (m *MGO) ListUsers(ctx context.Context, printer *message.Printer, filterStr
 			return nil, 0, "", err
 		}
 	}
-	return users, total, nextToken, err
+	return users, total, nextToken, nil
 }
 
 // CountUsers returns the number of user documents corresponding to the given filter.
 func (m *MGO) CountUsers(ctx context.Context, printer *message.Printer, filterString string) (int32, error) {
+	ctx, end := m.instrument(ctx, "CountUsers")
+	defer end()
 	filter, err := m.bsonDocFromRsqlString(printer, filterString)
 	if err != nil {
 		return 0, err
 	}
-	count, err := m.usersCollection.CountDocuments(ctx, filter, nil)
+	count, err := m.usersCollection.CountDocuments(ctx, notDeleted(filter), nil)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return 0, nil
 		}
-		m.errorLogger.Printf("unable to count users: %s", err)
+		m.log(ctx).Error("unable to count users", zap.Error(err))
+		return 0, status.Errorf(codes.Internal, printer.Sprintf("unable to count users"))
+	}
+	return int32(count), nil
+}
+
+// CountUsersByAttribute counts the users whose custom attribute with the given name has the given value.
+// The user with the given id is not counted, if the id is not empty.
+func (m *MGO) CountUsersByAttribute(ctx context.Context, printer *message.Printer, name string, value interface{}, excludeId string) (int32, error) {
+	ctx, end := m.instrument(ctx, "CountUsersByAttribute")
+	defer end()
+	filter := bson.M{"attributes." + name: value}
+	if excludeId != "" {
+		oid, err := primitive.ObjectIDFromHex(excludeId)
+		if err != nil {
+			return 0, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid id '%s'", excludeId))
+		}
+		filter["_id"] = bson.M{"$ne": oid}
+	}
+	count, err := m.usersCollection.CountDocuments(ctx, notDeleted(filter), nil)
+	if err != nil {
+		m.log(ctx).Error("unable to count users", zap.Error(err))
 		return 0, status.Errorf(codes.Internal, printer.Sprintf("unable to count users"))
 	}
 	return int32(count), nil
@@ -77,6 +104,8 @@ func (m *MGO) CountUsers(ctx context.Context, printer *message.Printer, filterSt
 // GetUser gets the user with the given id from the mongo db.
 // It returns a grpc status type error if anything goes wrong.
 func (m *MGO) GetUser(ctx context.Context, printer *message.Printer, id string) (*User, error) {
+	ctx, end := m.instrument(ctx, "GetUser")
+	defer end()
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid id '%s'", id))
@@ -86,7 +115,7 @@ func (m *MGO) GetUser(ctx context.Context, printer *message.Printer, id string)
 	if ctx.Err() == context.Canceled {
 		return nil, status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
 	}
-	if err := m.usersCollection.FindOne(ctx, filter).Decode(u); err != nil {
+	if err := m.usersCollection.FindOne(ctx, notDeleted(filter)).Decode(u); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, status.Errorf(codes.NotFound, printer.Sprintf("unable to find user with id %s", id))
 		}
@@ -102,7 +131,7 @@ func (m *MGO) getUser(ctx context.Context, printer *message.Printer, filter bson
 	if ctx.Err() == context.Canceled {
 		return nil, status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
 	}
-	if err := m.usersCollection.FindOne(ctx, filter).Decode(u); err != nil {
+	if err := m.usersCollection.FindOne(ctx, notDeleted(filter)).Decode(u); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, status.Errorf(codes.NotFound, printer.Sprintf("unable to find user"))
 		}
@@ -112,22 +141,30 @@ func (m *MGO) getUser(ctx context.Context, printer *message.Printer, filter bson
 }
 
 // GetUserByUsername gets the user with the given username from the mongo db.
+// The username is compared case-insensitively.
 // It returns a grpc status type error if anything goes wrong.
 func (m *MGO) GetUserByUsername(ctx context.Context, printer *message.Printer, username string) (*User, error) {
-	filter := bson.M{"username": username}
+	ctx, end := m.instrument(ctx, "GetUserByUsername")
+	defer end()
+	filter := bson.M{"usernameKey": LookupKey(username)}
 	return m.getUser(ctx, printer, filter)
 }
 
 // GetUserByMail gets the user with the given mail.
+// The mail address is compared case-insensitively.
 // It returns a grpc status type error if anything goes wrong.
 func (m *MGO) GetUserByMail(ctx context.Context, printer *message.Printer, mail string) (*User, error) {
-	filter := bson.M{"mail": mail}
+	ctx, end := m.instrument(ctx, "GetUserByMail")
+	defer end()
+	filter := bson.M{"mailKey": LookupKey(mail)}
 	return m.getUser(ctx, printer, filter)
 }
 
 // GetUserByConfirmToken gets the user with the confirmation token.
 // It returns a grpc status type error if anything goes wrong.
 func (m *MGO) GetUserByConfirmToken(ctx context.Context, printer *message.Printer, token string) (*User, error) {
+	ctx, end := m.instrument(ctx, "GetUserByConfirmToken")
+	defer end()
 	filter := bson.M{"confirmToken": token}
 	return m.getUser(ctx, printer, filter)
 }
@@ -135,16 +172,52 @@ func (m *MGO) GetUserByConfirmToken(ctx context.Context, printer *message.Printe
 // GetUserByPasswordResetToken gets the user with the given token.
 // It returns a grpc status type error if anything goes wrong.
 func (m *MGO) GetUserByPasswordResetToken(ctx context.Context, printer *message.Printer, token string) (*User, error) {
+	ctx, end := m.instrument(ctx, "GetUserByPasswordResetToken")
+	defer end()
 	filter := bson.M{"passwordResetToken": token}
 	return m.getUser(ctx, printer, filter)
 }
 
+// GetUserByInvitationToken gets the user with the given invitation token.
+// It returns a grpc status type error if anything goes wrong.
+func (m *MGO) GetUserByInvitationToken(ctx context.Context, printer *message.Printer, token string) (*User, error) {
+	ctx, end := m.instrument(ctx, "GetUserByInvitationToken")
+	defer end()
+	filter := bson.M{"invitationToken": token}
+	return m.getUser(ctx, printer, filter)
+}
+
+// GetUserByMailRevertToken gets the user with the given token to revert a mail address change.
+// It returns a grpc status type error if anything goes wrong.
+func (m *MGO) GetUserByMailRevertToken(ctx context.Context, printer *message.Printer, token string) (*User, error) {
+	ctx, end := m.instrument(ctx, "GetUserByMailRevertToken")
+	defer end()
+	filter := bson.M{"mailRevertToken": token}
+	return m.getUser(ctx, printer, filter)
+}
+
+// GetUserByPendingMailToken gets the user with the given token to confirm a pending mail address.
+// It returns a grpc status type error if anything goes wrong.
+func (m *MGO) GetUserByPendingMailToken(ctx context.Context, printer *message.Printer, token string) (*User, error) {
+	ctx, end := m.instrument(ctx, "GetUserByPendingMailToken")
+	defer end()
+	filter := bson.M{"pendingMailToken": token}
+	return m.getUser(ctx, printer, filter)
+}
+
 // SaveUser stores the given user in the database.
 // The users id will be used to determine if a new user has to be created
-// or an existing one can be updated.
+// or an existing one can be updated. An existing user is only updated if its
+// revision matches the stored one, otherwise an aborted error is returned.
+// If the username or mail address is already taken, an already exists error is returned.
 func (m *MGO) SaveUser(ctx context.Context, printer *message.Printer, user *User) (*User, error) {
+	ctx, end := m.instrument(ctx, "SaveUser")
+	defer end()
 	var err error
 	var oid primitive.ObjectID
+	var filter bson.M
+	opts := options.FindOneAndUpdate()
+	opts.SetReturnDocument(options.After)
 	user.UpdatedAt = time.Now()
 	if user.Id != "" {
 		oid, err = primitive.ObjectIDFromHex(user.Id)
@@ -152,26 +225,42 @@ func (m *MGO) SaveUser(ctx context.Context, printer *message.Printer, user *User
 			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid user id '%s'", user.Id))
 		}
 		user.Id = ""
+		filter = bson.M{"_id": oid, "revision": user.Revision}
+		user.Revision++
 	} else {
 		oid = primitive.NewObjectID()
 		user.CreatedAt = user.UpdatedAt
+		filter = bson.M{"_id": oid}
+		opts.SetUpsert(true)
+		user.Revision = 1
+	}
+	// deleted users do not block their username and mail address
+	user.UsernameKey, user.MailKey = "", ""
+	if user.DeletedAt.IsZero() {
+		user.UsernameKey = LookupKey(user.Username)
+		user.MailKey = LookupKey(user.Mail)
 	}
-	opts := options.FindOneAndUpdate()
-	opts.SetUpsert(true)
-	opts.SetReturnDocument(options.After)
-	filter := bson.M{"_id": oid}
 	doc := bson.M{"$set": user}
 	u := &User{}
 	err = m.usersCollection.FindOneAndUpdate(ctx, filter, doc, opts).Decode(u)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.Aborted, printer.Sprintf("user was modified or deleted in the meantime"))
+	}
+	if isDuplicateKeyError(err) {
+		return nil, status.Errorf(codes.AlreadyExists, printer.Sprintf("username or mail address is already taken"))
+	}
 	if err != nil {
-		m.errorLogger.Printf("error while saving user: %s", err)
+		m.log(ctx).Error("error while saving user", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while saving user"))
 	}
 	return u, nil
 }
 
-// DeleteUser deletes the user with the given id in mongo db.
+// DeleteUser soft deletes the user with the given id in mongo db. The user is
+// excluded from all queries but can be restored until it is purged.
 func (m *MGO) DeleteUser(ctx context.Context, printer *message.Printer, id string) error {
+	ctx, end := m.instrument(ctx, "DeleteUser")
+	defer end()
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid user id"))
@@ -180,12 +269,110 @@ func (m *MGO) DeleteUser(ctx context.Context, printer *message.Printer, id strin
 	if ctx.Err() == context.Canceled {
 		return status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
 	}
-	res, err := m.usersCollection.DeleteOne(ctx, filter)
+	// the lookup keys are cleared to release username and mail address
+	doc := bson.M{
+		"$set": bson.M{"deletedAt": time.Now(), "usernameKey": "", "mailKey": ""},
+		"$inc": bson.M{"revision": 1},
+	}
+	res, err := m.usersCollection.UpdateOne(ctx, notDeleted(filter), doc)
 	if err != nil {
+		m.log(ctx).Error("unable to delete user", zap.Error(err))
 		return status.Errorf(codes.Internal, "unable to delete user")
 	}
-	if res.DeletedCount != 1 {
+	if res.MatchedCount != 1 {
 		return status.Errorf(codes.InvalidArgument, printer.Sprintf("unable to find user with given id"))
 	}
 	return nil
 }
+
+// RestoreUser restores the soft deleted user with the given id.
+// It fails if the username or mail address was taken in the meantime.
+func (m *MGO) RestoreUser(ctx context.Context, printer *message.Printer, id string) (*User, error) {
+	ctx, end := m.instrument(ctx, "RestoreUser")
+	defer end()
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid user id"))
+	}
+	filter := bson.M{"_id": oid, "deletedAt": bson.M{"$exists": true}}
+	u := &User{}
+	if err := m.usersCollection.FindOne(ctx, filter).Decode(u); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, printer.Sprintf("unable to find deleted user with id %s", id))
+		}
+		return nil, err
+	}
+	usernameKey, mailKey := LookupKey(u.Username), LookupKey(u.Mail)
+	var taken bson.A
+	// pending invitations do not have a username yet
+	if usernameKey != "" {
+		taken = append(taken, bson.M{"usernameKey": usernameKey})
+	}
+	if mailKey != "" {
+		taken = append(taken, bson.M{"mailKey": mailKey})
+	}
+	if len(taken) > 0 {
+		count, err := m.usersCollection.CountDocuments(ctx, notDeleted(bson.M{"$or": taken}))
+		if err != nil {
+			m.log(ctx).Error("unable to count users", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to count users"))
+		}
+		if count > 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("username or mail address was taken in the meantime"))
+		}
+	}
+	opts := options.FindOneAndUpdate()
+	opts.SetReturnDocument(options.After)
+	doc := bson.M{
+		"$set":   bson.M{"usernameKey": usernameKey, "mailKey": mailKey},
+		"$unset": bson.M{"deletedAt": ""},
+		"$inc":   bson.M{"revision": 1},
+	}
+	u = &User{}
+	err = m.usersCollection.FindOneAndUpdate(ctx, filter, doc, opts).Decode(u)
+	if isDuplicateKeyError(err) {
+		return nil, status.Errorf(codes.FailedPrecondition, printer.Sprintf("username or mail address was taken in the meantime"))
+	}
+	if err != nil {
+		m.log(ctx).Error("error while restoring user", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while restoring user"))
+	}
+	return u, nil
+}
+
+// ListUsersScheduledForDeletion returns the users whose account deletion
+// was scheduled for the given time or earlier.
+func (m *MGO) ListUsersScheduledForDeletion(ctx context.Context, printer *message.Printer, before time.Time) (*[]User, error) {
+	ctx, end := m.instrument(ctx, "ListUsersScheduledForDeletion")
+	defer end()
+	filter := bson.M{"deletionScheduledAt": bson.M{"$gt": time.Time{}, "$lte": before}}
+	cur, err := m.usersCollection.Find(ctx, notDeleted(filter))
+	if err != nil {
+		m.log(ctx).Error("unable to query users scheduled for deletion", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while querying users"))
+	}
+	defer cur.Close(ctx)
+	users := &[]User{}
+	for cur.Next(ctx) {
+		var u User
+		if err := cur.Decode(&u); err != nil {
+			return nil, status.Errorf(codes.Internal, printer.Sprintf("unable to decode user: %s", err))
+		}
+		*users = append(*users, u)
+	}
+	return users, nil
+}
+
+// RemoveUserAttribute removes the custom attribute with the given name from all users,
+// including the soft deleted ones.
+func (m *MGO) RemoveUserAttribute(ctx context.Context, printer *message.Printer, name string) error {
+	ctx, end := m.instrument(ctx, "RemoveUserAttribute")
+	defer end()
+	field := "attributes." + name
+	filter := bson.M{field: bson.M{"$exists": true}}
+	if _, err := m.usersCollection.UpdateMany(ctx, filter, bson.M{"$unset": bson.M{field: ""}, "$inc": bson.M{"revision": 1}}); err != nil {
+		m.log(ctx).Error("unable to remove user attribute", zap.String("attribute", name), zap.Error(err))
+		return status.Errorf(codes.Internal, printer.Sprintf("unable to remove attribute %s from users", name))
+	}
+	return nil
+}
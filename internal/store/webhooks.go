@@ -0,0 +1,246 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/text/message"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListWebhooks lists webhooks from the mongo db.
+// It returns the documents, the total size of documents for the given filter and a grpc status type error if anything goes wrong.
+func (m *MGO) ListWebhooks(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (webhooks *[]Webhook, totalSize int32, nextToken string, err error) {
+	ctx, end := m.instrument(ctx, "ListWebhooks")
+	defer end()
+	cur, total, err := m.queryDocuments(
+		ctx,
+		printer,
+		m.webhooksCollection,
+		filterString,
+		orderBy,
+		token,
+		size,
+	)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	defer cur.Close(ctx)
+	webhooks = &[]Webhook{}
+	var w Webhook
+	for cur.Next(ctx) {
+		w = Webhook{}
+		err = cur.Decode(&w)
+		if err != nil {
+			return nil, 0, "", status.Errorf(codes.Internal, printer.Sprintf("unable to decode webhook: %s", err))
+		}
+		*webhooks = append(*webhooks, w)
+	}
+	// if there might be more results
+	l := int32(len(*webhooks))
+	if size == l && total > l {
+		nextToken, err = m.NextPageToken(
+			ctx,
+			printer,
+			m.webhooksCollection,
+			filterString,
+			orderBy,
+			w,
+		)
+		if err != nil {
+			return nil, 0, "", err
+		}
+	}
+	return webhooks, total, nextToken, nil
+}
+
+// GetWebhook gets the webhook with the given id from the mongo db.
+// It returns a grpc status type error if anything goes wrong.
+func (m *MGO) GetWebhook(ctx context.Context, printer *message.Printer, id string) (*Webhook, error) {
+	ctx, end := m.instrument(ctx, "GetWebhook")
+	defer end()
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid id '%s'", id))
+	}
+	filter := bson.M{"_id": oid}
+	w := &Webhook{}
+	if ctx.Err() == context.Canceled {
+		return nil, status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
+	}
+	if err := m.webhooksCollection.FindOne(ctx, filter).Decode(w); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, printer.Sprintf("unable to find webhook with id %s", id))
+		}
+		return nil, err
+	}
+	return w, nil
+}
+
+// SaveWebhook stores the given webhook in the database.
+// The webhook id will be used to determine if a new webhook has to be created
+// or an existing one can be updated.
+func (m *MGO) SaveWebhook(ctx context.Context, printer *message.Printer, webhook *Webhook) (*Webhook, error) {
+	ctx, end := m.instrument(ctx, "SaveWebhook")
+	defer end()
+	var err error
+	var oid primitive.ObjectID
+	webhook.UpdatedAt = time.Now()
+	if webhook.Id != "" {
+		oid, err = primitive.ObjectIDFromHex(webhook.Id)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid webhook id '%s'", webhook.Id))
+		}
+		webhook.Id = ""
+	} else {
+		oid = primitive.NewObjectID()
+		webhook.CreatedAt = webhook.UpdatedAt
+	}
+	opts := options.FindOneAndUpdate()
+	opts.SetUpsert(true)
+	opts.SetReturnDocument(options.After)
+	filter := bson.M{"_id": oid}
+	doc := bson.M{"$set": webhook}
+	w := &Webhook{}
+	err = m.webhooksCollection.FindOneAndUpdate(ctx, filter, doc, opts).Decode(w)
+	if err != nil {
+		m.log(ctx).Error("error while saving webhook", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while saving webhook"))
+	}
+	return w, nil
+}
+
+// DeleteWebhook deletes the webhook with the given id.
+func (m *MGO) DeleteWebhook(ctx context.Context, printer *message.Printer, id string) error {
+	ctx, end := m.instrument(ctx, "DeleteWebhook")
+	defer end()
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid webhook id"))
+	}
+	filter := bson.M{"_id": oid}
+	if ctx.Err() == context.Canceled {
+		return status.Errorf(codes.Canceled, printer.Sprintf("the request was canceled by the client"))
+	}
+	res, err := m.webhooksCollection.DeleteOne(ctx, filter)
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to delete webhook")
+	}
+	if res.DeletedCount != 1 {
+		return status.Errorf(codes.InvalidArgument, printer.Sprintf("unable to find webhook with id '%s'", id))
+	}
+	return nil
+}
+
+// ListWebhookDeliveries lists webhook deliveries from the mongo db.
+// It returns the documents, the total size of documents for the given filter and a grpc status type error if anything goes wrong.
+func (m *MGO) ListWebhookDeliveries(ctx context.Context, printer *message.Printer, filterString, orderBy, token string, size int32) (deliveries *[]WebhookDelivery, totalSize int32, nextToken string, err error) {
+	ctx, end := m.instrument(ctx, "ListWebhookDeliveries")
+	defer end()
+	cur, total, err := m.queryDocuments(
+		ctx,
+		printer,
+		m.webhookDeliveriesCollection,
+		filterString,
+		orderBy,
+		token,
+		size,
+	)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	defer cur.Close(ctx)
+	deliveries = &[]WebhookDelivery{}
+	var d WebhookDelivery
+	for cur.Next(ctx) {
+		d = WebhookDelivery{}
+		err = cur.Decode(&d)
+		if err != nil {
+			return nil, 0, "", status.Errorf(codes.Internal, printer.Sprintf("unable to decode webhook delivery: %s", err))
+		}
+		*deliveries = append(*deliveries, d)
+	}
+	// if there might be more results
+	l := int32(len(*deliveries))
+	if size == l && total > l {
+		nextToken, err = m.NextPageToken(
+			ctx,
+			printer,
+			m.webhookDeliveriesCollection,
+			filterString,
+			orderBy,
+			d,
+		)
+		if err != nil {
+			return nil, 0, "", err
+		}
+	}
+	return deliveries, total, nextToken, nil
+}
+
+// SaveWebhookDelivery stores the given webhook delivery in the database.
+// The delivery id will be used to determine if a new delivery has to be created
+// or an existing one can be updated.
+func (m *MGO) SaveWebhookDelivery(ctx context.Context, printer *message.Printer, delivery *WebhookDelivery) (*WebhookDelivery, error) {
+	ctx, end := m.instrument(ctx, "SaveWebhookDelivery")
+	defer end()
+	var err error
+	var oid primitive.ObjectID
+	delivery.UpdatedAt = time.Now()
+	if delivery.Id != "" {
+		oid, err = primitive.ObjectIDFromHex(delivery.Id)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, printer.Sprintf("invalid webhook delivery id '%s'", delivery.Id))
+		}
+		delivery.Id = ""
+	} else {
+		oid = primitive.NewObjectID()
+		delivery.CreatedAt = delivery.UpdatedAt
+	}
+	opts := options.FindOneAndUpdate()
+	opts.SetUpsert(true)
+	opts.SetReturnDocument(options.After)
+	filter := bson.M{"_id": oid}
+	doc := bson.M{"$set": delivery}
+	d := &WebhookDelivery{}
+	err = m.webhookDeliveriesCollection.FindOneAndUpdate(ctx, filter, doc, opts).Decode(d)
+	if err != nil {
+		m.log(ctx).Error("error while saving webhook delivery", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while saving webhook delivery"))
+	}
+	return d, nil
+}
+
+// ClaimWebhookDelivery returns the pending webhook delivery which is due the longest.
+// The next attempt of the returned delivery is postponed by the given lease, which
+// prevents other workers from claiming the same delivery while it is being sent.
+// It returns nil if no delivery is due.
+func (m *MGO) ClaimWebhookDelivery(ctx context.Context, printer *message.Printer, lease time.Duration) (*WebhookDelivery, error) {
+	ctx, end := m.instrument(ctx, "ClaimWebhookDelivery")
+	defer end()
+	now := time.Now()
+	filter := bson.M{
+		"state":         WebhookDeliveryPending,
+		"nextAttemptAt": bson.M{"$lte": now},
+	}
+	doc := bson.M{"$set": bson.M{"nextAttemptAt": now.Add(lease)}}
+	opts := options.FindOneAndUpdate()
+	opts.SetSort(bson.M{"nextAttemptAt": 1})
+	opts.SetReturnDocument(options.After)
+	d := &WebhookDelivery{}
+	if err := m.webhookDeliveriesCollection.FindOneAndUpdate(ctx, filter, doc, opts).Decode(d); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		m.log(ctx).Error("error while claiming webhook delivery", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, printer.Sprintf("error while claiming webhook delivery"))
+	}
+	return d, nil
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	if _, err := Setup(context.Background(), ExporterNone, "gooser"); err != nil {
+		t.Fatalf("unable to set up tracing: %s", err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	// incoming request with trace context
+	traceId := "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"traceparent", "00-"+traceId+"-00f067aa0ba902b7-01",
+	))
+	info := &grpc.UnaryServerInfo{FullMethod: "/gooser.v1.Gooser/GetUser"}
+	_, err := UnaryServerInterceptor()(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		_, span := Start(ctx, "store.GetUser")
+		span.End()
+		return nil, status.Errorf(grpccodes.NotFound, "not found")
+	})
+	assert.Equal(t, grpccodes.NotFound, status.Code(err))
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 2) {
+		return
+	}
+	child, server := spans[0], spans[1]
+	assert.Equal(t, "/gooser.v1.Gooser/GetUser", server.Name())
+	assert.Equal(t, traceId, server.SpanContext().TraceID().String(), "trace id needs to be propagated")
+	assert.True(t, server.Parent().IsRemote(), "parent needs to be the remote span")
+	assert.Equal(t, codes.Error, server.Status().Code)
+	assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID(), "store span needs to be a child of the server span")
+}
+
+func TestSetup(t *testing.T) {
+	_, err := Setup(context.Background(), "zipkin", "gooser")
+	assert.Error(t, err, "unknown exporters need to be rejected")
+}
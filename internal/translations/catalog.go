@@ -36,54 +36,82 @@ func init() {
 }
 
 var messageKeyToIndex = map[string]int{
-	"%s has a length of 0":     4,
-	"%s: confirm mail address": 34,
-	"%s: password reset":       37,
-	"Hi %s! Please confirm your mail address by clicking the following link. Thanks!\n%s":                                                                35,
-	"Hi %s! To reset your password, click the following link: \n%s\n\nIf you did not request to reset your password, please ignore this message. Thanks": 38,
-	"could not find group with id %s":                                  39,
-	"could not find user with id %s":                                   49,
-	"could not parse given language":                                   51,
-	"error while querying %s":                                          10,
-	"error while querying member":                                      43,
-	"error while saving group":                                         17,
-	"error while saving user":                                          31,
-	"error while sending mail: %s":                                     36,
-	"group name needs to have a length of at least 3":                  40,
-	"internal error while building filter":                             0,
-	"invalid group id '%s'":                                            16,
-	"invalid id '%s'":                                                  13,
-	"invalid mail address":                                             52,
-	"invalid page token given":                                         5,
-	"invalid rsql filter string '%s': %s":                              3,
-	"invalid token":                                                    23,
-	"invalid user id":                                                  32,
-	"invalid user id '%s'":                                             30,
-	"invalid username, only lowercase letters and numbers are allowed": 50,
-	"mail address not set":                                             55,
-	"no token given":                                                   21,
-	"not allowed to change password for other users":                   64,
-	"not allowed to create groups":                                     44,
-	"not allowed to delete groups":                                     48,
-	"not allowed to delete user":                                       62,
-	"not allowed to edit other users":                                  58,
-	"not allowed to set confirmed":                                     56,
-	"not allowed to update groups":                                     45,
-	"only %v of %v given memberIds were found":                         42,
-	"orderBy field has a length of 0":                                  1,
-	"pagination filter and given filters do not match":                 6,
-	"pagination orderBy and given orderBy do not match":                7,
-	"password cannot be changed using the UpdateUser function, use ChangePassword instead": 60,
-	"password mismatch":                                              65,
-	"password must have a length of at least 7":                      53,
-	"roles cannot be assigned to users directly":                     59,
-	"roles cannot be assigned to users directly, use groups instead": 54,
+	"%s has a length of 0": 4,
+	"A change of the mail address of your account from %s to %s was requested on %s. The new address is used once it is confirmed.": 77,
+	"Accept invitation": 94,
+	"As requested, your account will be deleted on %s.": 88,
+	"Cancel mail address change":                        79,
+	"Confirm mail address":                              82,
+	"Hi %s!":                                            65,
+	"Hi!":                                               91,
+	"If you changed your mind, sign in and cancel the deletion before then. If you did not request to delete your account, please change your password immediately.": 89,
+	"If you did not change your mail address, restore the previous one using the following link and reset your password.":                                            70,
+	"If you did not change your password, please reset it immediately and contact the support.":                                                                      67,
+	"If you did not request this change, cancel it using the following link and reset your password.":                                                                78,
+	"If you did not request to reset your password, please ignore this message. Thanks":                                                                              85,
+	"If you think this is a mistake, please contact the support.":                                                                                                    74,
+	"Please confirm your mail address by clicking the following link. Thanks!":                                                                                       81,
+	"Reset password":                86,
+	"Restore previous mail address": 71,
+	"The invitation expires on %s.": 93,
+	"The mail address of your account was changed from %s to %s on %s.":                                                 69,
+	"The password of your account was changed on %s.":                                                                   66,
+	"To reset your password, click the following link:":                                                                 84,
+	"You have been invited to %s. To accept the invitation, choose a username and a password using the following link:": 92,
+	"You were added to the group %s.":                                                                                   73,
+	"You were granted the roles %s through the group %s.":                                                               75,
+	"access granted":                                  72,
+	"account deletion":                                87,
+	"confirm mail address":                            80,
+	"could not find group with id %s":                 35,
+	"could not find user with id %s":                  45,
+	"could not parse given language":                  47,
+	"error while querying %s":                         10,
+	"error while querying member":                     39,
+	"error while saving group":                        17,
+	"error while saving user":                         31,
+	"error while sending mail: %s":                    34,
+	"group name needs to have a length of at least 3": 36,
+	"internal error while building filter":            0,
+	"invalid group id '%s'":                           16,
+	"invalid id '%s'":                                 13,
+	"invalid mail address":                            48,
+	"invalid page token given":                        5,
+	"invalid rsql filter string '%s': %s":             3,
+	"invalid token":                                   23,
+	"invalid user id":                                 32,
+	"invalid user id '%s'":                            30,
+	"invalid username, only lowercase letters and numbers are allowed": 46,
+	"invitation":                                        90,
+	"mail address change requested":                     76,
+	"mail address changed":                              68,
+	"mail address not set":                              51,
+	"no token given":                                    21,
+	"not allowed to change password for other users":    60,
+	"not allowed to create groups":                      40,
+	"not allowed to delete groups":                      44,
+	"not allowed to delete user":                        58,
+	"not allowed to edit other users":                   54,
+	"not allowed to set confirmed":                      52,
+	"not allowed to update groups":                      41,
+	"only %v of %v given memberIds were found":          38,
+	"orderBy field has a length of 0":                   1,
+	"pagination filter and given filters do not match":  6,
+	"pagination orderBy and given orderBy do not match": 7,
+	"password cannot be changed using the UpdateUser function, use ChangePassword instead": 56,
+	"password changed":                                               64,
+	"password mismatch":                                              61,
+	"password must have a length of at least 7":                      49,
+	"password reset":                                                 83,
+	"roles cannot be assigned to users directly":                     55,
+	"roles cannot be assigned to users directly, use groups instead": 50,
+	"the contents of the mail have been purged":                      96,
 	"the request was canceled by the client":                         8,
 	"token mismatch":                                                 22,
 	"unable to count %s":                                             9,
 	"unable to count groups":                                         12,
 	"unable to count users":                                          27,
-	"unable to create generate field mask: %s":                       46,
+	"unable to create generate field mask: %s":                       42,
 	"unable to decode group: %s":                                     11,
 	"unable to decode user: %s":                                      26,
 	"unable to encrypt confirmation: %s":                             20,
@@ -94,19 +122,22 @@ var messageKeyToIndex = map[string]int{
 	"unable to find user":                                            29,
 	"unable to find user with given id":                              33,
 	"unable to find user with id %s":                                 28,
-	"unable to hash given password":                                  57,
+	"unable to hash given password":                                  53,
 	"unable to json marshal confirmation: %s":                        19,
 	"unable to json marshal reset password struct: %s":               24,
-	"unable to merge groups":                                         47,
-	"unable to merge users":                                          61,
-	"unable to query members":                                        41,
-	"unable to remove user from group %s":                            63,
-	"unable to save user":                                            66,
+	"unable to merge groups":                                         43,
+	"unable to merge users":                                          57,
+	"unable to purge failed mails":                                   95,
+	"unable to query members":                                        37,
+	"unable to remove user from group %s":                            59,
+	"unable to save user":                                            62,
 	"unable to search next document while creating pagination token": 2,
-	"unable to send reset password mail":                             67,
+	"unable to send account deletion mail":                           98,
+	"unable to send invitation mail":                                 97,
+	"unable to send reset password mail":                             63,
 }
 
-var deIndex = []uint32{ // 69 elements
+var deIndex = []uint32{ // 100 elements
 	// Entry 0 - 1F
 	0x00000000, 0x0000002b, 0x0000004d, 0x000000aa,
 	0x000000d8, 0x000000f4, 0x0000011a, 0x00000158,
@@ -117,20 +148,28 @@ var deIndex = []uint32{ // 69 elements
 	0x000003eb, 0x00000428, 0x00000468, 0x00000497,
 	0x000004be, 0x000004f3, 0x00000519, 0x00000538,
 	// Entry 20 - 3F
-	0x0000055c, 0x00000573, 0x000005ae, 0x000005ce,
-	0x00000637, 0x0000065e, 0x0000067c, 0x00000736,
-	0x00000769, 0x000007a7, 0x000007d1, 0x00000800,
-	0x00000823, 0x0000084a, 0x00000875, 0x000008a3,
-	0x000008d1, 0x000008f7, 0x0000092a, 0x00000970,
-	0x00000995, 0x000009ad, 0x000009e6, 0x00000a35,
-	0x00000a50, 0x00000a75, 0x00000aab, 0x00000adf,
-	0x00000b17, 0x00000b8d, 0x00000bbc, 0x00000be7,
+	0x0000055c, 0x00000573, 0x000005ae, 0x000005d5,
+	0x00000608, 0x00000646, 0x00000670, 0x0000069f,
+	0x000006c2, 0x000006e9, 0x00000714, 0x00000742,
+	0x00000770, 0x00000796, 0x000007c9, 0x0000080f,
+	0x00000834, 0x0000084c, 0x00000885, 0x000008d4,
+	0x000008ef, 0x00000914, 0x0000094a, 0x0000097e,
+	0x000009b6, 0x00000a2c, 0x00000a5b, 0x00000a86,
+	0x00000ab5, 0x00000af6, 0x00000b15, 0x00000b3c,
 	// Entry 40 - 5F
-	0x00000c16, 0x00000c57, 0x00000c76, 0x00000c9d,
-	0x00000cce,
-} // Size: 300 bytes
+	0x00000b6d, 0x00000b80, 0x00000b8d, 0x00000bc2,
+	0x00000c2a, 0x00000c41, 0x00000c8d, 0x00000d17,
+	0x00000d3f, 0x00000d4f, 0x00000d79, 0x00000dc3,
+	0x00000dff, 0x00000e26, 0x00000ec1, 0x00000f37,
+	0x00000f5c, 0x00000f75, 0x00000fca, 0x00000fe3,
+	0x00000ffa, 0x00001037, 0x0000109f, 0x000010b6,
+	0x000010cb, 0x000010fe, 0x000011b7, 0x000011c1,
+	0x000011c8, 0x00001249, 0x0000126b, 0x0000127e,
+	// Entry 60 - 7F
+	0x000012b3, 0x000012de, 0x0000130a, 0x0000134a,
+} // Size: 424 bytes
 
-const deData string = "" + // Size: 3278 bytes
+const deData string = "" + // Size: 4938 bytes
 	"\x02Interner Fehler beim Erstellen des Filters\x02Sortierfeld hat eine L" +
 	"änge von 0\x02während dem Erstellen des Pagination-Tokens konnte das Fo" +
 	"lgedokument nicht abgefragt werden\x02ungültiger rsql Filter String '%[1" +
@@ -152,36 +191,59 @@ const deData string = "" + // Size: 3278 bytes
 	"it ID '%[1]s' konnte nicht gefunden werden\x02Benutzer konnte nicht gefu" +
 	"nden werden\x02Ungültige Benutzer ID '%[1]s'\x02Fehler beim Speichern de" +
 	"s Benutzers\x02Ungültige Benutzer ID\x02Benutzer mit der gegebenen ID ko" +
-	"nnte nicht gefunden werden\x02%[1]s: Mail-Adresse bestätigen\x02Hallo %[" +
-	"1]s! Bitte bestätige deine Mail-Adresse, indem du auf den folgenden Link" +
-	" klickst. Danke!\x0a%[2]s \x02Fehler beim Versenden des Mails: %[1]s\x02" +
-	"%[1]s: Passwort zurücksetzen\x02Hallo %[1]s! Um dein Passwort zurückzuse" +
-	"tzen, klicke den folgenden Link: \x0a%[2]s\x0a\x0aFalls du das zurückset" +
-	"zen des Passworts nicht angefordert hast, bitte ignoriere diese Nachrich" +
-	"t. Danke\x02Gruppe mit ID '%[1]s' konnte nicht gefunden werden\x02Name d" +
-	"er Gruppe sollte mindestens eine Länge von 3 aufweisen\x02Mitglieder kon" +
-	"nten nicht abgefragt werden\x02Nur %[1]v der %[2]v Mitglieder wurden gef" +
-	"unden\x02Fehler beim Abfragen des Mitglieds\x02Nicht berechtigt, Gruppen" +
-	" zu erstellen\x02Nicht berechtigt, Gruppen zu aktualisieren\x02Feldmaske" +
-	" konnte nicht erstellt werden: %[1]s\x02Gruppen konnten nicht zusammenge" +
-	"führt werden\x02Nicht berechtigt, Gruppen zu löschen\x02Benutzer mit id " +
-	"%[1]s konnte nicht gefunden werden\x02Ungüliger Benutzername, nur Kleinb" +
-	"uchstaben und Nummern sind erlaubt\x02Sprache konnte nicht bestimmt werd" +
-	"en\x02Ungültige Mail Adresse\x02Das Passwort muss mindestens eine Länge " +
-	"von 7 aufweisen\x02Rollen können nicht direkt Benutzern zugewiesen werde" +
-	"n, verwende Gruppen dazu\x02Mail Adresse nicht gegeben\x02Bestätigt darf" +
-	" nicht gesetzt werden\x02Es konnte kein Hash für das Passwort erstellt w" +
-	"erden\x02Keine Berechtigung um andere Benutzer zu bearbeiten\x02Rollen k" +
-	"önnen nicht direkt Benutzern zugeordnet werden\x02Passwort kann nicht m" +
-	"it der UpdateUser Funktion aktualisiert werden, verwende die ChangePassw" +
-	"ord Funktion stattdessen\x02Benutzer können nicht zusammengeführt werden" +
-	"\x02Keine Berechtigung um Benutzer zu löschen\x02Benutzer kann nicht von" +
-	" Gruppe entfernt werden\x02Keine Berechtigungen um das Passwort anderer " +
-	"Benutzer zu ändern\x02Passwort stimmt nicht überein\x02Benutzer kann nic" +
-	"ht gespeichert werden\x02Passwort Reset Mail konnte nicht versandt werde" +
-	"n"
+	"nnte nicht gefunden werden\x02Fehler beim Versenden des Mails: %[1]s\x02" +
+	"Gruppe mit ID '%[1]s' konnte nicht gefunden werden\x02Name der Gruppe so" +
+	"llte mindestens eine Länge von 3 aufweisen\x02Mitglieder konnten nicht a" +
+	"bgefragt werden\x02Nur %[1]v der %[2]v Mitglieder wurden gefunden\x02Feh" +
+	"ler beim Abfragen des Mitglieds\x02Nicht berechtigt, Gruppen zu erstelle" +
+	"n\x02Nicht berechtigt, Gruppen zu aktualisieren\x02Feldmaske konnte nich" +
+	"t erstellt werden: %[1]s\x02Gruppen konnten nicht zusammengeführt werden" +
+	"\x02Nicht berechtigt, Gruppen zu löschen\x02Benutzer mit id %[1]s konnte" +
+	" nicht gefunden werden\x02Ungüliger Benutzername, nur Kleinbuchstaben un" +
+	"d Nummern sind erlaubt\x02Sprache konnte nicht bestimmt werden\x02Ungült" +
+	"ige Mail Adresse\x02Das Passwort muss mindestens eine Länge von 7 aufwei" +
+	"sen\x02Rollen können nicht direkt Benutzern zugewiesen werden, verwende " +
+	"Gruppen dazu\x02Mail Adresse nicht gegeben\x02Bestätigt darf nicht geset" +
+	"zt werden\x02Es konnte kein Hash für das Passwort erstellt werden\x02Kei" +
+	"ne Berechtigung um andere Benutzer zu bearbeiten\x02Rollen können nicht " +
+	"direkt Benutzern zugeordnet werden\x02Passwort kann nicht mit der Update" +
+	"User Funktion aktualisiert werden, verwende die ChangePassword Funktion " +
+	"stattdessen\x02Benutzer können nicht zusammengeführt werden\x02Keine Ber" +
+	"echtigung um Benutzer zu löschen\x02Benutzer kann nicht von Gruppe entfe" +
+	"rnt werden\x02Keine Berechtigungen um das Passwort anderer Benutzer zu ä" +
+	"ndern\x02Passwort stimmt nicht überein\x02Benutzer kann nicht gespeicher" +
+	"t werden\x02Passwort Reset Mail konnte nicht versandt werden\x02Passwort" +
+	" geändert\x02Hallo %[1]s!\x02Das Passwort deines Kontos wurde am %[1]s g" +
+	"eändert.\x02Falls du dein Passwort nicht geändert hast, setze es bitte s" +
+	"ofort zurück und kontaktiere den Support.\x02Mail-Adresse geändert\x02Di" +
+	"e Mail-Adresse deines Kontos wurde am %[3]s von %[1]s zu %[2]s geändert." +
+	"\x02Falls du deine Mail-Adresse nicht geändert hast, stelle die bisherig" +
+	"e mit dem folgenden Link wieder her und setze dein Passwort zurück.\x02B" +
+	"isherige Mail-Adresse wiederherstellen\x02Zugriff erteilt\x02Du wurdest " +
+	"zur Gruppe %[1]s hinzugefügt.\x02Falls du denkst, dass dies ein Fehler i" +
+	"st, kontaktiere bitte den Support.\x02Dir wurden über die Gruppe %[2]s d" +
+	"ie Rollen %[1]s erteilt.\x02Änderung der Mail-Adresse angefordert\x02Am " +
+	"%[3]s wurde eine Änderung der Mail-Adresse deines Kontos von %[1]s zu %[" +
+	"2]s angefordert. Die neue Adresse wird verwendet, sobald sie bestätigt w" +
+	"urde.\x02Falls du diese Änderung nicht angefordert hast, brich sie mit d" +
+	"em folgenden Link ab und setze dein Passwort zurück.\x02Änderung der Mai" +
+	"l-Adresse abbrechen\x02Mail-Adresse bestätigen\x02Bitte bestätige deine " +
+	"Mail-Adresse, indem du auf den folgenden Link klickst. Danke!\x02Mail-Ad" +
+	"resse bestätigen\x02Passwort zurücksetzen\x02Um dein Passwort zurückzuse" +
+	"tzen, klicke den folgenden Link:\x02Falls du das Zurücksetzen des Passwo" +
+	"rts nicht angefordert hast, ignoriere bitte diese Nachricht. Danke\x02Pa" +
+	"sswort zurücksetzen\x02Löschung des Kontos\x02Wie gewünscht wird dein Ko" +
+	"nto am %[1]s gelöscht.\x02Falls du es dir anders überlegt hast, melde di" +
+	"ch an und brich die Löschung vorher ab. Falls du die Löschung deines Kon" +
+	"tos nicht angefordert hast, ändere bitte sofort dein Passwort.\x02Einlad" +
+	"ung\x02Hallo!\x02Du wurdest zu %[1]s eingeladen. Um die Einladung anzune" +
+	"hmen, wähle mit dem folgenden Link einen Benutzernamen und ein Passwort:" +
+	"\x02Die Einladung läuft am %[1]s ab.\x02Einladung annehmen\x02Fehlgeschl" +
+	"agene Mails konnten nicht bereinigt werden\x02Der Inhalt der Mail wurde " +
+	"bereits entfernt\x02Die Einladung konnte nicht versendet werden\x02Die M" +
+	"ail zur Löschung des Kontos konnte nicht versendet werden"
 
-var enIndex = []uint32{ // 69 elements
+var enIndex = []uint32{ // 100 elements
 	// Entry 0 - 1F
 	0x00000000, 0x00000025, 0x00000045, 0x00000084,
 	0x000000ae, 0x000000c6, 0x000000df, 0x00000110,
@@ -192,20 +254,28 @@ var enIndex = []uint32{ // 69 elements
 	0x000002fa, 0x0000032e, 0x0000035d, 0x0000037a,
 	0x00000390, 0x000003b2, 0x000003c6, 0x000003de,
 	// Entry 20 - 3F
-	0x000003f6, 0x00000406, 0x00000428, 0x00000444,
-	0x0000049d, 0x000004bd, 0x000004d3, 0x00000569,
-	0x0000058c, 0x000005bc, 0x000005d4, 0x00000603,
-	0x0000061f, 0x0000063c, 0x00000659, 0x00000685,
-	0x0000069c, 0x000006b9, 0x000006db, 0x0000071c,
-	0x0000073b, 0x00000750, 0x0000077a, 0x000007b9,
-	0x000007ce, 0x000007eb, 0x00000809, 0x00000829,
-	0x00000854, 0x000008a9, 0x000008bf, 0x000008da,
+	0x000003f6, 0x00000406, 0x00000428, 0x00000448,
+	0x0000046b, 0x0000049b, 0x000004b3, 0x000004e2,
+	0x000004fe, 0x0000051b, 0x00000538, 0x00000564,
+	0x0000057b, 0x00000598, 0x000005ba, 0x000005fb,
+	0x0000061a, 0x0000062f, 0x00000659, 0x00000698,
+	0x000006ad, 0x000006ca, 0x000006e8, 0x00000708,
+	0x00000733, 0x00000788, 0x0000079e, 0x000007b9,
+	0x000007e0, 0x0000080f, 0x00000821, 0x00000835,
 	// Entry 40 - 5F
-	0x00000901, 0x00000930, 0x00000942, 0x00000956,
-	0x00000979,
-} // Size: 300 bytes
+	0x00000858, 0x00000869, 0x00000873, 0x000008a6,
+	0x00000900, 0x00000915, 0x00000960, 0x000009d4,
+	0x000009f2, 0x00000a01, 0x00000a24, 0x00000a60,
+	0x00000a9a, 0x00000ab8, 0x00000b3f, 0x00000b9f,
+	0x00000bba, 0x00000bcf, 0x00000c18, 0x00000c2d,
+	0x00000c3c, 0x00000c6e, 0x00000cc0, 0x00000ccf,
+	0x00000ce0, 0x00000d15, 0x00000db4, 0x00000dbf,
+	0x00000dc3, 0x00000e38, 0x00000e59, 0x00000e6b,
+	// Entry 60 - 7F
+	0x00000e88, 0x00000eb2, 0x00000ed1, 0x00000ef6,
+} // Size: 424 bytes
 
-const enData string = "" + // Size: 2425 bytes
+const enData string = "" + // Size: 3830 bytes
 	"\x02internal error while building filter\x02orderBy field has a length o" +
 	"f 0\x02unable to search next document while creating pagination token" +
 	"\x02invalid rsql filter string '%[1]s': %[2]s\x02%[1]s has a length of 0" +
@@ -222,26 +292,47 @@ const enData string = "" + // Size: 2425 bytes
 	" %[1]s\x02unable to decode user: %[1]s\x02unable to count users\x02unabl" +
 	"e to find user with id %[1]s\x02unable to find user\x02invalid user id '" +
 	"%[1]s'\x02error while saving user\x02invalid user id\x02unable to find u" +
-	"ser with given id\x02%[1]s: confirm mail address\x02Hi %[1]s! Please con" +
-	"firm your mail address by clicking the following link. Thanks!\x0a%[2]s" +
-	"\x02error while sending mail: %[1]s\x02%[1]s: password reset\x02Hi %[1]s" +
-	"! To reset your password, click the following link: \x0a%[2]s\x0a\x0aIf " +
-	"you did not request to reset your password, please ignore this message. " +
-	"Thanks\x02could not find group with id %[1]s\x02group name needs to have" +
-	" a length of at least 3\x02unable to query members\x02only %[1]v of %[2]" +
-	"v given memberIds were found\x02error while querying member\x02not allow" +
-	"ed to create groups\x02not allowed to update groups\x02unable to create " +
-	"generate field mask: %[1]s\x02unable to merge groups\x02not allowed to d" +
-	"elete groups\x02could not find user with id %[1]s\x02invalid username, o" +
-	"nly lowercase letters and numbers are allowed\x02could not parse given l" +
-	"anguage\x02invalid mail address\x02password must have a length of at lea" +
-	"st 7\x02roles cannot be assigned to users directly, use groups instead" +
-	"\x02mail address not set\x02not allowed to set confirmed\x02unable to ha" +
-	"sh given password\x02not allowed to edit other users\x02roles cannot be " +
-	"assigned to users directly\x02password cannot be changed using the Updat" +
-	"eUser function, use ChangePassword instead\x02unable to merge users\x02n" +
-	"ot allowed to delete user\x02unable to remove user from group %[1]s\x02n" +
-	"ot allowed to change password for other users\x02password mismatch\x02un" +
-	"able to save user\x02unable to send reset password mail"
+	"ser with given id\x02error while sending mail: %[1]s\x02could not find g" +
+	"roup with id %[1]s\x02group name needs to have a length of at least 3" +
+	"\x02unable to query members\x02only %[1]v of %[2]v given memberIds were " +
+	"found\x02error while querying member\x02not allowed to create groups\x02" +
+	"not allowed to update groups\x02unable to create generate field mask: %[" +
+	"1]s\x02unable to merge groups\x02not allowed to delete groups\x02could n" +
+	"ot find user with id %[1]s\x02invalid username, only lowercase letters a" +
+	"nd numbers are allowed\x02could not parse given language\x02invalid mail" +
+	" address\x02password must have a length of at least 7\x02roles cannot be" +
+	" assigned to users directly, use groups instead\x02mail address not set" +
+	"\x02not allowed to set confirmed\x02unable to hash given password\x02not" +
+	" allowed to edit other users\x02roles cannot be assigned to users direct" +
+	"ly\x02password cannot be changed using the UpdateUser function, use Chan" +
+	"gePassword instead\x02unable to merge users\x02not allowed to delete use" +
+	"r\x02unable to remove user from group %[1]s\x02not allowed to change pas" +
+	"sword for other users\x02password mismatch\x02unable to save user\x02una" +
+	"ble to send reset password mail\x02password changed\x02Hi %[1]s!\x02The " +
+	"password of your account was changed on %[1]s.\x02If you did not change " +
+	"your password, please reset it immediately and contact the support.\x02m" +
+	"ail address changed\x02The mail address of your account was changed from" +
+	" %[1]s to %[2]s on %[3]s.\x02If you did not change your mail address, re" +
+	"store the previous one using the following link and reset your password." +
+	"\x02Restore previous mail address\x02access granted\x02You were added to" +
+	" the group %[1]s.\x02If you think this is a mistake, please contact the " +
+	"support.\x02You were granted the roles %[1]s through the group %[2]s." +
+	"\x02mail address change requested\x02A change of the mail address of you" +
+	"r account from %[1]s to %[2]s was requested on %[3]s. The new address is" +
+	" used once it is confirmed.\x02If you did not request this change, cance" +
+	"l it using the following link and reset your password.\x02Cancel mail ad" +
+	"dress change\x02confirm mail address\x02Please confirm your mail address" +
+	" by clicking the following link. Thanks!\x02Confirm mail address\x02pass" +
+	"word reset\x02To reset your password, click the following link:\x02If yo" +
+	"u did not request to reset your password, please ignore this message. Th" +
+	"anks\x02Reset password\x02account deletion\x02As requested, your account" +
+	" will be deleted on %[1]s.\x02If you changed your mind, sign in and canc" +
+	"el the deletion before then. If you did not request to delete your accou" +
+	"nt, please change your password immediately.\x02invitation\x02Hi!\x02You" +
+	" have been invited to %[1]s. To accept the invitation, choose a username" +
+	" and a password using the following link:\x02The invitation expires on %" +
+	"[1]s.\x02Accept invitation\x02unable to purge failed mails\x02the conten" +
+	"ts of the mail have been purged\x02unable to send invitation mail\x02una" +
+	"ble to send account deletion mail"
 
-	// Total table size 6303 bytes (6KiB); checksum: 11146F60
+	// Total table size 9616 bytes (9KiB); checksum: ABFFC2A2
@@ -5,6 +5,7 @@ import (
 	"crypto/cipher"
 	crand "crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"math/rand"
@@ -45,6 +46,16 @@ func RandomString(n int) string {
 	return string(b)
 }
 
+// RandomToken returns a hex encoded string of n bytes generated by
+// a cryptographically secure random number generator.
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(crand.Reader, b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
 // RemoveFromStringSlice removes the first occurrence of the
 // given element from the slice of strings.
 //
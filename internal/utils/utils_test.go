@@ -42,6 +42,17 @@ func TestIsMailAddress(t *testing.T) {
 	}
 }
 
+// TestRandomToken tests the RandomToken function.
+func TestRandomToken(t *testing.T) {
+	assert := assert.New(t)
+	a, err := RandomToken(16)
+	assert.Nil(err)
+	assert.Len(a, 32)
+	b, err := RandomToken(16)
+	assert.Nil(err)
+	assert.NotEqual(a, b)
+}
+
 func TestAppendUniqueString(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,376 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/rbicker/gooser/internal/logging"
+	"github.com/rbicker/gooser/internal/store"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// events which can be subscribed by webhooks.
+const (
+	EventUserCreated        = "user.created"
+	EventUserConfirmed      = "user.confirmed"
+	EventUserUpdated        = "user.updated"
+	EventUserDeleted        = "user.deleted"
+	EventUserSuspended      = "user.suspended"
+	EventUserReactivated    = "user.reactivated"
+	EventUserRestored       = "user.restored"
+	EventUserInvited        = "user.invited"
+	EventGroupMemberAdded   = "group.member.added"
+	EventGroupMemberRemoved = "group.member.removed"
+)
+
+// Events contains all the events which can be subscribed.
+var Events = []string{
+	EventUserCreated,
+	EventUserConfirmed,
+	EventUserUpdated,
+	EventUserDeleted,
+	EventUserSuspended,
+	EventUserReactivated,
+	EventUserRestored,
+	EventUserInvited,
+	EventGroupMemberAdded,
+	EventGroupMemberRemoved,
+}
+
+// headers sent with every delivery.
+const (
+	HeaderEvent     = "X-Gooser-Event"
+	HeaderDelivery  = "X-Gooser-Delivery"
+	HeaderSignature = "X-Gooser-Signature"
+)
+
+// Emitter describes the functions to publish events.
+type Emitter interface {
+	Emit(ctx context.Context, event string, data interface{}) error
+}
+
+// Membership is the data of the group membership events.
+type Membership struct {
+	GroupId   string `json:"groupId"`
+	GroupName string `json:"groupName"`
+	UserId    string `json:"userId"`
+}
+
+// Payload is the json body sent to the webhooks.
+type Payload struct {
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Dispatcher implements the Emitter interface. It queues a delivery for every
+// webhook subscribed to an event and sends the queued deliveries in the background.
+type Dispatcher struct {
+	store        store.WebhookStore
+	httpClient   *http.Client
+	logger       *zap.Logger
+	workers      int
+	pollInterval time.Duration
+	lease        time.Duration
+	maxAttempts  int32
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	stop         chan struct{}
+	wg           sync.WaitGroup
+}
+
+// ensure Dispatcher implements the Emitter interface.
+var _ Emitter = &Dispatcher{}
+
+// NewDispatcher creates a new webhook dispatcher using the given store.
+// It takes functional parameters to change default options.
+func NewDispatcher(s store.WebhookStore, opts ...func(*Dispatcher) error) (*Dispatcher, error) {
+	var d = Dispatcher{
+		store:        s,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		workers:      2,
+		pollInterval: 5 * time.Second,
+		lease:        time.Minute,
+		maxAttempts:  10,
+		minBackoff:   30 * time.Second,
+		maxBackoff:   6 * time.Hour,
+	}
+	// run functional options
+	for _, op := range opts {
+		err := op(&d)
+		if err != nil {
+			return nil, fmt.Errorf("setting option failed: %w", err)
+		}
+	}
+	// default logger
+	if d.logger == nil {
+		d.logger = logging.Default()
+	}
+	return &d, nil
+}
+
+// Emit queues a delivery of the given event for every active webhook
+// which subscribed to it. Protobuf messages are marshalled using jsonpb,
+// any other data using encoding/json.
+func (d *Dispatcher) Emit(ctx context.Context, event string, data interface{}) error {
+	printer := message.NewPrinter(language.English)
+	filter := fmt.Sprintf(`active==true;events=="%s"`, event)
+	webhooks, _, _, err := d.store.ListWebhooks(ctx, printer, filter, "", "", -1)
+	if err != nil {
+		return fmt.Errorf("unable to list webhooks for event %s: %w", event, err)
+	}
+	if webhooks == nil || len(*webhooks) == 0 {
+		return nil
+	}
+	payload, err := NewPayload(event, data)
+	if err != nil {
+		return err
+	}
+	for _, w := range *webhooks {
+		_, err := d.store.SaveWebhookDelivery(ctx, printer, &store.WebhookDelivery{
+			WebhookId:     w.Id,
+			Event:         event,
+			Payload:       payload,
+			State:         store.WebhookDeliveryPending,
+			NextAttemptAt: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to queue delivery for webhook %s: %w", w.Id, err)
+		}
+	}
+	return nil
+}
+
+// NewPayload returns the json payload for the given event and data.
+func NewPayload(event string, data interface{}) (string, error) {
+	var raw []byte
+	var err error
+	if pb, ok := data.(proto.Message); ok {
+		var buf bytes.Buffer
+		err = (&jsonpb.Marshaler{}).Marshal(&buf, pb)
+		raw = buf.Bytes()
+	} else {
+		raw, err = json.Marshal(data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal data for event %s: %w", event, err)
+	}
+	b, err := json.Marshal(Payload{
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      raw,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal payload for event %s: %w", event, err)
+	}
+	return string(b), nil
+}
+
+// Sign returns the signature of the given body, which is the hex encoded
+// HMAC-SHA256 using the given secret, prefixed with "sha256=".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns the duration to wait before the next attempt,
+// after the given number of failed attempts.
+func (d *Dispatcher) Backoff(attempts int32) time.Duration {
+	backoff := d.minBackoff
+	for i := int32(1); i < attempts; i++ {
+		backoff *= 2
+		if backoff >= d.maxBackoff {
+			return d.maxBackoff
+		}
+	}
+	return backoff
+}
+
+// Start starts the workers which send the queued deliveries.
+func (d *Dispatcher) Start() {
+	d.stop = make(chan struct{})
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+}
+
+// Stop stops the workers and waits for running deliveries to finish.
+func (d *Dispatcher) Stop() {
+	if d.stop == nil {
+		return
+	}
+	close(d.stop)
+	d.wg.Wait()
+	d.stop = nil
+}
+
+// work sends deliveries until there are none left, then waits for
+// the poll interval to pass.
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	printer := message.NewPrinter(language.English)
+	t := time.NewTicker(d.pollInterval)
+	defer t.Stop()
+	for {
+		for {
+			delivery, err := d.store.ClaimWebhookDelivery(context.Background(), printer, d.lease)
+			if err != nil {
+				d.logger.Error("unable to claim webhook delivery", zap.Error(err))
+				break
+			}
+			if delivery == nil {
+				break
+			}
+			d.Deliver(context.Background(), delivery)
+			select {
+			case <-d.stop:
+				return
+			default:
+			}
+		}
+		select {
+		case <-d.stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Deliver sends the given delivery to its webhook and saves the outcome.
+// Failed deliveries are rescheduled with an exponential backoff until
+// the maximum number of attempts is reached.
+func (d *Dispatcher) Deliver(ctx context.Context, delivery *store.WebhookDelivery) {
+	printer := message.NewPrinter(language.English)
+	delivery.Attempts++
+	delivery.ResponseCode = 0
+	delivery.LastError = ""
+	w, err := d.store.GetWebhook(ctx, printer, delivery.WebhookId)
+	if err != nil {
+		// webhook was deleted, no need to retry
+		delivery.State = store.WebhookDeliveryFailed
+		delivery.LastError = fmt.Sprintf("unable to get webhook: %s", err)
+	} else {
+		code, err := d.send(ctx, w, delivery)
+		delivery.ResponseCode = int32(code)
+		if err != nil {
+			delivery.LastError = err.Error()
+			if delivery.Attempts >= d.maxAttempts {
+				delivery.State = store.WebhookDeliveryFailed
+			} else {
+				delivery.NextAttemptAt = time.Now().Add(d.Backoff(delivery.Attempts))
+			}
+		} else {
+			delivery.State = store.WebhookDeliverySucceeded
+		}
+	}
+	if delivery.LastError != "" {
+		d.logger.Warn("webhook delivery failed",
+			zap.String("deliveryId", delivery.Id),
+			zap.String("event", delivery.Event),
+			zap.String("webhookId", delivery.WebhookId),
+			zap.Int32("attempts", delivery.Attempts),
+			zap.String("error", delivery.LastError),
+		)
+	}
+	if _, err := d.store.SaveWebhookDelivery(ctx, printer, delivery); err != nil {
+		d.logger.Error("unable to save webhook delivery", zap.String("deliveryId", delivery.Id), zap.Error(err))
+	}
+}
+
+// send posts the delivery's payload to the given webhook.
+// It returns the http status code of the response.
+func (d *Dispatcher) send(ctx context.Context, w *store.Webhook, delivery *store.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest("POST", w.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("unable to create request: %w", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gooser-webhooks")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.Id)
+	req.Header.Set(HeaderSignature, Sign(w.Secret, body))
+	res, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("unable to send request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status code %v", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// WithLogger sets the logger of the dispatcher.
+func WithLogger(logger *zap.Logger) func(*Dispatcher) error {
+	return func(d *Dispatcher) error {
+		d.logger = logger
+		return nil
+	}
+}
+
+// WithHTTPClient sets the http client used to send the deliveries.
+func WithHTTPClient(client *http.Client) func(*Dispatcher) error {
+	return func(d *Dispatcher) error {
+		d.httpClient = client
+		return nil
+	}
+}
+
+// WithWorkers sets the number of workers sending deliveries.
+func WithWorkers(workers int) func(*Dispatcher) error {
+	return func(d *Dispatcher) error {
+		if workers <= 0 {
+			return fmt.Errorf("number of workers %v is invalid because it is less or equal 0", workers)
+		}
+		d.workers = workers
+		return nil
+	}
+}
+
+// WithPollInterval sets the interval in which the workers look for due deliveries.
+func WithPollInterval(interval time.Duration) func(*Dispatcher) error {
+	return func(d *Dispatcher) error {
+		d.pollInterval = interval
+		return nil
+	}
+}
+
+// WithMaxAttempts sets the number of attempts after which a delivery is
+// marked as failed.
+func WithMaxAttempts(attempts int32) func(*Dispatcher) error {
+	return func(d *Dispatcher) error {
+		if attempts <= 0 {
+			return fmt.Errorf("number of attempts %v is invalid because it is less or equal 0", attempts)
+		}
+		d.maxAttempts = attempts
+		return nil
+	}
+}
+
+// WithBackoff sets the minimum and maximum duration between two attempts.
+func WithBackoff(min, max time.Duration) func(*Dispatcher) error {
+	return func(d *Dispatcher) error {
+		if min <= 0 || max < min {
+			return fmt.Errorf("invalid backoff from %s to %s", min, max)
+		}
+		d.minBackoff = min
+		d.maxBackoff = max
+		return nil
+	}
+}
@@ -0,0 +1,125 @@
+package webhooks
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rbicker/gooser/internal/mocks"
+	"github.com/rbicker/gooser/internal/store"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"golang.org/x/text/message"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '{"event":"user.created"}' | openssl dgst -sha256 -hmac secret
+	got := Sign("secret", []byte(`{"event":"user.created"}`))
+	assert.Equal(t, "sha256=e851f51160ef29a5847ccec510a3d5b801d448e9f8d769e8484a75fb4b9f6947", got)
+}
+
+func TestBackoff(t *testing.T) {
+	d, err := NewDispatcher(nil, WithBackoff(time.Second, 10*time.Second))
+	if err != nil {
+		t.Fatalf("unable to create dispatcher: %s", err)
+	}
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 50, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, d.Backoff(tt.attempts), "backoff mismatch for %v attempts", tt.attempts)
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		attempts      int32
+		wantState     string
+		wantAttempts  int32
+		wantRetryWait bool
+	}{
+		{
+			name:         "successful delivery",
+			status:       http.StatusNoContent,
+			wantState:    store.WebhookDeliverySucceeded,
+			wantAttempts: 1,
+		},
+		{
+			name:          "failed delivery is retried",
+			status:        http.StatusInternalServerError,
+			wantState:     store.WebhookDeliveryPending,
+			wantAttempts:  1,
+			wantRetryWait: true,
+		},
+		{
+			name:         "failed delivery without attempts left",
+			status:       http.StatusInternalServerError,
+			attempts:     2,
+			wantState:    store.WebhookDeliveryFailed,
+			wantAttempts: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			var gotBody []byte
+			var gotHeader http.Header
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotBody, _ = ioutil.ReadAll(r.Body)
+				gotHeader = r.Header
+				w.WriteHeader(tt.status)
+			}))
+			defer ts.Close()
+			db := new(mocks.WebhookStore)
+			db.On("GetWebhook", mock.Anything, mock.Anything, "hook1").Return(&store.Webhook{
+				Id:     "hook1",
+				Url:    ts.URL,
+				Secret: "secret",
+				Active: true,
+			}, nil).Once()
+			var saved *store.WebhookDelivery
+			db.On("SaveWebhookDelivery", mock.Anything, mock.Anything, mock.Anything).Return(
+				func(ctx context.Context, printer *message.Printer, d *store.WebhookDelivery) *store.WebhookDelivery {
+					saved = d
+					return d
+				},
+				nil,
+			).Once()
+			d, err := NewDispatcher(db, WithMaxAttempts(3))
+			if err != nil {
+				t.Fatalf("unable to create dispatcher: %s", err)
+			}
+			payload, err := NewPayload(EventUserDeleted, &Membership{UserId: "user1"})
+			assert.Nil(err)
+			d.Deliver(context.Background(), &store.WebhookDelivery{
+				Id:        "delivery1",
+				WebhookId: "hook1",
+				Event:     EventUserDeleted,
+				Payload:   payload,
+				State:     store.WebhookDeliveryPending,
+				Attempts:  tt.attempts,
+			})
+			db.AssertExpectations(t)
+			assert.Equal(payload, string(gotBody), "body mismatch")
+			assert.Equal(Sign("secret", gotBody), gotHeader.Get(HeaderSignature), "signature mismatch")
+			assert.Equal(EventUserDeleted, gotHeader.Get(HeaderEvent), "event header mismatch")
+			assert.Equal("delivery1", gotHeader.Get(HeaderDelivery), "delivery header mismatch")
+			assert.Equal(tt.wantState, saved.State, "state mismatch")
+			assert.Equal(tt.wantAttempts, saved.Attempts, "attempts mismatch")
+			assert.Equal(int32(tt.status), saved.ResponseCode, "response code mismatch")
+			assert.Equal(tt.wantRetryWait, saved.NextAttemptAt.After(time.Now()), "next attempt mismatch")
+		})
+	}
+}